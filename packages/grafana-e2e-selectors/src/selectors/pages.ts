@@ -629,6 +629,9 @@ export const versionedPages = {
       TemplateVariablesWarningAlert: {
         '9.1.0': 'data-testid public dashboard disabled template variables alert',
       },
+      VariableValuesSelect: {
+        '11.5.0': (name: string) => `data-testid public dashboard allowed values of variable ${name}`,
+      },
       UnsupportedDataSourcesWarningAlert: {
         '9.5.0': 'data-testid public dashboard unsupported data sources alert',
       },
@@ -661,6 +664,23 @@ export const versionedPages = {
           '9.5.0': 'data-testid public dashboard reshare link button',
         },
       },
+      AccessTokens: {
+        TokenList: {
+          '11.5.0': 'data-testid public dashboard access token list',
+        },
+        AddButton: {
+          '11.5.0': 'data-testid public dashboard add access token button',
+        },
+        NameInput: {
+          '11.5.0': 'data-testid public dashboard access token name input',
+        },
+        CreateButton: {
+          '11.5.0': 'data-testid public dashboard create access token button',
+        },
+        CreatedUrlInput: {
+          '11.5.0': 'data-testid public dashboard created access token url input',
+        },
+      },
     },
     SnapshotScene: {
       url: {
@@ -796,6 +816,17 @@ export const versionedPages = {
         '9.5.0': 'public-dashboard-paused-description',
       },
     },
+    PasswordPrompt: {
+      container: {
+        '11.5.0': 'public-dashboard-password-prompt',
+      },
+      passwordInput: {
+        '11.5.0': 'public-dashboard-password-input',
+      },
+      submitButton: {
+        '11.5.0': 'public-dashboard-password-submit-button',
+      },
+    },
     footer: {
       '11.0.0': 'public-dashboard-footer',
     },
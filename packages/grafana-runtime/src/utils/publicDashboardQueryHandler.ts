@@ -4,6 +4,7 @@ import { DataQuery, DataQueryRequest, DataQueryResponse } from '@grafana/data';
 
 import { config } from '../config';
 import { getBackendSrv } from '../services/backendSrv';
+import { getTemplateSrv } from '../services/templateSrv';
 
 import { BackendDataSourceResponse, toDataQueryResponse } from './queryResponse';
 
@@ -30,6 +31,7 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       to: toRange.valueOf().toString(),
       timezone: request.timezone,
     },
+    variables: getVariableValues(),
   };
 
   return getBackendSrv()
@@ -48,3 +50,22 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       })
     );
 }
+
+/**
+ * Returns the selected values of the template variables. The server only accepts the values
+ * allowed by the public dashboard, ad hoc filters are not supported.
+ */
+function getVariableValues(): Record<string, string[]> {
+  const values: Record<string, string[]> = {};
+  for (const variable of getTemplateSrv().getVariables()) {
+    if (variable.type === 'adhoc' || variable.type === 'system' || !('current' in variable)) {
+      continue;
+    }
+    const value = variable.current.value;
+    if (value === undefined || value === null) {
+      continue;
+    }
+    values[variable.name] = Array.isArray(value) ? value.map(String) : [String(value)];
+  }
+  return values;
+}
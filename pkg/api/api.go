@@ -547,11 +547,32 @@ func (hs *HTTPServer) registerRoutes() {
 			// POST influx line protocol.
 			liveRoute.Post("/push/:streamId", hs.LivePushGateway.Handle)
 
+			// POST Prometheus remote-write and OTLP/HTTP metrics to the channel rules of each metric.
+			liveRoute.Post("/pipeline/push/prometheus/*", reqOrgAdmin, hs.LivePushGateway.HandlePrometheusRemoteWritePush)
+			liveRoute.Post("/pipeline/push/otlp/*", reqOrgAdmin, hs.LivePushGateway.HandleOTLPMetricsPush)
+
 			// List available streams and fields
 			liveRoute.Get("/list", routing.Wrap(hs.Live.HandleListHTTP))
 
 			// Some channels may have info
 			liveRoute.Get("/info/*", routing.Wrap(hs.Live.HandleInfoHTTP))
+
+			// GET Live channel history.
+			liveRoute.Get("/history/*", routing.Wrap(hs.Live.HandleHistoryHTTP))
+
+			if hs.Cfg.LivePipelineEnabled {
+				// Channel rules of the pipeline, frames are processed and output according to them.
+				liveRoute.Get("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesListHTTP))
+				liveRoute.Post("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesPostHTTP))
+				liveRoute.Put("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesPutHTTP))
+				liveRoute.Delete("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesDeleteHTTP))
+				liveRoute.Post("/pipeline-convert-test", reqOrgAdmin, routing.Wrap(hs.Live.HandlePipelineConvertTestHTTP))
+				liveRoute.Get("/pipeline-entities", reqOrgAdmin, routing.Wrap(hs.Live.HandlePipelineEntitiesListHTTP))
+				liveRoute.Get("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsListHTTP))
+				liveRoute.Post("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsPostHTTP))
+				liveRoute.Put("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsPutHTTP))
+				liveRoute.Delete("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsDeleteHTTP))
+			}
 		}, requestmeta.SetSLOGroup(requestmeta.SLOGroupNone))
 
 		// short urls
@@ -610,6 +631,10 @@ func (hs *HTTPServer) registerRoutes() {
 	r.Get("/api/snapshot/shared-options/", reqSignedIn, hs.GetSharingOptions)
 
 	r.Post("/api/snapshots/", reqSnapshotPublicModeOrCreate, hs.getCreatedSnapshotHandler())
+	r.Post("/api/snapshots/dashboards/:dashboardUid", reqSignedIn, authorize(ac.EvalAll(
+		ac.EvalPermission(dashboards.ActionSnapshotsCreate),
+		ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":dashboardUid"))),
+	)), routing.Wrap(hs.CaptureDashboardSnapshot))
 	r.Get("/api/snapshots/:key", routing.Wrap(hs.GetDashboardSnapshot))
 	r.Delete("/api/snapshots/:key", authorize(ac.EvalPermission(dashboards.ActionSnapshotsDelete)), routing.Wrap(hs.DeleteDashboardSnapshot))
 
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/errhttp"
 	"github.com/grafana/grafana/pkg/web"
@@ -92,6 +93,50 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *contextmodel.ReqContext) {
 	}, cmd, hs.dashboardsnapshotsService)
 }
 
+// swagger:route POST /snapshots/dashboards/{dashboardUid} snapshots captureDashboardSnapshot
+//
+// Create a snapshot of a dashboard on the server.
+//
+// The queries of the panels run on the server with the permissions of the caller, and with the given time range and template variable values.
+// Panels querying data sources the caller can't query have no data in the snapshot.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) CaptureDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	cmd := dashboardsnapshots.CaptureDashboardSnapshotCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.DashboardUID = web.Params(c.Req)[":dashboardUid"]
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+
+	result, err := hs.dashboardsnapshotsService.CaptureDashboardSnapshot(c.Req.Context(), &cmd)
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return response.Error(http.StatusNotFound, "Dashboard not found", err)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create snapshot", err)
+	}
+
+	metrics.MApiDashboardSnapshotCreate.Inc()
+
+	return response.JSON(http.StatusOK, dashboardsnapshot.DashboardCreateResponse{
+		Key:       result.Key,
+		DeleteKey: result.DeleteKey,
+		URL:       setting.ToAbsUrl("dashboard/snapshot/" + result.Key),
+		DeleteURL: setting.ToAbsUrl("api/snapshots-delete/" + result.DeleteKey),
+	})
+}
+
 // GET /api/snapshots/:key
 // swagger:route GET /snapshots/{key} snapshots getDashboardSnapshot
 //
@@ -314,6 +359,16 @@ type CreateSnapshotParams struct {
 	Body dashboardsnapshots.CreateDashboardSnapshotCommand `json:"body"`
 }
 
+// swagger:parameters captureDashboardSnapshot
+type CaptureSnapshotParams struct {
+	// in:path
+	// required:true
+	DashboardUID string `json:"dashboardUid"`
+	// in:body
+	// required:true
+	Body dashboardsnapshots.CaptureDashboardSnapshotCommand `json:"body"`
+}
+
 // swagger:parameters searchDashboardSnapshots
 type GetSnapshotsParams struct {
 	// Search Query
@@ -125,6 +125,79 @@ func TestHTTPServer_DeleteDashboardSnapshot(t *testing.T) {
 	})
 }
 
+func TestHTTPServer_CaptureDashboardSnapshot(t *testing.T) {
+	setup := func(t *testing.T, svc dashboardsnapshots.Service) *webtest.Server {
+		t.Helper()
+
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			cfg := setting.NewCfg()
+			cfg.SnapshotEnabled = true
+			hs.Cfg = cfg
+			hs.dashboardsnapshotsService = svc
+			hs.AccessControl = acimpl.ProvideAccessControl(featuremgmt.WithFeatures(), zanzana.NewNoopClient())
+		})
+	}
+
+	allowedUser := userWithPermissions(1, []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:abc"},
+		{Action: dashboards.ActionSnapshotsCreate},
+	})
+	newRequest := func(server *webtest.Server, dashboardUID string) *http.Request {
+		req := server.NewPostRequest("/api/snapshots/dashboards/"+dashboardUID, strings.NewReader(`{"from": "now-1h", "to": "now", "variables": {"server": ["a", "b"]}}`))
+		req.Header.Set("Content-Type", "application/json")
+		return webtest.RequestWithSignedInUser(req, allowedUser)
+	}
+
+	t.Run("User should not be able to capture a snapshot of a dashboard they can't read", func(t *testing.T) {
+		server := setup(t, dashboardsnapshots.NewMockService(t))
+
+		res, err := server.Send(newRequest(server, "other"))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("User should be able to capture a snapshot with correct permissions", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("CaptureDashboardSnapshot", mock.Anything, mock.MatchedBy(func(cmd *dashboardsnapshots.CaptureDashboardSnapshotCommand) bool {
+			return cmd.DashboardUID == "abc" && cmd.OrgID == 1 && cmd.From == "now-1h" && cmd.To == "now" &&
+				assert.ObjectsAreEqual([]string{"a", "b"}, cmd.Variables["server"])
+		})).Return(&dashboardsnapshots.DashboardSnapshot{Key: "key", DeleteKey: "deleteKey"}, nil)
+		server := setup(t, svc)
+
+		res, err := server.Send(newRequest(server, "abc"))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("User should get not found when the dashboard doesn't exist", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("CaptureDashboardSnapshot", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound)
+		server := setup(t, svc)
+
+		res, err := server.Send(newRequest(server, "abc"))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("User should get bad request for invalid variables", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("CaptureDashboardSnapshot", mock.Anything, mock.Anything).Return(nil, dashboardsnapshots.ErrInvalidVariables.Errorf("unknown template variable server"))
+		server := setup(t, svc)
+
+		res, err := server.Send(newRequest(server, "abc"))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 	setupRemoteServer := func(fn func(http.ResponseWriter, *http.Request)) *httptest.Server {
 		s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
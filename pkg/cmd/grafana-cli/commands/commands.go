@@ -8,11 +8,13 @@ import (
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/datamigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/secretsmigrations"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/unifiedstorage"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/server"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
 )
 
 func runRunnerCommand(command func(commandLine utils.CommandLine, runner server.Runner) error) func(context *cli.Context) error {
@@ -167,6 +169,70 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "unified-storage",
+		Usage: "Export and import the resources in unified storage",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "Writes every resource of a namespace to a tar archive",
+				Action: runDbCommand(unifiedstorage.ExportNamespace),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "namespace",
+						Usage:    "The namespace to export, for example default or org-2",
+						Required: true,
+					},
+					&cli.StringSliceFlag{
+						Name:  "resource",
+						Usage: "Only export this resource, formatted as {resource}.{group}. Exports all resources when not set",
+					},
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "The archive file, or - for stdout",
+						Value: "-",
+					},
+					&cli.BoolFlag{
+						Name:  "history",
+						Usage: "Include every version of the resources",
+					},
+					&cli.BoolFlag{
+						Name:  "blobs",
+						Usage: "Include the blobs linked from the resources",
+					},
+				},
+			},
+			{
+				Name:   "import",
+				Usage:  "Loads a tar archive created by the export command",
+				Action: runDbCommand(unifiedstorage.ImportNamespace),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "namespace",
+						Usage: "The target namespace. Defaults to the namespace of the archive",
+					},
+					&cli.StringFlag{
+						Name:  "input",
+						Usage: "The archive file, or - for stdin",
+						Value: "-",
+					},
+					&cli.StringFlag{
+						Name:  "on-conflict",
+						Usage: "What to do when a resource already exists: fail, skip, overwrite or rename",
+						Value: string(resource.ImportConflictFail),
+					},
+					&cli.BoolFlag{
+						Name:  "history",
+						Usage: "Replay the history in the archive instead of writing the latest versions",
+					},
+					&cli.BoolFlag{
+						Name:  "blobs",
+						Usage: "Import the blobs in the archive",
+					},
+				},
+			},
+		},
+	},
 }
 
 var Commands = []*cli.Command{
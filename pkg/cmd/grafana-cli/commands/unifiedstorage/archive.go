@@ -0,0 +1,156 @@
+package unifiedstorage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/sql"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db/dbimpl"
+)
+
+// ExportNamespace writes the resources of a namespace to a tar archive
+func ExportNamespace(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	ctx := context.Background()
+	namespace := c.String("namespace")
+	if namespace == "" {
+		return fmt.Errorf("missing --namespace")
+	}
+	resources, err := parseResources(c.StringSlice("resource"))
+	if err != nil {
+		return err
+	}
+
+	backend, err := newBackend(ctx, cfg, sqlStore)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = backend.Stop(ctx) }()
+
+	opts := resource.ExportOptions{
+		Namespace: namespace,
+		Resources: resources,
+		History:   c.Bool("history"),
+	}
+	if c.Bool("blobs") {
+		if opts.Blobs, err = newBlobSupport(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if name := c.String("output"); name != "" && name != "-" {
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	manifest, err := resource.ExportNamespace(ctx, backend, opts, out)
+	if err != nil {
+		return err
+	}
+	logger.Infof("%s Exported %d resource types from namespace %s\n", color.GreenString("✔"), len(manifest.Resources), namespace)
+	return nil
+}
+
+// ImportNamespace loads a tar archive created by ExportNamespace
+func ImportNamespace(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	ctx := context.Background()
+	backend, err := newBackend(ctx, cfg, sqlStore)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = backend.Stop(ctx) }()
+
+	opts := resource.ImportOptions{
+		Namespace:  c.String("namespace"),
+		OnConflict: resource.ImportConflict(c.String("on-conflict")),
+		History:    c.Bool("history"),
+	}
+	if c.Bool("blobs") {
+		if opts.Blobs, err = newBlobSupport(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if name := c.String("input"); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+
+	summary, err := resource.ImportNamespace(ctx, backend, in, opts)
+	if summary != nil {
+		logger.Infof("created: %d, updated: %d, deleted: %d, skipped: %d, blobs: %d\n",
+			summary.Created, summary.Updated, summary.Deleted, summary.Skipped, summary.Blobs)
+		for from, to := range summary.Renamed {
+			logger.Infof("renamed %s to %s\n", from, to)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	logger.Infof("%s Imported namespace %s\n", color.GreenString("✔"), summary.Manifest.Namespace)
+	return nil
+}
+
+// parseResources reads the resource flags, formatted as {resource}.{group}
+func parseResources(values []string) ([]resource.NamespacedResource, error) {
+	resources := make([]resource.NamespacedResource, 0, len(values))
+	for _, v := range values {
+		res, group, ok := strings.Cut(v, ".")
+		if !ok || res == "" || group == "" {
+			return nil, fmt.Errorf("invalid resource %q, expected {resource}.{group}", v)
+		}
+		resources = append(resources, resource.NamespacedResource{Group: group, Resource: res})
+	}
+	return resources, nil
+}
+
+func newBackend(ctx context.Context, cfg *setting.Cfg, sqlStore db.DB) (sql.Backend, error) {
+	eDB, err := dbimpl.ProvideResourceDB(sqlStore, cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := sql.NewBackend(sql.BackendOptions{
+		DBProvider: eDB,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backend, backend.Init(ctx)
+}
+
+// newBlobSupport opens the blob storage configured for the resource server
+func newBlobSupport(ctx context.Context, cfg *setting.Cfg) (resource.BlobSupport, error) {
+	url := cfg.SectionWithEnvOverrides("grafana-apiserver").Key("blob_url").MustString("")
+	if url == "" {
+		return nil, fmt.Errorf("blob storage is not configured (grafana-apiserver.blob_url)")
+	}
+	if strings.HasPrefix(url, "./data/") {
+		url = "file:///" + strings.Replace(url, "./data", cfg.DataPath, 1)
+	}
+	bucket, err := resource.OpenBlobBucket(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return resource.NewCDKBlobSupport(ctx, resource.CDKBlobSupportOptions{
+		Bucket: bucket,
+	})
+}
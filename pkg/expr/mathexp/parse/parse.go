@@ -19,6 +19,7 @@ type Tree struct {
 	Text     string // text parsed to create the expression.
 	Root     Node   // top-level root of the tree, returns a number.
 	VarNames []string
+	Mode     Mode // controls the syntax accepted by the parser.
 
 	funcs []map[string]Func
 
@@ -28,6 +29,15 @@ type Tree struct {
 	peekCount int
 }
 
+// A Mode is a set of flags (or 0) which control the syntax accepted by the parser.
+type Mode uint
+
+const (
+	// CommaArgs requires commas between function arguments, so functions can take several
+	// expressions as arguments. Server side expressions don't use it.
+	CommaArgs Mode = 1 << iota
+)
+
 // Func holds the structure of a parsed function call.
 type Func struct {
 	Args          []ReturnType
@@ -332,6 +342,24 @@ func (t *Tree) Func() (f *FuncNode) {
 		case itemRightParen:
 			return
 		}
+		if t.Mode&CommaArgs != 0 {
+			t.argSeparator()
+		}
+	}
+}
+
+// argSeparator consumes the comma after a function argument, the last argument is followed
+// by the right paren instead.
+func (t *Tree) argSeparator() {
+	switch token := t.next(); token.typ {
+	case itemRightParen:
+		t.backup()
+	case itemComma:
+		if t.peek().typ == itemRightParen {
+			t.unexpected(token, "func")
+		}
+	default:
+		t.unexpected(token, "func")
 	}
 }
 
@@ -0,0 +1,60 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFuncArgs(t *testing.T) {
+	funcs := map[string]Func{
+		"clamp": {
+			Args:          []ReturnType{TypeVariantSet, TypeVariantSet, TypeVariantSet},
+			VariantReturn: true,
+		},
+		"now": {
+			Return: TypeScalar,
+		},
+	}
+	parse := func(input string) (*Tree, error) {
+		tree := New()
+		tree.Mode = CommaArgs
+		return tree, tree.Parse(input, funcs)
+	}
+
+	tree, err := parse("clamp($A, now(), 2 * 3)")
+	require.NoError(t, err)
+	require.Equal(t, "clamp($A, now(), *(2, 3))", tree.Root.StringAST())
+	require.Equal(t, []string{"A"}, tree.VarNames)
+
+	for _, input := range []string{"clamp(, 1, 2)", "clamp(1, 2, 3,)", "clamp(1 2 3)", "clamp(1, 2)", "clamp(1, 2, 3, 4)"} {
+		_, err := parse(input)
+		require.Error(t, err, input)
+	}
+}
+
+// Server side expressions don't separate function arguments, commas are rejected with the
+// same errors as before model expressions could use them.
+func TestParseServerSideExpressionFuncArgs(t *testing.T) {
+	funcs := map[string]Func{
+		"abs": {
+			Args:          []ReturnType{TypeVariantSet},
+			VariantReturn: true,
+		},
+	}
+
+	tree, err := Parse("abs($A) + abs(-2)", funcs)
+	require.NoError(t, err)
+	require.Equal(t, "+(abs($A), abs(-2))", tree.Root.StringAST())
+
+	for input, expected := range map[string]string{
+		"abs($A, $B)": `expr: unexpected "," in input: F()`,
+		"abs(, $A)":   `expr: unexpected "," in input: F()`,
+		"abs($A,)":    `expr: unexpected "," in input: F()`,
+		"abs($A $B)":  "expr: parse: too many arguments for abs",
+		"$A, $B":      `expr: unexpected "," in root input`,
+	} {
+		_, err := Parse(input, funcs)
+		require.EqualError(t, err, expected, input)
+	}
+}
@@ -69,6 +69,9 @@ var (
 	// MApiDashboardSnapshotGet is a metric loaded dashboards
 	MApiDashboardSnapshotGet prometheus.Counter
 
+	// MDashboardSnapshotExpiredDeleted is a metric expired dashboard snapshots deleted by the cleanup job
+	MDashboardSnapshotExpiredDeleted prometheus.Counter
+
 	// MApiDashboardInsert is a metric dashboards inserted
 	MApiDashboardInsert prometheus.Counter
 
@@ -108,6 +111,12 @@ var (
 	// MPublicDashboardDatasourceQuerySuccess is a metric counter for successful queries labelled by datasource
 	MPublicDashboardDatasourceQuerySuccess *prometheus.CounterVec
 
+	// MPublicDashboardQueryCache is a metric counter for public dashboards query cache lookups labelled by result
+	MPublicDashboardQueryCache *prometheus.CounterVec
+
+	// MPublicDashboardRequestRejected is a metric counter for public dashboards requests rejected by a rate limit
+	MPublicDashboardRequestRejected *prometheus.CounterVec
+
 	// MFolderIDsAPICount is a metric counter for folder ids count in the api package
 	MFolderIDsAPICount *prometheus.CounterVec
 
@@ -366,6 +375,12 @@ func init() {
 		Namespace: ExporterName,
 	})
 
+	MDashboardSnapshotExpiredDeleted = metricutil.NewCounterStartingAtZero(prometheus.CounterOpts{
+		Name:      "dashboard_snapshot_expired_deleted_total",
+		Help:      "expired dashboard snapshots deleted",
+		Namespace: ExporterName,
+	})
+
 	MApiDashboardInsert = metricutil.NewCounterStartingAtZero(prometheus.CounterOpts{
 		Name:      "api_models_dashboard_insert_total",
 		Help:      "dashboards inserted ",
@@ -470,6 +485,18 @@ func init() {
 		Namespace: ExporterName,
 	}, []string{"datasource", "status"}, map[string][]string{"status": pubdash.QueryResultStatuses})
 
+	MPublicDashboardQueryCache = metricutil.NewCounterVecStartingAtZero(prometheus.CounterOpts{
+		Name:      "public_dashboard_query_cache_total",
+		Help:      "counter for public dashboards query cache lookups labelled by result hit/miss",
+		Namespace: ExporterName,
+	}, []string{"result"}, map[string][]string{"result": pubdash.QueryCacheResults})
+
+	MPublicDashboardRequestRejected = metricutil.NewCounterVecStartingAtZero(prometheus.CounterOpts{
+		Name:      "public_dashboard_rejected_request_count",
+		Help:      "counter for public dashboards requests rejected by a rate limit labelled by limit client_ip/dashboard",
+		Namespace: ExporterName,
+	}, []string{"limit"}, map[string][]string{"limit": pubdash.RateLimits})
+
 	MFolderIDsAPICount = metricutil.NewCounterVecStartingAtZero(prometheus.CounterOpts{
 		Name:      "folder_id_api_count",
 		Help:      "counter for folder id usage in api package",
@@ -744,6 +771,7 @@ func initMetricVars(reg prometheus.Registerer) {
 		MApiDashboardSnapshotCreate,
 		MApiDashboardSnapshotExternal,
 		MApiDashboardSnapshotGet,
+		MDashboardSnapshotExpiredDeleted,
 		MApiDashboardInsert,
 		MAlertingResultState,
 		MAlertingNotificationSent,
@@ -787,6 +815,8 @@ func initMetricVars(reg prometheus.Registerer) {
 		MStatTotalPublicDashboards,
 		MPublicDashboardRequestCount,
 		MPublicDashboardDatasourceQuerySuccess,
+		MPublicDashboardQueryCache,
+		MPublicDashboardRequestRejected,
 		MStatTotalCorrelations,
 		MFolderIDsAPICount,
 		MFolderIDsServiceCount,
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/apiserver/builder"
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/search"
 	"github.com/grafana/grafana/pkg/util/errhttp"
 )
 
@@ -78,6 +79,42 @@ func (s *SearchHandler) GetAPIRoutes(defs map[string]common.OpenAPIDefinition) *
 										Schema:      spec.StringProperty(),
 									},
 								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "datasource",
+										In:          "query",
+										Description: "dashboards using the data source uid",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "variable",
+										In:          "query",
+										Description: "dashboards with the template variable",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "libraryPanel",
+										In:          "query",
+										Description: "dashboards using the library panel uid",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "panelQuery",
+										In:          "query",
+										Description: "text in the panel queries, eg: a metric name",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
 								{
 									ParameterProps: spec3.ParameterProps{
 										Name:        "sort",
@@ -190,6 +227,14 @@ func (s *SearchHandler) DoSortable(w http.ResponseWriter, r *http.Request) {
 	s.write(w, sortable)
 }
 
+// panelFilters maps the query parameters to the dashboard search fields
+var panelFilters = []struct{ param, field string }{
+	{"datasource", search.DASHBOARD_DS_UID},
+	{"variable", search.DASHBOARD_VARIABLES},
+	{"libraryPanel", search.DASHBOARD_LIBRARY_PANEL},
+	{"panelQuery", search.DASHBOARD_PANEL_QUERY},
+}
+
 func (s *SearchHandler) DoSearch(w http.ResponseWriter, r *http.Request) {
 	ctx, span := s.tracer.Start(r.Context(), "dashboard.search")
 	defer span.End()
@@ -244,6 +289,20 @@ func (s *SearchHandler) DoSearch(w http.ResponseWriter, r *http.Request) {
 		}}
 	}
 
+	// Add the panel constraints, every value must match
+	for _, filter := range panelFilters {
+		for _, v := range queryParams[filter.param] {
+			if v == "" {
+				continue
+			}
+			searchRequest.Options.Fields = append(searchRequest.Options.Fields, &resource.Requirement{
+				Key:      "fields." + filter.field,
+				Operator: "=",
+				Values:   []string{v},
+			})
+		}
+	}
+
 	// Add sorting
 	if queryParams.Has("sort") {
 		for _, sort := range queryParams["sort"] {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/annotations"
@@ -204,6 +205,12 @@ func (srv *CleanUpService) deleteExpiredSnapshots(ctx context.Context) {
 	cmd := dashboardsnapshots.DeleteExpiredSnapshotsCommand{}
 	if err := srv.dashboardSnapshotService.DeleteExpiredSnapshots(ctx, &cmd); err != nil {
 		logger.Error("Failed to delete expired snapshots", "error", err.Error())
+		return
+	}
+
+	metrics.MDashboardSnapshotExpiredDeleted.Add(float64(cmd.DeletedRows))
+	if cmd.DeletedRows > 0 {
+		logger.Info("Deleted expired snapshots", "rows affected", cmd.DeletedRows)
 	} else {
 		logger.Debug("Deleted expired snapshots", "rows affected", cmd.DeletedRows)
 	}
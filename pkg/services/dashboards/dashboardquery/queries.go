@@ -0,0 +1,97 @@
+// Package dashboardquery extracts the queries of the panels of a dashboard and interpolates their template
+// variables, so they can be run on the server.
+package dashboardquery
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+// PanelQueries are the queries of a panel
+type PanelQueries struct {
+	Panel   *simplejson.Json
+	Queries []*simplejson.Json
+}
+
+// GroupQueriesByPanelID returns the queries of every panel of the dashboard, including the panels of collapsed
+// rows. The queries are copies, so they can be changed without changing the dashboard. Hidden queries are left out
+// unless the panel has an expression, which may use them.
+func GroupQueriesByPanelID(dashboard *simplejson.Json) map[int64]*PanelQueries {
+	result := make(map[int64]*PanelQueries)
+
+	extractQueriesFromPanels(dashboard.Get("panels").MustArray(), result)
+
+	return result
+}
+
+func extractQueriesFromPanels(panels []any, result map[int64]*PanelQueries) {
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+
+		// if the panel is a row and it is collapsed, get the queries from the panels inside the row
+		if panel.Get("type").MustString() == "row" && panel.Get("collapsed").MustBool() {
+			// recursive call to get queries from panels inside a row
+			extractQueriesFromPanels(panel.Get("panels").MustArray(), result)
+			continue
+		}
+
+		var queries []*simplejson.Json
+		hasExpression := panelHasAnExpression(panel)
+
+		for _, queryObj := range panel.Get("targets").MustArray() {
+			query := simplejson.NewFromAny(copyJSON(queryObj))
+
+			// it the panel doesn't have an expression and the query is disabled (hide is true), skip the query
+			// the expression handler will take care later of removing hidden queries which could be necessary to calculate
+			// the value of other queries
+			if !hasExpression && query.Get("hide").MustBool() {
+				continue
+			}
+			queries = append(queries, query)
+		}
+
+		result[panel.Get("id").MustInt64()] = &PanelQueries{Panel: panel, Queries: queries}
+	}
+}
+
+func panelHasAnExpression(panel *simplejson.Json) bool {
+	for _, queryObj := range panel.Get("targets").MustArray() {
+		query := simplejson.NewFromAny(queryObj)
+		if expr.NodeTypeFromDatasourceUID(DataSourceUID(query)) == expr.TypeCMDNode {
+			return true
+		}
+	}
+	return false
+}
+
+// DataSourceUID returns the uid of the data source of a panel or a query
+func DataSourceUID(query *simplejson.Json) string {
+	uid := query.Get("datasource").Get("uid").MustString()
+
+	// before 8.3 special types could be sent as datasource (expr)
+	if uid == "" {
+		uid = query.Get("datasource").MustString()
+	}
+
+	return uid
+}
+
+// copyJSON returns a deep copy of a decoded JSON value
+func copyJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for key, item := range v {
+			c[key] = copyJSON(item)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, item := range v {
+			c[i] = copyJSON(item)
+		}
+		return c
+	default:
+		return v
+	}
+}
@@ -0,0 +1,45 @@
+package dashboardquery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestGroupQueriesByPanelID(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 1, "targets": [{"refId": "A"}, {"refId": "B", "hide": true}]},
+			{"id": 2, "targets": [
+				{"refId": "A", "hide": true},
+				{"refId": "B", "datasource": {"uid": "__expr__", "type": "__expr__"}}
+			]},
+			{"id": 3, "type": "row", "collapsed": true, "panels": [{"id": 4, "targets": [{"refId": "A"}]}]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	queries := GroupQueriesByPanelID(dashboard)
+	require.Len(t, queries, 3)
+
+	require.Len(t, queries[1].Queries, 1)
+	assert.Equal(t, int64(1), queries[1].Panel.Get("id").MustInt64())
+
+	// the hidden query may be used by the expression
+	assert.Len(t, queries[2].Queries, 2)
+
+	require.Len(t, queries[4].Queries, 1)
+
+	// the queries are copies of the dashboard queries
+	queries[1].Queries[0].Set("refId", "C")
+	assert.Equal(t, "A", dashboard.Get("panels").GetIndex(0).Get("targets").GetIndex(0).Get("refId").MustString())
+}
+
+func TestDataSourceUID(t *testing.T) {
+	assert.Equal(t, "ds1", DataSourceUID(simplejson.NewFromAny(map[string]any{"datasource": map[string]any{"uid": "ds1"}})))
+	assert.Equal(t, "__expr__", DataSourceUID(simplejson.NewFromAny(map[string]any{"datasource": "__expr__"})))
+	assert.Equal(t, "", DataSourceUID(simplejson.New()))
+}
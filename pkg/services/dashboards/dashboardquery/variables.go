@@ -0,0 +1,179 @@
+package dashboardquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// AllValue is the value of a variable when "All" is selected
+const AllValue = "$__all"
+
+// variableRegex matches $var, [[var]], [[var:format]], ${var} and ${var:format}
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?::(\w+))?\}`)
+
+// Variable is the value of a template variable
+type Variable struct {
+	Values []string
+	// Multi is set for variables which can have several values or include all values,
+	// some data sources format the values of these variables differently.
+	Multi bool
+	// Raw is set when the value is the custom all value of the variable, it's used without
+	// formatting.
+	Raw bool
+}
+
+// StringOrArray returns the values of a variable value, which is a string or an array of strings
+func StringOrArray(js *simplejson.Json) []string {
+	if s, err := js.String(); err == nil {
+		return []string{s}
+	}
+	return js.MustStringArray()
+}
+
+// InterpolateVariables replaces the template variables in every string of the query. Variables
+// without a format are formatted like the data source of the query formats them.
+func InterpolateVariables(query *simplejson.Json, variables map[string]Variable) {
+	if len(variables) == 0 {
+		return
+	}
+	dsType := query.Get("datasource").Get("type").MustString()
+	for key, value := range query.MustMap() {
+		if key == "datasource" || key == "refId" {
+			continue
+		}
+		query.Set(key, interpolateValue(value, variables, dsType))
+	}
+}
+
+func interpolateValue(value any, variables map[string]Variable, dsType string) any {
+	switch v := value.(type) {
+	case string:
+		return variableRegex.ReplaceAllStringFunc(v, func(match string) string {
+			groups := variableRegex.FindStringSubmatch(match)
+			name, format := groups[1], ""
+			if groups[2] != "" {
+				name, format = groups[2], groups[3]
+			} else if groups[4] != "" {
+				name, format = groups[4], groups[5]
+			}
+			variable, ok := variables[name]
+			if !ok {
+				return match // built in variables like $__interval are handled by the data source
+			}
+			if variable.Raw {
+				return strings.Join(variable.Values, ",")
+			}
+			if format == "" {
+				return formatDataSourceVariable(variable, dsType)
+			}
+			return FormatVariable(variable.Values, format)
+		})
+	case map[string]any:
+		for key, item := range v {
+			v[key] = interpolateValue(item, variables, dsType)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = interpolateValue(item, variables, dsType)
+		}
+		return v
+	}
+	return value
+}
+
+// FormatVariable formats the values like the variable formats of the dashboard
+func FormatVariable(values []string, format string) string {
+	quote := func(q string) string {
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = q + strings.ReplaceAll(v, q, "\\"+q) + q
+		}
+		return strings.Join(quoted, ",")
+	}
+
+	switch format {
+	case "raw", "csv":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "singlequote":
+		return quote("'")
+	case "doublequote":
+		return quote(`"`)
+	case "regex":
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = regexp.QuoteMeta(v)
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "json":
+		raw, _ := json.Marshal(values)
+		return string(raw)
+	}
+
+	// glob is the default format
+	if len(values) == 1 {
+		return values[0]
+	}
+	return fmt.Sprintf("{%s}", strings.Join(values, ","))
+}
+
+var (
+	prometheusRegexEscaper = regexp.MustCompile(`[$^*{}\[\]'+?.()|]`)
+	lokiRegexEscaper       = regexp.MustCompile(`[$^*{}\[\]+?.()|]`)
+)
+
+// formatDataSourceVariable formats the values of a variable without a format like the
+// frontend of the data source does, other data sources use the glob format.
+func formatDataSourceVariable(variable Variable, dsType string) string {
+	values := variable.Values
+	switch dsType {
+	case "prometheus":
+		if !variable.Multi {
+			return prometheusRegularEscape(strings.Join(values, ","))
+		}
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = prometheusRegexEscaper.ReplaceAllString(strings.ReplaceAll(v, `\`, `\\\\`), `\\$0`)
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "loki":
+		if !variable.Multi {
+			return lokiRegularEscape(strings.Join(values, ","))
+		}
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = lokiRegularEscape(lokiRegexEscaper.ReplaceAllString(strings.ReplaceAll(v, `\`, `\\\\`), `\\$0`))
+		}
+		return strings.Join(escaped, "|")
+	case "mysql", "mssql", "postgres", "grafana-postgresql-datasource":
+		if !variable.Multi {
+			return strings.ReplaceAll(strings.Join(values, ","), "'", "''")
+		}
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		}
+		return strings.Join(quoted, ",")
+	}
+	return FormatVariable(values, "")
+}
+
+func prometheusRegularEscape(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, `\`, `\\`), "'", `\\'`)
+}
+
+func lokiRegularEscape(value string) string {
+	return strings.ReplaceAll(value, "'", `\\'`)
+}
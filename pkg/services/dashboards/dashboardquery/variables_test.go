@@ -0,0 +1,91 @@
+package dashboardquery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestInterpolateVariables(t *testing.T) {
+	query := simplejson.NewFromAny(map[string]any{
+		"datasource": map[string]any{"uid": "$job"},
+		"refId":      "A",
+		"expr":       `up{job=~"$job", env="${env}", region=~"${region:regex}"} [$__interval]`,
+		"legend":     "[[job]] [[env:singlequote]]",
+		"nested":     []any{map[string]any{"sql": "SELECT * WHERE job IN (${job:singlequote}) AND env = '$envx'"}},
+		"intervalMs": int64(1000),
+	})
+	InterpolateVariables(query, map[string]Variable{
+		"job":    {Values: []string{"api", "web"}, Multi: true},
+		"env":    {Values: []string{"prod"}},
+		"region": {Values: []string{"eu.west", "us.east"}, Multi: true},
+	})
+
+	assert.Equal(t, "$job", query.GetPath("datasource", "uid").MustString())
+	assert.Equal(t, `up{job=~"{api,web}", env="prod", region=~"(eu\.west|us\.east)"} [$__interval]`, query.Get("expr").MustString())
+	assert.Equal(t, "{api,web} 'prod'", query.Get("legend").MustString())
+	assert.Equal(t, "SELECT * WHERE job IN ('api','web') AND env = '$envx'", query.Get("nested").GetIndex(0).Get("sql").MustString())
+	assert.Equal(t, int64(1000), query.Get("intervalMs").MustInt64())
+}
+
+func TestInterpolateVariablesWithDataSourceFormat(t *testing.T) {
+	variables := map[string]Variable{
+		"job":    {Values: []string{"api", "web.1"}, Multi: true},
+		"host":   {Values: []string{"a.b"}, Multi: true},
+		"env":    {Values: []string{"it's"}},
+		"region": {Values: []string{".*"}, Multi: true, Raw: true},
+	}
+	interpolate := func(dsType string, expr string) string {
+		query := simplejson.NewFromAny(map[string]any{
+			"datasource": map[string]any{"type": dsType, "uid": "ds1"},
+			"expr":       expr,
+		})
+		InterpolateVariables(query, variables)
+		return query.Get("expr").MustString()
+	}
+
+	t.Run("prometheus", func(t *testing.T) {
+		assert.Equal(t, `up{job=~"(api|web\\.1)", host=~"a\\.b", env="it\\'s", region=~".*"}`,
+			interpolate("prometheus", `up{job=~"$job", host=~"$host", env="$env", region=~"$region"}`))
+		assert.Equal(t, `up{job="api,web.1"}`, interpolate("prometheus", `up{job="${job:csv}"}`))
+	})
+
+	t.Run("loki", func(t *testing.T) {
+		assert.Equal(t, `{job=~"api|web\\.1"}`, interpolate("loki", `{job=~"$job"}`))
+	})
+
+	t.Run("sql", func(t *testing.T) {
+		for _, dsType := range []string{"mysql", "mssql", "grafana-postgresql-datasource"} {
+			assert.Equal(t, `SELECT * WHERE job IN ('api','web.1') AND host IN ('a.b') AND env = 'it''s'`,
+				interpolate(dsType, `SELECT * WHERE job IN ($job) AND host IN ($host) AND env = '$env'`), dsType)
+		}
+	})
+
+	t.Run("other data sources use the glob format", func(t *testing.T) {
+		assert.Equal(t, "{api,web.1} a.b", interpolate("elasticsearch", "$job $host"))
+	})
+}
+
+func TestFormatVariable(t *testing.T) {
+	tests := []struct {
+		format   string
+		values   []string
+		expected string
+	}{
+		{format: "", values: []string{"a"}, expected: "a"},
+		{format: "", values: []string{"a", "b"}, expected: "{a,b}"},
+		{format: "glob", values: []string{"a", "b"}, expected: "{a,b}"},
+		{format: "csv", values: []string{"a", "b"}, expected: "a,b"},
+		{format: "pipe", values: []string{"a", "b"}, expected: "a|b"},
+		{format: "regex", values: []string{"a.b"}, expected: `a\.b`},
+		{format: "regex", values: []string{"a", "b"}, expected: "(a|b)"},
+		{format: "singlequote", values: []string{"it's", "b"}, expected: `'it\'s','b'`},
+		{format: "doublequote", values: []string{"a", "b"}, expected: `"a","b"`},
+		{format: "json", values: []string{"a", "b"}, expected: `["a","b"]`},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, FormatVariable(tc.values, tc.format), tc.format)
+	}
+}
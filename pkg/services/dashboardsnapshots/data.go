@@ -0,0 +1,123 @@
+package dashboardsnapshots
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// SnapshotData holds the query results of the panels of a snapshot, captured on the server when the snapshot is
+// created
+type SnapshotData struct {
+	Panels []*SnapshotPanelData `json:"panels"`
+}
+
+// SnapshotPanelData holds the result of a query of a panel. The frames are serialized in the Arrow format.
+type SnapshotPanelData struct {
+	PanelID int64    `json:"panelId"`
+	RefID   string   `json:"refId,omitempty"`
+	Frames  [][]byte `json:"frames,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// AddResponse adds the results of the queries of a panel
+func (d *SnapshotData) AddResponse(panelID int64, res *backend.QueryDataResponse) error {
+	refIDs := make([]string, 0, len(res.Responses))
+	for refID := range res.Responses {
+		refIDs = append(refIDs, refID)
+	}
+	sort.Strings(refIDs)
+
+	for _, refID := range refIDs {
+		dr := res.Responses[refID]
+		panel := &SnapshotPanelData{PanelID: panelID, RefID: refID}
+		if dr.Error != nil {
+			panel.Error = dr.Error.Error()
+		}
+		if len(dr.Frames) > 0 {
+			frames, err := dr.Frames.MarshalArrow()
+			if err != nil {
+				return err
+			}
+			panel.Frames = frames
+		}
+		d.Panels = append(d.Panels, panel)
+	}
+	return nil
+}
+
+// AddError records that the queries of a panel failed
+func (d *SnapshotData) AddError(panelID int64, err error) {
+	d.Panels = append(d.Panels, &SnapshotPanelData{PanelID: panelID, Error: err.Error()})
+}
+
+// Frames returns the frames of each panel
+func (d *SnapshotData) Frames() (map[int64]data.Frames, error) {
+	result := make(map[int64]data.Frames)
+	for _, panel := range d.Panels {
+		if _, ok := result[panel.PanelID]; !ok {
+			result[panel.PanelID] = data.Frames{}
+		}
+		if len(panel.Frames) == 0 {
+			continue
+		}
+		frames, err := data.UnmarshalArrowFrames(panel.Frames)
+		if err != nil {
+			return nil, err
+		}
+		for _, frame := range frames {
+			frame.RefID = panel.RefID
+		}
+		result[panel.PanelID] = append(result[panel.PanelID], frames...)
+	}
+	return result, nil
+}
+
+// ApplyToDashboard sets the captured frames as the snapshot data of the panels of the dashboard, which is what the
+// frontend renders. Panels without captured results are left untouched.
+func (d *SnapshotData) ApplyToDashboard(dashboard *simplejson.Json) error {
+	framesByPanel, err := d.Frames()
+	if err != nil {
+		return err
+	}
+
+	snapshotData := make(map[int64]any, len(framesByPanel))
+	for panelID, frames := range framesByPanel {
+		// the dashboard must only hold plain JSON values
+		raw, err := json.Marshal(frames)
+		if err != nil {
+			return err
+		}
+		var value []any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		if value == nil {
+			value = []any{}
+		}
+		snapshotData[panelID] = value
+	}
+
+	applySnapshotData(dashboard.Get("panels").MustArray(), snapshotData)
+	return nil
+}
+
+func applySnapshotData(panels []any, snapshotData map[int64]any) {
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+
+		// collapsed rows contain their panels
+		if panel.Get("type").MustString() == "row" {
+			applySnapshotData(panel.Get("panels").MustArray(), snapshotData)
+			continue
+		}
+
+		if value, ok := snapshotData[panel.Get("id").MustInt64()]; ok {
+			panel.Set("snapshotData", value)
+		}
+	}
+}
@@ -63,6 +63,7 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 			ExternalDeleteURL:  cmd.ExternalDeleteURL,
 			Dashboard:          simplejson.New(),
 			DashboardEncrypted: cmd.DashboardEncrypted,
+			DataEncrypted:      cmd.DataEncrypted,
 			Expires:            expires,
 			Created:            time.Now(),
 			Updated:            time.Now(),
@@ -48,9 +48,13 @@ func TestIntegrationDashboardSnapshotDBAccess(t *testing.T) {
 		encryptedDashboard, err := secretsService.Encrypt(context.Background(), rawDashboard, secrets.WithoutScope())
 		require.NoError(t, err)
 
+		encryptedData, err := secretsService.Encrypt(context.Background(), []byte(`{"panels":[]}`), secrets.WithoutScope())
+		require.NoError(t, err)
+
 		cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
 			Key:                "hej",
 			DashboardEncrypted: encryptedDashboard,
+			DataEncrypted:      encryptedData,
 			UserID:             1000,
 			OrgID:              1,
 		}
@@ -75,6 +79,10 @@ func TestIntegrationDashboardSnapshotDBAccess(t *testing.T) {
 			require.NoError(t, err)
 
 			assert.Equal(t, "mupp", dashboard.Get("hello").MustString())
+
+			decryptedData, err := secretsService.Decrypt(context.Background(), queryResult.DataEncrypted)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"panels":[]}`, string(decryptedData))
 		})
 
 		t.Run("And the user has the admin role", func(t *testing.T) {
@@ -171,8 +179,10 @@ func TestIntegrationDeleteExpiredSnapshots(t *testing.T) {
 		createTestSnapshot(t, dashStore, "key2", -1200)
 		createTestSnapshot(t, dashStore, "key3", -1200)
 
-		err := dashStore.DeleteExpiredSnapshots(context.Background(), &dashboardsnapshots.DeleteExpiredSnapshotsCommand{})
+		cmd := dashboardsnapshots.DeleteExpiredSnapshotsCommand{}
+		err := dashStore.DeleteExpiredSnapshots(context.Background(), &cmd)
 		require.NoError(t, err)
+		assert.EqualValues(t, 2, cmd.DeletedRows)
 
 		query := dashboardsnapshots.GetDashboardSnapshotsQuery{
 			OrgID:        1,
@@ -184,8 +194,10 @@ func TestIntegrationDeleteExpiredSnapshots(t *testing.T) {
 		assert.Len(t, queryResult, 1)
 		assert.Equal(t, nonExpiredSnapshot.Key, queryResult[0].Key)
 
-		err = dashStore.DeleteExpiredSnapshots(context.Background(), &dashboardsnapshots.DeleteExpiredSnapshotsCommand{})
+		cmd = dashboardsnapshots.DeleteExpiredSnapshotsCommand{}
+		err = dashStore.DeleteExpiredSnapshots(context.Background(), &cmd)
 		require.NoError(t, err)
+		assert.EqualValues(t, 0, cmd.DeletedRows)
 
 		query = dashboardsnapshots.GetDashboardSnapshotsQuery{
 			OrgID:        1,
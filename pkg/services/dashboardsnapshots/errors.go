@@ -5,3 +5,7 @@ import (
 )
 
 var ErrBaseNotFound = errutil.NotFound("dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+
+var ErrInvalidTimeRange = errutil.BadRequest("dashboardsnapshots.invalid-time-range", errutil.WithPublicMessage("Invalid time range"))
+
+var ErrInvalidVariables = errutil.BadRequest("dashboardsnapshots.invalid-variables", errutil.WithPublicMessage("Invalid template variable values"))
@@ -26,6 +26,10 @@ type DashboardSnapshot struct {
 
 	Dashboard          *simplejson.Json
 	DashboardEncrypted []byte
+
+	// Data holds the panel results captured on the server, they are stored encrypted in DataEncrypted
+	Data          *SnapshotData `xorm:"-"`
+	DataEncrypted []byte
 }
 
 // DashboardSnapshotDTO without dashboard map
@@ -63,10 +67,45 @@ type CreateDashboardSnapshotCommand struct {
 	// required:false
 	DeleteKey string `json:"deleteKey"`
 
+	// Run the queries of the panels on the server and store their results as data frames, rather than relying on
+	// the data embedded in the dashboard. The time range of the dashboard is used.
+	// required:false
+	// default: false
+	CaptureData bool `json:"captureData"`
+
 	OrgID  int64 `json:"-"`
 	UserID int64 `json:"-"`
 
-	DashboardEncrypted []byte `json:"-"`
+	DashboardEncrypted []byte        `json:"-"`
+	Data               *SnapshotData `json:"-"`
+	DataEncrypted      []byte        `json:"-"`
+}
+
+// swagger:model
+type CaptureDashboardSnapshotCommand struct {
+	// Snapshot name, defaults to the dashboard title
+	// required:false
+	Name string `json:"name"`
+
+	// When the snapshot should expire in seconds. Default is never to expire.
+	// required:false
+	// default:0
+	Expires int64 `json:"expires"`
+
+	// Start of the time range, e.g. now-6h, an epoch in milliseconds or a date. Defaults to the time range of the dashboard.
+	// required:false
+	From string `json:"from"`
+
+	// End of the time range, e.g. now, an epoch in milliseconds or a date. Defaults to the time range of the dashboard.
+	// required:false
+	To string `json:"to"`
+
+	// Values of the template variables by name. The other variables keep their current value in the dashboard.
+	// required:false
+	Variables map[string][]string `json:"variables"`
+
+	DashboardUID string `json:"-"`
+	OrgID        int64  `json:"-"`
 }
 
 type DeleteDashboardSnapshotCommand struct {
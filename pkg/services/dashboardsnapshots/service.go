@@ -23,6 +23,7 @@ import (
 
 //go:generate mockery --name Service --structname MockService --inpackage --filename service_mock.go
 type Service interface {
+	CaptureDashboardSnapshot(context.Context, *CaptureDashboardSnapshotCommand) (*DashboardSnapshot, error)
 	CreateDashboardSnapshot(context.Context, *CreateDashboardSnapshotCommand) (*DashboardSnapshot, error)
 	DeleteDashboardSnapshot(context.Context, *DeleteDashboardSnapshotCommand) error
 	DeleteExpiredSnapshots(context.Context, *DeleteExpiredSnapshotsCommand) error
@@ -121,6 +122,10 @@ func CreateDashboardSnapshot(c *contextmodel.ReqContext, cfg dashboardsnapshot.S
 
 	result, err := svc.CreateDashboardSnapshot(c.Req.Context(), &cmd)
 	if err != nil {
+		if errors.Is(err, ErrInvalidTimeRange) {
+			c.JsonApiErr(http.StatusBadRequest, "Invalid dashboard time range", err)
+			return
+		}
 		c.JsonApiErr(http.StatusInternalServerError, "Failed to create snapshot", err)
 		return
 	}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardquery"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+)
+
+// defaultMaxDataPoints is used for panels which don't define their max data points, the frontend derives it from
+// the width of the panel which is unknown on the server
+const defaultMaxDataPoints = 1000
+
+// captureData runs the queries of the panels of the dashboard on behalf of the user, with the current values of the
+// template variables, and returns their results. The failure of a panel doesn't fail the capture, it is recorded with
+// the results.
+func (s *ServiceImpl) captureData(ctx context.Context, user identity.Requester, dashboard *simplejson.Json) (*dashboardsnapshots.SnapshotData, error) {
+	from, to, err := dashboardTimeRange(dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := resolveVariables(dashboard)
+	queriesByPanel := groupQueriesByPanelId(dashboard)
+	panelIDs := make([]int64, 0, len(queriesByPanel))
+	for panelID := range queriesByPanel {
+		panelIDs = append(panelIDs, panelID)
+	}
+	sort.Slice(panelIDs, func(i, j int) bool { return panelIDs[i] < panelIDs[j] })
+
+	result := &dashboardsnapshots.SnapshotData{Panels: []*dashboardsnapshots.SnapshotPanelData{}}
+	for _, panelID := range panelIDs {
+		panel := queriesByPanel[panelID]
+		if len(panel.Queries) == 0 {
+			continue
+		}
+
+		intervalMs, maxDataPoints := panelInterval(panel.Panel, from, to)
+		for _, query := range panel.Queries {
+			dashboardquery.InterpolateVariables(query, variables)
+			query.Set("intervalMs", intervalMs)
+			query.Set("maxDataPoints", maxDataPoints)
+		}
+
+		res, err := s.queryService.QueryData(ctx, user, false, dtos.MetricRequest{
+			From:    strconv.FormatInt(from.UnixMilli(), 10),
+			To:      strconv.FormatInt(to.UnixMilli(), 10),
+			Queries: panel.Queries,
+		})
+		if err != nil {
+			s.log.Warn("Failed to capture the data of a snapshot panel", "panelId", panelID, "error", err)
+			result.AddError(panelID, err)
+			continue
+		}
+
+		if err := result.AddResponse(panelID, res); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// groupQueriesByPanelId returns the queries of the panels, the queries without a data source use the data source of
+// their panel
+func groupQueriesByPanelId(dashboard *simplejson.Json) map[int64]*dashboardquery.PanelQueries {
+	result := dashboardquery.GroupQueriesByPanelID(dashboard)
+	for _, panel := range result {
+		panelDatasource, ok := panel.Panel.CheckGet("datasource")
+		if !ok {
+			continue
+		}
+		for _, query := range panel.Queries {
+			if _, ok := query.CheckGet("datasource"); !ok {
+				query.Set("datasource", panelDatasource.Interface())
+			}
+		}
+	}
+	return result
+}
+
+// removePanelQueries removes the queries and the data sources of the panels, snapshots only show the captured data
+func removePanelQueries(panels []any) {
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("type").MustString() == "row" {
+			removePanelQueries(panel.Get("panels").MustArray())
+			continue
+		}
+		panel.Set("targets", []any{})
+		panel.Set("datasource", nil)
+	}
+}
+
+// dashboardTimeRange returns the time range of the dashboard in its timezone
+func dashboardTimeRange(dashboard *simplejson.Json) (time.Time, time.Time, error) {
+	location, err := time.LoadLocation(dashboard.Get("timezone").MustString())
+	if err != nil {
+		location = time.UTC
+	}
+
+	timeRange := gtime.NewTimeRange(dashboard.GetPath("time", "from").MustString("now-6h"), dashboard.GetPath("time", "to").MustString("now"))
+	from, err := timeRange.ParseFrom(gtime.WithLocation(location))
+	if err != nil {
+		return time.Time{}, time.Time{}, dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid time range start: %w", err)
+	}
+	to, err := timeRange.ParseTo(gtime.WithLocation(location))
+	if err != nil {
+		return time.Time{}, time.Time{}, dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid time range end: %w", err)
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, dashboardsnapshots.ErrInvalidTimeRange.Errorf("the time range start must be before its end")
+	}
+
+	return from, to, nil
+}
+
+// panelInterval returns the interval and the max data points of the queries of a panel, honoring its minimum
+// interval
+func panelInterval(panel *simplejson.Json, from time.Time, to time.Time) (int64, int64) {
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64(defaultMaxDataPoints)
+	if maxDataPoints <= 0 {
+		maxDataPoints = defaultMaxDataPoints
+	}
+
+	interval := gtime.RoundInterval(to.Sub(from) / time.Duration(maxDataPoints))
+	if minInterval, err := gtime.ParseInterval(panel.Get("interval").MustString()); err == nil && minInterval > interval {
+		interval = minInterval
+	}
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+
+	return interval.Milliseconds(), maxDataPoints
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestPanelInterval(t *testing.T) {
+	from := time.UnixMilli(0)
+	to := from.Add(time.Hour)
+
+	t.Run("derives the interval from the default max data points", func(t *testing.T) {
+		intervalMs, maxDataPoints := panelInterval(simplejson.New(), from, to)
+		assert.EqualValues(t, defaultMaxDataPoints, maxDataPoints)
+		assert.EqualValues(t, 5000, intervalMs)
+	})
+
+	t.Run("uses the max data points of the panel", func(t *testing.T) {
+		panel := simplejson.NewFromAny(map[string]any{"maxDataPoints": 60})
+		intervalMs, maxDataPoints := panelInterval(panel, from, to)
+		assert.EqualValues(t, 60, maxDataPoints)
+		assert.EqualValues(t, time.Minute.Milliseconds(), intervalMs)
+	})
+
+	t.Run("honors the minimum interval of the panel", func(t *testing.T) {
+		panel := simplejson.NewFromAny(map[string]any{"interval": "5m"})
+		intervalMs, _ := panelInterval(panel, from, to)
+		assert.EqualValues(t, (5 * time.Minute).Milliseconds(), intervalMs)
+	})
+}
+
+func TestGroupQueriesByPanelId(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 1, "datasource": {"uid": "ds1"}, "targets": [{"refId": "A"}, {"refId": "B", "hide": true}]},
+			{"id": 2, "datasource": {"uid": "ds1"}, "targets": [
+				{"refId": "A", "hide": true},
+				{"refId": "B", "datasource": {"uid": "__expr__", "type": "__expr__"}}
+			]},
+			{"id": 3, "type": "row", "collapsed": true, "panels": [{"id": 4, "targets": [{"refId": "A"}]}]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	queries := groupQueriesByPanelId(dashboard)
+	require.Len(t, queries, 3)
+
+	require.Len(t, queries[1].Queries, 1)
+	assert.Equal(t, "ds1", queries[1].Queries[0].Get("datasource").Get("uid").MustString())
+
+	// the hidden query may be used by the expression
+	assert.Len(t, queries[2].Queries, 2)
+
+	require.Len(t, queries[4].Queries, 1)
+	_, ok := queries[4].Queries[0].CheckGet("datasource")
+	assert.False(t, ok)
+
+	// the dashboard is not modified
+	_, ok = dashboard.Get("panels").GetIndex(0).Get("targets").GetIndex(0).CheckGet("datasource")
+	assert.False(t, ok)
+}
@@ -2,27 +2,40 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	dashboardsnapshot "github.com/grafana/grafana/pkg/apis/dashboardsnapshot/v0alpha1"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 type ServiceImpl struct {
 	store            dashboardsnapshots.Store
 	secretsService   secrets.Service
 	dashboardService dashboards.DashboardService
+	queryService     query.Service
+	log              log.Logger
 }
 
 // ServiceImpl implements the dashboardsnapshots Service interface
 var _ dashboardsnapshots.Service = (*ServiceImpl)(nil)
 
-func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service, dashboardService dashboards.DashboardService) *ServiceImpl {
+func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service, dashboardService dashboards.DashboardService, queryService query.Service) *ServiceImpl {
 	s := &ServiceImpl{
 		store:            store,
 		secretsService:   secretsService,
 		dashboardService: dashboardService,
+		queryService:     queryService,
+		log:              log.New("dashboardsnapshots"),
 	}
 
 	return s
@@ -46,9 +59,123 @@ func (s *ServiceImpl) CreateDashboardSnapshot(ctx context.Context, cmd *dashboar
 
 	cmd.DashboardEncrypted = encryptedDashboard
 
+	if cmd.CaptureData && !cmd.External {
+		user, err := identity.GetRequester(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		dashboard, err := simplejson.NewJson(marshalledData)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmd.Data, err = s.captureData(ctx, user, dashboard); err != nil {
+			return nil, err
+		}
+	}
+
+	if cmd.Data != nil {
+		marshalledFrames, err := json.Marshal(cmd.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmd.DataEncrypted, err = s.secretsService.Encrypt(ctx, marshalledFrames, secrets.WithoutScope()); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.store.CreateDashboardSnapshot(ctx, cmd)
 }
 
+// CaptureDashboardSnapshot creates a snapshot of a stored dashboard. The queries of its panels run on the server
+// on behalf of the signed in user, so the data sources they can't query have no data in the snapshot.
+func (s *ServiceImpl) CaptureDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.CaptureDashboardSnapshotCommand) (*dashboardsnapshots.DashboardSnapshot, error) {
+	user, err := identity.GetRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: cmd.DashboardUID, OrgID: cmd.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	// the dashboard is modified to hold the snapshot state
+	marshalledDashboard, err := dash.Data.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	dashboard, err := simplejson.NewJson(marshalledDashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.From != "" || cmd.To != "" {
+		if cmd.From == "" || cmd.To == "" {
+			return nil, dashboardsnapshots.ErrInvalidTimeRange.Errorf("both the start and the end of the time range are required")
+		}
+		dashboard.Set("time", map[string]any{"from": cmd.From, "to": cmd.To})
+	}
+	from, to, err := dashboardTimeRange(dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := selectVariableValues(dashboard, cmd.Variables); err != nil {
+		return nil, err
+	}
+
+	data, err := s.captureData(ctx, user, dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	// the snapshot shows the captured time range and data, the queries are not part of it
+	dashboard.Set("time", map[string]any{
+		"from": from.UTC().Format(time.RFC3339Nano),
+		"to":   to.UTC().Format(time.RFC3339Nano),
+		"raw":  map[string]any{"from": dashboard.GetPath("time", "from").Interface(), "to": dashboard.GetPath("time", "to").Interface()},
+	})
+	dashboard.Set("snapshot", map[string]any{
+		"timestamp":   time.Now().UTC().Format(time.RFC3339Nano),
+		"originalUrl": fmt.Sprintf("/d/%s", dash.UID),
+	})
+	removePanelQueries(dashboard.Get("panels").MustArray())
+	freezeVariables(dashboard)
+
+	dashboardMap, err := dashboard.Map()
+	if err != nil {
+		return nil, err
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = dash.Title
+	}
+
+	createCmd := &dashboardsnapshots.CreateDashboardSnapshotCommand{
+		DashboardCreateCommand: dashboardsnapshot.DashboardCreateCommand{
+			Name:      name,
+			Expires:   cmd.Expires,
+			Dashboard: &common.Unstructured{Object: dashboardMap},
+		},
+		OrgID: cmd.OrgID,
+		Data:  data,
+	}
+	createCmd.UserID, _ = identity.UserIdentifier(user.GetID())
+
+	if createCmd.Key, err = util.GetRandomString(32); err != nil {
+		return nil, err
+	}
+	if createCmd.DeleteKey, err = util.GetRandomString(32); err != nil {
+		return nil, err
+	}
+
+	return s.CreateDashboardSnapshot(ctx, createCmd)
+}
+
 func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotQuery) (*dashboardsnapshots.DashboardSnapshot, error) {
 	queryResult, err := s.store.GetDashboardSnapshot(ctx, query)
 	if err != nil {
@@ -69,6 +196,26 @@ func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboard
 		queryResult.Dashboard = dashboard
 	}
 
+	if queryResult.DataEncrypted != nil {
+		decryptedData, err := s.secretsService.Decrypt(ctx, queryResult.DataEncrypted)
+		if err != nil {
+			return nil, err
+		}
+
+		data := &dashboardsnapshots.SnapshotData{}
+		if err := json.Unmarshal(decryptedData, data); err != nil {
+			return nil, err
+		}
+
+		if queryResult.Dashboard != nil {
+			if err := data.ApplyToDashboard(queryResult.Dashboard); err != nil {
+				return nil, fmt.Errorf("failed to apply the snapshot data: %w", err)
+			}
+		}
+
+		queryResult.Data = data
+	}
+
 	return queryResult, err
 }
 
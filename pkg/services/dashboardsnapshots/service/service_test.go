@@ -6,10 +6,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/api/dtos"
 	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	dashboardsnapshot "github.com/grafana/grafana/pkg/apis/dashboardsnapshot/v0alpha1"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/authz/zanzana"
@@ -22,10 +28,12 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/folder/folderimpl"
 	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/secrets/database"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
 	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
 )
@@ -40,7 +48,7 @@ func TestDashboardSnapshotsService(t *testing.T) {
 	dsStore := dashsnapdb.ProvideStore(sqlStore, cfg)
 	fakeDashboardService := &dashboards.FakeDashboardService{}
 	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
-	s := ProvideService(dsStore, secretsService, fakeDashboardService)
+	s := ProvideService(dsStore, secretsService, fakeDashboardService, &fakeQueryService{})
 
 	origSecret := cfg.SecretKey
 	cfg.SecretKey = "dashboard_snapshot_service_test"
@@ -91,6 +99,208 @@ func TestDashboardSnapshotsService(t *testing.T) {
 
 		require.Equal(t, rawDashboard, decrypted)
 	})
+
+	t.Run("create dashboard snapshot should capture the data of the panels", func(t *testing.T) {
+		queryService := &fakeQueryService{}
+		s := ProvideService(dsStore, secretsService, fakeDashboardService, queryService)
+		ctx := identity.WithRequester(context.Background(), &user.SignedInUser{OrgID: 1})
+
+		dashboard := &common.Unstructured{}
+		err := json.Unmarshal([]byte(`{
+			"time": {"from": "1700000000000", "to": "1700003600000"},
+			"panels": [
+				{"id": 1, "datasource": {"uid": "ds1"}, "targets": [{"refId": "A"}, {"refId": "B", "hide": true}]},
+				{"id": 2, "type": "row", "collapsed": true, "panels": [
+					{"id": 3, "targets": [{"refId": "A", "datasource": {"uid": "ds2"}}]}
+				]}
+			]
+		}`), dashboard)
+		require.NoError(t, err)
+
+		cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key:         "captured",
+			DeleteKey:   "captured-delete",
+			CaptureData: true,
+			DashboardCreateCommand: dashboardsnapshot.DashboardCreateCommand{
+				Dashboard: dashboard,
+			},
+		}
+
+		result, err := s.CreateDashboardSnapshot(ctx, &cmd)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.DataEncrypted)
+
+		require.Len(t, queryService.requests, 2)
+		require.Equal(t, "1700000000000", queryService.requests[0].From)
+		require.Equal(t, "1700003600000", queryService.requests[0].To)
+		require.Len(t, queryService.requests[0].Queries, 1)
+		require.Equal(t, "ds1", queryService.requests[0].Queries[0].Get("datasource").Get("uid").MustString())
+		require.Equal(t, "ds2", queryService.requests[1].Queries[0].Get("datasource").Get("uid").MustString())
+
+		queryResult, err := s.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "captured"})
+		require.NoError(t, err)
+		require.NotNil(t, queryResult.Data)
+		require.Len(t, queryResult.Data.Panels, 2)
+
+		panel := queryResult.Dashboard.Get("panels").GetIndex(0)
+		require.Len(t, panel.Get("snapshotData").MustArray(), 1)
+		require.Equal(t, "A", panel.Get("snapshotData").GetIndex(0).GetPath("schema", "refId").MustString())
+
+		nested := queryResult.Dashboard.Get("panels").GetIndex(1).Get("panels").GetIndex(0)
+		require.Len(t, nested.Get("snapshotData").MustArray(), 1)
+	})
+
+	t.Run("create dashboard snapshot should reject an invalid time range when capturing data", func(t *testing.T) {
+		s := ProvideService(dsStore, secretsService, fakeDashboardService, &fakeQueryService{})
+		ctx := identity.WithRequester(context.Background(), &user.SignedInUser{OrgID: 1})
+
+		dashboard := &common.Unstructured{}
+		err := json.Unmarshal([]byte(`{"time": {"from": "now", "to": "now-1h"}, "panels": []}`), dashboard)
+		require.NoError(t, err)
+
+		cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key:         "invalid",
+			DeleteKey:   "invalid-delete",
+			CaptureData: true,
+			DashboardCreateCommand: dashboardsnapshot.DashboardCreateCommand{
+				Dashboard: dashboard,
+			},
+		}
+
+		_, err = s.CreateDashboardSnapshot(ctx, &cmd)
+		require.ErrorIs(t, err, dashboardsnapshots.ErrInvalidTimeRange)
+	})
+}
+
+func TestCaptureDashboardSnapshot(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	dsStore := dashsnapdb.ProvideStore(sqlStore, cfg)
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := identity.WithRequester(context.Background(), &user.SignedInUser{UserID: 7, OrgID: 1})
+
+	dashboardData, err := simplejson.NewJson([]byte(`{
+		"id": 1,
+		"uid": "abc",
+		"title": "Incident",
+		"time": {"from": "now-6h", "to": "now"},
+		"templating": {"list": [
+			{"name": "server", "type": "query", "multi": true, "current": {"value": ["a"]}, "query": "label_values(server)", "datasource": {"uid": "ds1"}},
+			{"name": "env", "type": "custom", "current": {"value": "prod"}}
+		]},
+		"panels": [
+			{"id": 1, "datasource": {"uid": "ds1"}, "targets": [{"refId": "A", "expr": "up{server=~\"${server:regex}\", env=\"$env\"}"}]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	multiValueData, err := simplejson.NewJson([]byte(`{
+		"uid": "multi",
+		"title": "Servers",
+		"templating": {"list": [
+			{"name": "server", "type": "query", "multi": true, "current": {"value": ["a.b", "c"]}}
+		]},
+		"panels": [
+			{"id": 1, "datasource": {"type": "prometheus", "uid": "prom"}, "targets": [{"refId": "A", "expr": "up{server=~\"$server\"}"}]},
+			{"id": 2, "datasource": {"type": "mysql", "uid": "sql"}, "targets": [{"refId": "A", "rawSql": "SELECT * FROM servers WHERE name IN ($server)"}]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	setup := func(t *testing.T) (*ServiceImpl, *fakeQueryService) {
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "abc", OrgID: 1}).
+			Return(&dashboards.Dashboard{UID: "abc", OrgID: 1, Title: "Incident", Data: dashboardData}, nil).Maybe()
+		dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "multi", OrgID: 1}).
+			Return(&dashboards.Dashboard{UID: "multi", OrgID: 1, Title: "Servers", Data: multiValueData}, nil).Maybe()
+		dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
+		queryService := &fakeQueryService{}
+		return ProvideService(dsStore, secretsService, dashboardService, queryService), queryService
+	}
+
+	t.Run("captures the panels with the time range and the variable values", func(t *testing.T) {
+		s, queryService := setup(t)
+
+		result, err := s.CaptureDashboardSnapshot(ctx, &dashboardsnapshots.CaptureDashboardSnapshotCommand{
+			DashboardUID: "abc",
+			OrgID:        1,
+			From:         "1700000000000",
+			To:           "1700003600000",
+			Variables:    map[string][]string{"server": {"a", "b.c"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Incident", result.Name)
+		require.EqualValues(t, 7, result.UserID)
+		require.NotEmpty(t, result.Key)
+		require.NotEmpty(t, result.DeleteKey)
+
+		require.Len(t, queryService.requests, 1)
+		require.Equal(t, "1700000000000", queryService.requests[0].From)
+		require.Equal(t, `up{server=~"(a|b\.c)", env="prod"}`, queryService.requests[0].Queries[0].Get("expr").MustString())
+
+		snapshot, err := s.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: result.Key})
+		require.NoError(t, err)
+
+		panel := snapshot.Dashboard.Get("panels").GetIndex(0)
+		require.Empty(t, panel.Get("targets").MustArray())
+		require.Len(t, panel.Get("snapshotData").MustArray(), 1)
+
+		variable := snapshot.Dashboard.GetPath("templating", "list").GetIndex(0)
+		require.Equal(t, "custom", variable.Get("type").MustString())
+		require.Equal(t, []string{"a", "b.c"}, variable.GetPath("current", "value").MustStringArray())
+
+		require.Equal(t, "2023-11-14T22:13:20Z", snapshot.Dashboard.GetPath("time", "from").MustString())
+		require.Equal(t, "/d/abc", snapshot.Dashboard.GetPath("snapshot", "originalUrl").MustString())
+
+		// the stored dashboard is not modified
+		require.Equal(t, "now-6h", dashboardData.GetPath("time", "from").MustString())
+	})
+
+	t.Run("formats multi-value variables like the data sources of the panels", func(t *testing.T) {
+		s, queryService := setup(t)
+
+		_, err := s.CaptureDashboardSnapshot(ctx, &dashboardsnapshots.CaptureDashboardSnapshotCommand{
+			DashboardUID: "multi",
+			OrgID:        1,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, queryService.requests, 2)
+		require.Equal(t, `up{server=~"(a\\.b|c)"}`, queryService.requests[0].Queries[0].Get("expr").MustString())
+		require.Equal(t, "SELECT * FROM servers WHERE name IN ('a.b','c')", queryService.requests[1].Queries[0].Get("rawSql").MustString())
+	})
+
+	t.Run("rejects unknown variables", func(t *testing.T) {
+		s, _ := setup(t)
+
+		_, err := s.CaptureDashboardSnapshot(ctx, &dashboardsnapshots.CaptureDashboardSnapshotCommand{
+			DashboardUID: "abc",
+			OrgID:        1,
+			Variables:    map[string][]string{"unknown": {"a"}},
+		})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrInvalidVariables)
+	})
+
+	t.Run("rejects a partial time range", func(t *testing.T) {
+		s, _ := setup(t)
+
+		_, err := s.CaptureDashboardSnapshot(ctx, &dashboardsnapshots.CaptureDashboardSnapshotCommand{
+			DashboardUID: "abc",
+			OrgID:        1,
+			From:         "now-1h",
+		})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrInvalidTimeRange)
+	})
+
+	t.Run("returns not found for an unknown dashboard", func(t *testing.T) {
+		s, _ := setup(t)
+
+		_, err := s.CaptureDashboardSnapshot(ctx, &dashboardsnapshots.CaptureDashboardSnapshotCommand{
+			DashboardUID: "unknown",
+			OrgID:        1,
+		})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+	})
 }
 
 func TestValidateDashboardExists(t *testing.T) {
@@ -102,7 +312,7 @@ func TestValidateDashboardExists(t *testing.T) {
 	require.NoError(t, err)
 	dashSvc, err := dashsvc.ProvideDashboardServiceImpl(cfg, dashboardStore, folderimpl.ProvideDashboardFolderStore(sqlStore), nil, nil, nil, acmock.New(), foldertest.NewFakeService(), folder.NewFakeStore(), nil, zanzana.NewNoopClient())
 	require.NoError(t, err)
-	s := ProvideService(dsStore, secretsService, dashSvc)
+	s := ProvideService(dsStore, secretsService, dashSvc, &fakeQueryService{})
 	ctx := context.Background()
 
 	t.Run("returns false when dashboard does not exist", func(t *testing.T) {
@@ -120,6 +330,24 @@ func TestValidateDashboardExists(t *testing.T) {
 	})
 }
 
+type fakeQueryService struct {
+	query.Service
+	requests []dtos.MetricRequest
+}
+
+func (f *fakeQueryService) QueryData(_ context.Context, _ identity.Requester, _ bool, req dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	f.requests = append(f.requests, req)
+
+	res := backend.NewQueryDataResponse()
+	for _, q := range req.Queries {
+		refID := q.Get("refId").MustString()
+		res.Responses[refID] = backend.DataResponse{
+			Frames: data.Frames{data.NewFrame("", data.NewField("value", nil, []float64{1, 2, 3}))},
+		}
+	}
+	return res, nil
+}
+
 func createDashboard(store db.DB) error {
 	return store.WithDbSession(context.Background(), func(sess *db.Session) error {
 		dashboard := &dashboards.Dashboard{
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardquery"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+)
+
+// selectVariableValues sets the current value of the template variables of the dashboard
+func selectVariableValues(dashboard *simplejson.Json, selected map[string][]string) error {
+	variables := make(map[string]*simplejson.Json)
+	for _, obj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		variables[v.Get("name").MustString()] = v
+	}
+
+	for name, values := range selected {
+		v, ok := variables[name]
+		if !ok || name == "" {
+			return dashboardsnapshots.ErrInvalidVariables.Errorf("unknown template variable %s", name)
+		}
+		if v.Get("type").MustString() == "adhoc" {
+			return dashboardsnapshots.ErrInvalidVariables.Errorf("ad hoc filter %s can not be selected", name)
+		}
+		if len(values) == 0 {
+			return dashboardsnapshots.ErrInvalidVariables.Errorf("template variable %s has no value", name)
+		}
+
+		current := map[string]any{"text": strings.Join(values, " + "), "value": values[0]}
+		if len(values) > 1 || v.Get("multi").MustBool() {
+			// the dashboard must only hold plain JSON values
+			multi := make([]any, len(values))
+			for i, value := range values {
+				multi[i] = value
+			}
+			current["value"] = multi
+		}
+		v.Set("current", current)
+	}
+
+	return nil
+}
+
+// resolveVariables returns the values of the template variables of the dashboard
+func resolveVariables(dashboard *simplejson.Json) map[string]dashboardquery.Variable {
+	values := make(map[string]dashboardquery.Variable)
+	for _, obj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		name := v.Get("name").MustString()
+		if name == "" || v.Get("type").MustString() == "adhoc" {
+			continue
+		}
+
+		variable := dashboardquery.Variable{
+			Values: dashboardquery.StringOrArray(v.GetPath("current", "value")),
+			Multi:  v.Get("multi").MustBool() || v.Get("includeAll").MustBool(),
+		}
+		if slices.Contains(variable.Values, dashboardquery.AllValue) {
+			if custom := v.Get("allValue").MustString(); custom != "" {
+				variable.Values = []string{custom}
+				variable.Raw = true
+			} else {
+				variable.Values = variableOptions(v)
+			}
+		}
+		values[name] = variable
+	}
+	return values
+}
+
+// freezeVariables turns the template variables into custom variables holding their current value, so the
+// snapshot neither shows their queries nor lets viewers select values which weren't captured
+func freezeVariables(dashboard *simplejson.Json) {
+	for _, obj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		if v.Get("type").MustString() == "adhoc" {
+			continue
+		}
+
+		current := dashboardquery.StringOrArray(v.GetPath("current", "value"))
+		options := make([]any, 0, len(current))
+		for _, value := range current {
+			options = append(options, map[string]any{"text": value, "value": value, "selected": true})
+		}
+		v.Set("type", "custom")
+		v.Set("query", strings.Join(current, ","))
+		v.Set("options", options)
+		v.Del("definition")
+		v.Del("datasource")
+		v.Del("regex")
+	}
+}
+
+func variableOptions(v *simplejson.Json) []string {
+	var options []string
+	for _, opt := range v.Get("options").MustArray() {
+		for _, value := range dashboardquery.StringOrArray(simplejson.NewFromAny(opt).Get("value")) {
+			if value != dashboardquery.AllValue {
+				options = append(options, value)
+			}
+		}
+	}
+	return options
+}
@@ -5,12 +5,18 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/otlp"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/prometheus"
 	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
 )
 
 type Converter struct {
-	telegrafConverterWide         *telegraf.Converter
-	telegrafConverterLabelsColumn *telegraf.Converter
+	telegrafConverterWide           *telegraf.Converter
+	telegrafConverterLabelsColumn   *telegraf.Converter
+	prometheusConverterWide         *prometheus.Converter
+	prometheusConverterLabelsColumn *prometheus.Converter
+	otlpConverterWide               *otlp.Converter
+	otlpConverterLabelsColumn       *otlp.Converter
 }
 
 func NewConverter() *Converter {
@@ -22,11 +28,16 @@ func NewConverter() *Converter {
 			telegraf.WithUseLabelsColumn(true),
 			telegraf.WithFloat64Numbers(true),
 		),
+		prometheusConverterWide:         prometheus.NewConverter(),
+		prometheusConverterLabelsColumn: prometheus.NewConverter(prometheus.WithUseLabelsColumn(true)),
+		otlpConverterWide:               otlp.NewConverter(),
+		otlpConverterLabelsColumn:       otlp.NewConverter(otlp.WithUseLabelsColumn(true)),
 	}
 }
 
 var ErrUnsupportedFrameFormat = errors.New("unsupported frame format")
 
+// Convert Influx line protocol.
 func (c *Converter) Convert(data []byte, frameFormat string) ([]telemetry.FrameWrapper, error) {
 	var converter telemetry.Converter
 	switch frameFormat {
@@ -37,7 +48,38 @@ func (c *Converter) Convert(data []byte, frameFormat string) ([]telemetry.FrameW
 	default:
 		return nil, ErrUnsupportedFrameFormat
 	}
+	return convert(converter, data)
+}
+
+// ConvertPrometheusRemoteWrite converts a snappy compressed Prometheus remote-write request.
+func (c *Converter) ConvertPrometheusRemoteWrite(data []byte, frameFormat string) ([]telemetry.FrameWrapper, error) {
+	var converter telemetry.Converter
+	switch frameFormat {
+	case "wide":
+		converter = c.prometheusConverterWide
+	case "labels_column":
+		converter = c.prometheusConverterLabelsColumn
+	default:
+		return nil, ErrUnsupportedFrameFormat
+	}
+	return convert(converter, data)
+}
+
+// ConvertOTLPMetrics converts an OTLP/HTTP metrics export request in protobuf or JSON encoding.
+func (c *Converter) ConvertOTLPMetrics(data []byte, frameFormat string) ([]telemetry.FrameWrapper, error) {
+	var converter telemetry.Converter
+	switch frameFormat {
+	case "wide":
+		converter = c.otlpConverterWide
+	case "labels_column":
+		converter = c.otlpConverterLabelsColumn
+	default:
+		return nil, ErrUnsupportedFrameFormat
+	}
+	return convert(converter, data)
+}
 
+func convert(converter telemetry.Converter, data []byte) ([]telemetry.FrameWrapper, error) {
 	metricFrames, err := converter.Convert(data)
 	if err != nil {
 		return nil, fmt.Errorf("error converting metrics: %w", err)
@@ -155,21 +155,55 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	}
 
 	if redisClient != nil {
+		var frameHistory managedstream.FrameHistory
+		if g.Cfg.LiveHistoryMaxFrames > 0 {
+			frameHistory = managedstream.NewRedisFrameHistory(redisClient, g.keyPrefix, g.Cfg.LiveHistoryMaxFrames, g.Cfg.LiveHistoryMaxAge)
+		}
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
 			managedstream.NewRedisFrameCache(redisClient, g.keyPrefix),
+			frameHistory,
 		)
 	} else {
+		var frameHistory managedstream.FrameHistory
+		if g.Cfg.LiveHistoryMaxFrames > 0 {
+			frameHistory = managedstream.NewMemoryFrameHistory(g.Cfg.LiveHistoryMaxFrames, g.Cfg.LiveHistoryMaxAge)
+		}
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
 			managedstream.NewMemoryFrameCache(),
+			frameHistory,
 		)
 	}
 
 	g.ManagedStreamRunner = managedStreamRunner
 
+	provisionedStorage := pipeline.NewProvisionedStorage(&pipeline.FileStorage{
+		DataPath:       cfg.DataPath,
+		SecretsService: secretsService,
+	})
+	g.pipelineStorage = provisionedStorage
+	g.provisionedStorage = provisionedStorage
+
+	g.ruleBuilder = &pipeline.StorageRuleBuilder{
+		Node:                 node,
+		ManagedStream:        g.ManagedStreamRunner,
+		FrameStorage:         pipeline.NewFrameStorage(),
+		DownsampleStorage:    pipeline.NewDownsampleStorage(),
+		Storage:              g.pipelineStorage,
+		ChannelHandlerGetter: g,
+		SecretsService:       secretsService,
+	}
+
+	if g.Cfg.LivePipelineEnabled {
+		g.Pipeline, err = pipeline.New(pipeline.NewCacheSegmentedTree(g.ruleBuilder))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	g.contextGetter = liveplugin.NewContextGetter(g.PluginContextProvider, g.DataSourceCache)
 	pipelinedChannelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, g.Pipeline)
 	numLocalSubscribersGetter := liveplugin.NewNumLocalSubscribersGetter(node)
@@ -410,6 +444,8 @@ type GrafanaLive struct {
 	ManagedStreamRunner *managedstream.Runner
 	Pipeline            *pipeline.Pipeline
 	pipelineStorage     pipeline.Storage
+	provisionedStorage  *pipeline.ProvisionedStorage
+	ruleBuilder         *pipeline.StorageRuleBuilder
 
 	contextGetter    *liveplugin.ContextGetter
 	runStreamManager *runstream.Manager
@@ -837,6 +873,18 @@ func publishStatusToHTTPError(status backend.PublishStreamStatus) (int, string)
 	}
 }
 
+// SetProvisionedChannelRules replaces the channel rules provisioned from files, the
+// pipeline picks them up on its next rule cache refresh.
+func (g *GrafanaLive) SetProvisionedChannelRules(rules []pipeline.ChannelRule) {
+	g.provisionedStorage.SetProvisionedChannelRules(rules)
+}
+
+// ValidateChannelRule checks the channel rule can be built by the pipeline, so a provisioned
+// rule with a broken configuration doesn't break the other rules of its org.
+func (g *GrafanaLive) ValidateChannelRule(ctx context.Context, rule pipeline.ChannelRule) error {
+	return g.ruleBuilder.ValidateRule(ctx, rule.OrgId, rule)
+}
+
 // GetChannelHandler gives thread-safe access to the channel.
 func (g *GrafanaLive) GetChannelHandler(ctx context.Context, user identity.Requester, channel string) (model.ChannelHandler, live.Channel, error) {
 	// Parse the identifier ${scope}/${namespace}/${path}
@@ -1058,6 +1106,41 @@ func (g *GrafanaLive) HandleInfoHTTP(ctx *contextmodel.ReqContext) response.Resp
 	})
 }
 
+type channelHistoryResponse struct {
+	Frames []json.RawMessage `json:"frames"`
+}
+
+// HandleHistoryHTTP returns recent frames of a managed stream channel. The optional
+// since query parameter (epoch milliseconds) only returns frames pushed after it.
+func (g *GrafanaLive) HandleHistoryHTTP(c *contextmodel.ReqContext) response.Response {
+	channel := web.Params(c.Req)["*"]
+	addr, err := live.ParseChannel(channel)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid channel", err)
+	}
+	if addr.Scope != live.ScopeStream {
+		return response.Error(http.StatusNotFound, "History is not supported for this channel", nil)
+	}
+	var since time.Time
+	if sinceParam := c.Query("since"); sinceParam != "" {
+		sinceMs, err := strconv.ParseInt(sinceParam, 10, 64)
+		if err != nil {
+			return response.Error(http.StatusBadRequest, "Invalid since parameter", err)
+		}
+		since = time.UnixMilli(sinceMs)
+	}
+	frames, err := g.ManagedStreamRunner.GetChannelHistory(c.Req.Context(), c.SignedInUser.GetOrgID(), channel, since)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), err)
+	}
+	if frames == nil {
+		frames = []json.RawMessage{}
+	}
+	return response.JSONStreaming(http.StatusOK, channelHistoryResponse{
+		Frames: frames,
+	})
+}
+
 // HandleChannelRulesListHTTP ...
 func (g *GrafanaLive) HandleChannelRulesListHTTP(c *contextmodel.ReqContext) response.Response {
 	result, err := g.pipelineStorage.ListChannelRules(c.Req.Context(), c.SignedInUser.GetOrgID())
@@ -1077,6 +1160,8 @@ type ConvertDryRunRequest struct {
 
 type ConvertDryRunResponse struct {
 	ChannelFrames []*pipeline.ChannelFrame `json:"channelFrames"`
+	// ProcessedFrames are the channel frames after the frame processors of their channel rules.
+	ProcessedFrames []*pipeline.ChannelFrame `json:"processedFrames"`
 }
 
 type DryRunRuleStorage struct {
@@ -1159,8 +1244,18 @@ func (g *GrafanaLive) HandlePipelineConvertTestHTTP(c *contextmodel.ReqContext)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Error converting data", err)
 	}
+	// Processors modify frames in place, so process frames of a separate conversion.
+	framesToProcess, err := pipe.DataToChannelFrames(c.Req.Context(), *rule, c.SignedInUser.GetOrgID(), req.Channel, []byte(req.Data))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Error converting data", err)
+	}
+	processedFrames, err := pipe.ApplyFrameProcessors(c.Req.Context(), c.SignedInUser.GetOrgID(), req.Channel, framesToProcess)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Error processing frames", err)
+	}
 	return response.JSON(http.StatusOK, ConvertDryRunResponse{
-		ChannelFrames: channelFrames,
+		ChannelFrames:   channelFrames,
+		ProcessedFrames: processedFrames,
 	})
 }
 
@@ -1176,6 +1271,9 @@ func (g *GrafanaLive) HandleChannelRulesPostHTTP(c *contextmodel.ReqContext) res
 		return response.Error(http.StatusBadRequest, "Error decoding channel rule", err)
 	}
 	rule, err := g.pipelineStorage.CreateChannelRule(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
+	if errors.Is(err, pipeline.ErrProvisionedChannelRule) {
+		return response.Error(http.StatusForbidden, "Provisioned channel rule can't be changed", err)
+	}
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to create channel rule", err)
 	}
@@ -1199,6 +1297,9 @@ func (g *GrafanaLive) HandleChannelRulesPutHTTP(c *contextmodel.ReqContext) resp
 		return response.Error(http.StatusBadRequest, "Rule pattern required", nil)
 	}
 	rule, err := g.pipelineStorage.UpdateChannelRule(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
+	if errors.Is(err, pipeline.ErrProvisionedChannelRule) {
+		return response.Error(http.StatusForbidden, "Provisioned channel rule can't be changed", err)
+	}
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to update channel rule", err)
 	}
@@ -1222,6 +1323,9 @@ func (g *GrafanaLive) HandleChannelRulesDeleteHTTP(c *contextmodel.ReqContext) r
 		return response.Error(http.StatusBadRequest, "Rule pattern required", nil)
 	}
 	err = g.pipelineStorage.DeleteChannelRule(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
+	if errors.Is(err, pipeline.ErrProvisionedChannelRule) {
+		return response.Error(http.StatusForbidden, "Provisioned channel rule can't be changed", err)
+	}
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to delete channel rule", err)
 	}
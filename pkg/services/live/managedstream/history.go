@@ -0,0 +1,62 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// FrameHistory keeps recent frames of managed stream channels, so that late subscribers
+// get more than the last frame. History is bounded by number of frames and by frame age.
+type FrameHistory interface {
+	// Add appends full JSON frame pushed at time t to the history of a channel in org.
+	Add(ctx context.Context, orgID int64, channel string, t time.Time, frameJSON json.RawMessage) error
+	// Get returns frames of a channel in org pushed after since, oldest first.
+	Get(ctx context.Context, orgID int64, channel string, since time.Time) ([]json.RawMessage, error)
+}
+
+// mergeHistoryFrames merges frames into a single frame with the schema of the
+// last frame. Frames with another schema (pushed before a schema change) are skipped.
+func mergeHistoryFrames(frames []json.RawMessage) (json.RawMessage, error) {
+	if len(frames) == 0 {
+		return nil, nil
+	}
+	decoded := make([]*data.Frame, 0, len(frames))
+	for _, frameJSON := range frames {
+		var frame data.Frame
+		if err := json.Unmarshal(frameJSON, &frame); err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, &frame)
+	}
+
+	last := decoded[len(decoded)-1]
+	merged := last.EmptyCopy()
+	for _, frame := range decoded {
+		if !sameFrameFields(merged, frame) {
+			continue
+		}
+		rows, err := frame.RowLen()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < rows; i++ {
+			merged.AppendRow(frame.RowCopy(i)...)
+		}
+	}
+	return data.FrameToJSON(merged, data.IncludeAll)
+}
+
+func sameFrameFields(a, b *data.Frame) bool {
+	if len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type() != b.Fields[i].Type() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// MemoryFrameHistory keeps a ring buffer of recent frames per channel in memory.
+type MemoryFrameHistory struct {
+	mu        sync.RWMutex
+	maxFrames int
+	maxAge    time.Duration
+	rings     map[int64]map[string]*frameRing
+	// lastSweep is the time of the last removal of idle channels.
+	lastSweep time.Time
+}
+
+// NewMemoryFrameHistory ...
+func NewMemoryFrameHistory(maxFrames int, maxAge time.Duration) *MemoryFrameHistory {
+	return &MemoryFrameHistory{
+		maxFrames: maxFrames,
+		maxAge:    maxAge,
+		rings:     map[int64]map[string]*frameRing{},
+	}
+}
+
+type historyFrame struct {
+	time  time.Time
+	frame json.RawMessage
+}
+
+// frameRing is a fixed size ring buffer, start points to the oldest frame.
+type frameRing struct {
+	frames []historyFrame
+	start  int
+	size   int
+}
+
+func (r *frameRing) at(i int) historyFrame {
+	return r.frames[(r.start+i)%len(r.frames)]
+}
+
+func (r *frameRing) push(f historyFrame) {
+	if r.size < len(r.frames) {
+		r.frames[(r.start+r.size)%len(r.frames)] = f
+		r.size++
+		return
+	}
+	r.frames[r.start] = f
+	r.start = (r.start + 1) % len(r.frames)
+}
+
+// dropBefore removes frames older than t from the beginning of the ring.
+func (r *frameRing) dropBefore(t time.Time) {
+	for r.size > 0 && r.at(0).time.Before(t) {
+		r.frames[r.start] = historyFrame{}
+		r.start = (r.start + 1) % len(r.frames)
+		r.size--
+	}
+}
+
+func (h *MemoryFrameHistory) Add(_ context.Context, orgID int64, channel string, t time.Time, frameJSON json.RawMessage) error {
+	if h.maxFrames <= 0 {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.rings[orgID]; !ok {
+		h.rings[orgID] = map[string]*frameRing{}
+	}
+	ring, ok := h.rings[orgID][channel]
+	if !ok {
+		ring = &frameRing{frames: make([]historyFrame, h.maxFrames)}
+		h.rings[orgID][channel] = ring
+	}
+	ring.push(historyFrame{time: t, frame: frameJSON})
+	ring.dropBefore(t.Add(-h.maxAge))
+	if t.Sub(h.lastSweep) > h.maxAge {
+		h.removeIdleChannels(t.Add(-h.maxAge))
+		h.lastSweep = t
+	}
+	return nil
+}
+
+// removeIdleChannels removes channels which got no frames since minTime, otherwise
+// every channel ever pushed to would stay in memory. Must be called with the lock held.
+func (h *MemoryFrameHistory) removeIdleChannels(minTime time.Time) {
+	for orgID, channels := range h.rings {
+		for channel, ring := range channels {
+			ring.dropBefore(minTime)
+			if ring.size == 0 {
+				delete(channels, channel)
+			}
+		}
+		if len(channels) == 0 {
+			delete(h.rings, orgID)
+		}
+	}
+}
+
+func (h *MemoryFrameHistory) Get(_ context.Context, orgID int64, channel string, since time.Time) ([]json.RawMessage, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	ring, ok := h.rings[orgID][channel]
+	if !ok {
+		return nil, nil
+	}
+	minTime := time.Now().Add(-h.maxAge)
+	frames := make([]json.RawMessage, 0, ring.size)
+	for i := 0; i < ring.size; i++ {
+		f := ring.at(i)
+		if f.time.Before(minTime) || !f.time.After(since) {
+			continue
+		}
+		frames = append(frames, f.frame)
+	}
+	return frames, nil
+}
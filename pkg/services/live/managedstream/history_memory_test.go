@@ -0,0 +1,98 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func testFrameHistory(t *testing.T, h FrameHistory, maxFrames int) {
+	ctx := context.Background()
+	now := time.Now()
+
+	frames, err := h.Get(ctx, 1, "test", time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, frames)
+
+	// Add more frames than history can keep.
+	for i := 0; i < maxFrames+2; i++ {
+		frameJSON := json.RawMessage(fmt.Sprintf(`{"index":%d}`, i))
+		err := h.Add(ctx, 1, "test", now.Add(time.Duration(i-maxFrames-2)*time.Second), frameJSON)
+		require.NoError(t, err)
+	}
+
+	// Only the last frames are kept, oldest first.
+	frames, err = h.Get(ctx, 1, "test", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, frames, maxFrames)
+	require.JSONEq(t, `{"index":2}`, string(frames[0]))
+	require.JSONEq(t, fmt.Sprintf(`{"index":%d}`, maxFrames+1), string(frames[maxFrames-1]))
+
+	// Only frames pushed after since.
+	frames, err = h.Get(ctx, 1, "test", now.Add(-2*time.Second))
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+
+	// Other org and channel are not affected.
+	frames, err = h.Get(ctx, 2, "test", time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, frames)
+	frames, err = h.Get(ctx, 1, "test2", time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, frames)
+
+	// Frames older than max age are dropped.
+	err = h.Add(ctx, 1, "old", now.Add(-time.Hour), json.RawMessage(`{}`))
+	require.NoError(t, err)
+	frames, err = h.Get(ctx, 1, "old", time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, frames)
+}
+
+func TestMemoryFrameHistory(t *testing.T) {
+	h := NewMemoryFrameHistory(3, time.Minute)
+	require.NotNil(t, h)
+	testFrameHistory(t, h, 3)
+}
+
+func TestMemoryFrameHistory_RemovesIdleChannels(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	h := NewMemoryFrameHistory(3, time.Minute)
+
+	require.NoError(t, h.Add(ctx, 1, "idle", now.Add(-2*time.Minute), json.RawMessage(`{}`)))
+	require.NoError(t, h.Add(ctx, 2, "idle", now.Add(-90*time.Second), json.RawMessage(`{}`)))
+	require.NoError(t, h.Add(ctx, 1, "active", now, json.RawMessage(`{}`)))
+
+	require.Len(t, h.rings, 1)
+	require.Len(t, h.rings[1], 1)
+	require.Contains(t, h.rings[1], "active")
+}
+
+func TestMergeHistoryFrames(t *testing.T) {
+	frames := make([]json.RawMessage, 0, 3)
+	for _, frame := range []*data.Frame{
+		data.NewFrame("test", data.NewField("value", nil, []string{"old"})),
+		data.NewFrame("test", data.NewField("time", nil, []time.Time{time.UnixMilli(1)}), data.NewField("value", nil, []float64{1})),
+		data.NewFrame("test", data.NewField("time", nil, []time.Time{time.UnixMilli(2), time.UnixMilli(3)}), data.NewField("value", nil, []float64{2, 3})),
+	} {
+		frameJSON, err := data.FrameToJSON(frame, data.IncludeAll)
+		require.NoError(t, err)
+		frames = append(frames, frameJSON)
+	}
+
+	merged, err := mergeHistoryFrames(frames)
+	require.NoError(t, err)
+
+	var frame data.Frame
+	require.NoError(t, json.Unmarshal(merged, &frame))
+	require.Len(t, frame.Fields, 2)
+	// Frame with another schema is skipped.
+	require.Equal(t, 3, frame.Fields[1].Len())
+	require.Equal(t, 3.0, frame.Fields[1].At(2))
+}
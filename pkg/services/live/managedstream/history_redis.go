@@ -0,0 +1,85 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+// RedisFrameHistory keeps recent frames per channel in a Redis sorted set scored
+// by push time in microseconds, so history is shared between Grafana instances.
+type RedisFrameHistory struct {
+	redisClient *redis.Client
+	keyPrefix   string
+	maxFrames   int
+	maxAge      time.Duration
+}
+
+// NewRedisFrameHistory ...
+func NewRedisFrameHistory(redisClient *redis.Client, keyPrefix string, maxFrames int, maxAge time.Duration) *RedisFrameHistory {
+	return &RedisFrameHistory{
+		redisClient: redisClient,
+		keyPrefix:   keyPrefix,
+		maxFrames:   maxFrames,
+		maxAge:      maxAge,
+	}
+}
+
+func (h *RedisFrameHistory) Add(ctx context.Context, orgID int64, channel string, t time.Time, frameJSON json.RawMessage) error {
+	if h.maxFrames <= 0 {
+		return nil
+	}
+	key := h.getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+	score := t.UnixMicro()
+
+	pipe := h.redisClient.TxPipeline()
+	defer func() { _ = pipe.Close() }()
+
+	// Members must be unique, identical frames pushed at different times are kept apart by time prefix.
+	pipe.ZAdd(ctx, key, &redis.Z{
+		Score:  float64(score),
+		Member: strconv.FormatInt(score, 10) + ":" + string(frameJSON),
+	})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(t.Add(-h.maxAge).UnixMicro(), 10))
+	pipe.ZRemRangeByRank(ctx, key, 0, int64(-h.maxFrames-1))
+	pipe.PExpire(ctx, key, h.maxAge)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisFrameHistory) Get(ctx context.Context, orgID int64, channel string, since time.Time) ([]json.RawMessage, error) {
+	key := h.getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+	minTime := time.Now().Add(-h.maxAge)
+	minScore := strconv.FormatInt(minTime.UnixMicro(), 10)
+	if since.After(minTime) {
+		minScore = "(" + strconv.FormatInt(since.UnixMicro(), 10)
+	}
+	members, err := h.redisClient.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: minScore,
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	frames := make([]json.RawMessage, 0, len(members))
+	for _, member := range members {
+		_, frameJSON, ok := strings.Cut(member, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed frame history entry in %s", key)
+		}
+		frames = append(frames, json.RawMessage(frameJSON))
+	}
+	return frames, nil
+}
+
+func (h *RedisFrameHistory) getHistoryKey(channelID string) string {
+	return h.keyPrefix + ".managed_stream_history." + channelID
+}
@@ -0,0 +1,42 @@
+package managedstream
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationRedisFrameHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	u, ok := os.LookupEnv("REDIS_URL")
+	if !ok || u == "" {
+		t.Skip("No redis URL supplied")
+	}
+
+	addr := u
+	db := 0
+	parsed, err := redis.ParseURL(u)
+	if err == nil {
+		addr = parsed.Addr
+		db = parsed.DB
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: addr,
+		DB:   db,
+	})
+	prefix := uuid.New().String()
+
+	t.Cleanup(redisCleanup(t, redisClient, prefix))
+
+	h := NewRedisFrameHistory(redisClient, prefix, 3, time.Minute)
+	require.NotNil(t, h)
+	testFrameHistory(t, h, 3)
+}
@@ -42,22 +42,38 @@ type Runner struct {
 	publisher      model.ChannelPublisher
 	localPublisher LocalPublisher
 	frameCache     FrameCache
+	frameHistory   FrameHistory
 }
 
 type LocalPublisher interface {
 	PublishLocal(channel string, data []byte) error
 }
 
-// NewRunner creates new Runner.
-func NewRunner(publisher model.ChannelPublisher, localPublisher LocalPublisher, frameCache FrameCache) *Runner {
+// NewRunner creates new Runner. frameHistory is optional, without it new
+// subscribers only receive the last frame of a channel.
+func NewRunner(publisher model.ChannelPublisher, localPublisher LocalPublisher, frameCache FrameCache, frameHistory FrameHistory) *Runner {
 	return &Runner{
 		publisher:      publisher,
 		localPublisher: localPublisher,
 		streams:        map[int64]map[string]*NamespaceStream{},
 		frameCache:     frameCache,
+		frameHistory:   frameHistory,
 	}
 }
 
+// GetChannelHistory returns frames of a channel pushed after since, oldest first.
+// Without frame history the last frame of a channel is returned.
+func (r *Runner) GetChannelHistory(ctx context.Context, orgID int64, channel string, since time.Time) ([]json.RawMessage, error) {
+	if r.frameHistory != nil {
+		return r.frameHistory.Get(ctx, orgID, channel, since)
+	}
+	frameJSON, ok, err := r.frameCache.GetFrame(ctx, orgID, channel)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return []json.RawMessage{frameJSON}, nil
+}
+
 func (r *Runner) GetManagedChannels(orgID int64) ([]*ManagedChannel, error) {
 	activeChannels, err := r.frameCache.GetActiveChannels(orgID)
 	if err != nil {
@@ -125,7 +141,7 @@ func (r *Runner) GetOrCreateStream(orgID int64, scope string, namespace string)
 	prefix := scope + "/" + namespace
 	s, ok := r.streams[orgID][prefix]
 	if !ok {
-		s = NewNamespaceStream(orgID, scope, namespace, r.publisher, r.localPublisher, r.frameCache)
+		s = NewNamespaceStream(orgID, scope, namespace, r.publisher, r.localPublisher, r.frameCache, r.frameHistory)
 		r.streams[orgID][prefix] = s
 	}
 	return s, nil
@@ -139,6 +155,7 @@ type NamespaceStream struct {
 	publisher      model.ChannelPublisher
 	localPublisher LocalPublisher
 	frameCache     FrameCache
+	frameHistory   FrameHistory
 	rateMu         sync.RWMutex
 	rates          map[string][60]rateEntry
 }
@@ -156,7 +173,7 @@ type ManagedChannel struct {
 }
 
 // NewNamespaceStream creates new NamespaceStream.
-func NewNamespaceStream(orgID int64, scope string, namespace string, publisher model.ChannelPublisher, localPublisher LocalPublisher, schemaUpdater FrameCache) *NamespaceStream {
+func NewNamespaceStream(orgID int64, scope string, namespace string, publisher model.ChannelPublisher, localPublisher LocalPublisher, schemaUpdater FrameCache, frameHistory FrameHistory) *NamespaceStream {
 	return &NamespaceStream{
 		orgID:          orgID,
 		scope:          scope,
@@ -164,12 +181,13 @@ func NewNamespaceStream(orgID int64, scope string, namespace string, publisher m
 		publisher:      publisher,
 		localPublisher: localPublisher,
 		frameCache:     schemaUpdater,
+		frameHistory:   frameHistory,
 		rates:          map[string][60]rateEntry{},
 	}
 }
 
 // Push sends frame to the stream and saves it for later retrieval by subscribers.
-// * Saves the entire frame to cache.
+// * Saves the entire frame to cache and to history.
 // * If schema has been changed sends entire frame to channel, otherwise only data.
 func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Frame) error {
 	jsonFrameCache, err := data.FrameToJSONCache(frame)
@@ -186,6 +204,14 @@ func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Fra
 		return err
 	}
 
+	if s.frameHistory != nil {
+		err = s.frameHistory.Add(ctx, s.orgID, channel, time.Now(), jsonFrameCache.Bytes(data.IncludeAll))
+		if err != nil {
+			logger.Error("Error adding frame to managed stream history", "error", err)
+			return err
+		}
+	}
+
 	// When the schema has not changed, just send the data.
 	include := data.IncludeDataOnly
 	if isUpdated {
@@ -241,6 +267,20 @@ func (s *NamespaceStream) GetHandlerForPath(_ string) (model.ChannelHandler, err
 
 func (s *NamespaceStream) OnSubscribe(ctx context.Context, u identity.Requester, e model.SubscribeEvent) (model.SubscribeReply, backend.SubscribeStreamStatus, error) {
 	reply := model.SubscribeReply{}
+	if s.frameHistory != nil {
+		frames, err := s.frameHistory.Get(ctx, u.GetOrgID(), e.Channel, time.Time{})
+		if err != nil {
+			return reply, 0, err
+		}
+		if len(frames) > 0 {
+			// Late subscribers get recent frames merged into one frame.
+			reply.Data, err = mergeHistoryFrames(frames)
+			if err != nil {
+				return reply, 0, err
+			}
+			return reply, backend.SubscribeStreamStatusOK, nil
+		}
+	}
 	frameJSON, ok, err := s.frameCache.GetFrame(ctx, u.GetOrgID(), e.Channel)
 	if err != nil {
 		return reply, 0, err
@@ -32,14 +32,18 @@ type ChannelRule struct {
 	OrgId    int64               `json:"-"`
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
+	// Provisioned rules are read from provisioning files and can't be changed over API.
+	Provisioned bool `json:"provisioned,omitempty"`
 }
 
 type ConverterConfig struct {
-	Type                      string                     `json:"type" ts_type:"Omit<keyof ConverterConfig, 'type'>"`
-	AutoJsonConverterConfig   *AutoJsonConverterConfig   `json:"jsonAuto,omitempty"`
-	ExactJsonConverterConfig  *ExactJsonConverterConfig  `json:"jsonExact,omitempty"`
-	AutoInfluxConverterConfig *AutoInfluxConverterConfig `json:"influxAuto,omitempty"`
-	JsonFrameConverterConfig  *JsonFrameConverterConfig  `json:"jsonFrame,omitempty"`
+	Type                                 string                                `json:"type" ts_type:"Omit<keyof ConverterConfig, 'type'>"`
+	AutoJsonConverterConfig              *AutoJsonConverterConfig              `json:"jsonAuto,omitempty"`
+	ExactJsonConverterConfig             *ExactJsonConverterConfig             `json:"jsonExact,omitempty"`
+	AutoInfluxConverterConfig            *AutoInfluxConverterConfig            `json:"influxAuto,omitempty"`
+	JsonFrameConverterConfig             *JsonFrameConverterConfig             `json:"jsonFrame,omitempty"`
+	PrometheusRemoteWriteConverterConfig *PrometheusRemoteWriteConverterConfig `json:"prometheusRemoteWrite,omitempty"`
+	OTLPMetricsConverterConfig           *OTLPMetricsConverterConfig           `json:"otlpMetrics,omitempty"`
 }
 
 type DropFieldsFrameProcessorConfig struct {
@@ -50,11 +54,56 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+// RenameFieldsFrameProcessorConfig maps field names to their new names.
+type RenameFieldsFrameProcessorConfig struct {
+	Renames map[string]string `json:"renames"`
+}
+
+// DerivedFieldFrameProcessorConfig adds a field computed row by row with a math expression
+// over other fields, which are referenced as $name or ${field name}.
+type DerivedFieldFrameProcessorConfig struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Unit       string `json:"unit,omitempty"`
+}
+
+type ConvertFieldsFrameProcessorConfig struct {
+	Fields []ConvertFieldConfig `json:"fields"`
+}
+
+// ConvertFieldConfig converts a field to another type (number, string, boolean or time)
+// and/or number values from one unit to another, for example ms to s.
+type ConvertFieldConfig struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	FromUnit string `json:"fromUnit,omitempty"`
+	ToUnit   string `json:"toUnit,omitempty"`
+}
+
+// DownsampleFrameProcessorConfig aggregates rows into time windows. Aggregation is one of
+// mean (default), min, max, sum, count, first or last and can be overridden per field name.
+type DownsampleFrameProcessorConfig struct {
+	Window            string            `json:"window"`
+	Aggregation       string            `json:"aggregation,omitempty"`
+	FieldAggregations map[string]string `json:"fieldAggregations,omitempty"`
+}
+
+// LabelsFromPathFrameProcessorConfig attaches labels from channel segments matched by a
+// pattern like stream/telegraf/:host/:metric, in the same format as channel rule patterns.
+type LabelsFromPathFrameProcessorConfig struct {
+	Pattern string `json:"pattern"`
+}
+
 type FrameProcessorConfig struct {
-	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
-	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
-	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
-	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	Type                          string                              `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
+	DropFieldsProcessorConfig     *DropFieldsFrameProcessorConfig     `json:"dropFields,omitempty"`
+	KeepFieldsProcessorConfig     *KeepFieldsFrameProcessorConfig     `json:"keepFields,omitempty"`
+	MultipleProcessorConfig       *MultipleFrameProcessorConfig       `json:"multiple,omitempty"`
+	RenameFieldsProcessorConfig   *RenameFieldsFrameProcessorConfig   `json:"renameFields,omitempty"`
+	DerivedFieldProcessorConfig   *DerivedFieldFrameProcessorConfig   `json:"derivedField,omitempty"`
+	ConvertFieldsProcessorConfig  *ConvertFieldsFrameProcessorConfig  `json:"convertFields,omitempty"`
+	DownsampleProcessorConfig     *DownsampleFrameProcessorConfig     `json:"downsample,omitempty"`
+	LabelsFromPathProcessorConfig *LabelsFromPathFrameProcessorConfig `json:"labelsFromPath,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -152,4 +201,14 @@ type AutoInfluxConverterConfig struct {
 
 type JsonFrameConverterConfig struct{}
 
+// PrometheusRemoteWriteConverterConfig ...
+type PrometheusRemoteWriteConverterConfig struct {
+	FrameFormat string `json:"frameFormat"`
+}
+
+// OTLPMetricsConverterConfig ...
+type OTLPMetricsConverterConfig struct {
+	FrameFormat string `json:"frameFormat"`
+}
+
 type ManagedStreamOutputConfig struct{}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/services/live/convert"
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
 )
 
 // AutoInfluxConverter decodes Influx line protocol input and transforms it
@@ -30,12 +31,18 @@ func (c *AutoInfluxConverter) Convert(_ context.Context, vars Vars, body []byte)
 	if err != nil {
 		return nil, err
 	}
+	return FrameWrappersToChannelFrames(vars.Channel, frameWrappers), nil
+}
+
+// FrameWrappersToChannelFrames creates a ChannelFrame for each converted frame,
+// with Channel constructed from channel + / + <frame key>.
+func FrameWrappersToChannelFrames(channel string, frameWrappers []telemetry.FrameWrapper) []*ChannelFrame {
 	channelFrames := make([]*ChannelFrame, 0, len(frameWrappers))
 	for _, fw := range frameWrappers {
 		channelFrames = append(channelFrames, &ChannelFrame{
-			Channel: vars.Channel + "/" + fw.Key(),
+			Channel: channel + "/" + fw.Key(),
 			Frame:   fw.Frame(),
 		})
 	}
-	return channelFrames, nil
+	return channelFrames
 }
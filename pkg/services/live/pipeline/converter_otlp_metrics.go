@@ -0,0 +1,34 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/live/convert"
+)
+
+// OTLPMetricsConverter decodes OTLP/HTTP metrics input and transforms it to
+// several ChannelFrame objects where Channel is constructed from original
+// channel + / + <metric_name>.
+type OTLPMetricsConverter struct {
+	config    OTLPMetricsConverterConfig
+	converter *convert.Converter
+}
+
+// NewOTLPMetricsConverter creates new OTLPMetricsConverter.
+func NewOTLPMetricsConverter(config OTLPMetricsConverterConfig) *OTLPMetricsConverter {
+	return &OTLPMetricsConverter{config: config, converter: convert.NewConverter()}
+}
+
+const ConverterTypeOTLPMetrics = "otlpMetrics"
+
+func (c *OTLPMetricsConverter) Type() string {
+	return ConverterTypeOTLPMetrics
+}
+
+func (c *OTLPMetricsConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	frameWrappers, err := c.converter.ConvertOTLPMetrics(body, c.config.FrameFormat)
+	if err != nil {
+		return nil, err
+	}
+	return FrameWrappersToChannelFrames(vars.Channel, frameWrappers), nil
+}
@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+)
+
+func otlpMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "agent")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+	ts := pcommon.NewTimestampFromTime(time.Unix(100, 0))
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("memory.used")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(42)
+	dp.Attributes().PutStr("host", "a")
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("request.duration")
+	hdp := histogram.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(ts)
+	hdp.SetCount(3)
+	hdp.SetSum(1.5)
+	hdp.ExplicitBounds().FromRaw([]float64{0.5})
+	hdp.BucketCounts().FromRaw([]uint64{2, 1})
+	return md
+}
+
+func TestOTLPMetricsConverter_Convert(t *testing.T) {
+	req := pmetricotlp.NewExportRequestFromMetrics(otlpMetrics())
+	protoBody, err := req.MarshalProto()
+	require.NoError(t, err)
+	jsonBody, err := req.MarshalJSON()
+	require.NoError(t, err)
+
+	for name, body := range map[string][]byte{"protobuf": protoBody, "json": jsonBody} {
+		t.Run(name, func(t *testing.T) {
+			c := NewOTLPMetricsConverter(OTLPMetricsConverterConfig{FrameFormat: "labels_column"})
+			channelFrames, err := c.Convert(context.Background(), Vars{Channel: "stream/agents/otlp"}, body)
+			require.NoError(t, err)
+
+			channels := make([]string, 0, len(channelFrames))
+			for _, cf := range channelFrames {
+				channels = append(channels, cf.Channel)
+			}
+			require.Equal(t, []string{
+				"stream/agents/otlp/memory.used",
+				"stream/agents/otlp/request.duration_count",
+				"stream/agents/otlp/request.duration_sum",
+				"stream/agents/otlp/request.duration_bucket",
+			}, channels)
+
+			gauge := channelFrames[0].Frame
+			require.Equal(t, data.Labels{"service.name": "agent", "host": "a"}.String(), gauge.Fields[0].At(0))
+			require.Equal(t, time.Unix(100, 0).UTC(), gauge.Fields[1].At(0).(time.Time).UTC())
+			require.Equal(t, 42.0, gauge.Fields[2].At(0))
+
+			buckets := channelFrames[3].Frame
+			require.Equal(t, 2, buckets.Rows())
+			require.Equal(t, data.Labels{"service.name": "agent", "le": "0.5"}.String(), buckets.Fields[0].At(0))
+			require.Equal(t, 2.0, buckets.Fields[2].At(0))
+			require.Equal(t, data.Labels{"service.name": "agent", "le": "+Inf"}.String(), buckets.Fields[0].At(1))
+			require.Equal(t, 3.0, buckets.Fields[2].At(1))
+		})
+	}
+}
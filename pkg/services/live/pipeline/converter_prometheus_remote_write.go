@@ -0,0 +1,34 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/live/convert"
+)
+
+// PrometheusRemoteWriteConverter decodes snappy compressed Prometheus remote-write
+// input and transforms it to several ChannelFrame objects where Channel is constructed
+// from original channel + / + <metric_name>.
+type PrometheusRemoteWriteConverter struct {
+	config    PrometheusRemoteWriteConverterConfig
+	converter *convert.Converter
+}
+
+// NewPrometheusRemoteWriteConverter creates new PrometheusRemoteWriteConverter.
+func NewPrometheusRemoteWriteConverter(config PrometheusRemoteWriteConverterConfig) *PrometheusRemoteWriteConverter {
+	return &PrometheusRemoteWriteConverter{config: config, converter: convert.NewConverter()}
+}
+
+const ConverterTypePrometheusRemoteWrite = "prometheusRemoteWrite"
+
+func (c *PrometheusRemoteWriteConverter) Type() string {
+	return ConverterTypePrometheusRemoteWrite
+}
+
+func (c *PrometheusRemoteWriteConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	frameWrappers, err := c.converter.ConvertPrometheusRemoteWrite(body, c.config.FrameFormat)
+	if err != nil {
+		return nil, err
+	}
+	return FrameWrappersToChannelFrames(vars.Channel, frameWrappers), nil
+}
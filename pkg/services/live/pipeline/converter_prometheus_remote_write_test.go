@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+)
+
+func remoteWriteBody(t *testing.T) []byte {
+	t.Helper()
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "cpu"}, {Name: "host", Value: "a"}},
+				Samples: []prompb.Sample{{Timestamp: 2000, Value: 2}, {Timestamp: 1000, Value: 1}},
+			},
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "cpu"}, {Name: "host", Value: "b"}},
+				Samples: []prompb.Sample{{Timestamp: 1000, Value: 3}, {Timestamp: 3000, Value: math.Float64frombits(value.StaleNaN)}},
+			},
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "job:requests:rate5m"}},
+				Samples: []prompb.Sample{{Timestamp: 1000, Value: 10}},
+			},
+		},
+	}
+	b, err := req.Marshal()
+	require.NoError(t, err)
+	return snappy.Encode(nil, b)
+}
+
+func TestPrometheusRemoteWriteConverter_Convert(t *testing.T) {
+	t.Run("labels column", func(t *testing.T) {
+		c := NewPrometheusRemoteWriteConverter(PrometheusRemoteWriteConverterConfig{FrameFormat: "labels_column"})
+		channelFrames, err := c.Convert(context.Background(), Vars{Channel: "stream/agents/metrics"}, remoteWriteBody(t))
+		require.NoError(t, err)
+		require.Len(t, channelFrames, 2)
+
+		require.Equal(t, "stream/agents/metrics/cpu", channelFrames[0].Channel)
+		frame := channelFrames[0].Frame
+		require.Equal(t, 3, frame.Rows())
+		require.Equal(t, `host=a`, frame.Fields[0].At(0))
+		require.Equal(t, `host=b`, frame.Fields[0].At(1))
+		require.Equal(t, time.UnixMilli(1000), frame.Fields[1].At(0))
+		require.Equal(t, time.UnixMilli(2000), frame.Fields[1].At(2))
+		require.Equal(t, 2.0, frame.Fields[2].At(2))
+
+		// characters not allowed in channel paths are replaced
+		require.Equal(t, "stream/agents/metrics/job_requests_rate5m", channelFrames[1].Channel)
+		require.Equal(t, "job:requests:rate5m", channelFrames[1].Frame.Name)
+	})
+
+	t.Run("wide", func(t *testing.T) {
+		c := NewPrometheusRemoteWriteConverter(PrometheusRemoteWriteConverterConfig{FrameFormat: "wide"})
+		channelFrames, err := c.Convert(context.Background(), Vars{Channel: "stream/agents/metrics"}, remoteWriteBody(t))
+		require.NoError(t, err)
+		require.Len(t, channelFrames, 2)
+
+		frame := channelFrames[0].Frame
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, data.Labels{"host": "a"}, frame.Fields[1].Labels)
+		require.Equal(t, 2.0, *frame.Fields[1].At(1).(*float64))
+		require.Equal(t, data.Labels{"host": "b"}, frame.Fields[2].Labels)
+		require.Equal(t, 3.0, *frame.Fields[2].At(0).(*float64))
+		require.Nil(t, frame.Fields[2].At(1))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		c := NewPrometheusRemoteWriteConverter(PrometheusRemoteWriteConverterConfig{FrameFormat: "labels_column"})
+		_, err := c.Convert(context.Background(), Vars{Channel: "stream/agents/metrics"}, []byte("cpu value=1"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,78 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// DownsampleStorage keeps the downsample processors of channel rules in memory, so their
+// buffered windows are kept when the rules are rebuilt. A processor is reused while its
+// rule keeps the same pattern and configuration. Not usable in HA setup.
+type DownsampleStorage struct {
+	mu         sync.Mutex
+	processors map[int64]map[string]*DownsampleFrameProcessor
+}
+
+func NewDownsampleStorage() *DownsampleStorage {
+	return &DownsampleStorage{
+		processors: map[int64]map[string]*DownsampleFrameProcessor{},
+	}
+}
+
+func downsampleProcessorKey(pattern string, config DownsampleFrameProcessorConfig) (string, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s", pattern, configJSON), nil
+}
+
+// GetOrCreate returns the processor of the rule with the pattern and configuration, it's
+// created if the rule has no processor yet.
+func (s *DownsampleStorage) GetOrCreate(orgID int64, pattern string, config DownsampleFrameProcessorConfig) (*DownsampleFrameProcessor, error) {
+	key, err := downsampleProcessorKey(pattern, config)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.processors[orgID][key]; ok {
+		return p, nil
+	}
+	p, err := NewDownsampleFrameProcessor(config)
+	if err != nil {
+		return nil, err
+	}
+	if s.processors[orgID] == nil {
+		s.processors[orgID] = map[string]*DownsampleFrameProcessor{}
+	}
+	s.processors[orgID][key] = p
+	return p, nil
+}
+
+// Retain removes the processors of the org which are not in the given processors, it's
+// called with the processors of the rules after they are rebuilt.
+func (s *DownsampleStorage) Retain(orgID int64, processors []FrameProcessor) {
+	used := map[*DownsampleFrameProcessor]struct{}{}
+	var collect func([]FrameProcessor)
+	collect = func(processors []FrameProcessor) {
+		for _, p := range processors {
+			switch p := p.(type) {
+			case *DownsampleFrameProcessor:
+				used[p] = struct{}{}
+			case *MultipleFrameProcessor:
+				collect(p.Processors)
+			}
+		}
+	}
+	collect(processors)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, p := range s.processors[orgID] {
+		if _, ok := used[p]; !ok {
+			delete(s.processors[orgID], key)
+		}
+	}
+}
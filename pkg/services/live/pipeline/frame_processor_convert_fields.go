@@ -0,0 +1,256 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ConvertFieldsFrameProcessor can convert the type of fields and the unit of number fields.
+// Converted fields are nullable, values which can't be converted become null.
+type ConvertFieldsFrameProcessor struct {
+	config ConvertFieldsFrameProcessorConfig
+}
+
+const (
+	ConvertFieldTypeNumber  = "number"
+	ConvertFieldTypeString  = "string"
+	ConvertFieldTypeBoolean = "boolean"
+	ConvertFieldTypeTime    = "time"
+)
+
+type unitConversion struct {
+	group string
+	// scale and offset convert a value of the unit to the base unit of the group.
+	scale  float64
+	offset float64
+}
+
+// unitConversions holds the Grafana unit ids which can be converted to each other.
+var unitConversions = map[string]unitConversion{
+	"ns":          {group: "time", scale: 1e-9},
+	"µs":          {group: "time", scale: 1e-6},
+	"ms":          {group: "time", scale: 1e-3},
+	"s":           {group: "time", scale: 1},
+	"m":           {group: "time", scale: 60},
+	"h":           {group: "time", scale: 3600},
+	"d":           {group: "time", scale: 86400},
+	"bits":        {group: "data", scale: 0.125},
+	"bytes":       {group: "data", scale: 1},
+	"kbytes":      {group: "data", scale: 1 << 10},
+	"mbytes":      {group: "data", scale: 1 << 20},
+	"gbytes":      {group: "data", scale: 1 << 30},
+	"tbytes":      {group: "data", scale: 1 << 40},
+	"decbytes":    {group: "data", scale: 1},
+	"deckbytes":   {group: "data", scale: 1e3},
+	"decmbytes":   {group: "data", scale: 1e6},
+	"decgbytes":   {group: "data", scale: 1e9},
+	"dectbytes":   {group: "data", scale: 1e12},
+	"percent":     {group: "percent", scale: 0.01},
+	"percentunit": {group: "percent", scale: 1},
+	"celsius":     {group: "temperature", scale: 1},
+	"fahrenheit":  {group: "temperature", scale: 5.0 / 9, offset: -32 * 5.0 / 9},
+	"kelvin":      {group: "temperature", scale: 1, offset: -273.15},
+}
+
+func NewConvertFieldsFrameProcessor(config ConvertFieldsFrameProcessorConfig) (*ConvertFieldsFrameProcessor, error) {
+	for _, f := range config.Fields {
+		switch f.Type {
+		case "", ConvertFieldTypeNumber, ConvertFieldTypeString, ConvertFieldTypeBoolean, ConvertFieldTypeTime:
+		default:
+			return nil, fmt.Errorf("unsupported type %s for field %s", f.Type, f.Name)
+		}
+		if f.FromUnit == "" && f.ToUnit == "" {
+			continue
+		}
+		from, ok := unitConversions[f.FromUnit]
+		if !ok {
+			return nil, fmt.Errorf("unsupported unit %s for field %s", f.FromUnit, f.Name)
+		}
+		to, ok := unitConversions[f.ToUnit]
+		if !ok {
+			return nil, fmt.Errorf("unsupported unit %s for field %s", f.ToUnit, f.Name)
+		}
+		if from.group != to.group {
+			return nil, fmt.Errorf("can't convert field %s from %s to %s", f.Name, f.FromUnit, f.ToUnit)
+		}
+	}
+	return &ConvertFieldsFrameProcessor{config: config}, nil
+}
+
+const FrameProcessorTypeConvertFields = "convertFields"
+
+func (p *ConvertFieldsFrameProcessor) Type() string {
+	return FrameProcessorTypeConvertFields
+}
+
+func (p *ConvertFieldsFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	for _, c := range p.config.Fields {
+		for i, field := range frame.Fields {
+			if field.Name != c.Name {
+				continue
+			}
+			converted := field
+			if c.FromUnit != "" {
+				var err error
+				converted, err = convertFieldUnit(converted, c.FromUnit, c.ToUnit)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if c.Type != "" {
+				converted = convertFieldType(converted, c.Type)
+			}
+			frame.Fields[i] = converted
+		}
+	}
+	return frame, nil
+}
+
+func convertFieldUnit(field *data.Field, fromUnit, toUnit string) (*data.Field, error) {
+	from, to := unitConversions[fromUnit], unitConversions[toUnit]
+	converted := newConvertedField(field, make([]*float64, field.Len()))
+	for i := 0; i < field.Len(); i++ {
+		v, err := field.NullableFloatAt(i)
+		if err != nil {
+			return nil, fmt.Errorf("can't convert unit of field %s: %w", field.Name, err)
+		}
+		if v == nil {
+			continue
+		}
+		base := *v*from.scale + from.offset
+		result := (base - to.offset) / to.scale
+		converted.Set(i, &result)
+	}
+	if converted.Config == nil {
+		converted.Config = &data.FieldConfig{}
+	}
+	converted.Config.Unit = toUnit
+	return converted, nil
+}
+
+func convertFieldType(field *data.Field, fieldType string) *data.Field {
+	switch fieldType {
+	case ConvertFieldTypeNumber:
+		converted := newConvertedField(field, make([]*float64, field.Len()))
+		for i := 0; i < field.Len(); i++ {
+			converted.Set(i, fieldFloatValueAt(field, i))
+		}
+		return converted
+	case ConvertFieldTypeString:
+		converted := newConvertedField(field, make([]*string, field.Len()))
+		for i := 0; i < field.Len(); i++ {
+			if v, ok := field.ConcreteAt(i); ok {
+				s := valueToString(v)
+				converted.Set(i, &s)
+			}
+		}
+		return converted
+	case ConvertFieldTypeBoolean:
+		converted := newConvertedField(field, make([]*bool, field.Len()))
+		for i := 0; i < field.Len(); i++ {
+			v, ok := field.ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			if s, isString := v.(string); isString {
+				if b, err := strconv.ParseBool(s); err == nil {
+					converted.Set(i, &b)
+				}
+			} else if f := fieldFloatValueAt(field, i); f != nil {
+				b := *f != 0
+				converted.Set(i, &b)
+			}
+		}
+		return converted
+	case ConvertFieldTypeTime:
+		converted := newConvertedField(field, make([]*time.Time, field.Len()))
+		for i := 0; i < field.Len(); i++ {
+			converted.Set(i, fieldTimeValueAt(field, i))
+		}
+		return converted
+	}
+	return field
+}
+
+func newConvertedField(field *data.Field, values interface{}) *data.Field {
+	converted := data.NewField(field.Name, field.Labels, values)
+	if field.Config != nil {
+		config := *field.Config
+		converted.Config = &config
+	}
+	return converted
+}
+
+// fieldFloatValueAt converts numbers, booleans, numeric strings and times (as epoch milliseconds)
+// to float64, nil is returned for null values and values which can't be converted.
+func fieldFloatValueAt(field *data.Field, row int) *float64 {
+	if field.Type().Numeric() {
+		f, _ := field.NullableFloatAt(row)
+		return f
+	}
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return nil
+	}
+	var f float64
+	switch val := v.(type) {
+	case string:
+		parsed, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil
+		}
+		f = parsed
+	case bool:
+		f = boolToFloat(val)
+	case time.Time:
+		f = float64(val.UnixMilli())
+	default:
+		return nil
+	}
+	return &f
+}
+
+// fieldTimeValueAt converts numbers as epoch milliseconds and strings in RFC 3339 format to time.
+func fieldTimeValueAt(field *data.Field, row int) *time.Time {
+	if field.Type().Numeric() {
+		ms, _ := field.NullableFloatAt(row)
+		if ms == nil {
+			return nil
+		}
+		t := time.UnixMilli(int64(*ms))
+		return &t
+	}
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return nil
+	}
+	switch val := v.(type) {
+	case time.Time:
+		return &val
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, val)
+		if err != nil {
+			return nil
+		}
+		return &t
+	}
+	return nil
+}
+
+func valueToString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	}
+	return fmt.Sprint(v)
+}
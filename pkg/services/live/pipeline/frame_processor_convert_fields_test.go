@@ -0,0 +1,71 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFieldsFrameProcessor(t *testing.T) {
+	t.Run("units", func(t *testing.T) {
+		p, err := NewConvertFieldsFrameProcessor(ConvertFieldsFrameProcessorConfig{
+			Fields: []ConvertFieldConfig{
+				{Name: "duration", FromUnit: "ms", ToUnit: "s"},
+				{Name: "temp", FromUnit: "fahrenheit", ToUnit: "celsius"},
+				{Name: "mem", FromUnit: "kbytes", ToUnit: "bytes"},
+			},
+		})
+		require.NoError(t, err)
+		frame := data.NewFrame("test",
+			data.NewField("duration", nil, []int64{1500}),
+			data.NewField("temp", nil, []float64{212}),
+			data.NewField("mem", nil, []float64{2}),
+		)
+		frame, err = p.ProcessFrame(context.Background(), Vars{}, frame)
+		require.NoError(t, err)
+		require.Equal(t, 1.5, *frame.Fields[0].At(0).(*float64))
+		require.Equal(t, "s", frame.Fields[0].Config.Unit)
+		require.InDelta(t, 100.0, *frame.Fields[1].At(0).(*float64), 1e-9)
+		require.Equal(t, 2048.0, *frame.Fields[2].At(0).(*float64))
+	})
+
+	t.Run("types", func(t *testing.T) {
+		p, err := NewConvertFieldsFrameProcessor(ConvertFieldsFrameProcessorConfig{
+			Fields: []ConvertFieldConfig{
+				{Name: "value", Type: ConvertFieldTypeNumber},
+				{Name: "ok", Type: ConvertFieldTypeBoolean},
+				{Name: "code", Type: ConvertFieldTypeString},
+				{Name: "ts", Type: ConvertFieldTypeTime},
+			},
+		})
+		require.NoError(t, err)
+		frame := data.NewFrame("test",
+			data.NewField("value", nil, []string{"1.5", "abc"}),
+			data.NewField("ok", nil, []string{"true", "0"}),
+			data.NewField("code", nil, []int64{200, 404}),
+			data.NewField("ts", nil, []float64{1000, 2000}),
+		)
+		frame, err = p.ProcessFrame(context.Background(), Vars{}, frame)
+		require.NoError(t, err)
+		require.Equal(t, 1.5, *frame.Fields[0].At(0).(*float64))
+		require.Nil(t, frame.Fields[0].At(1))
+		require.True(t, *frame.Fields[1].At(0).(*bool))
+		require.False(t, *frame.Fields[1].At(1).(*bool))
+		require.Equal(t, "404", *frame.Fields[2].At(1).(*string))
+		require.Equal(t, time.UnixMilli(2000), *frame.Fields[3].At(1).(*time.Time))
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		_, err := NewConvertFieldsFrameProcessor(ConvertFieldsFrameProcessorConfig{
+			Fields: []ConvertFieldConfig{{Name: "x", FromUnit: "ms", ToUnit: "bytes"}},
+		})
+		require.Error(t, err)
+		_, err = NewConvertFieldsFrameProcessor(ConvertFieldsFrameProcessorConfig{
+			Fields: []ConvertFieldConfig{{Name: "x", Type: "json"}},
+		})
+		require.Error(t, err)
+	})
+}
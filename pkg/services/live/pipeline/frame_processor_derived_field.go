@@ -0,0 +1,185 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// DerivedFieldFrameProcessor adds a field to a data.Frame computed for each row with
+// a math expression. The expression uses the syntax of server side math expressions,
+// fields are referenced as $name or ${field name}. The result is null if one of the
+// referenced fields is null in a row.
+type DerivedFieldFrameProcessor struct {
+	config DerivedFieldFrameProcessorConfig
+	tree   *parse.Tree
+}
+
+var derivedFieldFuncs = map[string]parse.Func{
+	"abs":   derivedFieldFunc(math.Abs),
+	"ceil":  derivedFieldFunc(math.Ceil),
+	"floor": derivedFieldFunc(math.Floor),
+	"log":   derivedFieldFunc(math.Log),
+	"round": derivedFieldFunc(math.Round),
+	"sqrt":  derivedFieldFunc(math.Sqrt),
+}
+
+func derivedFieldFunc(f func(float64) float64) parse.Func {
+	return parse.Func{
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             f,
+	}
+}
+
+func NewDerivedFieldFrameProcessor(config DerivedFieldFrameProcessorConfig) (*DerivedFieldFrameProcessor, error) {
+	if config.Name == "" {
+		return nil, errors.New("derived field name is required")
+	}
+	tree, err := parse.Parse(config.Expression, derivedFieldFuncs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derived field expression: %w", err)
+	}
+	return &DerivedFieldFrameProcessor{config: config, tree: tree}, nil
+}
+
+const FrameProcessorTypeDerivedField = "derivedField"
+
+func (p *DerivedFieldFrameProcessor) Type() string {
+	return FrameProcessorTypeDerivedField
+}
+
+func (p *DerivedFieldFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	fields := make(map[string]*data.Field, len(frame.Fields))
+	for _, field := range frame.Fields {
+		if _, ok := fields[field.Name]; !ok {
+			fields[field.Name] = field
+		}
+	}
+	for _, name := range p.tree.VarNames {
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("derived field %s: field %s not found", p.config.Name, name)
+		}
+		if !field.Type().Numeric() && field.Type() != data.FieldTypeBool && field.Type() != data.FieldTypeNullableBool {
+			return nil, fmt.Errorf("derived field %s: field %s is not a number", p.config.Name, name)
+		}
+	}
+
+	rows, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+	derived := data.NewField(p.config.Name, nil, make([]*float64, rows))
+	if p.config.Unit != "" {
+		derived.Config = &data.FieldConfig{Unit: p.config.Unit}
+	}
+	for i := 0; i < rows; i++ {
+		v, err := evalDerivedNode(p.tree.Root, fields, i)
+		if err != nil {
+			return nil, fmt.Errorf("derived field %s: %w", p.config.Name, err)
+		}
+		derived.Set(i, v)
+	}
+	frame.Fields = append(frame.Fields, derived)
+	return frame, nil
+}
+
+// evalDerivedNode evaluates the expression for a single row, nil means null.
+func evalDerivedNode(node parse.Node, fields map[string]*data.Field, row int) (*float64, error) {
+	switch n := node.(type) {
+	case *parse.ScalarNode:
+		v := n.Float64
+		return &v, nil
+	case *parse.VarNode:
+		return fieldFloatValueAt(fields[n.Name], row), nil
+	case *parse.UnaryNode:
+		a, err := evalDerivedNode(n.Arg, fields, row)
+		if err != nil || a == nil {
+			return nil, err
+		}
+		var v float64
+		switch n.OpStr {
+		case "-":
+			v = -*a
+		case "!":
+			v = boolToFloat(*a == 0)
+		default:
+			return nil, fmt.Errorf("unsupported unary operator %s", n.OpStr)
+		}
+		return &v, nil
+	case *parse.BinaryNode:
+		a, err := evalDerivedNode(n.Args[0], fields, row)
+		if err != nil || a == nil {
+			return nil, err
+		}
+		b, err := evalDerivedNode(n.Args[1], fields, row)
+		if err != nil || b == nil {
+			return nil, err
+		}
+		v, err := derivedBinaryOp(n.OpStr, *a, *b)
+		if err != nil {
+			return nil, err
+		}
+		return &v, nil
+	case *parse.FuncNode:
+		f, ok := n.F.F.(func(float64) float64)
+		if !ok || len(n.Args) != 1 {
+			return nil, fmt.Errorf("unsupported function %s", n.Name)
+		}
+		a, err := evalDerivedNode(n.Args[0], fields, row)
+		if err != nil || a == nil {
+			return nil, err
+		}
+		v := f(*a)
+		return &v, nil
+	default:
+		return nil, fmt.Errorf("unsupported expression %s", node.String())
+	}
+}
+
+func derivedBinaryOp(op string, a, b float64) (float64, error) {
+	switch op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		return a / b, nil
+	case "%":
+		return math.Mod(a, b), nil
+	case "**":
+		return math.Pow(a, b), nil
+	case "==":
+		return boolToFloat(a == b), nil
+	case "!=":
+		return boolToFloat(a != b), nil
+	case ">":
+		return boolToFloat(a > b), nil
+	case ">=":
+		return boolToFloat(a >= b), nil
+	case "<":
+		return boolToFloat(a < b), nil
+	case "<=":
+		return boolToFloat(a <= b), nil
+	case "&&":
+		return boolToFloat(a != 0 && b != 0), nil
+	case "||":
+		return boolToFloat(a != 0 || b != 0), nil
+	}
+	return 0, fmt.Errorf("unsupported binary operator %s", op)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerivedFieldFrameProcessor(t *testing.T) {
+	newFrame := func() *data.Frame {
+		used, total := 25.0, 100.0
+		return data.NewFrame("mem",
+			data.NewField("used", nil, []*float64{&used, nil}),
+			data.NewField("total bytes", nil, []*float64{&total, &total}),
+			data.NewField("host", nil, []string{"a", "b"}),
+		)
+	}
+
+	t.Run("computes value for each row", func(t *testing.T) {
+		p, err := NewDerivedFieldFrameProcessor(DerivedFieldFrameProcessorConfig{
+			Name:       "used_percent",
+			Expression: "round($used / ${total bytes} * 100) + abs(-1)",
+			Unit:       "percent",
+		})
+		require.NoError(t, err)
+		frame, err := p.ProcessFrame(context.Background(), Vars{}, newFrame())
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 4)
+		derived := frame.Fields[3]
+		require.Equal(t, "used_percent", derived.Name)
+		require.Equal(t, "percent", derived.Config.Unit)
+		require.Equal(t, 26.0, *derived.At(0).(*float64))
+		require.Nil(t, derived.At(1))
+	})
+
+	t.Run("comparison", func(t *testing.T) {
+		p, err := NewDerivedFieldFrameProcessor(DerivedFieldFrameProcessorConfig{Name: "high", Expression: "${total bytes} > 50 && !($used < 10)"})
+		require.NoError(t, err)
+		frame, err := p.ProcessFrame(context.Background(), Vars{}, newFrame())
+		require.NoError(t, err)
+		require.Equal(t, 1.0, *frame.Fields[3].At(0).(*float64))
+	})
+
+	t.Run("invalid expression", func(t *testing.T) {
+		_, err := NewDerivedFieldFrameProcessor(DerivedFieldFrameProcessorConfig{Name: "x", Expression: "$used +"})
+		require.Error(t, err)
+		_, err = NewDerivedFieldFrameProcessor(DerivedFieldFrameProcessorConfig{Name: "x", Expression: "unknown($used)"})
+		require.Error(t, err)
+	})
+
+	t.Run("missing or non number field", func(t *testing.T) {
+		p, err := NewDerivedFieldFrameProcessor(DerivedFieldFrameProcessorConfig{Name: "x", Expression: "$missing * 2"})
+		require.NoError(t, err)
+		_, err = p.ProcessFrame(context.Background(), Vars{}, newFrame())
+		require.ErrorContains(t, err, "field missing not found")
+
+		p, err = NewDerivedFieldFrameProcessor(DerivedFieldFrameProcessorConfig{Name: "x", Expression: "$host * 2"})
+		require.NoError(t, err)
+		_, err = p.ProcessFrame(context.Background(), Vars{}, newFrame())
+		require.ErrorContains(t, err, "field host is not a number")
+	})
+}
@@ -0,0 +1,315 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	AggregationMean  = "mean"
+	AggregationMin   = "min"
+	AggregationMax   = "max"
+	AggregationSum   = "sum"
+	AggregationCount = "count"
+	AggregationFirst = "first"
+	AggregationLast  = "last"
+)
+
+// DownsampleFrameProcessor aggregates rows of the frames of a channel into time windows.
+// Rows are buffered until a row of a later window arrives, the aggregated rows of the
+// closed window are then passed to the next processors and outputs. Frames which don't
+// close a window are dropped. Rows are grouped by the values of string fields, so frames
+// with a labels column are aggregated per series. The window of a channel which receives
+// no frames for downsampleIdleWindows windows is dropped. Rule builders keep the processors
+// in a DownsampleStorage, so the windows are kept when the rules are rebuilt.
+type DownsampleFrameProcessor struct {
+	config DownsampleFrameProcessorConfig
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	states    map[string]*downsampleState
+	lastSweep time.Time
+}
+
+// downsampleIdleWindows is the number of windows without frames after which the state
+// of a channel is removed.
+const downsampleIdleWindows = 10
+
+func NewDownsampleFrameProcessor(config DownsampleFrameProcessorConfig) (*DownsampleFrameProcessor, error) {
+	window, err := time.ParseDuration(config.Window)
+	if err != nil {
+		return nil, fmt.Errorf("invalid downsample window: %w", err)
+	}
+	if window <= 0 {
+		return nil, errors.New("downsample window must be positive")
+	}
+	if config.Aggregation == "" {
+		config.Aggregation = AggregationMean
+	}
+	for _, aggregation := range append([]string{config.Aggregation}, mapValues(config.FieldAggregations)...) {
+		switch aggregation {
+		case AggregationMean, AggregationMin, AggregationMax, AggregationSum, AggregationCount, AggregationFirst, AggregationLast:
+		default:
+			return nil, fmt.Errorf("unsupported downsample aggregation: %s", aggregation)
+		}
+	}
+	return &DownsampleFrameProcessor{
+		config: config,
+		window: window,
+		now:    time.Now,
+		states: map[string]*downsampleState{},
+	}, nil
+}
+
+func mapValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
+
+const FrameProcessorTypeDownsample = "downsample"
+
+func (p *DownsampleFrameProcessor) Type() string {
+	return FrameProcessorTypeDownsample
+}
+
+// downsampleState holds the rows of the current window of a channel.
+type downsampleState struct {
+	start time.Time
+	// lastSeen is the (wall clock) time of the last frame of the channel.
+	lastSeen time.Time
+	// groupFields are the names of the string fields rows are grouped by.
+	groupFields []string
+	// valueFields are the number fields, a template keeps name, labels and config.
+	valueFields []*data.Field
+	valueIndex  map[string]int
+	groups      map[string]*downsampleGroup
+	groupsOrder []string
+	timeName    string
+}
+
+type downsampleGroup struct {
+	keys   []string
+	values map[int]*downsampleAccumulator
+}
+
+type downsampleAccumulator struct {
+	count       float64
+	sum         float64
+	min         float64
+	max         float64
+	first, last float64
+}
+
+func (a *downsampleAccumulator) add(v float64) {
+	if a.count == 0 {
+		a.min, a.max, a.first = v, v, v
+	}
+	a.count++
+	a.sum += v
+	a.min = min(a.min, v)
+	a.max = max(a.max, v)
+	a.last = v
+}
+
+func (a *downsampleAccumulator) result(aggregation string) float64 {
+	switch aggregation {
+	case AggregationMin:
+		return a.min
+	case AggregationMax:
+		return a.max
+	case AggregationSum:
+		return a.sum
+	case AggregationCount:
+		return a.count
+	case AggregationFirst:
+		return a.first
+	case AggregationLast:
+		return a.last
+	}
+	return a.sum / a.count
+}
+
+func (p *DownsampleFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	timeIndex := -1
+	for i, field := range frame.Fields {
+		if field.Type().Time() {
+			timeIndex = i
+			break
+		}
+	}
+	if timeIndex < 0 {
+		return nil, errors.New("downsample requires a time field")
+	}
+	rows, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	p.evictIdle(now)
+	key := fmt.Sprintf("%d/%s", vars.OrgID, vars.Channel)
+	state, ok := p.states[key]
+	if !ok {
+		state = &downsampleState{}
+		p.states[key] = state
+	}
+	state.lastSeen = now
+
+	var output *data.Frame
+	for row := 0; row < rows; row++ {
+		t, ok := frame.Fields[timeIndex].ConcreteAt(row)
+		if !ok {
+			continue
+		}
+		windowStart := t.(time.Time).Truncate(p.window)
+		if state.start.IsZero() {
+			state.reset(windowStart, frame.Fields[timeIndex].Name)
+		} else if windowStart.After(state.start) {
+			output = p.appendWindow(output, state)
+			state.reset(windowStart, frame.Fields[timeIndex].Name)
+		}
+		// Late rows are aggregated into the current window.
+		state.add(frame, timeIndex, row)
+	}
+	if output == nil {
+		return nil, nil
+	}
+	output.Name = frame.Name
+	return output, nil
+}
+
+// evictIdle removes the states of channels without frames for downsampleIdleWindows
+// windows, their buffered rows are dropped. It runs at most once per window.
+func (p *DownsampleFrameProcessor) evictIdle(now time.Time) {
+	if now.Sub(p.lastSweep) < p.window {
+		return
+	}
+	p.lastSweep = now
+	idle := downsampleIdleWindows * p.window
+	for key, state := range p.states {
+		if now.Sub(state.lastSeen) > idle {
+			delete(p.states, key)
+		}
+	}
+}
+
+func (s *downsampleState) reset(start time.Time, timeName string) {
+	s.start = start
+	s.timeName = timeName
+	s.groupFields = nil
+	s.valueFields = nil
+	s.valueIndex = map[string]int{}
+	s.groups = map[string]*downsampleGroup{}
+	s.groupsOrder = nil
+}
+
+func (s *downsampleState) add(frame *data.Frame, timeIndex int, row int) {
+	if s.groupFields == nil {
+		s.groupFields = []string{}
+		for i, field := range frame.Fields {
+			if i != timeIndex && (field.Type() == data.FieldTypeString || field.Type() == data.FieldTypeNullableString) {
+				s.groupFields = append(s.groupFields, field.Name)
+			}
+		}
+	}
+
+	keys := make([]string, len(s.groupFields))
+	for i, name := range s.groupFields {
+		field, _ := frame.FieldByName(name)
+		if field != nil {
+			if v, ok := field.ConcreteAt(row); ok {
+				keys[i] = v.(string)
+			}
+		}
+	}
+	groupKey := strings.Join(keys, "\x00")
+	group, ok := s.groups[groupKey]
+	if !ok {
+		group = &downsampleGroup{keys: keys, values: map[int]*downsampleAccumulator{}}
+		s.groups[groupKey] = group
+		s.groupsOrder = append(s.groupsOrder, groupKey)
+	}
+
+	for i, field := range frame.Fields {
+		if i == timeIndex || !field.Type().Numeric() {
+			continue
+		}
+		fieldKey := field.Name + field.Labels.String()
+		index, ok := s.valueIndex[fieldKey]
+		if !ok {
+			index = len(s.valueFields)
+			s.valueIndex[fieldKey] = index
+			template := data.NewField(field.Name, field.Labels, []*float64{})
+			template.Config = field.Config
+			s.valueFields = append(s.valueFields, template)
+		}
+		v, err := field.NullableFloatAt(row)
+		if err != nil || v == nil {
+			continue
+		}
+		acc, ok := group.values[index]
+		if !ok {
+			acc = &downsampleAccumulator{}
+			group.values[index] = acc
+		}
+		acc.add(*v)
+	}
+}
+
+// appendWindow appends a row per group of the current window to the output frame. The
+// output frame is created from the first window, later windows of the same frame
+// are only appended if their fields are the same.
+func (p *DownsampleFrameProcessor) appendWindow(output *data.Frame, s *downsampleState) *data.Frame {
+	if output != nil && len(output.Fields) != 1+len(s.groupFields)+len(s.valueFields) {
+		return output
+	}
+	if output == nil {
+		fields := []*data.Field{data.NewField(s.timeName, nil, []time.Time{})}
+		for _, name := range s.groupFields {
+			fields = append(fields, data.NewField(name, nil, []string{}))
+		}
+		for _, template := range s.valueFields {
+			field := data.NewField(template.Name, template.Labels, []*float64{})
+			field.Config = template.Config
+			fields = append(fields, field)
+		}
+		output = data.NewFrame("", fields...)
+	}
+
+	for _, groupKey := range s.groupsOrder {
+		group := s.groups[groupKey]
+		values := make([]interface{}, 0, len(output.Fields))
+		values = append(values, s.start)
+		for _, k := range group.keys {
+			values = append(values, k)
+		}
+		for i, template := range s.valueFields {
+			acc, ok := group.values[i]
+			if !ok {
+				values = append(values, (*float64)(nil))
+				continue
+			}
+			aggregation := p.config.Aggregation
+			if fieldAggregation, ok := p.config.FieldAggregations[template.Name]; ok {
+				aggregation = fieldAggregation
+			}
+			result := acc.result(aggregation)
+			values = append(values, &result)
+		}
+		output.AppendRow(values...)
+	}
+	return output
+}
@@ -0,0 +1,163 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownsampleFrameProcessor(t *testing.T) {
+	vars := Vars{OrgID: 1, Channel: "stream/telegraf/cpu"}
+	frame := func(sec int64, host string, value float64) *data.Frame {
+		return data.NewFrame("cpu",
+			data.NewField("labels", nil, []string{host}),
+			data.NewField("time", nil, []time.Time{time.Unix(sec, 0)}),
+			data.NewField("usage", nil, []float64{value}),
+		)
+	}
+
+	p, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{
+		Window:            "10s",
+		FieldAggregations: map[string]string{"missing": AggregationMax},
+	})
+	require.NoError(t, err)
+
+	for _, f := range []*data.Frame{
+		frame(100, "host=a", 1),
+		frame(101, "host=b", 10),
+		frame(105, "host=a", 3),
+	} {
+		out, err := p.ProcessFrame(context.Background(), vars, f)
+		require.NoError(t, err)
+		require.Nil(t, out)
+	}
+
+	// a row of the next window closes the current one
+	out, err := p.ProcessFrame(context.Background(), vars, frame(111, "host=a", 100))
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, "cpu", out.Name)
+	require.Equal(t, 2, out.Rows())
+	require.Equal(t, time.Unix(100, 0), out.Fields[0].At(0))
+	require.Equal(t, "host=a", out.Fields[1].At(0))
+	require.Equal(t, 2.0, *out.Fields[2].At(0).(*float64))
+	require.Equal(t, "host=b", out.Fields[1].At(1))
+	require.Equal(t, 10.0, *out.Fields[2].At(1).(*float64))
+
+	// windows are kept per channel
+	out, err = p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/telegraf/mem"}, frame(200, "host=a", 1))
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		_, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{Window: "abc"})
+		require.Error(t, err)
+		_, err = NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{Window: "10s", Aggregation: "median"})
+		require.Error(t, err)
+	})
+}
+
+func TestDownsampleFrameProcessor_EvictIdle(t *testing.T) {
+	p, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{Window: "10s"})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	p.now = func() time.Time { return now }
+
+	frame := data.NewFrame("cpu",
+		data.NewField("time", nil, []time.Time{time.Unix(100, 0)}),
+		data.NewField("usage", nil, []float64{1}),
+	)
+	for _, channel := range []string{"stream/telegraf/cpu", "stream/telegraf/mem"} {
+		_, err := p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: channel}, frame)
+		require.NoError(t, err)
+	}
+	require.Len(t, p.states, 2)
+
+	// only the channel which keeps receiving frames is kept
+	now = now.Add(downsampleIdleWindows*p.window - time.Second)
+	_, err = p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/telegraf/cpu"}, frame)
+	require.NoError(t, err)
+	require.Len(t, p.states, 2)
+
+	now = now.Add(p.window)
+	_, err = p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/telegraf/cpu"}, frame)
+	require.NoError(t, err)
+	require.Len(t, p.states, 1)
+	require.Contains(t, p.states, "1/stream/telegraf/cpu")
+}
+
+func TestDownsampleFrameProcessor_Aggregations(t *testing.T) {
+	acc := &downsampleAccumulator{}
+	for _, v := range []float64{3, 1, 2} {
+		acc.add(v)
+	}
+	require.Equal(t, 2.0, acc.result(AggregationMean))
+	require.Equal(t, 1.0, acc.result(AggregationMin))
+	require.Equal(t, 3.0, acc.result(AggregationMax))
+	require.Equal(t, 6.0, acc.result(AggregationSum))
+	require.Equal(t, 3.0, acc.result(AggregationCount))
+	require.Equal(t, 3.0, acc.result(AggregationFirst))
+	require.Equal(t, 2.0, acc.result(AggregationLast))
+}
+
+type testRuleStorage struct {
+	Storage
+	rules []ChannelRule
+}
+
+func (s *testRuleStorage) ListChannelRules(_ context.Context, _ int64) ([]ChannelRule, error) {
+	return s.rules, nil
+}
+
+func (s *testRuleStorage) ListWriteConfigs(_ context.Context, _ int64) ([]WriteConfig, error) {
+	return nil, nil
+}
+
+func TestDownsampleFrameProcessor_RuleRebuild(t *testing.T) {
+	ctx := context.Background()
+	vars := Vars{OrgID: 1, Channel: "stream/telegraf/cpu"}
+	frame := func(sec int64, value float64) *data.Frame {
+		return data.NewFrame("cpu",
+			data.NewField("time", nil, []time.Time{time.Unix(sec, 0)}),
+			data.NewField("usage", nil, []float64{value}),
+		)
+	}
+	downsampleRule := func(window string) ChannelRule {
+		return ChannelRule{OrgId: 1, Pattern: "stream/telegraf/cpu", Settings: ChannelRuleSettings{
+			FrameProcessors: []*FrameProcessorConfig{{
+				Type:                      FrameProcessorTypeDownsample,
+				DownsampleProcessorConfig: &DownsampleFrameProcessorConfig{Window: window},
+			}},
+		}}
+	}
+	storage := &testRuleStorage{rules: []ChannelRule{downsampleRule("1m")}}
+	builder := &StorageRuleBuilder{Storage: storage, DownsampleStorage: NewDownsampleStorage()}
+
+	rules, err := builder.BuildRules(ctx, 1)
+	require.NoError(t, err)
+	out, err := rules[0].FrameProcessors[0].ProcessFrame(ctx, vars, frame(0, 1))
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// the window spans the rebuild of the rules
+	rules, err = builder.BuildRules(ctx, 1)
+	require.NoError(t, err)
+	out, err = rules[0].FrameProcessors[0].ProcessFrame(ctx, vars, frame(30, 3))
+	require.NoError(t, err)
+	require.Nil(t, out)
+	out, err = rules[0].FrameProcessors[0].ProcessFrame(ctx, vars, frame(60, 5))
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, 1, out.Rows())
+	require.Equal(t, 2.0, *out.Fields[1].At(0).(*float64))
+
+	// a changed configuration starts new windows and the old processor is removed
+	storage.rules = []ChannelRule{downsampleRule("2m")}
+	rebuilt, err := builder.BuildRules(ctx, 1)
+	require.NoError(t, err)
+	require.NotSame(t, rules[0].FrameProcessors[0], rebuilt[0].FrameProcessors[0])
+	require.Len(t, builder.DownsampleStorage.processors[1], 1)
+}
@@ -0,0 +1,85 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// LabelsFromPathFrameProcessor attaches labels extracted from channel segments to the
+// value fields of a data.Frame. The pattern uses :name to capture a single segment and
+// *name to capture the rest of the channel. Frames of channels which don't match the
+// pattern pass unchanged.
+type LabelsFromPathFrameProcessor struct {
+	segments []string
+}
+
+func NewLabelsFromPathFrameProcessor(config LabelsFromPathFrameProcessorConfig) *LabelsFromPathFrameProcessor {
+	return &LabelsFromPathFrameProcessor{segments: strings.Split(strings.Trim(config.Pattern, "/"), "/")}
+}
+
+const FrameProcessorTypeLabelsFromPath = "labelsFromPath"
+
+func (p *LabelsFromPathFrameProcessor) Type() string {
+	return FrameProcessorTypeLabelsFromPath
+}
+
+func (p *LabelsFromPathFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	labels, ok := p.match(vars.Channel)
+	if !ok || len(labels) == 0 {
+		return frame, nil
+	}
+	for _, field := range frame.Fields {
+		if field.Type().Time() {
+			continue
+		}
+		// Labels column frames keep labels of each row in a string field.
+		if field.Name == "labels" && field.Type() == data.FieldTypeString {
+			for i := 0; i < field.Len(); i++ {
+				rowLabels, err := data.LabelsFromString(field.At(i).(string))
+				if err != nil {
+					return nil, err
+				}
+				field.Set(i, mergeLabels(rowLabels, labels).String())
+			}
+			continue
+		}
+		field.Labels = mergeLabels(field.Labels, labels)
+	}
+	return frame, nil
+}
+
+func (p *LabelsFromPathFrameProcessor) match(channel string) (data.Labels, bool) {
+	parts := strings.Split(channel, "/")
+	labels := data.Labels{}
+	for i, segment := range p.segments {
+		if strings.HasPrefix(segment, "*") {
+			if i >= len(parts) {
+				return nil, false
+			}
+			labels[segment[1:]] = strings.Join(parts[i:], "/")
+			return labels, true
+		}
+		if i >= len(parts) {
+			return nil, false
+		}
+		if strings.HasPrefix(segment, ":") {
+			labels[segment[1:]] = parts[i]
+		} else if segment != parts[i] {
+			return nil, false
+		}
+	}
+	return labels, len(parts) == len(p.segments)
+}
+
+// mergeLabels returns a copy of labels with extra labels added, existing labels are kept.
+func mergeLabels(labels data.Labels, extra data.Labels) data.Labels {
+	merged := labels.Copy()
+	for k, v := range extra {
+		if _, ok := merged[k]; !ok {
+			merged[k] = v
+		}
+	}
+	return merged
+}
@@ -0,0 +1,46 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelsFromPathFrameProcessor(t *testing.T) {
+	newFrame := func() *data.Frame {
+		return data.NewFrame("cpu",
+			data.NewField("labels", nil, []string{"cpu=cpu0", "cpu=cpu1, host=other"}),
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(1, 0)}),
+			data.NewField("usage", data.Labels{"mode": "user"}, []float64{1, 2}),
+		)
+	}
+
+	t.Run("named segments", func(t *testing.T) {
+		p := NewLabelsFromPathFrameProcessor(LabelsFromPathFrameProcessorConfig{Pattern: "stream/telegraf/:host/:metric"})
+		frame, err := p.ProcessFrame(context.Background(), Vars{Channel: "stream/telegraf/server1/cpu"}, newFrame())
+		require.NoError(t, err)
+		require.Equal(t, "cpu=cpu0, host=server1, metric=cpu", frame.Fields[0].At(0))
+		// existing labels are kept
+		require.Equal(t, "cpu=cpu1, host=other, metric=cpu", frame.Fields[0].At(1))
+		require.Nil(t, frame.Fields[1].Labels)
+		require.Equal(t, data.Labels{"mode": "user", "host": "server1", "metric": "cpu"}, frame.Fields[2].Labels)
+	})
+
+	t.Run("catch all segment", func(t *testing.T) {
+		p := NewLabelsFromPathFrameProcessor(LabelsFromPathFrameProcessorConfig{Pattern: "stream/telegraf/*path"})
+		frame, err := p.ProcessFrame(context.Background(), Vars{Channel: "stream/telegraf/server1/cpu"}, newFrame())
+		require.NoError(t, err)
+		require.Equal(t, "server1/cpu", frame.Fields[2].Labels["path"])
+	})
+
+	t.Run("channel not matching", func(t *testing.T) {
+		p := NewLabelsFromPathFrameProcessor(LabelsFromPathFrameProcessorConfig{Pattern: "stream/influx/:host/:metric"})
+		frame, err := p.ProcessFrame(context.Background(), Vars{Channel: "stream/telegraf/server1/cpu"}, newFrame())
+		require.NoError(t, err)
+		require.Equal(t, "cpu=cpu0", frame.Fields[0].At(0))
+		require.Equal(t, data.Labels{"mode": "user"}, frame.Fields[2].Labels)
+	})
+}
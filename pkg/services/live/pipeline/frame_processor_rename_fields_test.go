@@ -0,0 +1,23 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameFieldsFrameProcessor(t *testing.T) {
+	p := NewRenameFieldsFrameProcessor(RenameFieldsFrameProcessorConfig{
+		Renames: map[string]string{"usage_idle": "idle", "missing": "x"},
+	})
+	frame := data.NewFrame("cpu",
+		data.NewField("usage_idle", nil, []float64{1}),
+		data.NewField("usage_user", nil, []float64{2}),
+	)
+	frame, err := p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Equal(t, "idle", frame.Fields[0].Name)
+	require.Equal(t, "usage_user", frame.Fields[1].Name)
+}
@@ -1,6 +1,7 @@
 package pipeline
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/services/live/pipeline/pattern"
@@ -146,6 +147,15 @@ func checkRulesValid(orgID int64, rules []ChannelRule) (ok bool, reason string)
 	return ok, reason
 }
 
+// CheckChannelRulesValid checks that patterns of rules of an org can be used together.
+func CheckChannelRulesValid(orgID int64, rules []ChannelRule) error {
+	ok, reason := checkRulesValid(orgID, rules)
+	if !ok {
+		return errors.New(reason)
+	}
+	return nil
+}
+
 type ChannelRuleCreateCmd struct {
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
@@ -235,6 +235,48 @@ func (p *Pipeline) ProcessInput(ctx context.Context, orgID int64, channelID stri
 	return ok, err
 }
 
+// ProcessFrames passes frames which were already converted, for example by push endpoints
+// of other protocols, through the rules of their channels. Returns false if none of the
+// frame channels has a rule.
+func (p *Pipeline) ProcessFrames(ctx context.Context, orgID int64, channelID string, channelFrames []*ChannelFrame) (bool, error) {
+	var span trace.Span
+	if p.tracer != nil {
+		ctx, span = p.tracer.Start(ctx, "live.pipeline.process_frames")
+		span.SetAttributes(
+			attribute.Int64("orgId", orgID),
+			attribute.String("channel", channelID),
+			attribute.Int("frames", len(channelFrames)),
+		)
+		defer span.End()
+	}
+	ruleFound := false
+	for _, channelFrame := range channelFrames {
+		frameChannel := channelID
+		if channelFrame.Channel != "" {
+			frameChannel = channelFrame.Channel
+		}
+		_, ok, err := p.ruleGetter.Get(orgID, frameChannel)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			ruleFound = true
+			break
+		}
+	}
+	if !ruleFound {
+		return false, nil
+	}
+	err := p.processChannelFrames(ctx, orgID, channelID, channelFrames, nil)
+	if err != nil {
+		if p.tracer != nil && span != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return false, fmt.Errorf("error processing frame: %w", err)
+	}
+	return true, nil
+}
+
 func (p *Pipeline) processInput(ctx context.Context, orgID int64, channelID string, body []byte, visitedChannels map[string]struct{}) (bool, error) {
 	var span trace.Span
 	if p.tracer != nil {
@@ -434,6 +476,50 @@ func (p *Pipeline) processFrame(ctx context.Context, orgID int64, channelID stri
 	return nil, nil
 }
 
+// ApplyFrameProcessors passes each channel frame through the frame processors of its
+// channel rule without outputting it, frames dropped by a processor are omitted.
+// It's used to test channel rules.
+func (p *Pipeline) ApplyFrameProcessors(ctx context.Context, orgID int64, channelID string, channelFrames []*ChannelFrame) ([]*ChannelFrame, error) {
+	processed := make([]*ChannelFrame, 0, len(channelFrames))
+	for _, channelFrame := range channelFrames {
+		frameChannel := channelID
+		if channelFrame.Channel != "" {
+			frameChannel = channelFrame.Channel
+		}
+		rule, ok, err := p.ruleGetter.Get(orgID, frameChannel)
+		if err != nil {
+			return nil, err
+		}
+		frame := channelFrame.Frame
+		if ok {
+			ch, err := live.ParseChannel(frameChannel)
+			if err != nil {
+				return nil, err
+			}
+			vars := Vars{
+				OrgID:     orgID,
+				Channel:   frameChannel,
+				Scope:     ch.Scope,
+				Namespace: ch.Namespace,
+				Path:      ch.Path,
+			}
+			for _, proc := range rule.FrameProcessors {
+				frame, err = p.execProcessor(ctx, proc, vars, frame)
+				if err != nil {
+					return nil, err
+				}
+				if frame == nil {
+					break
+				}
+			}
+		}
+		if frame != nil {
+			processed = append(processed, &ChannelFrame{Channel: channelFrame.Channel, Frame: frame})
+		}
+	}
+	return processed, nil
+}
+
 func (p *Pipeline) execProcessor(ctx context.Context, proc FrameProcessor, vars Vars, frame *data.Frame) (*data.Frame, error) {
 	var span trace.Span
 	if p.tracer != nil {
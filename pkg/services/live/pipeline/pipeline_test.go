@@ -142,3 +142,69 @@ func TestPipeline_Recursion(t *testing.T) {
 	_, err = p.ProcessInput(context.Background(), 1, "stream/test/xxx", []byte(`{}`))
 	require.ErrorIs(t, err, errChannelRecursion)
 }
+
+func TestPipeline_ProcessFrames(t *testing.T) {
+	outputter := &testOutputter{}
+	p, err := New(&testRuleGetter{
+		rules: map[string]*LiveChannelRule{
+			"stream/test/metrics/cpu": {
+				FrameOutputters: []FrameOutputter{outputter},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	frame := data.NewFrame("cpu", data.NewField("value", nil, []float64{1}))
+	ok, err := p.ProcessFrames(context.Background(), 1, "stream/test/metrics", []*ChannelFrame{
+		{Channel: "stream/test/metrics/cpu", Frame: frame},
+		{Channel: "stream/test/metrics/mem", Frame: data.NewFrame("mem")},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, frame, outputter.frame)
+
+	ok, err = p.ProcessFrames(context.Background(), 1, "stream/test/metrics", []*ChannelFrame{
+		{Channel: "stream/test/metrics/mem", Frame: data.NewFrame("mem")},
+	})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestPipeline_ApplyFrameProcessors(t *testing.T) {
+	builder := &StorageRuleBuilder{}
+	rename, err := builder.extractFrameProcessor(1, "stream/test", &FrameProcessorConfig{
+		Type:                        FrameProcessorTypeRenameFields,
+		RenameFieldsProcessorConfig: &RenameFieldsFrameProcessorConfig{Renames: map[string]string{"usage_idle": "idle"}},
+	})
+	require.NoError(t, err)
+	derived, err := builder.extractFrameProcessor(1, "stream/test", &FrameProcessorConfig{
+		Type:                        FrameProcessorTypeDerivedField,
+		DerivedFieldProcessorConfig: &DerivedFieldFrameProcessorConfig{Name: "busy", Expression: "100 - $idle"},
+	})
+	require.NoError(t, err)
+	_, err = builder.extractFrameProcessor(1, "stream/test", &FrameProcessorConfig{Type: FrameProcessorTypeDownsample})
+	require.Error(t, err)
+
+	outputter := &testOutputter{}
+	p, err := New(&testRuleGetter{
+		rules: map[string]*LiveChannelRule{
+			"stream/test/cpu": {
+				FrameProcessors: []FrameProcessor{rename, derived},
+				FrameOutputters: []FrameOutputter{outputter},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	frames, err := p.ApplyFrameProcessors(context.Background(), 1, "stream/test", []*ChannelFrame{
+		{Channel: "stream/test/cpu", Frame: data.NewFrame("cpu", data.NewField("usage_idle", nil, []float64{75}))},
+		{Channel: "stream/test/mem", Frame: data.NewFrame("mem")},
+	})
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+	require.Equal(t, "idle", frames[0].Frame.Fields[0].Name)
+	require.Equal(t, 25.0, *frames[0].Frame.Fields[1].At(0).(*float64))
+	require.Equal(t, "mem", frames[1].Frame.Name)
+	// frames are not output
+	require.Nil(t, outputter.frame)
+}
@@ -66,6 +66,20 @@ var ConvertersRegistry = []EntityInfo{
 		Type:        ConverterTypeJsonFrame,
 		Description: "JSON-encoded Grafana data frame",
 	},
+	{
+		Type:        ConverterTypePrometheusRemoteWrite,
+		Description: "accept snappy compressed Prometheus remote-write protobuf",
+		Example: PrometheusRemoteWriteConverterConfig{
+			FrameFormat: "labels_column",
+		},
+	},
+	{
+		Type:        ConverterTypeOTLPMetrics,
+		Description: "accept OTLP/HTTP metrics in protobuf or JSON encoding",
+		Example: OTLPMetricsConverterConfig{
+			FrameFormat: "labels_column",
+		},
+	},
 }
 
 var FrameProcessorsRegistry = []EntityInfo{
@@ -79,6 +93,44 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeRenameFields,
+		Description: "rename fields",
+		Example: RenameFieldsFrameProcessorConfig{
+			Renames: map[string]string{"usage_idle": "idle"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeDerivedField,
+		Description: "add a field computed with a math expression",
+		Example: DerivedFieldFrameProcessorConfig{
+			Name:       "used_percent",
+			Expression: "$used / $total * 100",
+			Unit:       "percent",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeConvertFields,
+		Description: "convert field types and units",
+		Example: ConvertFieldsFrameProcessorConfig{
+			Fields: []ConvertFieldConfig{{Name: "duration", FromUnit: "ms", ToUnit: "s"}},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeDownsample,
+		Description: "aggregate rows over a time window",
+		Example: DownsampleFrameProcessorConfig{
+			Window:      "10s",
+			Aggregation: "mean",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeLabelsFromPath,
+		Description: "add labels from channel path segments",
+		Example: LabelsFromPathFrameProcessorConfig{
+			Pattern: "stream/telegraf/:host/:metric",
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
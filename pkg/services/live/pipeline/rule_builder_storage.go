@@ -14,6 +14,7 @@ type StorageRuleBuilder struct {
 	Node                 *centrifuge.Node
 	ManagedStream        *managedstream.Runner
 	FrameStorage         *FrameStorage
+	DownsampleStorage    *DownsampleStorage
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
@@ -69,12 +70,22 @@ func (f *StorageRuleBuilder) extractConverter(config *ConverterConfig) (Converte
 			return nil, missingConfiguration
 		}
 		return NewAutoInfluxConverter(*config.AutoInfluxConverterConfig), nil
+	case ConverterTypePrometheusRemoteWrite:
+		if config.PrometheusRemoteWriteConverterConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewPrometheusRemoteWriteConverter(*config.PrometheusRemoteWriteConverterConfig), nil
+	case ConverterTypeOTLPMetrics:
+		if config.OTLPMetricsConverterConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewOTLPMetricsConverter(*config.OTLPMetricsConverterConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown converter type: %s", config.Type)
 	}
 }
 
-func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig) (FrameProcessor, error) {
+func (f *StorageRuleBuilder) extractFrameProcessor(orgID int64, pattern string, config *FrameProcessorConfig) (FrameProcessor, error) {
 	if config == nil {
 		return nil, nil
 	}
@@ -97,13 +108,41 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 		var processors []FrameProcessor
 		for _, outConf := range config.MultipleProcessorConfig.Processors {
 			out := outConf
-			proc, err := f.extractFrameProcessor(&out)
+			proc, err := f.extractFrameProcessor(orgID, pattern, &out)
 			if err != nil {
 				return nil, err
 			}
 			processors = append(processors, proc)
 		}
 		return NewMultipleFrameProcessor(processors...), nil
+	case FrameProcessorTypeRenameFields:
+		if config.RenameFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewRenameFieldsFrameProcessor(*config.RenameFieldsProcessorConfig), nil
+	case FrameProcessorTypeDerivedField:
+		if config.DerivedFieldProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewDerivedFieldFrameProcessor(*config.DerivedFieldProcessorConfig)
+	case FrameProcessorTypeConvertFields:
+		if config.ConvertFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewConvertFieldsFrameProcessor(*config.ConvertFieldsProcessorConfig)
+	case FrameProcessorTypeDownsample:
+		if config.DownsampleProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		if f.DownsampleStorage == nil {
+			return NewDownsampleFrameProcessor(*config.DownsampleProcessorConfig)
+		}
+		return f.DownsampleStorage.GetOrCreate(orgID, pattern, *config.DownsampleProcessorConfig)
+	case FrameProcessorTypeLabelsFromPath:
+		if config.LabelsFromPathProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewLabelsFromPathFrameProcessor(*config.LabelsFromPathProcessorConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown processor type: %s", config.Type)
 	}
@@ -311,70 +350,98 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 	}
 
 	rules := make([]*LiveChannelRule, 0, len(channelRules))
+	var allProcessors []FrameProcessor
 
 	for _, ruleConfig := range channelRules {
-		rule := &LiveChannelRule{
-			OrgId:   orgID,
-			Pattern: ruleConfig.Pattern,
+		rule, err := f.buildRule(orgID, ruleConfig, writeConfigs)
+		if err != nil {
+			return nil, err
 		}
+		allProcessors = append(allProcessors, rule.FrameProcessors...)
+		rules = append(rules, rule)
+	}
 
-		if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Subscribe != nil {
-			rule.SubscribeAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Subscribe.RequireRole)
-		}
+	if f.DownsampleStorage != nil {
+		f.DownsampleStorage.Retain(orgID, allProcessors)
+	}
 
-		if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Publish != nil {
-			rule.PublishAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Publish.RequireRole)
-		}
+	return rules, nil
+}
 
-		var err error
+// ValidateRule checks the converter, processors, outputters and subscribers of the rule
+// can be built with the write configs of the org.
+func (f *StorageRuleBuilder) ValidateRule(ctx context.Context, orgID int64, ruleConfig ChannelRule) error {
+	writeConfigs, err := f.Storage.ListWriteConfigs(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	// Processors of rules which are only validated must not be kept.
+	builder := *f
+	builder.DownsampleStorage = nil
+	_, err = builder.buildRule(orgID, ruleConfig, writeConfigs)
+	return err
+}
 
-		rule.Converter, err = f.extractConverter(ruleConfig.Settings.Converter)
-		if err != nil {
-			return nil, fmt.Errorf("error building converter for %s: %w", rule.Pattern, err)
-		}
+func (f *StorageRuleBuilder) buildRule(orgID int64, ruleConfig ChannelRule, writeConfigs []WriteConfig) (*LiveChannelRule, error) {
+	rule := &LiveChannelRule{
+		OrgId:   orgID,
+		Pattern: ruleConfig.Pattern,
+	}
 
-		var processors []FrameProcessor
-		for _, procConfig := range ruleConfig.Settings.FrameProcessors {
-			proc, err := f.extractFrameProcessor(procConfig)
-			if err != nil {
-				return nil, fmt.Errorf("error building processor for %s: %w", rule.Pattern, err)
-			}
-			processors = append(processors, proc)
-		}
-		rule.FrameProcessors = processors
+	if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Subscribe != nil {
+		rule.SubscribeAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Subscribe.RequireRole)
+	}
 
-		var dataOutputters []DataOutputter
-		for _, outConfig := range ruleConfig.Settings.DataOutputters {
-			out, err := f.extractDataOutputter(outConfig, writeConfigs)
-			if err != nil {
-				return nil, fmt.Errorf("error building data outputter for %s: %w", rule.Pattern, err)
-			}
-			dataOutputters = append(dataOutputters, out)
+	if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Publish != nil {
+		rule.PublishAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Publish.RequireRole)
+	}
+
+	var err error
+
+	rule.Converter, err = f.extractConverter(ruleConfig.Settings.Converter)
+	if err != nil {
+		return nil, fmt.Errorf("error building converter for %s: %w", rule.Pattern, err)
+	}
+
+	var processors []FrameProcessor
+	for _, procConfig := range ruleConfig.Settings.FrameProcessors {
+		proc, err := f.extractFrameProcessor(orgID, rule.Pattern, procConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error building processor for %s: %w", rule.Pattern, err)
 		}
-		rule.DataOutputters = dataOutputters
+		processors = append(processors, proc)
+	}
+	rule.FrameProcessors = processors
 
-		var outputters []FrameOutputter
-		for _, outConfig := range ruleConfig.Settings.FrameOutputters {
-			out, err := f.extractFrameOutputter(outConfig, writeConfigs)
-			if err != nil {
-				return nil, fmt.Errorf("error building frame outputter for %s: %w", rule.Pattern, err)
-			}
-			outputters = append(outputters, out)
+	var dataOutputters []DataOutputter
+	for _, outConfig := range ruleConfig.Settings.DataOutputters {
+		out, err := f.extractDataOutputter(outConfig, writeConfigs)
+		if err != nil {
+			return nil, fmt.Errorf("error building data outputter for %s: %w", rule.Pattern, err)
 		}
-		rule.FrameOutputters = outputters
+		dataOutputters = append(dataOutputters, out)
+	}
+	rule.DataOutputters = dataOutputters
 
-		var subscribers []Subscriber
-		for _, subConfig := range ruleConfig.Settings.Subscribers {
-			sub, err := f.extractSubscriber(subConfig)
-			if err != nil {
-				return nil, fmt.Errorf("error building subscriber for %s: %w", rule.Pattern, err)
-			}
-			subscribers = append(subscribers, sub)
+	var outputters []FrameOutputter
+	for _, outConfig := range ruleConfig.Settings.FrameOutputters {
+		out, err := f.extractFrameOutputter(outConfig, writeConfigs)
+		if err != nil {
+			return nil, fmt.Errorf("error building frame outputter for %s: %w", rule.Pattern, err)
 		}
-		rule.Subscribers = subscribers
+		outputters = append(outputters, out)
+	}
+	rule.FrameOutputters = outputters
 
-		rules = append(rules, rule)
+	var subscribers []Subscriber
+	for _, subConfig := range ruleConfig.Settings.Subscribers {
+		sub, err := f.extractSubscriber(subConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error building subscriber for %s: %w", rule.Pattern, err)
+		}
+		subscribers = append(subscribers, sub)
 	}
+	rule.Subscribers = subscribers
 
-	return rules, nil
+	return rule, nil
 }
@@ -0,0 +1,123 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+)
+
+// ErrProvisionedChannelRule is returned when a provisioned channel rule is changed over API.
+var ErrProvisionedChannelRule = errors.New("channel rule is provisioned and can't be changed")
+
+// ProvisionedStorage adds channel rules provisioned from files to rules of the
+// underlying Storage. Provisioned rules take precedence over stored rules with the
+// same pattern and can only be changed by changing provisioning files.
+type ProvisionedStorage struct {
+	Storage
+
+	mu    sync.RWMutex
+	rules []ChannelRule
+}
+
+// NewProvisionedStorage ...
+func NewProvisionedStorage(storage Storage) *ProvisionedStorage {
+	return &ProvisionedStorage{Storage: storage}
+}
+
+// SetProvisionedChannelRules replaces all provisioned channel rules.
+func (s *ProvisionedStorage) SetProvisionedChannelRules(rules []ChannelRule) {
+	provisioned := make([]ChannelRule, 0, len(rules))
+	for _, r := range rules {
+		r.Provisioned = true
+		provisioned = append(provisioned, r)
+	}
+	s.mu.Lock()
+	s.rules = provisioned
+	s.mu.Unlock()
+}
+
+func (s *ProvisionedStorage) provisionedRules(orgID int64) []ChannelRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var rules []ChannelRule
+	for _, r := range s.rules {
+		if r.OrgId == orgID || (orgID == 1 && r.OrgId == 0) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+func (s *ProvisionedStorage) isProvisioned(orgID int64, pattern string) bool {
+	for _, r := range s.provisionedRules(orgID) {
+		if patternMatch(orgID, pattern, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ProvisionedStorage) ListChannelRules(ctx context.Context, orgID int64) ([]ChannelRule, error) {
+	rules := s.provisionedRules(orgID)
+	storedRules, err := s.Storage.ListChannelRules(ctx, orgID)
+	if err != nil {
+		// Provisioned rules are enough to run the pipeline without a rules file.
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	for _, r := range storedRules {
+		if !s.isProvisioned(orgID, r.Pattern) {
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+func (s *ProvisionedStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]WriteConfig, error) {
+	writeConfigs, err := s.Storage.ListWriteConfigs(ctx, orgID)
+	if err != nil {
+		// Provisioned rules without outputs to remote write don't need a write configs file.
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return writeConfigs, nil
+}
+
+// checkProvisionedConflict makes sure a stored rule neither replaces a provisioned
+// rule nor conflicts with patterns of provisioned rules.
+func (s *ProvisionedStorage) checkProvisionedConflict(orgID int64, rule ChannelRule) error {
+	if s.isProvisioned(orgID, rule.Pattern) {
+		return ErrProvisionedChannelRule
+	}
+	rule.OrgId = orgID
+	ok, reason := checkRulesValid(orgID, append(s.provisionedRules(orgID), rule))
+	if !ok {
+		return fmt.Errorf("channel rule conflicts with provisioned rules: %s", reason)
+	}
+	return nil
+}
+
+func (s *ProvisionedStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+	if err := s.checkProvisionedConflict(orgID, ChannelRule{Pattern: cmd.Pattern, Settings: cmd.Settings}); err != nil {
+		return ChannelRule{}, err
+	}
+	return s.Storage.CreateChannelRule(ctx, orgID, cmd)
+}
+
+func (s *ProvisionedStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+	if err := s.checkProvisionedConflict(orgID, ChannelRule{Pattern: cmd.Pattern, Settings: cmd.Settings}); err != nil {
+		return ChannelRule{}, err
+	}
+	return s.Storage.UpdateChannelRule(ctx, orgID, cmd)
+}
+
+func (s *ProvisionedStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error {
+	if s.isProvisioned(orgID, cmd.Pattern) {
+		return ErrProvisionedChannelRule
+	}
+	return s.Storage.DeleteChannelRule(ctx, orgID, cmd)
+}
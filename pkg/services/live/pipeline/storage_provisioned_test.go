@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvisionedStorage(t *testing.T) {
+	ctx := context.Background()
+	dataPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataPath, "pipeline"), 0750))
+
+	s := NewProvisionedStorage(&FileStorage{DataPath: dataPath})
+
+	// Provisioned rules are listed without a rules file.
+	s.SetProvisionedChannelRules([]ChannelRule{
+		{OrgId: 1, Pattern: "stream/provisioned"},
+		{OrgId: 2, Pattern: "stream/other"},
+	})
+	rules, err := s.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, "stream/provisioned", rules[0].Pattern)
+	require.True(t, rules[0].Provisioned)
+
+	// Provisioned rules are built without a write configs file.
+	writeConfigs, err := s.ListWriteConfigs(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, writeConfigs)
+	builder := &StorageRuleBuilder{Storage: s}
+	builtRules, err := builder.BuildRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, builtRules, 1)
+	require.NoError(t, builder.ValidateRule(ctx, 1, ChannelRule{Pattern: "stream/valid"}))
+	require.Error(t, builder.ValidateRule(ctx, 1, ChannelRule{Pattern: "stream/invalid", Settings: ChannelRuleSettings{
+		FrameProcessors: []*FrameProcessorConfig{{Type: FrameProcessorTypeDownsample}},
+	}}))
+
+	// Provisioned rules can't be changed.
+	_, err = s.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/provisioned"})
+	require.ErrorIs(t, err, ErrProvisionedChannelRule)
+	_, err = s.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/provisioned"})
+	require.ErrorIs(t, err, ErrProvisionedChannelRule)
+	err = s.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/provisioned"})
+	require.ErrorIs(t, err, ErrProvisionedChannelRule)
+
+	// Other orgs are not affected by provisioned rules.
+	_, err = s.CreateChannelRule(ctx, 2, ChannelRuleCreateCmd{Pattern: "stream/provisioned"})
+	require.NotErrorIs(t, err, ErrProvisionedChannelRule)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dataPath, "pipeline", "live-channel-rules.json"), []byte(`{"rules":[]}`), 0600))
+	_, err = s.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/stored"})
+	require.NoError(t, err)
+	rules, err = s.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, "stream/stored", rules[1].Pattern)
+	require.False(t, rules[1].Provisioned)
+
+	// Provisioned rule replaces stored rule with the same pattern.
+	s.SetProvisionedChannelRules([]ChannelRule{
+		{OrgId: 1, Pattern: "stream/stored"},
+	})
+	rules, err = s.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.True(t, rules[0].Provisioned)
+}
@@ -1,6 +1,7 @@
 package pushhttp
 
 import (
+	"compress/gzip"
 	"context"
 	"errors"
 	"io"
@@ -12,7 +13,9 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/live"
 	"github.com/grafana/grafana/pkg/services/live/convert"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
 	"github.com/grafana/grafana/pkg/services/live/pushurl"
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -21,6 +24,11 @@ var (
 	logger = log.New("live.push_http")
 )
 
+// maxPushBodySize is the maximum size of a pipeline push body, after decompression.
+const maxPushBodySize = telemetry.MaxBodySize
+
+var errBodyTooLarge = telemetry.ErrBodyTooLarge
+
 func ProvideService(cfg *setting.Cfg, live *live.GrafanaLive) *Gateway {
 	logger.Info("Live Push Gateway initialization")
 	g := &Gateway{
@@ -131,3 +139,106 @@ func (g *Gateway) HandlePipelinePush(ctx *contextmodel.ReqContext) {
 
 	ctx.Resp.WriteHeader(http.StatusOK)
 }
+
+// HandlePrometheusRemoteWritePush accepts snappy compressed Prometheus remote-write requests
+// and passes the frame of each metric through the rule of channel + / + <metric_name>.
+func (g *Gateway) HandlePrometheusRemoteWritePush(ctx *contextmodel.ReqContext) {
+	g.handlePipelineFramesPush(ctx, "prometheus", g.converter.ConvertPrometheusRemoteWrite)
+}
+
+// HandleOTLPMetricsPush accepts OTLP/HTTP metrics export requests, protobuf or JSON encoded,
+// and passes the frame of each metric through the rule of channel + / + <metric_name>.
+func (g *Gateway) HandleOTLPMetricsPush(ctx *contextmodel.ReqContext) {
+	g.handlePipelineFramesPush(ctx, "otlp", g.converter.ConvertOTLPMetrics)
+}
+
+func (g *Gateway) handlePipelineFramesPush(ctx *contextmodel.ReqContext, protocol string, convertFunc func([]byte, string) ([]telemetry.FrameWrapper, error)) {
+	if g.GrafanaLive.Pipeline == nil {
+		ctx.Resp.WriteHeader(http.StatusNotFound)
+		return
+	}
+	channelID := web.Params(ctx.Req)["*"]
+	if _, err := liveDto.ParseChannel(channelID); err != nil {
+		logger.Error("Invalid channel", "error", err, "channel", channelID)
+		ctx.Resp.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	frameFormat := pushurl.FrameFormatFromValues(ctx.Req.URL.Query())
+
+	body, err := readBody(ctx.Req)
+	if err != nil {
+		logger.Error("Error reading body", "error", err)
+		if errors.Is(err, errBodyTooLarge) {
+			ctx.Resp.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			ctx.Resp.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+	logger.Debug("Live channel push request",
+		"protocol", protocol,
+		"channel", channelID,
+		"bodyLength", len(body),
+		"frameFormat", frameFormat,
+	)
+
+	metricFrames, err := convertFunc(body, frameFormat)
+	if err != nil {
+		// Decoding errors are caused by the client, remote-write senders don't retry on 4xx.
+		logger.Error("Error converting metrics", "error", err, "protocol", protocol, "frameFormat", frameFormat)
+		if errors.Is(err, errBodyTooLarge) {
+			ctx.Resp.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			ctx.Resp.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+
+	// Metric names which only differ in characters not allowed in channel paths map to
+	// the same channel, the pipeline would reject the second frame as a recursion.
+	keys := make(map[string]struct{}, len(metricFrames))
+	for _, mf := range metricFrames {
+		if _, ok := keys[mf.Key()]; ok {
+			logger.Error("Metric names map to the same channel", "protocol", protocol, "channel", channelID, "key", mf.Key())
+			ctx.Resp.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		keys[mf.Key()] = struct{}{}
+	}
+
+	ruleFound, err := g.GrafanaLive.Pipeline.ProcessFrames(ctx.Req.Context(), ctx.OrgID, channelID, pipeline.FrameWrappersToChannelFrames(channelID, metricFrames))
+	if err != nil {
+		logger.Error("Pipeline frames processing error", "error", err, "protocol", protocol, "channel", channelID)
+		ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !ruleFound && len(metricFrames) > 0 {
+		logger.Error("No rule for channel frames", "protocol", protocol, "channel", channelID)
+		ctx.Resp.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	ctx.Resp.WriteHeader(http.StatusOK)
+}
+
+// readBody reads the request body, decompressing it if it's gzip encoded. Bodies larger
+// than maxPushBodySize after decompression are rejected with errBodyTooLarge.
+func readBody(r *http.Request) ([]byte, error) {
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gz.Close() }()
+		reader = gz
+	}
+	body, err := io.ReadAll(io.LimitReader(reader, maxPushBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxPushBodySize {
+		return nil, errBodyTooLarge
+	}
+	return body, nil
+}
@@ -0,0 +1,159 @@
+package pushhttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/services/live/convert"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const otlpCPUBody = `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[
+	{"name":"cpu","gauge":{"dataPoints":[{"timeUnixNano":"1000000000","asDouble":1}]}}
+]}]}]}`
+
+type testRuleGetter struct {
+	rules map[string]*pipeline.LiveChannelRule
+}
+
+func (t *testRuleGetter) Get(_ int64, channel string) (*pipeline.LiveChannelRule, bool, error) {
+	rule, ok := t.rules[channel]
+	return rule, ok, nil
+}
+
+type testOutputter struct {
+	mu     sync.Mutex
+	frames []*data.Frame
+}
+
+func (t *testOutputter) Type() string {
+	return "test"
+}
+
+func (t *testOutputter) OutputFrame(_ context.Context, _ pipeline.Vars, frame *data.Frame) ([]*pipeline.ChannelFrame, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.frames = append(t.frames, frame)
+	return nil, nil
+}
+
+func newTestGateway(t *testing.T, rules map[string]*pipeline.LiveChannelRule) *Gateway {
+	t.Helper()
+	p, err := pipeline.New(&testRuleGetter{rules: rules})
+	require.NoError(t, err)
+	return &Gateway{
+		GrafanaLive: &live.GrafanaLive{Pipeline: p},
+		converter:   convert.NewConverter(),
+	}
+}
+
+func doOTLPPush(g *Gateway, channel string, body []byte, headers map[string]string) int {
+	return doPush(g.HandleOTLPMetricsPush, "/api/live/pipeline/push/otlp/", channel, body, headers)
+}
+
+func doPush(handler func(*contextmodel.ReqContext), path string, channel string, body []byte, headers map[string]string) int {
+	req := httptest.NewRequest(http.MethodPost, path+channel, bytes.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req = web.SetURLParams(req, map[string]string{"*": channel})
+	rec := httptest.NewRecorder()
+	ctx := &contextmodel.ReqContext{
+		Context:      &web.Context{Req: req, Resp: web.NewResponseWriter(req.Method, rec)},
+		SignedInUser: &user.SignedInUser{OrgID: 1},
+	}
+	handler(ctx)
+	return rec.Code
+}
+
+func TestHandleOTLPMetricsPush(t *testing.T) {
+	t.Run("Should pass the metrics through the channel rules", func(t *testing.T) {
+		outputter := &testOutputter{}
+		g := newTestGateway(t, map[string]*pipeline.LiveChannelRule{
+			"stream/test/push/cpu": {FrameOutputters: []pipeline.FrameOutputter{outputter}},
+		})
+
+		require.Equal(t, http.StatusOK, doOTLPPush(g, "stream/test/push", []byte(otlpCPUBody), nil))
+		require.Len(t, outputter.frames, 1)
+		require.Equal(t, "cpu", outputter.frames[0].Name)
+	})
+
+	t.Run("Should accept gzip encoded bodies", func(t *testing.T) {
+		outputter := &testOutputter{}
+		g := newTestGateway(t, map[string]*pipeline.LiveChannelRule{
+			"stream/test/push/cpu": {FrameOutputters: []pipeline.FrameOutputter{outputter}},
+		})
+
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(otlpCPUBody))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		require.Equal(t, http.StatusOK, doOTLPPush(g, "stream/test/push", buf.Bytes(), map[string]string{"Content-Encoding": "gzip"}))
+		require.Len(t, outputter.frames, 1)
+	})
+
+	t.Run("Should return 404 when the pipeline is disabled", func(t *testing.T) {
+		g := &Gateway{GrafanaLive: &live.GrafanaLive{}, converter: convert.NewConverter()}
+		require.Equal(t, http.StatusNotFound, doOTLPPush(g, "stream/test/push", []byte(otlpCPUBody), nil))
+	})
+
+	t.Run("Should return 404 when no channel has a rule", func(t *testing.T) {
+		g := newTestGateway(t, map[string]*pipeline.LiveChannelRule{})
+		require.Equal(t, http.StatusNotFound, doOTLPPush(g, "stream/test/push", []byte(otlpCPUBody), nil))
+	})
+
+	t.Run("Should return 400 when metric names map to the same channel", func(t *testing.T) {
+		g := newTestGateway(t, map[string]*pipeline.LiveChannelRule{
+			"stream/test/push/cpu_total": {FrameOutputters: []pipeline.FrameOutputter{&testOutputter{}}},
+		})
+		body := `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[
+			{"name":"cpu/total","gauge":{"dataPoints":[{"timeUnixNano":"1000000000","asDouble":1}]}},
+			{"name":"cpu:total","gauge":{"dataPoints":[{"timeUnixNano":"1000000000","asDouble":2}]}}
+		]}]}]}`
+		require.Equal(t, http.StatusBadRequest, doOTLPPush(g, "stream/test/push", []byte(body), nil))
+	})
+
+	t.Run("Should return 413 when the decompressed body is too large", func(t *testing.T) {
+		g := newTestGateway(t, map[string]*pipeline.LiveChannelRule{})
+
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(strings.Repeat(" ", maxPushBodySize+1)))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		require.Equal(t, http.StatusRequestEntityTooLarge, doOTLPPush(g, "stream/test/push", buf.Bytes(), map[string]string{"Content-Encoding": "gzip"}))
+	})
+}
+
+func TestHandlePrometheusRemoteWritePush(t *testing.T) {
+	t.Run("Should return 413 when the decoded body would be too large", func(t *testing.T) {
+		g := newTestGateway(t, map[string]*pipeline.LiveChannelRule{})
+
+		// A snappy body starts with its decoded length, this one declares 4GiB without any data.
+		body := binary.AppendUvarint(nil, 1<<32-1)
+
+		require.Equal(t, http.StatusRequestEntityTooLarge, doPush(g.HandlePrometheusRemoteWritePush, "/api/live/pipeline/push/prometheus/", "stream/test/push", body, nil))
+	})
+
+	t.Run("Should return 400 when the body is not snappy encoded", func(t *testing.T) {
+		g := newTestGateway(t, map[string]*pipeline.LiveChannelRule{})
+		require.Equal(t, http.StatusBadRequest, doPush(g.HandlePrometheusRemoteWritePush, "/api/live/pipeline/push/prometheus/", "stream/test/push", []byte{0xff}, nil))
+	})
+}
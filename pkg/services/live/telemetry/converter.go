@@ -1,6 +1,16 @@
 package telemetry
 
-import "github.com/grafana/grafana-plugin-sdk-go/data"
+import (
+	"errors"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// MaxBodySize is the maximum size of a metrics request body, after decompression.
+const MaxBodySize = 32 << 20
+
+// ErrBodyTooLarge is returned when a request body is larger than MaxBodySize after decompression.
+var ErrBodyTooLarge = errors.New("request body too large")
 
 // Converter can convert input to Grafana Data Frames.
 type Converter interface {
@@ -0,0 +1,160 @@
+package otlp
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+)
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// Converter converts OTLP/HTTP metrics export requests to Grafana frames.
+type Converter struct {
+	useLabelsColumn bool
+}
+
+// ConverterOption ...
+type ConverterOption func(*Converter)
+
+// WithUseLabelsColumn ...
+func WithUseLabelsColumn(enabled bool) ConverterOption {
+	return func(h *Converter) {
+		h.useLabelsColumn = enabled
+	}
+}
+
+// NewConverter creates new Converter from OTLP metrics to Grafana Data Frames.
+// This converter generates one frame for each metric name. Histograms and summaries
+// are converted to _count, _sum, _bucket and quantile series like in Prometheus.
+func NewConverter(opts ...ConverterOption) *Converter {
+	c := &Converter{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Convert metrics export request, both the protobuf and the JSON encodings are supported.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	req := pmetricotlp.NewExportRequest()
+	var err error
+	if isJSON(body) {
+		err = req.UnmarshalJSON(body)
+	} else {
+		err = req.UnmarshalProto(body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	var samples []telemetry.Sample
+	resourceMetrics := req.Metrics().ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		rm := resourceMetrics.At(i)
+		scopeMetrics := rm.ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			metrics := scopeMetrics.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				samples = appendMetricSamples(samples, metrics.At(k), rm.Resource().Attributes())
+			}
+		}
+	}
+	return telemetry.SamplesToFrames(samples, c.useLabelsColumn), nil
+}
+
+func isJSON(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+func appendMetricSamples(samples []telemetry.Sample, m pmetric.Metric, resourceAttrs pcommon.Map) []telemetry.Sample {
+	name := m.Name()
+	sample := func(name string, attrs pcommon.Map, ts pcommon.Timestamp, value float64, extra ...string) telemetry.Sample {
+		sampleLabels := attributesToLabels(resourceAttrs, attrs)
+		for i := 0; i+1 < len(extra); i += 2 {
+			sampleLabels[extra[i]] = extra[i+1]
+		}
+		return telemetry.Sample{Name: name, Labels: sampleLabels, Time: ts.AsTime(), Value: value}
+	}
+
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		return appendNumberSamples(samples, name, m.Gauge().DataPoints(), sample)
+	case pmetric.MetricTypeSum:
+		return appendNumberSamples(samples, name, m.Sum().DataPoints(), sample)
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			samples = append(samples, sample(name+"_count", dp.Attributes(), dp.Timestamp(), float64(dp.Count())))
+			if dp.HasSum() {
+				samples = append(samples, sample(name+"_sum", dp.Attributes(), dp.Timestamp(), dp.Sum()))
+			}
+			// Bucket counts are cumulative in Prometheus, the last bucket has no explicit bound.
+			var cumulative uint64
+			for b := 0; b < dp.BucketCounts().Len(); b++ {
+				cumulative += dp.BucketCounts().At(b)
+				le := "+Inf"
+				if b < dp.ExplicitBounds().Len() {
+					le = strconv.FormatFloat(dp.ExplicitBounds().At(b), 'f', -1, 64)
+				}
+				samples = append(samples, sample(name+"_bucket", dp.Attributes(), dp.Timestamp(), float64(cumulative), "le", le))
+			}
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			samples = append(samples, sample(name+"_count", dp.Attributes(), dp.Timestamp(), float64(dp.Count())))
+			if dp.HasSum() {
+				samples = append(samples, sample(name+"_sum", dp.Attributes(), dp.Timestamp(), dp.Sum()))
+			}
+		}
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			samples = append(samples, sample(name+"_count", dp.Attributes(), dp.Timestamp(), float64(dp.Count())))
+			samples = append(samples, sample(name+"_sum", dp.Attributes(), dp.Timestamp(), dp.Sum()))
+			for q := 0; q < dp.QuantileValues().Len(); q++ {
+				qv := dp.QuantileValues().At(q)
+				samples = append(samples, sample(name, dp.Attributes(), dp.Timestamp(), qv.Value(), "quantile", strconv.FormatFloat(qv.Quantile(), 'f', -1, 64)))
+			}
+		}
+	}
+	return samples
+}
+
+func appendNumberSamples(samples []telemetry.Sample, name string, dps pmetric.NumberDataPointSlice, sample func(string, pcommon.Map, pcommon.Timestamp, float64, ...string) telemetry.Sample) []telemetry.Sample {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.Flags().NoRecordedValue() {
+			continue
+		}
+		value := dp.DoubleValue()
+		if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+			value = float64(dp.IntValue())
+		}
+		samples = append(samples, sample(name, dp.Attributes(), dp.Timestamp(), value))
+	}
+	return samples
+}
+
+// attributesToLabels merges resource and data point attributes, data point attributes take precedence.
+func attributesToLabels(resourceAttrs, attrs pcommon.Map) data.Labels {
+	labels := make(data.Labels, resourceAttrs.Len()+attrs.Len())
+	for _, m := range []pcommon.Map{resourceAttrs, attrs} {
+		m.Range(func(k string, v pcommon.Value) bool {
+			labels[k] = v.AsString()
+			return true
+		})
+	}
+	return labels
+}
@@ -0,0 +1,90 @@
+package prometheus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+)
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// Converter converts Prometheus remote-write requests to Grafana frames.
+type Converter struct {
+	useLabelsColumn bool
+}
+
+// ConverterOption ...
+type ConverterOption func(*Converter)
+
+// WithUseLabelsColumn ...
+func WithUseLabelsColumn(enabled bool) ConverterOption {
+	return func(h *Converter) {
+		h.useLabelsColumn = enabled
+	}
+}
+
+// NewConverter creates new Converter from snappy compressed remote-write protobuf to Grafana Data Frames.
+// This converter generates one frame for each metric name.
+func NewConverter(opts ...ConverterOption) *Converter {
+	c := &Converter{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Convert remote-write request.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	// The decoded length is read from the request, check it before allocating the buffer.
+	decodedLen, err := snappy.DecodedLen(body)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing remote-write request: %w", err)
+	}
+	if decodedLen > telemetry.MaxBodySize {
+		return nil, fmt.Errorf("error decompressing remote-write request: %w", telemetry.ErrBodyTooLarge)
+	}
+	decoded, err := snappy.Decode(nil, body)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing remote-write request: %w", err)
+	}
+	var req prompb.WriteRequest
+	if err := req.Unmarshal(decoded); err != nil {
+		return nil, fmt.Errorf("error parsing remote-write request: %w", err)
+	}
+
+	var samples []telemetry.Sample
+	for _, ts := range req.Timeseries {
+		name := ""
+		seriesLabels := make(data.Labels, len(ts.Labels))
+		for _, l := range ts.Labels {
+			if l.Name == labels.MetricName {
+				name = l.Value
+				continue
+			}
+			seriesLabels[l.Name] = l.Value
+		}
+		if name == "" {
+			return nil, fmt.Errorf("error parsing remote-write request: series without %s label", labels.MetricName)
+		}
+		// Native histograms are not supported, only float samples are converted.
+		for _, s := range ts.Samples {
+			if value.IsStaleNaN(s.Value) {
+				continue
+			}
+			samples = append(samples, telemetry.Sample{
+				Name:   name,
+				Labels: seriesLabels,
+				Time:   time.UnixMilli(s.Timestamp),
+				Value:  s.Value,
+			})
+		}
+	}
+	return telemetry.SamplesToFrames(samples, c.useLabelsColumn), nil
+}
@@ -0,0 +1,111 @@
+package telemetry
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Sample is a single value of a metric series. Protocols like Prometheus remote-write
+// and OTLP send independent samples which are grouped to frames with SamplesToFrames.
+type Sample struct {
+	Name   string
+	Labels data.Labels
+	Time   time.Time
+	Value  float64
+}
+
+type samplesFrame struct {
+	key   string
+	frame *data.Frame
+}
+
+func (s *samplesFrame) Key() string {
+	return s.key
+}
+
+func (s *samplesFrame) Frame() *data.Frame {
+	return s.frame
+}
+
+// SamplesToFrames creates one frame for each metric name, in the order the names appear in input.
+// With labelsColumn the frame has labels, time and value columns with one row per sample, otherwise
+// the frame is wide with a time column and a value field for each series.
+func SamplesToFrames(samples []Sample, labelsColumn bool) []FrameWrapper {
+	var names []string
+	byName := make(map[string][]Sample)
+	for _, s := range samples {
+		if _, ok := byName[s.Name]; !ok {
+			names = append(names, s.Name)
+		}
+		byName[s.Name] = append(byName[s.Name], s)
+	}
+
+	frameWrappers := make([]FrameWrapper, 0, len(names))
+	for _, name := range names {
+		nameSamples := byName[name]
+		sort.SliceStable(nameSamples, func(i, j int) bool {
+			return nameSamples[i].Time.Before(nameSamples[j].Time)
+		})
+		var frame *data.Frame
+		if labelsColumn {
+			frame = labelsColumnFrame(name, nameSamples)
+		} else {
+			frame = wideFrame(name, nameSamples)
+		}
+		frameWrappers = append(frameWrappers, &samplesFrame{key: channelPathSegment(name), frame: frame})
+	}
+	return frameWrappers
+}
+
+func labelsColumnFrame(name string, samples []Sample) *data.Frame {
+	labelsField := data.NewField("labels", nil, make([]string, 0, len(samples)))
+	timeField := data.NewField("time", nil, make([]time.Time, 0, len(samples)))
+	valueField := data.NewField(name, nil, make([]float64, 0, len(samples)))
+	for _, s := range samples {
+		labelsField.Append(s.Labels.String())
+		timeField.Append(s.Time)
+		valueField.Append(s.Value)
+	}
+	return data.NewFrame(name, labelsField, timeField, valueField)
+}
+
+func wideFrame(name string, samples []Sample) *data.Frame {
+	var times []time.Time
+	rows := make(map[int64]int)
+	for _, s := range samples {
+		if _, ok := rows[s.Time.UnixNano()]; !ok {
+			rows[s.Time.UnixNano()] = len(times)
+			times = append(times, s.Time)
+		}
+	}
+
+	fields := []*data.Field{data.NewField("time", nil, times)}
+	series := make(map[string]*data.Field)
+	for _, s := range samples {
+		key := s.Labels.String()
+		field, ok := series[key]
+		if !ok {
+			field = data.NewField(name, s.Labels, make([]*float64, len(times)))
+			series[key] = field
+			fields = append(fields, field)
+		}
+		value := s.Value
+		field.Set(rows[s.Time.UnixNano()], &value)
+	}
+	return data.NewFrame(name, fields...)
+}
+
+// channelPathSegment replaces the characters of a metric name which are not allowed in channel paths.
+func channelPathSegment(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.', r == '=':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,146 @@
+package liverules
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+)
+
+type ruleValidator interface {
+	ValidateChannelRule(ctx context.Context, rule pipeline.ChannelRule) error
+}
+
+type configReader struct {
+	log log.Logger
+	// validator checks the rules can be built by the pipeline, rules are only checked
+	// like rules created over API when it's nil.
+	validator ruleValidator
+}
+
+// provisioningFiles returns sorted names of YAML files in path, missing directory means no files.
+func (cr *configReader) provisioningFiles(path string) ([]string, error) {
+	files, err := os.ReadDir(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, file := range files {
+		if !file.IsDir() && (strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml")) {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// checksum returns a hash of names and contents of provisioning files, it changes
+// when a file is added, changed or removed.
+func (cr *configReader) checksum(path string) (string, error) {
+	names, err := cr.provisioningFiles(path)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, name := range names {
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `path` comes from ps.Cfg.ProvisioningPath
+		content, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return "", err
+		}
+		_, _ = h.Write([]byte(name))
+		_, _ = h.Write(content)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readConfig returns the rules of the provisioning files in path. Files which can't be parsed
+// and invalid rules are logged and skipped, so they don't affect the rules of other files.
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]pipeline.ChannelRule, error) {
+	names, err := cr.provisioningFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read live channel rule provisioning files: %w", err)
+	}
+
+	var rules []pipeline.ChannelRule
+	for _, name := range names {
+		cr.log.Debug("Parsing live channel rule provisioning file", "path", path, "file", name)
+		fileRules, err := cr.parseChannelRulesConfig(filepath.Join(path, name))
+		if err != nil {
+			cr.log.Error("Failed to parse live channel rule provisioning file, skipping it", "path", path, "file", name, "error", err)
+			continue
+		}
+		for _, rule := range fileRules {
+			if rule.OrgId < 1 {
+				rule.OrgId = 1
+			}
+			if err := cr.validateChannelRule(ctx, rule, rules); err != nil {
+				cr.log.Error("Invalid live channel rule, skipping it", "path", path, "file", name, "pattern", rule.Pattern, "orgId", rule.OrgId, "error", err)
+				continue
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (cr *configReader) parseChannelRulesConfig(filename string) ([]pipeline.ChannelRule, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	err = yaml.Unmarshal(yamlFile, &apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	if apiVersion == nil || apiVersion.APIVersion != 1 {
+		return nil, errors.New("unsupported apiVersion, only apiVersion 1 is supported")
+	}
+
+	var v1 *channelRulesAsConfigV1
+	err = yaml.Unmarshal(yamlFile, &v1)
+	if err != nil {
+		return nil, err
+	}
+	return v1.mapToChannelRules()
+}
+
+// validateChannelRule checks a rule the same way as channel rules created over API, that it
+// can be used together with the rules provisioned before it and that the pipeline can build it.
+// A rule the pipeline can't build would stop all channel rules of its org from working.
+func (cr *configReader) validateChannelRule(ctx context.Context, rule pipeline.ChannelRule, previous []pipeline.ChannelRule) error {
+	if ok, reason := rule.Valid(); !ok {
+		return errors.New(reason)
+	}
+	for _, existing := range previous {
+		if existing.OrgId == rule.OrgId && existing.Pattern == rule.Pattern {
+			return fmt.Errorf("channel rule %q provisioned more than once in org %d", rule.Pattern, rule.OrgId)
+		}
+	}
+	if err := pipeline.CheckChannelRulesValid(rule.OrgId, append(previous[:len(previous):len(previous)], rule)); err != nil {
+		return err
+	}
+	if cr.validator == nil {
+		return nil
+	}
+	return cr.validator.ValidateChannelRule(ctx, rule)
+}
@@ -0,0 +1,152 @@
+package liverules
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+)
+
+const (
+	correctProperties = "./testdata/correct-properties"
+	brokenYaml        = "./testdata/broken-yaml"
+	invalidRule       = "./testdata/invalid-rule"
+	duplicatePattern  = "./testdata/duplicate-pattern"
+	emptyFolder       = "./testdata/empty-folder"
+	brokenProcessor   = "./testdata/broken-processor"
+)
+
+func TestConfigReader(t *testing.T) {
+	reader := &configReader{log: log.New("test logger")}
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		t.Setenv("CHANNEL_PATH", "metrics")
+		rules, err := reader.readConfig(context.Background(), correctProperties)
+		require.NoError(t, err)
+		require.Len(t, rules, 3)
+
+		// Files are read in name order.
+		require.Equal(t, "stream/json/metrics", rules[0].Pattern)
+		require.Equal(t, int64(1), rules[0].OrgId)
+		require.Equal(t, pipeline.ConverterTypeJsonAuto, rules[0].Settings.Converter.Type)
+
+		require.Equal(t, "stream/telegraf/cpu", rules[1].Pattern)
+		require.Equal(t, int64(1), rules[1].OrgId)
+		require.Equal(t, "labels_column", rules[1].Settings.Converter.AutoInfluxConverterConfig.FrameFormat)
+		require.Len(t, rules[1].Settings.FrameOutputters, 1)
+		require.Equal(t, pipeline.FrameOutputTypeManagedStream, rules[1].Settings.FrameOutputters[0].Type)
+
+		require.Equal(t, int64(2), rules[2].OrgId)
+	})
+
+	t.Run("Broken yaml should be skipped", func(t *testing.T) {
+		rules, err := reader.readConfig(context.Background(), brokenYaml)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+
+	t.Run("Invalid rule should be skipped", func(t *testing.T) {
+		rules, err := reader.readConfig(context.Background(), invalidRule)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+
+	t.Run("Pattern provisioned twice in org should keep the first rule", func(t *testing.T) {
+		rules, err := reader.readConfig(context.Background(), duplicatePattern)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, "stream/test", rules[0].Pattern)
+	})
+
+	t.Run("Broken file should not drop the rules of other files", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("{ this is: [ not yaml"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("apiVersion: 1\nrules:\n  - pattern: stream/test/b\n  - pattern: /stream/test\n"), 0600))
+
+		rules, err := reader.readConfig(context.Background(), dir)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, "stream/test/b", rules[0].Pattern)
+	})
+
+	t.Run("Rules the pipeline can't build should be skipped", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger"), validator: newRuleBuilderValidator(t)}
+		rules, err := reader.readConfig(context.Background(), brokenProcessor)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, "stream/test/valid", rules[0].Pattern)
+	})
+
+	t.Run("Empty or missing folder has no rules", func(t *testing.T) {
+		rules, err := reader.readConfig(context.Background(), emptyFolder)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+
+		rules, err = reader.readConfig(context.Background(), "./testdata/missing")
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+}
+
+type fakeRulesSetter struct {
+	rules []pipeline.ChannelRule
+	calls int
+}
+
+func (s *fakeRulesSetter) SetProvisionedChannelRules(rules []pipeline.ChannelRule) {
+	s.rules = rules
+	s.calls++
+}
+
+func (s *fakeRulesSetter) ValidateChannelRule(_ context.Context, _ pipeline.ChannelRule) error {
+	return nil
+}
+
+// ruleBuilderValidator validates rules with the rule builder of the pipeline.
+type ruleBuilderValidator struct {
+	builder *pipeline.StorageRuleBuilder
+}
+
+func newRuleBuilderValidator(t *testing.T) *ruleBuilderValidator {
+	storage := pipeline.NewProvisionedStorage(&pipeline.FileStorage{DataPath: t.TempDir()})
+	return &ruleBuilderValidator{builder: &pipeline.StorageRuleBuilder{Storage: storage}}
+}
+
+func (v *ruleBuilderValidator) ValidateChannelRule(ctx context.Context, rule pipeline.ChannelRule) error {
+	return v.builder.ValidateRule(ctx, rule.OrgId, rule)
+}
+
+func TestProvisionerReload(t *testing.T) {
+	dir := t.TempDir()
+	writeRules := func(pattern string) {
+		content := "apiVersion: 1\nrules:\n  - pattern: " + pattern + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte(content), 0600))
+	}
+
+	writeRules("stream/test/a")
+	setter := &fakeRulesSetter{}
+	p := New(dir, setter)
+	require.NoError(t, p.Provision(context.Background()))
+	require.Len(t, setter.rules, 1)
+	require.Equal(t, "stream/test/a", setter.rules[0].Pattern)
+
+	// Nothing changed.
+	p.pollChanges(context.Background())
+	require.Equal(t, 1, setter.calls)
+
+	writeRules("stream/test/b")
+	p.pollChanges(context.Background())
+	require.Equal(t, 2, setter.calls)
+	require.Equal(t, "stream/test/b", setter.rules[0].Pattern)
+
+	// Invalid rules are skipped.
+	writeRules("/stream/test")
+	p.pollChanges(context.Background())
+	require.Equal(t, 3, setter.calls)
+	require.Empty(t, setter.rules)
+}
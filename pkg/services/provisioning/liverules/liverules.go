@@ -0,0 +1,89 @@
+package liverules
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+)
+
+// pollInterval is how often provisioning files are checked for changes.
+const pollInterval = 10 * time.Second
+
+// RulesSetter validates and receives provisioned channel rules.
+type RulesSetter interface {
+	SetProvisionedChannelRules(rules []pipeline.ChannelRule)
+	ValidateChannelRule(ctx context.Context, rule pipeline.ChannelRule) error
+}
+
+// Provisioner provisions Live pipeline channel rules from YAML files.
+type Provisioner struct {
+	path      string
+	setter    RulesSetter
+	cfgReader *configReader
+	log       log.Logger
+	checksum  string
+}
+
+// New creates a Provisioner for the files in path.
+func New(path string, setter RulesSetter) *Provisioner {
+	logger := log.New("provisioning.liverules")
+	return &Provisioner{
+		path:      path,
+		setter:    setter,
+		cfgReader: &configReader{log: logger, validator: setter},
+		log:       logger,
+	}
+}
+
+// Provision reads channel rules from provisioning files and replaces previously
+// provisioned rules. Invalid files and rules are logged and skipped, rules are
+// only kept unchanged when the provisioning directory can't be read.
+func (p *Provisioner) Provision(ctx context.Context) error {
+	checksum, err := p.cfgReader.checksum(p.path)
+	if err != nil {
+		return err
+	}
+	rules, err := p.cfgReader.readConfig(ctx, p.path)
+	if err != nil {
+		return err
+	}
+	p.setter.SetProvisionedChannelRules(rules)
+	p.checksum = checksum
+	p.log.Debug("Provisioned live channel rules", "path", p.path, "rules", len(rules))
+	return nil
+}
+
+// PollChanges provisions channel rules again when provisioning files change until
+// ctx is done.
+func (p *Provisioner) PollChanges(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.pollChanges(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *Provisioner) pollChanges(ctx context.Context) {
+	checksum, err := p.cfgReader.checksum(p.path)
+	if err != nil {
+		p.log.Error("Failed to check live channel rule provisioning files", "path", p.path, "error", err)
+		return
+	}
+	if checksum == p.checksum {
+		return
+	}
+	if err := p.Provision(ctx); err != nil {
+		p.log.Error("Failed to provision live channel rules, keeping previous rules", "path", p.path, "error", err)
+		// Don't log the same error on each poll.
+		p.checksum = checksum
+		return
+	}
+	p.log.Info("Live channel rules provisioning files changed, rules reloaded", "path", p.path)
+}
@@ -0,0 +1,49 @@
+package liverules
+
+import (
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// ConfigVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+type channelRulesAsConfigV1 struct {
+	configVersion
+
+	Rules []*channelRuleFromConfigV1 `json:"rules" yaml:"rules"`
+}
+
+type channelRuleFromConfigV1 struct {
+	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Pattern  values.StringValue `json:"pattern" yaml:"pattern"`
+	Settings values.JSONValue   `json:"settings" yaml:"settings"`
+}
+
+// mapToChannelRules converts rule settings using the JSON model of channel rules,
+// so provisioning files have the same keys as the channel rules API.
+func (cfg *channelRulesAsConfigV1) mapToChannelRules() ([]pipeline.ChannelRule, error) {
+	rules := make([]pipeline.ChannelRule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		if r == nil {
+			continue
+		}
+		rule := pipeline.ChannelRule{
+			OrgId:   r.OrgID.Value(),
+			Pattern: r.Pattern.Value(),
+		}
+		settingsJSON, err := json.Marshal(r.Settings.Value())
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(settingsJSON, &rule.Settings); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -30,6 +30,7 @@ import (
 	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	"github.com/grafana/grafana/pkg/services/provisioning/liverules"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
@@ -57,6 +58,7 @@ func ProvideService(
 	orgService org.Service,
 	resourcePermissions accesscontrol.ReceiverPermissionsService,
 	tracer tracing.Tracer,
+	liveChannelRules liverules.RulesSetter,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -83,6 +85,7 @@ func ProvideService(
 		folderService:                folderService,
 		resourcePermissions:          resourcePermissions,
 		tracer:                       tracer,
+		liveRulesProvisioner:         liverules.New(filepath.Join(cfg.ProvisioningPath, "live"), liveChannelRules),
 	}
 
 	if err := s.setDashboardProvisioner(); err != nil {
@@ -164,6 +167,7 @@ type ProvisioningServiceImpl struct {
 	folderService                folder.Service
 	resourcePermissions          accesscontrol.ReceiverPermissionsService
 	tracer                       tracing.Tracer
+	liveRulesProvisioner         *liverules.Provisioner
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -185,6 +189,9 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 		return err
 	}
 
+	// Invalid channel rules are skipped, they don't prevent Grafana from starting.
+	ps.ProvisionLiveChannelRules(ctx)
+
 	return nil
 }
 
@@ -202,6 +209,10 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 		ps.searchService.TriggerReIndex()
 	}
 
+	if ps.liveRulesProvisioner != nil {
+		go ps.liveRulesProvisioner.PollChanges(ctx)
+	}
+
 	for {
 		// Wait for unlock. This is tied to new dashboardProvisioner to be instantiated before we start polling.
 		ps.mutex.Lock()
@@ -312,6 +323,17 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 	return ps.provisionAlerting(ctx, cfg)
 }
 
+// ProvisionLiveChannelRules provisions the Live pipeline channel rules, files and rules
+// which are invalid are logged and skipped.
+func (ps *ProvisioningServiceImpl) ProvisionLiveChannelRules(ctx context.Context) {
+	if ps.liveRulesProvisioner == nil {
+		return
+	}
+	if err := ps.liveRulesProvisioner.Provision(ctx); err != nil {
+		ps.log.Error("Failed to provision live channel rules", "error", err)
+	}
+}
+
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
 	return ps.dashboardProvisioner.GetProvisionerResolvedPath(name)
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/licensing"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/ratelimit"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
@@ -32,6 +33,9 @@ type Api struct {
 	license       licensing.Licensing
 	log           log.Logger
 	routeRegister routing.RouteRegister
+	clientLimiter *ratelimit.Limiter
+	// the client IP of requests from these addresses is read from the forwarding headers
+	trustedProxies IPAllowlist
 }
 
 func ProvideApi(
@@ -52,6 +56,8 @@ func ProvideApi(
 		license:                license,
 		log:                    log.New("publicdashboards.api"),
 		routeRegister:          rr,
+		clientLimiter:          ratelimit.New(cfg.PublicDashboardsClientRPS, cfg.PublicDashboardsClientBurst),
+		trustedProxies:         cfg.PublicDashboardsTrustedProxies,
 	}
 
 	// register endpoints if the feature is enabled
@@ -68,11 +74,13 @@ func (api *Api) RegisterAPIEndpoints() {
 	// Anonymous access to public dashboard route is configured in pkg/api/api.go
 	// because it is deeply dependent on the HTTPServer.Index() method and would result in a
 	// circular dependency
+	// Only viewing the dashboard counts as a use of an access token, the other requests are only allowed shortly
+	// after a view when the token has a usage limit
 	api.routeRegister.Group("/api/public/dashboards/:accessToken", func(apiRoute routing.RouteRegister) {
-		apiRoute.Get("/", routing.Wrap(api.ViewPublicDashboard))
-		apiRoute.Get("/annotations", routing.Wrap(api.GetPublicAnnotations))
-		apiRoute.Post("/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
-	}, api.Middleware.HandleApi)
+		apiRoute.Get("/", AuthorizeAccessToken(api.PublicDashboardService, api.trustedProxies, true), routing.Wrap(api.ViewPublicDashboard))
+		apiRoute.Get("/annotations", AuthorizeAccessToken(api.PublicDashboardService, api.trustedProxies, false), routing.Wrap(api.GetPublicAnnotations))
+		apiRoute.Post("/panels/:panelId/query", AuthorizeAccessToken(api.PublicDashboardService, api.trustedProxies, false), routing.Wrap(api.QueryPublicDashboard))
+	}, RateLimitPublicDashboardRequest(api.clientLimiter, api.trustedProxies), api.Middleware.HandleApi)
 
 	// Auth endpoints
 	auth := accesscontrol.Middleware(api.accessControl)
@@ -99,6 +107,21 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.routeRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid",
 		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.DeletePublicDashboard))
+
+	// List the access tokens of a public dashboard
+	api.routeRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, uidScope)),
+		routing.Wrap(api.ListPublicDashboardTokens))
+
+	// Create an access token for a public dashboard
+	api.routeRegister.Post("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.CreatePublicDashboardToken))
+
+	// Revoke an access token of a public dashboard
+	api.routeRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens/:tokenUid",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.RevokePublicDashboardToken))
 }
 
 // swagger:route GET /dashboards/public-dashboards dashboard_public listPublicDashboards
@@ -296,6 +319,118 @@ func (api *Api) DeletePublicDashboard(c *contextmodel.ReqContext) response.Respo
 	return response.Empty(http.StatusOK)
 }
 
+// swagger:route GET /dashboards/uid/{dashboardUid}/public-dashboards/{uid}/tokens dashboard_public listPublicDashboardTokens
+//
+//	Get the access tokens of a public dashboard
+//
+// Responses:
+// 200: listPublicDashboardTokensResponse
+// 400: badRequestPublicError
+// 401: unauthorisedPublicError
+// 403: forbiddenPublicError
+// 404: notFoundPublicError
+// 500: internalServerPublicError
+func (api *Api) ListPublicDashboardTokens(c *contextmodel.ReqContext) response.Response {
+	dashboardUid := web.Params(c.Req)[":dashboardUid"]
+	if !validation.IsValidShortUID(dashboardUid) {
+		return response.Err(ErrInvalidUid.Errorf("ListPublicDashboardTokens: invalid dashboard Uid %s", dashboardUid))
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("ListPublicDashboardTokens: invalid Uid %s", uid))
+	}
+
+	tokens, err := api.PublicDashboardService.FindTokens(c.Req.Context(), c.SignedInUser.GetOrgID(), dashboardUid, uid)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, tokens)
+}
+
+// swagger:route POST /dashboards/uid/{dashboardUid}/public-dashboards/{uid}/tokens dashboard_public createPublicDashboardToken
+//
+//	Create an access token for a public dashboard. The access token is only returned in this response.
+//
+// Produces:
+// - application/json
+//
+// Responses:
+// 200: createPublicDashboardTokenResponse
+// 400: badRequestPublicError
+// 401: unauthorisedPublicError
+// 403: forbiddenPublicError
+// 404: notFoundPublicError
+// 500: internalServerPublicError
+func (api *Api) CreatePublicDashboardToken(c *contextmodel.ReqContext) response.Response {
+	dashboardUid := web.Params(c.Req)[":dashboardUid"]
+	if !validation.IsValidShortUID(dashboardUid) {
+		return response.Err(ErrInvalidUid.Errorf("CreatePublicDashboardToken: invalid dashboard Uid %s", dashboardUid))
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("CreatePublicDashboardToken: invalid Uid %s", uid))
+	}
+
+	tokenDTO := &PublicDashboardTokenDTO{}
+	if err := web.Bind(c.Req, tokenDTO); err != nil {
+		return response.Err(ErrBadRequest.Errorf("CreatePublicDashboardToken: bad request data %v", err))
+	}
+
+	// Always set the orgID and userID from the session
+	dto := &SavePublicDashboardTokenDTO{
+		Uid:          uid,
+		DashboardUid: dashboardUid,
+		OrgID:        c.SignedInUser.GetOrgID(),
+		UserId:       c.UserID,
+		Token:        tokenDTO,
+	}
+
+	token, err := api.PublicDashboardService.CreateToken(c.Req.Context(), dto)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, token)
+}
+
+// swagger:route DELETE /dashboards/uid/{dashboardUid}/public-dashboards/{uid}/tokens/{tokenUid} dashboard_public revokePublicDashboardToken
+//
+//	Revoke an access token of a public dashboard
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestPublicError
+// 401: unauthorisedPublicError
+// 403: forbiddenPublicError
+// 404: notFoundPublicError
+// 500: internalServerPublicError
+func (api *Api) RevokePublicDashboardToken(c *contextmodel.ReqContext) response.Response {
+	dashboardUid := web.Params(c.Req)[":dashboardUid"]
+	if !validation.IsValidShortUID(dashboardUid) {
+		return response.Err(ErrInvalidUid.Errorf("RevokePublicDashboardToken: invalid dashboard Uid %s", dashboardUid))
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("RevokePublicDashboardToken: invalid Uid %s", uid))
+	}
+
+	tokenUid := web.Params(c.Req)[":tokenUid"]
+	if !validation.IsValidShortUID(tokenUid) {
+		return response.Err(ErrInvalidUid.Errorf("RevokePublicDashboardToken: invalid token Uid %s", tokenUid))
+	}
+
+	err := api.PublicDashboardService.RevokeToken(c.Req.Context(), c.SignedInUser.GetOrgID(), dashboardUid, uid, tokenUid)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.Empty(http.StatusOK)
+}
+
 // Copied from pkg/api/metrics.go
 func toJsonStreamingResponse(ctx context.Context, features featuremgmt.FeatureToggles, qdr *backend.QueryDataResponse) response.Response {
 	statusCode := http.StatusOK
@@ -371,3 +506,51 @@ type DeletePublicDashboardParams struct {
 	// required:true
 	Uid string `json:"uid"`
 }
+
+// swagger:parameters listPublicDashboardTokens
+type ListPublicDashboardTokensParams struct {
+	// in:path
+	// required:true
+	DashboardUid string `json:"dashboardUid"`
+	// in:path
+	// required:true
+	Uid string `json:"uid"`
+}
+
+// swagger:response listPublicDashboardTokensResponse
+type ListPublicDashboardTokensResponse struct {
+	// in: body
+	Body []*PublicDashboardToken `json:"body"`
+}
+
+// swagger:parameters createPublicDashboardToken
+type CreatePublicDashboardTokenParams struct {
+	// in:path
+	// required:true
+	DashboardUid string `json:"dashboardUid"`
+	// in:path
+	// required:true
+	Uid string `json:"uid"`
+	// in:body
+	// required:true
+	Body PublicDashboardTokenDTO
+}
+
+// swagger:response createPublicDashboardTokenResponse
+type CreatePublicDashboardTokenResponse struct {
+	// in: body
+	Body PublicDashboardTokenWithSecret `json:"body"`
+}
+
+// swagger:parameters revokePublicDashboardToken
+type RevokePublicDashboardTokenParams struct {
+	// in:path
+	// required:true
+	DashboardUid string `json:"dashboardUid"`
+	// in:path
+	// required:true
+	Uid string `json:"uid"`
+	// in:path
+	// required:true
+	TokenUid string `json:"tokenUid"`
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -615,3 +616,80 @@ func TestAPIUpdatePublicDashboard(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIPublicDashboardTokens(t *testing.T) {
+	dashboardUid := "abc1234"
+	publicDashboardUid := "1234asdfasdf"
+	userEditor := &user.SignedInUser{UserID: 4, OrgID: 1, OrgRole: org.RoleEditor, Login: "testEditorUser", Permissions: map[int64]map[string][]string{1: {
+		dashboards.ActionDashboardsRead:        {dashboards.ScopeDashboardsAll},
+		dashboards.ActionDashboardsPublicWrite: {dashboards.ScopeDashboardsAll},
+	}}}
+	path := fmt.Sprintf("/api/dashboards/uid/%s/public-dashboards/%s/tokens", dashboardUid, publicDashboardUid)
+
+	t.Run("lists the tokens", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("FindTokens", mock.Anything, int64(1), dashboardUid, publicDashboardUid).
+			Return([]*PublicDashboardToken{{Uid: "token1", Name: "partner", Token: "secret"}}, nil)
+		testServer := setupTestServer(t, nil, service, userEditor)
+
+		response := callAPI(testServer, http.MethodGet, path, nil, t)
+		require.Equal(t, http.StatusOK, response.Code)
+		assert.NotContains(t, response.Body.String(), "secret")
+
+		var tokens []*PublicDashboardToken
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &tokens))
+		require.Len(t, tokens, 1)
+		assert.Equal(t, "partner", tokens[0].Name)
+	})
+
+	t.Run("creates a token with the org and user of the session", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("CreateToken", mock.Anything, mock.MatchedBy(func(dto *SavePublicDashboardTokenDTO) bool {
+			return dto.OrgID == 1 && dto.UserId == 4 && dto.Uid == publicDashboardUid && dto.Token.Name == "partner" && dto.Token.MaxUses == 10
+		})).Return(&PublicDashboardTokenWithSecret{PublicDashboardToken: &PublicDashboardToken{Uid: "token1"}, AccessToken: "secret"}, nil)
+		testServer := setupTestServer(t, nil, service, userEditor)
+
+		response := callAPI(testServer, http.MethodPost, path, strings.NewReader(`{"name": "partner", "maxUses": 10, "orgId": 2}`), t)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		var token PublicDashboardTokenWithSecret
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &token))
+		assert.Equal(t, "secret", token.AccessToken)
+	})
+
+	t.Run("revokes a token", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("RevokeToken", mock.Anything, int64(1), dashboardUid, publicDashboardUid, "token1").Return(ErrTokenNotFound.Errorf(""))
+		testServer := setupTestServer(t, nil, service, userEditor)
+
+		response := callAPI(testServer, http.MethodDelete, path+"/token1", nil, t)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("viewers can't create tokens", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		testServer := setupTestServer(t, nil, service, userViewer)
+
+		response := callAPI(testServer, http.MethodPost, path, strings.NewReader(`{}`), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+		service.AssertNotCalled(t, "CreateToken")
+	})
+
+	t.Run("public endpoints enforce the restrictions of the access token", func(t *testing.T) {
+		accessToken := validAccessToken
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("AuthorizeAccessToken", mock.Anything, accessToken, mock.MatchedBy(func(req AccessTokenRequest) bool {
+			return req.CountUse && req.Password == "wrong"
+		})).Return(nil, ErrTokenPasswordRequired.Errorf(""))
+		testServer := setupTestServer(t, nil, service, anonymousUser)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/public/dashboards/%s", accessToken), nil)
+		require.NoError(t, err)
+		req.Header.Set(PublicDashboardPasswordHeader, "wrong")
+		response := httptest.NewRecorder()
+		testServer.ServeHTTP(response, req)
+
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		service.AssertNotCalled(t, "GetPublicDashboardForView")
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -71,6 +72,11 @@ func setupTestServer(
 		cfg.PublicDashboardsEnabled = true
 	}
 
+	// access tokens are not restricted unless the test mocked AuthorizeAccessToken before
+	if fake, ok := service.(*publicdashboards.FakePublicDashboardService); ok {
+		fake.On("AuthorizeAccessToken", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	}
+
 	// build api, this will mount the routes at the same time if the feature is enabled
 	license := licensingtest.NewFakeLicensing()
 	license.On("FeatureEnabled", publicdashboardModels.FeaturePublicDashboardsEmailSharing).Return(false)
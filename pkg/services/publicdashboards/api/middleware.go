@@ -1,11 +1,14 @@
 package api
 
 import (
+	"net"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/ratelimit"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -62,6 +65,62 @@ func RequiresExistingAccessToken(publicDashboardService publicdashboards.Service
 	}
 }
 
+// PublicDashboardPasswordHeader is the header used to send the password of a public dashboard token
+const PublicDashboardPasswordHeader = "X-Grafana-Public-Dashboard-Password"
+
+// AuthorizeAccessToken Middleware to enforce the expiration, usage limit, IP allowlist and password of the token
+// used to access a public dashboard. countUse is set on the endpoints which count as a use of the token.
+func AuthorizeAccessToken(publicDashboardService publicdashboards.Service, trustedProxies models.IPAllowlist, countUse bool) func(c *contextmodel.ReqContext) {
+	return func(c *contextmodel.ReqContext) {
+		accessToken, ok := web.Params(c.Req)[":accessToken"]
+		if !ok || !validation.IsValidAccessToken(accessToken) {
+			return
+		}
+
+		_, err := publicDashboardService.AuthorizeAccessToken(c.Req.Context(), accessToken, models.AccessTokenRequest{
+			RemoteAddr: clientAddr(c.Req, trustedProxies),
+			Password:   c.Req.Header.Get(PublicDashboardPasswordHeader),
+			CountUse:   countUse,
+		})
+		if err != nil {
+			c.WriteErr(err)
+			return
+		}
+	}
+}
+
+// peerAddr returns the IP address of the peer of the connection. Unlike web.RemoteAddr, it ignores the X-Real-IP and
+// X-Forwarded-For headers which can be set by any client.
+func peerAddr(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// clientAddr returns the IP address of the client. The X-Real-IP and X-Forwarded-For headers can be set by any client,
+// so they are only used when the peer is one of the trusted proxies.
+func clientAddr(req *http.Request, trustedProxies models.IPAllowlist) string {
+	addr := peerAddr(req)
+	if len(trustedProxies) > 0 && trustedProxies.Allows(addr) {
+		return web.RemoteAddr(req)
+	}
+	return addr
+}
+
+// RateLimitPublicDashboardRequest Middleware to limit the rate of public dashboard requests of each client IP. The
+// forwarding headers are only used for requests of the trusted proxies, as clients can set them to get a fresh limit.
+func RateLimitPublicDashboardRequest(limiter *ratelimit.Limiter, trustedProxies models.IPAllowlist) func(c *contextmodel.ReqContext) {
+	return func(c *contextmodel.ReqContext) {
+		if !limiter.Allow(clientAddr(c.Req, trustedProxies)) {
+			metrics.MPublicDashboardRequestRejected.WithLabelValues(models.RateLimitClientIP).Inc()
+			c.WriteErr(models.ErrTooManyRequests.Errorf("RateLimitPublicDashboardRequest: client reached the request rate limit"))
+			return
+		}
+	}
+}
+
 func CountPublicDashboardRequest() func(c *contextmodel.ReqContext) {
 	return func(c *contextmodel.ReqContext) {
 		metrics.MPublicDashboardRequestCount.Inc()
@@ -8,8 +8,11 @@ import (
 
 	"errors"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/ratelimit"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/service"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
@@ -152,6 +155,62 @@ func TestSetPublicDashboardFlag(t *testing.T) {
 	})
 }
 
+func TestRateLimitPublicDashboardRequest(t *testing.T) {
+	t.Run("Rejects the requests of a client over the limit", func(t *testing.T) {
+		mw := RateLimitPublicDashboardRequest(ratelimit.New(1, 2), nil)
+		for i := 0; i < 2; i++ {
+			_, resp := runMw(t, nil, "GET", "/api/public/dashboards/abc", nil, mw)
+			require.Equal(t, http.StatusOK, resp.Code)
+		}
+		_, resp := runMw(t, &contextmodel.ReqContext{Logger: log.NewNopLogger()}, "GET", "/api/public/dashboards/abc", nil, mw)
+		require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	})
+
+	t.Run("Allows every request without a limit", func(t *testing.T) {
+		mw := RateLimitPublicDashboardRequest(ratelimit.New(0, 0), nil)
+		for i := 0; i < 10; i++ {
+			_, resp := runMw(t, nil, "GET", "/api/public/dashboards/abc", nil, mw)
+			require.Equal(t, http.StatusOK, resp.Code)
+		}
+	})
+}
+
+func TestPeerAddr(t *testing.T) {
+	for remoteAddr, expected := range map[string]string{
+		"10.1.2.3:4567":      "10.1.2.3",
+		"[2001:db8::1]:4567": "2001:db8::1",
+		"10.1.2.3":           "10.1.2.3",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "192.168.0.1")
+		req.Header.Set("X-Real-IP", "192.168.0.2")
+		assert.Equal(t, expected, peerAddr(req))
+	}
+}
+
+func TestClientAddr(t *testing.T) {
+	trustedProxies := models.IPAllowlist{"10.0.0.1", "10.1.0.0/16"}
+	request := func(remoteAddr string, headers map[string]string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		return req
+	}
+
+	forwarded := map[string]string{"X-Forwarded-For": "192.168.0.1, 10.0.0.1"}
+	assert.Equal(t, "192.168.0.1", clientAddr(request("10.0.0.1:4567", forwarded), trustedProxies))
+	assert.Equal(t, "192.168.0.2", clientAddr(request("10.1.2.3:4567", map[string]string{"X-Real-IP": "192.168.0.2"}), trustedProxies))
+	// a trusted proxy which didn't forward the client address
+	assert.Equal(t, "10.0.0.1", clientAddr(request("10.0.0.1:4567", nil), trustedProxies))
+
+	// the headers of other peers are ignored
+	assert.Equal(t, "10.2.0.1", clientAddr(request("10.2.0.1:4567", forwarded), trustedProxies))
+	assert.Equal(t, "10.0.0.1", clientAddr(request("10.0.0.1:4567", forwarded), nil))
+}
+
 // This is a helper to test middleware. It handles creating a
 // proper contextmodel.ReqContext, setting web parameters, executing middleware, and
 // returning a response. Response will default to result of
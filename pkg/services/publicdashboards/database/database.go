@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -247,12 +248,24 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		panelSettingsJSON, err := json.Marshal(&cmd.PublicDashboard.PanelSettings)
+		if err != nil {
+			return err
+		}
+
+		variableSettingsJSON, err := json.Marshal(&cmd.PublicDashboard.VariableSettings)
+		if err != nil {
+			return err
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, panel_settings = ?, variable_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			cmd.PublicDashboard.Share,
 			string(timeSettingsJSON),
+			string(panelSettingsJSON),
+			string(variableSettingsJSON),
 			cmd.PublicDashboard.UpdatedBy,
 			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
 			cmd.PublicDashboard.Uid)
@@ -273,10 +286,14 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 func (d *PublicDashboardStoreImpl) Delete(ctx context.Context, uid string) (int64, error) {
 	dashboard := &PublicDashboard{Uid: uid}
 	var affectedRows int64
-	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		var err error
 		affectedRows, err = sess.Delete(dashboard)
+		if err != nil {
+			return err
+		}
 
+		_, err = sess.Exec("DELETE FROM dashboard_public_token WHERE public_dashboard_uid = ?", uid)
 		return err
 	})
 
@@ -309,3 +326,87 @@ func (d *PublicDashboardStoreImpl) GetMetrics(ctx context.Context) (*Metrics, er
 
 	return metrics, nil
 }
+
+// FindToken Returns the additional access token or nil if not found
+func (d *PublicDashboardStoreImpl) FindToken(ctx context.Context, token string) (*PublicDashboardToken, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	var found bool
+	publicDashboardToken := &PublicDashboardToken{Token: token}
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Get(publicDashboardToken)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return publicDashboardToken, nil
+}
+
+// FindTokens Returns the additional access tokens of a public dashboard, including the revoked ones
+func (d *PublicDashboardStoreImpl) FindTokens(ctx context.Context, publicDashboardUid string) ([]*PublicDashboardToken, error) {
+	tokens := make([]*PublicDashboardToken, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("public_dashboard_uid = ?", publicDashboardUid).Asc("created_at").Find(&tokens)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// CreateToken Creates an additional access token
+func (d *PublicDashboardStoreImpl) CreateToken(ctx context.Context, token *PublicDashboardToken) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Insert(token)
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// RevokeToken Marks the token as revoked, the token is kept for auditing
+func (d *PublicDashboardStoreImpl) RevokeToken(ctx context.Context, publicDashboardUid string, uid string, revokedAt time.Time) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		sqlResult, err := sess.Exec("UPDATE dashboard_public_token SET revoked_at = ? WHERE uid = ? AND public_dashboard_uid = ? AND revoked_at IS NULL",
+			revokedAt.UTC(), uid, publicDashboardUid)
+		if err != nil {
+			return err
+		}
+
+		affectedRows, err = sqlResult.RowsAffected()
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// UseToken Increments the usage count of the token unless it reached its usage limit
+func (d *PublicDashboardStoreImpl) UseToken(ctx context.Context, uid string, usedAt time.Time) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		sqlResult, err := sess.Exec("UPDATE dashboard_public_token SET use_count = use_count + 1, last_used_at = ? WHERE uid = ? AND (max_uses = 0 OR use_count < max_uses)",
+			usedAt.UTC(), uid)
+		if err != nil {
+			return err
+		}
+
+		affectedRows, err = sqlResult.RowsAffected()
+		return err
+	})
+
+	return affectedRows, err
+}
@@ -593,6 +593,8 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 			TimeSelectionEnabled: true,
 			Share:                EmailShareType,
 			TimeSettings:         &TimeSettings{From: "now-8", To: "now"},
+			PanelSettings:        PanelSettings{Hidden: []int64{2, 3}},
+			VariableSettings:     VariableSettings{Allowed: map[string][]string{"job": {"api", "web"}}},
 			UpdatedAt:            time.Now().UTC().Round(time.Second),
 			UpdatedBy:            8,
 		}
@@ -614,6 +616,8 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.Equal(t, updatedPublicDashboard.AnnotationsEnabled, pdRetrieved.AnnotationsEnabled)
 		assert.Equal(t, updatedPublicDashboard.TimeSelectionEnabled, pdRetrieved.TimeSelectionEnabled)
 		assert.Equal(t, updatedPublicDashboard.Share, pdRetrieved.Share)
+		assert.Equal(t, updatedPublicDashboard.PanelSettings, pdRetrieved.PanelSettings)
+		assert.Equal(t, updatedPublicDashboard.VariableSettings, pdRetrieved.VariableSettings)
 
 		// not updated dashboard shouldn't have changed
 		pdNotUpdatedRetrieved, err := publicdashboardStore.FindByDashboardUid(context.Background(), anotherSavedDashboard.OrgID, anotherSavedDashboard.UID)
@@ -622,6 +626,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.NotEqual(t, updatedPublicDashboard.IsEnabled, pdNotUpdatedRetrieved.IsEnabled)
 		assert.NotEqual(t, updatedPublicDashboard.AnnotationsEnabled, pdNotUpdatedRetrieved.AnnotationsEnabled)
 		assert.NotEqual(t, updatedPublicDashboard.Share, pdNotUpdatedRetrieved.Share)
+		assert.Empty(t, pdNotUpdatedRetrieved.PanelSettings.Hidden)
 	})
 }
 
@@ -740,6 +745,93 @@ func TestIntegrationDelete(t *testing.T) {
 	})
 }
 
+func TestIntegrationPublicDashboardTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBWithCfg(t)
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil))
+	require.NoError(t, err)
+	publicdashboardStore := ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+	savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, "", true)
+	savedPublicDashboard := insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true, PublicShareType)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(time.Hour)
+	token := &PublicDashboardToken{
+		Uid:                "tokenuid",
+		PublicDashboardUid: savedPublicDashboard.Uid,
+		OrgId:              savedPublicDashboard.OrgId,
+		Token:              "fe2b6a5b6c6f4a6bb9e1f8e0d8a1b1a3",
+		Name:               "partner",
+		ExpiresAt:          &expiresAt,
+		MaxUses:            2,
+		IPAllowlist:        IPAllowlist{"10.0.0.0/8"},
+		CreatedBy:          7,
+		CreatedAt:          now,
+	}
+	affectedRows, err := publicdashboardStore.CreateToken(context.Background(), token)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, affectedRows)
+
+	t.Run("FindToken returns the token", func(t *testing.T) {
+		found, err := publicdashboardStore.FindToken(context.Background(), token.Token)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, token.Uid, found.Uid)
+		assert.Equal(t, token.IPAllowlist, found.IPAllowlist)
+		assert.True(t, expiresAt.Equal(*found.ExpiresAt))
+
+		found, err = publicdashboardStore.FindToken(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("UseToken stops counting at the usage limit", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			affectedRows, err := publicdashboardStore.UseToken(context.Background(), token.Uid, now)
+			require.NoError(t, err)
+			assert.EqualValues(t, 1, affectedRows)
+		}
+		affectedRows, err := publicdashboardStore.UseToken(context.Background(), token.Uid, now)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, affectedRows)
+
+		tokens, err := publicdashboardStore.FindTokens(context.Background(), savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.EqualValues(t, 2, tokens[0].UseCount)
+		require.NotNil(t, tokens[0].LastUsedAt)
+	})
+
+	t.Run("RevokeToken revokes the token once", func(t *testing.T) {
+		affectedRows, err := publicdashboardStore.RevokeToken(context.Background(), "other", token.Uid, now)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, affectedRows)
+
+		affectedRows, err = publicdashboardStore.RevokeToken(context.Background(), savedPublicDashboard.Uid, token.Uid, now)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, affectedRows)
+
+		affectedRows, err = publicdashboardStore.RevokeToken(context.Background(), savedPublicDashboard.Uid, token.Uid, now)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, affectedRows)
+
+		found, err := publicdashboardStore.FindToken(context.Background(), token.Token)
+		require.NoError(t, err)
+		assert.True(t, found.IsRevoked())
+	})
+
+	t.Run("Delete removes the tokens of the public dashboard", func(t *testing.T) {
+		_, err := publicdashboardStore.Delete(context.Background(), savedPublicDashboard.Uid)
+		require.NoError(t, err)
+
+		tokens, err := publicdashboardStore.FindTokens(context.Background(), savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		assert.Empty(t, tokens)
+	})
+}
+
 func TestFindByFolder(t *testing.T) {
 	t.Run("returns nil when dashboard is not a folder", func(t *testing.T) {
 		sqlStore, cfg := db.InitTestDBWithCfg(t)
@@ -20,9 +20,23 @@ var (
 	ErrInvalidMaxDataPoints                = errutil.BadRequest("publicdashboards.maxDataPoints", errutil.WithPublicMessage("maxDataPoints should be greater than 0"))
 	ErrInvalidTimeRange                    = errutil.BadRequest("publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
 	ErrInvalidShareType                    = errutil.BadRequest("publicdashboards.invalidShareType", errutil.WithPublicMessage("Invalid share type"))
+	ErrInvalidVariableSettings             = errutil.BadRequest("publicdashboards.invalidVariableSettings", errutil.WithPublicMessage("Invalid template variable settings"))
+	ErrInvalidVariables                    = errutil.BadRequest("publicdashboards.invalidVariables", errutil.WithPublicMessage("Invalid template variable values"))
+	ErrInvalidIPAllowlist                  = errutil.BadRequest("publicdashboards.invalidIPAllowlist", errutil.WithPublicMessage("Invalid IP allowlist"))
+	ErrInvalidTokenExpiration              = errutil.BadRequest("publicdashboards.invalidTokenExpiration", errutil.WithPublicMessage("Token expiration must be in the future"))
+	ErrInvalidTokenMaxUses                 = errutil.BadRequest("publicdashboards.invalidTokenMaxUses", errutil.WithPublicMessage("maxUses should be greater than 0"))
 	ErrDashboardIsPublic                   = errutil.BadRequest("publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
 	ErrPublicDashboardUidExists            = errutil.BadRequest("publicdashboards.uidExists", errutil.WithPublicMessage("Dashboard Uid already exists"))
 	ErrPublicDashboardAccessTokenExists    = errutil.BadRequest("publicdashboards.accessTokenExists", errutil.WithPublicMessage("Dashboard Access Token already exists"))
 
+	ErrTokenNotFound = errutil.NotFound("publicdashboards.tokenNotFound", errutil.WithPublicMessage("Token not found"))
+
 	ErrPublicDashboardNotEnabled = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Dashboard paused"))
+	ErrTokenExpired              = errutil.Forbidden("publicdashboards.tokenExpired", errutil.WithPublicMessage("Access token expired"))
+	ErrTokenUsageLimit           = errutil.Forbidden("publicdashboards.tokenUsageLimit", errutil.WithPublicMessage("Access token usage limit reached"))
+	ErrTokenIPNotAllowed         = errutil.Forbidden("publicdashboards.tokenIPNotAllowed", errutil.WithPublicMessage("Access is not allowed from this address"))
+
+	ErrTokenPasswordRequired = errutil.Unauthorized("publicdashboards.tokenPasswordRequired", errutil.WithPublicMessage("Password required"))
+
+	ErrTooManyRequests = errutil.TooManyRequests("publicdashboards.tooManyRequests", errutil.WithPublicMessage("Too many requests"))
 )
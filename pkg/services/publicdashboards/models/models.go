@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"net"
 	"time"
 
 	"github.com/grafana/grafana/pkg/kinds/dashboard"
@@ -26,6 +27,10 @@ func (e PublicDashboardErr) Error() string {
 const (
 	QuerySuccess                                  = "success"
 	QueryFailure                                  = "failure"
+	QueryCacheHit                                 = "hit"
+	QueryCacheMiss                                = "miss"
+	RateLimitClientIP                             = "client_ip"
+	RateLimitDashboard                            = "dashboard"
 	EmailShareType                      ShareType = "email"
 	PublicShareType                     ShareType = "public"
 	FeaturePublicDashboardsEmailSharing           = "publicDashboardsEmailSharing"
@@ -33,6 +38,8 @@ const (
 
 var (
 	QueryResultStatuses = []string{QuerySuccess, QueryFailure}
+	QueryCacheResults   = []string{QueryCacheHit, QueryCacheMiss}
+	RateLimits          = []string{RateLimitClientIP, RateLimitDashboard}
 	ValidShareTypes     = []ShareType{EmailShareType, PublicShareType}
 )
 
@@ -54,6 +61,9 @@ type PublicDashboard struct {
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	Share                ShareType     `json:"share" xorm:"share"`
 	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	//visibility fields
+	PanelSettings    PanelSettings    `json:"panelSettings" xorm:"panel_settings"`
+	VariableSettings VariableSettings `json:"variableSettings" xorm:"variable_settings"`
 }
 
 type PublicDashboardDTO struct {
@@ -63,6 +73,9 @@ type PublicDashboardDTO struct {
 	IsEnabled            *bool     `json:"isEnabled"`
 	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
 	Share                ShareType `json:"share"`
+	// nil keeps the current settings
+	PanelSettings    *PanelSettings    `json:"panelSettings,omitempty"`
+	VariableSettings *VariableSettings `json:"variableSettings,omitempty"`
 }
 
 type EmailDTO struct {
@@ -134,6 +147,63 @@ func (ts *TimeSettings) ToDB() ([]byte, error) {
 	return json.Marshal(ts)
 }
 
+// PanelSettings controls which panels of the dashboard are public
+type PanelSettings struct {
+	// IDs of the panels which are removed from the public dashboard and can't be queried
+	Hidden []int64 `json:"hidden,omitempty"`
+}
+
+// IsHidden returns true when the panel is not part of the public dashboard
+func (ps *PanelSettings) IsHidden(panelID int64) bool {
+	if ps == nil {
+		return false
+	}
+	for _, id := range ps.Hidden {
+		if id == panelID {
+			return true
+		}
+	}
+	return false
+}
+
+func (ps *PanelSettings) FromDB(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, ps)
+}
+
+func (ps *PanelSettings) ToDB() ([]byte, error) {
+	return json.Marshal(ps)
+}
+
+// VariableSettings controls the template variable values viewers can select
+type VariableSettings struct {
+	// The values viewers can select for each template variable, keyed by the variable name.
+	// Variables which are not listed always use the value saved with the dashboard.
+	Allowed map[string][]string `json:"allowed,omitempty"`
+}
+
+// AllowedValues returns the values viewers can select for the variable
+func (vs *VariableSettings) AllowedValues(name string) ([]string, bool) {
+	if vs == nil {
+		return nil, false
+	}
+	values, ok := vs.Allowed[name]
+	return values, ok
+}
+
+func (vs *VariableSettings) FromDB(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, vs)
+}
+
+func (vs *VariableSettings) ToDB() ([]byte, error) {
+	return json.Marshal(vs)
+}
+
 // DTO for transforming user input in the api
 type SavePublicDashboardDTO struct {
 	Uid             string
@@ -154,6 +224,8 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// The selected template variable values, only the values allowed in VariableSettings are accepted
+	Variables map[string][]string `json:"variables,omitempty"`
 }
 
 type AnnotationsQueryDTO struct {
@@ -161,6 +233,109 @@ type AnnotationsQueryDTO struct {
 	To   int64
 }
 
+// PublicDashboardToken is an additional access token of a public dashboard. Unlike the
+// AccessToken of the public dashboard, it can expire, be revoked and restrict its viewers.
+type PublicDashboardToken struct {
+	Uid                string      `json:"uid" xorm:"pk uid"`
+	PublicDashboardUid string      `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	OrgId              int64       `json:"-" xorm:"org_id"`
+	Token              string      `json:"-" xorm:"token"`
+	Name               string      `json:"name" xorm:"name"`
+	ExpiresAt          *time.Time  `json:"expiresAt,omitempty" xorm:"expires_at"`
+	MaxUses            int64       `json:"maxUses" xorm:"max_uses"` // zero is unlimited
+	UseCount           int64       `json:"useCount" xorm:"use_count"`
+	LastUsedAt         *time.Time  `json:"lastUsedAt,omitempty" xorm:"last_used_at"`
+	IPAllowlist        IPAllowlist `json:"ipAllowlist" xorm:"ip_allowlist"`
+	PasswordHash       string      `json:"-" xorm:"password_hash"`
+	PasswordSalt       string      `json:"-" xorm:"password_salt"`
+	HasPassword        bool        `json:"hasPassword" xorm:"-"`
+	CreatedBy          int64       `json:"createdBy" xorm:"created_by"`
+	CreatedAt          time.Time   `json:"createdAt" xorm:"created_at"`
+	RevokedAt          *time.Time  `json:"revokedAt,omitempty" xorm:"revoked_at"`
+}
+
+func (t PublicDashboardToken) TableName() string {
+	return "dashboard_public_token"
+}
+
+// IsExpired returns true when the token expired before now
+func (t *PublicDashboardToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// IsRevoked returns true when the token can no longer be used
+func (t *PublicDashboardToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IPAllowlist is a list of IP addresses and CIDR ranges, an empty list allows every address
+type IPAllowlist []string
+
+// Allows returns true when the address is in the list
+func (l IPAllowlist) Allows(addr string) bool {
+	if len(l) == 0 {
+		return true
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, entry := range l {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *IPAllowlist) FromDB(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, l)
+}
+
+func (l *IPAllowlist) ToDB() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+// DTO to create a public dashboard token
+type PublicDashboardTokenDTO struct {
+	Name        string      `json:"name"`
+	ExpiresAt   *time.Time  `json:"expiresAt"`
+	MaxUses     int64       `json:"maxUses"`
+	IPAllowlist IPAllowlist `json:"ipAllowlist"`
+	Password    string      `json:"password"`
+}
+
+type SavePublicDashboardTokenDTO struct {
+	Uid          string
+	DashboardUid string
+	OrgID        int64
+	UserId       int64
+	Token        *PublicDashboardTokenDTO
+}
+
+// PublicDashboardTokenWithSecret is returned once, when the token is created
+type PublicDashboardTokenWithSecret struct {
+	*PublicDashboardToken
+	AccessToken string `json:"accessToken"`
+}
+
+// AccessTokenRequest are the details of a request that uses an access token
+type AccessTokenRequest struct {
+	RemoteAddr string
+	Password   string
+	// Counts the request towards the usage limit of the token
+	CountUse bool
+}
+
 //
 // COMMANDS
 //
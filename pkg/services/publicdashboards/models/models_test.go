@@ -9,3 +9,15 @@ import (
 func TestPublicDashboardTableName(t *testing.T) {
 	assert.Equal(t, "dashboard_public", PublicDashboard{}.TableName())
 }
+
+func TestIPAllowlistAllows(t *testing.T) {
+	assert.True(t, IPAllowlist{}.Allows("10.0.0.1"))
+
+	allowlist := IPAllowlist{"10.0.0.1", "192.168.0.0/16", "2001:db8::/32"}
+	assert.True(t, allowlist.Allows("10.0.0.1"))
+	assert.True(t, allowlist.Allows("192.168.10.20"))
+	assert.True(t, allowlist.Allows("2001:db8::1"))
+	assert.False(t, allowlist.Allows("10.0.0.2"))
+	assert.False(t, allowlist.Allows("::1"))
+	assert.False(t, allowlist.Allows("not an ip"))
+}
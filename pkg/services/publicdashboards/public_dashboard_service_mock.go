@@ -23,6 +23,32 @@ type FakePublicDashboardService struct {
 	mock.Mock
 }
 
+// AuthorizeAccessToken provides a mock function with given fields: ctx, accessToken, req
+func (_m *FakePublicDashboardService) AuthorizeAccessToken(ctx context.Context, accessToken string, req models.AccessTokenRequest) (*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, accessToken, req)
+
+	var r0 *models.PublicDashboardToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.AccessTokenRequest) (*models.PublicDashboardToken, error)); ok {
+		return rf(ctx, accessToken, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.AccessTokenRequest) *models.PublicDashboardToken); ok {
+		r0 = rf(ctx, accessToken, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.AccessTokenRequest) error); ok {
+		r1 = rf(ctx, accessToken, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) Create(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
@@ -49,6 +75,32 @@ func (_m *FakePublicDashboardService) Create(ctx context.Context, u *user.Signed
 	return r0, r1
 }
 
+// CreateToken provides a mock function with given fields: ctx, dto
+func (_m *FakePublicDashboardService) CreateToken(ctx context.Context, dto *models.SavePublicDashboardTokenDTO) (*models.PublicDashboardTokenWithSecret, error) {
+	ret := _m.Called(ctx, dto)
+
+	var r0 *models.PublicDashboardTokenWithSecret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SavePublicDashboardTokenDTO) (*models.PublicDashboardTokenWithSecret, error)); ok {
+		return rf(ctx, dto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SavePublicDashboardTokenDTO) *models.PublicDashboardTokenWithSecret); ok {
+		r0 = rf(ctx, dto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardTokenWithSecret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.SavePublicDashboardTokenDTO) error); ok {
+		r1 = rf(ctx, dto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, uid, dashboardUid
 func (_m *FakePublicDashboardService) Delete(ctx context.Context, uid string, dashboardUid string) error {
 	ret := _m.Called(ctx, uid, dashboardUid)
@@ -351,6 +403,32 @@ func (_m *FakePublicDashboardService) FindPublicDashboardAndDashboardByAccessTok
 	return r0, r1, r2
 }
 
+// FindTokens provides a mock function with given fields: ctx, orgID, dashboardUid, uid
+func (_m *FakePublicDashboardService) FindTokens(ctx context.Context, orgID int64, dashboardUid string, uid string) ([]*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, orgID, dashboardUid, uid)
+
+	var r0 []*models.PublicDashboardToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) ([]*models.PublicDashboardToken, error)); ok {
+		return rf(ctx, orgID, dashboardUid, uid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) []*models.PublicDashboardToken); ok {
+		r0 = rf(ctx, orgID, dashboardUid, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PublicDashboardToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, orgID, dashboardUid, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMetricRequest provides a mock function with given fields: ctx, dashboard, publicDashboard, panelId, reqDTO
 func (_m *FakePublicDashboardService) GetMetricRequest(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
 	ret := _m.Called(ctx, dashboard, publicDashboard, panelId, reqDTO)
@@ -451,23 +529,39 @@ func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context,
 	return r0, r1
 }
 
-// NewPublicDashboardAccessToken provides a mock function with given fields: ctx
-func (_m *FakePublicDashboardService) NewPublicDashboardAccessToken(ctx context.Context) (string, error) {
-	ret := _m.Called(ctx)
+// RevokeToken provides a mock function with given fields: ctx, orgID, dashboardUid, uid, tokenUid
+func (_m *FakePublicDashboardService) RevokeToken(ctx context.Context, orgID int64, dashboardUid string, uid string, tokenUid string) error {
+	ret := _m.Called(ctx, orgID, dashboardUid, uid, tokenUid)
 
-	var r0 string
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, string) error); ok {
+		r0 = rf(ctx, orgID, dashboardUid, uid, tokenUid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, u, dto
+func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
+	ret := _m.Called(ctx, u, dto)
+
+	var r0 *models.PublicDashboard
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *models.SavePublicDashboardDTO) (*models.PublicDashboard, error)); ok {
+		return rf(ctx, u, dto)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *models.SavePublicDashboardDTO) *models.PublicDashboard); ok {
+		r0 = rf(ctx, u, dto)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboard)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, *models.SavePublicDashboardDTO) error); ok {
+		r1 = rf(ctx, u, dto)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -475,8 +569,8 @@ func (_m *FakePublicDashboardService) NewPublicDashboardAccessToken(ctx context.
 	return r0, r1
 }
 
-// NewPublicDashboardUid provides a mock function with given fields: ctx
-func (_m *FakePublicDashboardService) NewPublicDashboardUid(ctx context.Context) (string, error) {
+// NewPublicDashboardAccessToken provides a mock function with given fields: ctx
+func (_m *FakePublicDashboardService) NewPublicDashboardAccessToken(ctx context.Context) (string, error) {
 	ret := _m.Called(ctx)
 
 	var r0 string
@@ -499,25 +593,23 @@ func (_m *FakePublicDashboardService) NewPublicDashboardUid(ctx context.Context)
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, u, dto
-func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
-	ret := _m.Called(ctx, u, dto)
+// NewPublicDashboardUid provides a mock function with given fields: ctx
+func (_m *FakePublicDashboardService) NewPublicDashboardUid(ctx context.Context) (string, error) {
+	ret := _m.Called(ctx)
 
-	var r0 *models.PublicDashboard
+	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *models.SavePublicDashboardDTO) (*models.PublicDashboard, error)); ok {
-		return rf(ctx, u, dto)
+	if rf, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *models.SavePublicDashboardDTO) *models.PublicDashboard); ok {
-		r0 = rf(ctx, u, dto)
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.PublicDashboard)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, *models.SavePublicDashboardDTO) error); ok {
-		r1 = rf(ctx, u, dto)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -7,6 +7,8 @@ import (
 
 	models "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // FakePublicDashboardStore is an autogenerated mock type for the Store type
@@ -38,6 +40,30 @@ func (_m *FakePublicDashboardStore) Create(ctx context.Context, cmd models.SaveP
 	return r0, r1
 }
 
+// CreateToken provides a mock function with given fields: ctx, token
+func (_m *FakePublicDashboardStore) CreateToken(ctx context.Context, token *models.PublicDashboardToken) (int64, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboardToken) (int64, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboardToken) int64); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.PublicDashboardToken) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, uid
 func (_m *FakePublicDashboardStore) Delete(ctx context.Context, uid string) (int64, error) {
 	ret := _m.Called(ctx, uid)
@@ -240,6 +266,58 @@ func (_m *FakePublicDashboardStore) FindByFolder(ctx context.Context, orgId int6
 	return r0, r1
 }
 
+// FindToken provides a mock function with given fields: ctx, token
+func (_m *FakePublicDashboardStore) FindToken(ctx context.Context, token string) (*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *models.PublicDashboardToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.PublicDashboardToken, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.PublicDashboardToken); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTokens provides a mock function with given fields: ctx, publicDashboardUid
+func (_m *FakePublicDashboardStore) FindTokens(ctx context.Context, publicDashboardUid string) ([]*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, publicDashboardUid)
+
+	var r0 []*models.PublicDashboardToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*models.PublicDashboardToken, error)); ok {
+		return rf(ctx, publicDashboardUid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*models.PublicDashboardToken); ok {
+		r0 = rf(ctx, publicDashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PublicDashboardToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, publicDashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMetrics provides a mock function with given fields: ctx
 func (_m *FakePublicDashboardStore) GetMetrics(ctx context.Context) (*models.Metrics, error) {
 	ret := _m.Called(ctx)
@@ -290,6 +368,30 @@ func (_m *FakePublicDashboardStore) GetOrgIdByAccessToken(ctx context.Context, a
 	return r0, r1
 }
 
+// RevokeToken provides a mock function with given fields: ctx, publicDashboardUid, uid, revokedAt
+func (_m *FakePublicDashboardStore) RevokeToken(ctx context.Context, publicDashboardUid string, uid string, revokedAt time.Time) (int64, error) {
+	ret := _m.Called(ctx, publicDashboardUid, uid, revokedAt)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (int64, error)); ok {
+		return rf(ctx, publicDashboardUid, uid, revokedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) int64); ok {
+		r0 = rf(ctx, publicDashboardUid, uid, revokedAt)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, publicDashboardUid, uid, revokedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, cmd
 func (_m *FakePublicDashboardStore) Update(ctx context.Context, cmd models.SavePublicDashboardCommand) (int64, error) {
 	ret := _m.Called(ctx, cmd)
@@ -314,6 +416,30 @@ func (_m *FakePublicDashboardStore) Update(ctx context.Context, cmd models.SaveP
 	return r0, r1
 }
 
+// UseToken provides a mock function with given fields: ctx, uid, usedAt
+func (_m *FakePublicDashboardStore) UseToken(ctx context.Context, uid string, usedAt time.Time) (int64, error) {
+	ret := _m.Called(ctx, uid, usedAt)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int64, error)); ok {
+		return rf(ctx, uid, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int64); ok {
+		r0 = rf(ctx, uid, usedAt)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, uid, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewFakePublicDashboardStore creates a new instance of FakePublicDashboardStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewFakePublicDashboardStore(t interface {
@@ -2,6 +2,7 @@ package publicdashboards
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -38,6 +39,11 @@ type Service interface {
 
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
+
+	AuthorizeAccessToken(ctx context.Context, accessToken string, req AccessTokenRequest) (*PublicDashboardToken, error)
+	FindTokens(ctx context.Context, orgID int64, dashboardUid string, uid string) ([]*PublicDashboardToken, error)
+	CreateToken(ctx context.Context, dto *SavePublicDashboardTokenDTO) (*PublicDashboardTokenWithSecret, error)
+	RevokeToken(ctx context.Context, orgID int64, dashboardUid string, uid string, tokenUid string) error
 }
 
 // ServiceWrapper these methods have different behavior between OSS and Enterprise. The latter would call the OSS service first
@@ -63,6 +69,12 @@ type Store interface {
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
 	GetMetrics(ctx context.Context) (*Metrics, error)
+
+	FindToken(ctx context.Context, token string) (*PublicDashboardToken, error)
+	FindTokens(ctx context.Context, publicDashboardUid string) ([]*PublicDashboardToken, error)
+	CreateToken(ctx context.Context, token *PublicDashboardToken) (int64, error)
+	RevokeToken(ctx context.Context, publicDashboardUid string, uid string, revokedAt time.Time) (int64, error)
+	UseToken(ctx context.Context, uid string, usedAt time.Time) (int64, error)
 }
 
 //go:generate mockery --name Middleware --structname FakePublicDashboardMiddleware --inpackage --filename public_dashboard_middleware_mock.go
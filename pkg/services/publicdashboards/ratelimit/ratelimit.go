@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout is the minimum time after which the limiter of a key which made no request is removed
+const idleTimeout = 5 * time.Minute
+
+// maxKeys is the number of keys tracked at once, so that requests from many addresses can't grow the limiter without
+// bounds
+const maxKeys = 100000
+
+// Limiter limits the rate of requests of each key, e.g. a client IP or a public dashboard
+type Limiter struct {
+	limit   rate.Limit
+	burst   int
+	idle    time.Duration
+	maxKeys int
+	now     func() time.Time
+
+	mu        sync.Mutex
+	limiters  map[string]*limiter
+	lastSweep time.Time
+}
+
+type limiter struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+// New returns a limiter allowing rps requests per second and bursts of burst requests per key. It returns nil,
+// which allows every request, when rps is not positive. The burst defaults to rps.
+func New(rps int, burst int) *Limiter {
+	if rps <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = rps
+	}
+	return newLimiter(rate.Limit(rps), burst, idleTimeout)
+}
+
+// NewEvery returns a limiter allowing a request every interval and bursts of burst requests per key, e.g. to limit
+// failed attempts. The limiter of a key is kept until it is full again.
+func NewEvery(interval time.Duration, burst int) *Limiter {
+	return newLimiter(rate.Every(interval), burst, max(idleTimeout, interval*time.Duration(burst)))
+}
+
+func newLimiter(limit rate.Limit, burst int, idle time.Duration) *Limiter {
+	return &Limiter{
+		limit:    limit,
+		burst:    burst,
+		idle:     idle,
+		maxKeys:  maxKeys,
+		now:      time.Now,
+		limiters: make(map[string]*limiter),
+	}
+}
+
+// Allow reports whether a request of the key may happen now
+func (l *Limiter) Allow(key string) bool {
+	if l == nil {
+		return true
+	}
+
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	lim, ok := l.limiters[key]
+	if !ok {
+		if len(l.limiters) >= l.maxKeys {
+			l.evict(now)
+		}
+		lim = &limiter{Limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = lim
+	}
+	lim.lastSeen = now
+
+	return lim.AllowN(now, 1)
+}
+
+// Exhausted reports whether the key can't make a request now, without counting a request
+func (l *Limiter) Exhausted(key string) bool {
+	if l == nil {
+		return false
+	}
+
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lim, ok := l.limiters[key]
+	return ok && lim.TokensAt(now) < 1
+}
+
+// sweep removes the limiters of idle keys, they are full again by the time they are removed
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.idle {
+		return
+	}
+	l.lastSweep = now
+
+	for key, lim := range l.limiters {
+		if now.Sub(lim.lastSeen) >= l.idle {
+			delete(l.limiters, key)
+		}
+	}
+}
+
+// evict makes room for a new key when the limiter tracks maxKeys keys. The idle keys are removed first, otherwise a
+// random key is removed, which at worst lets it burst again.
+func (l *Limiter) evict(now time.Time) {
+	l.lastSweep = time.Time{}
+	l.sweep(now)
+	for key := range l.limiters {
+		if len(l.limiters) < l.maxKeys {
+			return
+		}
+		delete(l.limiters, key)
+	}
+}
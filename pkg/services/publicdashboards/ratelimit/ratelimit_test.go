@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Run("allows every request when disabled", func(t *testing.T) {
+		l := New(0, 10)
+		require.Nil(t, l)
+		for i := 0; i < 100; i++ {
+			assert.True(t, l.Allow("key"))
+		}
+	})
+
+	t.Run("limits each key", func(t *testing.T) {
+		now := time.Now()
+		l := New(1, 2)
+		l.now = func() time.Time { return now }
+
+		assert.True(t, l.Allow("a"))
+		assert.True(t, l.Allow("a"))
+		assert.False(t, l.Allow("a"))
+		assert.True(t, l.Allow("b"))
+
+		now = now.Add(time.Second)
+		assert.True(t, l.Allow("a"))
+		assert.False(t, l.Allow("a"))
+	})
+
+	t.Run("the burst defaults to the rate", func(t *testing.T) {
+		now := time.Now()
+		l := New(3, 0)
+		l.now = func() time.Time { return now }
+
+		for i := 0; i < 3; i++ {
+			assert.True(t, l.Allow("a"))
+		}
+		assert.False(t, l.Allow("a"))
+	})
+
+	t.Run("limits failed attempts", func(t *testing.T) {
+		now := time.Now()
+		l := NewEvery(time.Minute, 10)
+		l.now = func() time.Time { return now }
+
+		assert.False(t, l.Exhausted("a"))
+		for i := 0; i < 10; i++ {
+			assert.True(t, l.Allow("a"))
+		}
+		assert.True(t, l.Exhausted("a"))
+		assert.False(t, l.Exhausted("b"))
+
+		now = now.Add(time.Minute)
+		assert.False(t, l.Exhausted("a"))
+		assert.True(t, l.Allow("a"))
+		assert.True(t, l.Exhausted("a"))
+
+		// the key is kept until its limiter is full again
+		now = now.Add(idleTimeout)
+		assert.True(t, l.Allow("b"))
+		assert.Contains(t, l.limiters, "a")
+		now = now.Add(10 * time.Minute)
+		assert.True(t, l.Allow("b"))
+		assert.NotContains(t, l.limiters, "a")
+	})
+
+	t.Run("removes idle keys", func(t *testing.T) {
+		now := time.Now()
+		l := New(1, 1)
+		l.now = func() time.Time { return now }
+
+		assert.True(t, l.Allow("a"))
+		assert.True(t, l.Allow("b"))
+		require.Len(t, l.limiters, 2)
+
+		now = now.Add(idleTimeout)
+		assert.True(t, l.Allow("b"))
+		assert.Len(t, l.limiters, 1)
+	})
+
+	t.Run("tracks at most max keys", func(t *testing.T) {
+		now := time.Now()
+		l := New(1, 1)
+		l.now = func() time.Time { return now }
+		l.maxKeys = 2
+
+		assert.True(t, l.Allow("a"))
+		now = now.Add(idleTimeout)
+		assert.True(t, l.Allow("b"))
+		// the idle key is removed first
+		assert.True(t, l.Allow("c"))
+		require.Len(t, l.limiters, 2)
+		assert.NotContains(t, l.limiters, "a")
+
+		assert.True(t, l.Allow("d"))
+		assert.Len(t, l.limiters, 2)
+		assert.Contains(t, l.limiters, "d")
+	})
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// maxQueryCacheEntries is the number of cached responses, so that requests for many time ranges or template variable
+// values can't grow the cache without bounds
+const maxQueryCacheEntries = 10000
+
+// queryCache caches the query responses of public dashboards panels, so that the traffic of a popular public
+// dashboard doesn't reach its data sources
+type queryCache struct {
+	cache      *localcache.CacheService
+	step       time.Duration
+	maxEntries int
+	group      singleflight.Group
+}
+
+// newQueryCache returns nil, which disables the cache, when ttl is not positive
+func newQueryCache(ttl time.Duration, step time.Duration) *queryCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &queryCache{
+		cache:      localcache.New(ttl, 2*ttl),
+		step:       step,
+		maxEntries: maxQueryCacheEntries,
+	}
+}
+
+// roundTimeRange rounds the time range of the request down to the step, so that viewers share cache entries
+func (c *queryCache) roundTimeRange(req *dtos.MetricRequest) {
+	step := c.step.Milliseconds()
+	if step <= 0 {
+		return
+	}
+	round := func(epoch string) string {
+		ms, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return epoch
+		}
+		return strconv.FormatInt(ms-ms%step, 10)
+	}
+	req.From = round(req.From)
+	req.To = round(req.To)
+}
+
+// key identifies the request of a panel. The queries are part of the key as they include the template variables.
+func (c *queryCache) key(publicDashboardUid string, panelID int64, req dtos.MetricRequest) (string, error) {
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(raw)
+	return fmt.Sprintf("%s/%d/%s", publicDashboardUid, panelID, hex.EncodeToString(hash[:])), nil
+}
+
+// getOrQuery returns the cached response or runs the query. Concurrent requests for the same key share a single
+// query, which runs with a context detached from the cancellation of the request which started it, so the other
+// requests don't fail when it is canceled. Responses containing errors are not cached, neither are responses once the
+// cache is full.
+func (c *queryCache) getOrQuery(ctx context.Context, key string, query func(ctx context.Context) (*backend.QueryDataResponse, error)) (*backend.QueryDataResponse, error) {
+	if cached, ok := c.cache.Get(key); ok {
+		metrics.MPublicDashboardQueryCache.WithLabelValues(models.QueryCacheHit).Inc()
+		return cached.(*backend.QueryDataResponse), nil
+	}
+	metrics.MPublicDashboardQueryCache.WithLabelValues(models.QueryCacheMiss).Inc()
+
+	shared := c.group.DoChan(key, func() (any, error) {
+		res, err := query(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if !hasErrors(res) && c.hasRoom() {
+			c.cache.SetDefault(key, res)
+		}
+		return res, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-shared:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return r.Val.(*backend.QueryDataResponse), nil
+	}
+}
+
+// hasRoom reports whether a response can be cached, removing the expired responses when the cache is full
+func (c *queryCache) hasRoom() bool {
+	if c.cache.ItemCount() < c.maxEntries {
+		return true
+	}
+	c.cache.DeleteExpired()
+	return c.cache.ItemCount() < c.maxEntries
+}
+
+func hasErrors(res *backend.QueryDataResponse) bool {
+	for _, r := range res.Responses {
+		if r.Error != nil {
+			return true
+		}
+	}
+	return false
+}
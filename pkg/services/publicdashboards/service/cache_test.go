@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestQueryCache(t *testing.T) {
+	ctx := context.Background()
+	require.Nil(t, newQueryCache(0, time.Second))
+
+	t.Run("rounds the time range down to the step", func(t *testing.T) {
+		c := newQueryCache(time.Minute, 10*time.Second)
+		req := dtos.MetricRequest{From: "1700000012345", To: "1700000029999"}
+		c.roundTimeRange(&req)
+		assert.Equal(t, "1700000010000", req.From)
+		assert.Equal(t, "1700000020000", req.To)
+
+		req = dtos.MetricRequest{From: "now-1h", To: "now"}
+		c.roundTimeRange(&req)
+		assert.Equal(t, "now-1h", req.From)
+	})
+
+	t.Run("the key depends on the panel and the queries", func(t *testing.T) {
+		c := newQueryCache(time.Minute, 0)
+		req := dtos.MetricRequest{From: "1", To: "2", Queries: []*simplejson.Json{simplejson.NewFromAny(map[string]any{"expr": "up{job=\"api\"}"})}}
+		other := dtos.MetricRequest{From: "1", To: "2", Queries: []*simplejson.Json{simplejson.NewFromAny(map[string]any{"expr": "up{job=\"web\"}"})}}
+
+		key, err := c.key("pubdash", 1, req)
+		require.NoError(t, err)
+		same, err := c.key("pubdash", 1, req)
+		require.NoError(t, err)
+		otherPanel, err := c.key("pubdash", 2, req)
+		require.NoError(t, err)
+		otherQuery, err := c.key("pubdash", 1, other)
+		require.NoError(t, err)
+
+		assert.Equal(t, key, same)
+		assert.NotEqual(t, key, otherPanel)
+		assert.NotEqual(t, key, otherQuery)
+	})
+
+	t.Run("caches responses without errors", func(t *testing.T) {
+		c := newQueryCache(time.Minute, 0)
+		var calls int
+		query := func(context.Context) (*backend.QueryDataResponse, error) {
+			calls++
+			return &backend.QueryDataResponse{Responses: backend.Responses{"A": {}}}, nil
+		}
+
+		first, err := c.getOrQuery(ctx, "key", query)
+		require.NoError(t, err)
+		second, err := c.getOrQuery(ctx, "key", query)
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("doesn't cache errors", func(t *testing.T) {
+		c := newQueryCache(time.Minute, 0)
+		var calls int
+		failed := func(context.Context) (*backend.QueryDataResponse, error) {
+			calls++
+			return &backend.QueryDataResponse{Responses: backend.Responses{"A": {Error: errors.New("boom")}}}, nil
+		}
+		_, err := c.getOrQuery(ctx, "key", failed)
+		require.NoError(t, err)
+		_, err = c.getOrQuery(ctx, "key", failed)
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+
+		_, err = c.getOrQuery(ctx, "other", func(context.Context) (*backend.QueryDataResponse, error) { return nil, errors.New("boom") })
+		require.Error(t, err)
+	})
+
+	t.Run("doesn't cache when full", func(t *testing.T) {
+		c := newQueryCache(time.Minute, 0)
+		c.maxEntries = 1
+		query := func(context.Context) (*backend.QueryDataResponse, error) { return &backend.QueryDataResponse{}, nil }
+
+		_, err := c.getOrQuery(ctx, "a", query)
+		require.NoError(t, err)
+		_, err = c.getOrQuery(ctx, "b", query)
+		require.NoError(t, err)
+		assert.Equal(t, 1, c.cache.ItemCount())
+		_, ok := c.cache.Get("b")
+		assert.False(t, ok)
+	})
+
+	t.Run("concurrent requests share the query", func(t *testing.T) {
+		c := newQueryCache(time.Minute, 0)
+		var calls atomic.Int32
+		release := make(chan struct{})
+		query := func(context.Context) (*backend.QueryDataResponse, error) {
+			calls.Add(1)
+			<-release
+			return &backend.QueryDataResponse{}, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := c.getOrQuery(ctx, "key", query)
+				assert.NoError(t, err)
+			}()
+		}
+		// let the requests wait on the first query before it returns
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.LessOrEqual(t, calls.Load(), int32(2))
+	})
+	t.Run("the shared query isn't canceled with the request which started it", func(t *testing.T) {
+		c := newQueryCache(time.Minute, 0)
+		var calls atomic.Int32
+		started := make(chan struct{})
+		release := make(chan struct{})
+		query := func(ctx context.Context) (*backend.QueryDataResponse, error) {
+			if calls.Add(1) == 1 {
+				close(started)
+			}
+			<-release
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return &backend.QueryDataResponse{}, nil
+		}
+
+		canceled, cancel := context.WithCancel(ctx)
+		first := make(chan error)
+		go func() {
+			_, err := c.getOrQuery(canceled, "key", query)
+			first <- err
+		}()
+		<-started
+		second := make(chan error)
+		go func() {
+			_, err := c.getOrQuery(ctx, "key", query)
+			second <- err
+		}()
+
+		cancel()
+		require.ErrorIs(t, <-first, context.Canceled)
+		// let the second request wait on the first query before it returns
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		require.NoError(t, <-second)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
@@ -9,10 +9,11 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/components/simplejson"
-	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardquery"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -139,6 +140,29 @@ func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context,
 		return nil, models.ErrPanelQueriesNotFound.Errorf("GetQueryDataResponse: failed to extract queries from panel")
 	}
 
+	if pd.queryCache == nil {
+		return pd.queryDataSources(ctx, dashboard, publicDashboard, skipDSCache, metricReq)
+	}
+
+	// anonymous viewers can skip the data source cache, but not the cache of the public dashboard
+	pd.queryCache.roundTimeRange(&metricReq)
+	key, err := pd.queryCache.key(publicDashboard.Uid, panelId, metricReq)
+	if err != nil {
+		return nil, models.ErrInternalServerError.Errorf("GetQueryDataResponse: failed to build the cache key: %w", err)
+	}
+
+	return pd.queryCache.getOrQuery(ctx, key, func(ctx context.Context) (*backend.QueryDataResponse, error) {
+		return pd.queryDataSources(ctx, dashboard, publicDashboard, skipDSCache, metricReq)
+	})
+}
+
+// queryDataSources sends the metric request to the data sources unless the public dashboard reached its rate limit
+func (pd *PublicDashboardServiceImpl) queryDataSources(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, skipDSCache bool, metricReq dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	if !pd.dashboardLimiter.Allow(publicDashboard.Uid) {
+		metrics.MPublicDashboardRequestRejected.WithLabelValues(models.RateLimitDashboard).Inc()
+		return nil, models.ErrTooManyRequests.Errorf("queryDataSources: public dashboard %s reached its query rate limit", publicDashboard.Uid)
+	}
+
 	anonymousUser := buildAnonymousUser(ctx, dashboard, pd.features)
 	res, err := pd.QueryDataService.QueryData(ctx, anonymousUser, skipDSCache, metricReq)
 
@@ -156,6 +180,10 @@ func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context,
 
 // buildMetricRequest merges public dashboard parameters with dashboard and returns a metrics request to be sent to query backend
 func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, panelID int64, reqDTO models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
+	if publicDashboard.PanelSettings.IsHidden(panelID) {
+		return dtos.MetricRequest{}, models.ErrPanelNotFound.Errorf("buildMetricRequest: public dashboard panel not found")
+	}
+
 	// group queries by panel
 	queriesByPanel := groupQueriesByPanelId(dashboard.Data)
 	queries, ok := queriesByPanel[panelID]
@@ -163,11 +191,18 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.D
 		return dtos.MetricRequest{}, models.ErrPanelNotFound.Errorf("buildMetricRequest: public dashboard panel not found")
 	}
 
+	// only the values chosen by the owner can be sent to the data sources
+	variables, err := resolveVariables(dashboard.Data, &publicDashboard.VariableSettings, reqDTO.Variables)
+	if err != nil {
+		return dtos.MetricRequest{}, err
+	}
+
 	ts := buildTimeSettings(dashboard, reqDTO, publicDashboard, panelID)
 
 	// determine safe resolution to query data at
 	safeInterval, safeResolution := pd.getSafeIntervalAndMaxDataPoints(reqDTO, ts)
 	for i := range queries {
+		dashboardquery.InterpolateVariables(queries[i], variables)
 		queries[i].Set("intervalMs", safeInterval)
 		queries[i].Set("maxDataPoints", safeResolution)
 		queries[i].Set("queryCachingTTL", reqDTO.QueryCachingTTL)
@@ -226,13 +261,13 @@ func getUniqueDashboardDatasourceUids(dashboard *simplejson.Json) []string {
 
 	for _, panelObj := range flattenedPanels {
 		panel := simplejson.NewFromAny(panelObj)
-		uid := getDataSourceUidFromJson(panel)
+		uid := dashboardquery.DataSourceUID(panel)
 
 		// if uid is for a mixed datasource, get the datasource uids from the targets
 		if uid == "-- Mixed --" {
 			for _, targetObj := range panel.Get("targets").MustArray() {
 				target := simplejson.NewFromAny(targetObj)
-				datasourceUid := getDataSourceUidFromJson(target)
+				datasourceUid := dashboardquery.DataSourceUID(target)
 				if _, ok := exists[datasourceUid]; !ok {
 					datasourceUids = append(datasourceUids, datasourceUid)
 					exists[datasourceUid] = true
@@ -266,74 +301,29 @@ func getFlattenedPanels(dashboard *simplejson.Json) []any {
 	return flatPanels
 }
 
+// groupQueriesByPanelId returns the queries of the panels, the queries without a data source use the data source of
+// their panel
 func groupQueriesByPanelId(dashboard *simplejson.Json) map[int64][]*simplejson.Json {
 	result := make(map[int64][]*simplejson.Json)
 
-	extractQueriesFromPanels(dashboard.Get("panels").MustArray(), result)
-
-	return result
-}
-
-func extractQueriesFromPanels(panels []any, result map[int64][]*simplejson.Json) {
-	for _, panelObj := range panels {
-		panel := simplejson.NewFromAny(panelObj)
-
-		// if the panel is a row and it is collapsed, get the queries from the panels inside the row
-		if panel.Get("type").MustString() == "row" && panel.Get("collapsed").MustBool() {
-			// recursive call to get queries from panels inside a row
-			extractQueriesFromPanels(panel.Get("panels").MustArray(), result)
-			continue
-		}
-
-		var panelQueries []*simplejson.Json
-		hasExpression := panelHasAnExpression(panel)
-
-		for _, queryObj := range panel.Get("targets").MustArray() {
-			query := simplejson.NewFromAny(queryObj)
-
-			// it the panel doesn't have an expression and the query is disabled (hide is true), skip the query
-			// the expression handler will take care later of removing hidden queries which could be necessary to calculate
-			// the value of other queries
-			if !hasExpression && query.Get("hide").MustBool() {
-				continue
-			}
-
+	for panelID, panel := range dashboardquery.GroupQueriesByPanelID(dashboard) {
+		for _, query := range panel.Queries {
 			// We don't support exemplars for public dashboards currently
 			query.Del("exemplar")
 
 			// if query target has no datasource, set it to have the datasource on the panel
 			if _, ok := query.CheckGet("datasource"); !ok {
-				uid := getDataSourceUidFromJson(panel)
-				datasource := map[string]any{"type": "public-ds", "uid": uid}
+				uid := dashboardquery.DataSourceUID(panel.Panel)
+				// the type selects how template variables are formatted
+				dsType := panel.Panel.Get("datasource").Get("type").MustString("public-ds")
+				datasource := map[string]any{"type": dsType, "uid": uid}
 				query.Set("datasource", datasource)
 			}
-			panelQueries = append(panelQueries, query)
-		}
-
-		result[panel.Get("id").MustInt64()] = panelQueries
-	}
-}
-
-func panelHasAnExpression(panel *simplejson.Json) bool {
-	var hasExpression bool
-	for _, queryObj := range panel.Get("targets").MustArray() {
-		query := simplejson.NewFromAny(queryObj)
-		if expr.NodeTypeFromDatasourceUID(getDataSourceUidFromJson(query)) == expr.TypeCMDNode {
-			hasExpression = true
 		}
+		result[panelID] = panel.Queries
 	}
-	return hasExpression
-}
-
-func getDataSourceUidFromJson(query *simplejson.Json) string {
-	uid := query.Get("datasource").Get("uid").MustString()
 
-	// before 8.3 special types could be sent as datasource (expr)
-	if uid == "" {
-		uid = query.Get("datasource").MustString()
-	}
-
-	return uid
+	return result
 }
 
 func sanitizeMetadataFromQueryData(res *backend.QueryDataResponse) {
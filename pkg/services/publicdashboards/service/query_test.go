@@ -16,11 +16,13 @@ import (
 	dashboard2 "github.com/grafana/grafana/pkg/kinds/dashboard"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardquery"
 	dashboardsDB "github.com/grafana/grafana/pkg/services/dashboards/database"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/internal"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/ratelimit"
 	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
@@ -726,6 +728,32 @@ func TestGetQueryDataResponse(t *testing.T) {
 
 		resp, _ := service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdashDto.AccessToken)
 		require.NotNil(t, resp)
+
+		t.Run("Caches the query data and limits the queries of the public dashboard", func(t *testing.T) {
+			cachedQueryService := &query.FakeQueryService{}
+			cachedQueryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{}, nil)
+			service.QueryDataService = cachedQueryService
+			service.queryCache = newQueryCache(time.Minute, 24*time.Hour)
+			service.dashboardLimiter = ratelimit.New(1, 1)
+			t.Cleanup(func() {
+				service.QueryDataService = fakeQueryService
+				service.queryCache = nil
+				service.dashboardLimiter = nil
+			})
+
+			for i := 0; i < 3; i++ {
+				resp, err := service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdashDto.AccessToken)
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+			}
+			cachedQueryService.AssertNumberOfCalls(t, "QueryData", 1)
+
+			// without the cached response, the query is over the limit of the public dashboard
+			service.queryCache = newQueryCache(time.Minute, 24*time.Hour)
+			_, err := service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdashDto.AccessToken)
+			require.ErrorIs(t, err, ErrTooManyRequests)
+			cachedQueryService.AssertNumberOfCalls(t, "QueryData", 1)
+		})
 	})
 }
 
@@ -1311,11 +1339,49 @@ func TestBuildMetricRequest(t *testing.T) {
 
 		require.Len(t, reqDTO.Queries, 1)
 
-		require.Equal(
-			t,
-			simplejson.NewFromAny(nonHiddenQuery),
-			reqDTO.Queries[0],
+		require.Equal(t, "B", reqDTO.Queries[0].Get("refId").MustString())
+		require.Equal(t, nonHiddenQuery["datasource"], reqDTO.Queries[0].Get("datasource").Interface())
+	})
+
+	t.Run("returns an error when panel is hidden", func(t *testing.T) {
+		pubdash := *publicDashboardPD
+		pubdash.PanelSettings = PanelSettings{Hidden: []int64{1}}
+
+		_, err := service.buildMetricRequest(
+			publicDashboard,
+			&pubdash,
+			1,
+			publicDashboardQueryDTO,
 		)
+		require.ErrorContains(t, err, ErrPanelNotFound.Error())
+	})
+
+	t.Run("interpolates the allowed template variable values", func(t *testing.T) {
+		data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+		require.NoError(t, err)
+		dash := &dashboards.Dashboard{Data: data, OrgID: publicDashboard.OrgID}
+		pubdash := *publicDashboardPD
+		pubdash.VariableSettings = VariableSettings{Allowed: map[string][]string{
+			"job":    {"api", "web"},
+			"region": {"eu.west", "us.east", "$__all"},
+		}}
+
+		queryDTO := publicDashboardQueryDTO
+		queryDTO.Variables = map[string][]string{"job": {"web"}}
+		reqDTO, err := service.buildMetricRequest(dash, &pubdash, 1, queryDTO)
+		require.NoError(t, err)
+		require.Len(t, reqDTO.Queries, 1)
+		require.Equal(t, `up{job=~"web", env="prod", region=~"(eu\.west|us\.east)"} [$__interval]`, reqDTO.Queries[0].Get("expr").MustString())
+
+		// multiple values are formatted like the Prometheus data source formats them
+		queryDTO.Variables = map[string][]string{"job": {"api", "web"}}
+		reqDTO, err = service.buildMetricRequest(dash, &pubdash, 1, queryDTO)
+		require.NoError(t, err)
+		require.Equal(t, `up{job=~"(api|web)", env="prod", region=~"(eu\.west|us\.east)"} [$__interval]`, reqDTO.Queries[0].Get("expr").MustString())
+
+		queryDTO.Variables = map[string][]string{"job": {"db"}}
+		_, err = service.buildMetricRequest(dash, &pubdash, 1, queryDTO)
+		require.ErrorIs(t, err, ErrInvalidVariables)
 	})
 }
 
@@ -1803,7 +1869,7 @@ func groupQueriesByDataSource(t *testing.T, queries []*simplejson.Json) (result
 	byDataSource := make(map[string][]*simplejson.Json)
 
 	for _, query := range queries {
-		uid := getDataSourceUidFromJson(query)
+		uid := dashboardquery.DataSourceUID(query)
 		byDataSource[uid] = append(byDataSource[uid], query)
 	}
 
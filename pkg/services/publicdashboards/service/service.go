@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/licensing"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/ratelimit"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/service/intervalv2"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/services/query"
@@ -43,6 +44,9 @@ type PublicDashboardServiceImpl struct {
 	serviceWrapper     publicdashboards.ServiceWrapper
 	dashboardService   dashboards.DashboardService
 	license            licensing.Licensing
+	queryCache         *queryCache
+	dashboardLimiter   *ratelimit.Limiter
+	passwordLimiter    *ratelimit.Limiter
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -77,6 +81,9 @@ func ProvideService(
 		serviceWrapper:     serviceWrapper,
 		dashboardService:   dashboardService,
 		license:            license,
+		queryCache:         newQueryCache(cfg.PublicDashboardsQueryCacheTTL, cfg.PublicDashboardsQueryCacheStep),
+		dashboardLimiter:   ratelimit.New(cfg.PublicDashboardsDashboardQueryRPS, cfg.PublicDashboardsDashboardQueryBurst),
+		passwordLimiter:    ratelimit.NewEvery(tokenPasswordFailureInterval, maxTokenPasswordFailures),
 	}
 }
 
@@ -109,6 +116,8 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
 	sanitizeData(dash.Data)
+	removeHiddenPanels(dash.Data, &pubdash.PanelSettings)
+	sanitizeVariables(dash.Data, &pubdash.VariableSettings)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
 }
@@ -157,6 +166,13 @@ func (pd *PublicDashboardServiceImpl) FindByAccessToken(ctx context.Context, acc
 		return nil, ErrInternalServerError.Errorf("FindByAccessToken: failed to find a public dashboard: %w", err)
 	}
 
+	if pubdash == nil {
+		pubdash, _, err = pd.findByAdditionalToken(ctx, accessToken)
+		if err != nil {
+			return nil, ErrInternalServerError.Errorf("FindByAccessToken: failed to find a public dashboard token: %w", err)
+		}
+	}
+
 	if pubdash == nil {
 		return nil, ErrPublicDashboardNotFound.Errorf("FindByAccessToken: Public dashboard not found accessToken: %s", accessToken)
 	}
@@ -216,7 +232,12 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 	}
 
 	// ensure dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dash, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateVariableSettings(dash, dto.PublicDashboard.VariableSettings)
 	if err != nil {
 		return nil, err
 	}
@@ -275,7 +296,12 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 	}
 
 	// validate dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dash, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateVariableSettings(dash, dto.PublicDashboard.VariableSettings)
 	if err != nil {
 		return nil, err
 	}
@@ -383,13 +409,33 @@ func (pd *PublicDashboardServiceImpl) ExistsEnabledByDashboardUid(ctx context.Co
 func (pd *PublicDashboardServiceImpl) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ctx, span := tracer.Start(ctx, "publicdashboards.ExistsEnabledByAccessToken")
 	defer span.End()
-	return pd.store.ExistsEnabledByAccessToken(ctx, accessToken)
+	exists, err := pd.store.ExistsEnabledByAccessToken(ctx, accessToken)
+	if err != nil || exists {
+		return exists, err
+	}
+
+	// the access token can be an additional token of the public dashboard
+	pubdash, _, err := pd.findByAdditionalToken(ctx, accessToken)
+	if err != nil {
+		return false, err
+	}
+	return pubdash != nil && pubdash.IsEnabled, nil
 }
 
 func (pd *PublicDashboardServiceImpl) GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error) {
 	ctx, span := tracer.Start(ctx, "publicdashboards.GetOrgIdByAccessToken")
 	defer span.End()
-	return pd.store.GetOrgIdByAccessToken(ctx, accessToken)
+	orgId, err := pd.store.GetOrgIdByAccessToken(ctx, accessToken)
+	if err != nil || orgId != 0 {
+		return orgId, err
+	}
+
+	// the access token can be an additional token of the public dashboard
+	_, token, err := pd.findByAdditionalToken(ctx, accessToken)
+	if err != nil || token == nil {
+		return 0, err
+	}
+	return token.OrgId, nil
 }
 
 func (pd *PublicDashboardServiceImpl) Delete(ctx context.Context, uid string, dashboardUid string) error {
@@ -542,6 +588,15 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		share = PublicShareType
 	}
 
+	var panelSettings PanelSettings
+	if dto.PublicDashboard.PanelSettings != nil {
+		panelSettings = *dto.PublicDashboard.PanelSettings
+	}
+	var variableSettings VariableSettings
+	if dto.PublicDashboard.VariableSettings != nil {
+		variableSettings = *dto.PublicDashboard.VariableSettings
+	}
+
 	now := time.Now()
 
 	return &PublicDashboard{
@@ -552,6 +607,8 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		AnnotationsEnabled:   annotationsEnabled,
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         &TimeSettings{},
+		PanelSettings:        panelSettings,
+		VariableSettings:     variableSettings,
 		Share:                share,
 		CreatedBy:            dto.UserId,
 		CreatedAt:            now,
@@ -572,12 +629,23 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		share = pd.Share
 	}
 
+	panelSettings := pd.PanelSettings
+	if pubdashDTO.PanelSettings != nil {
+		panelSettings = *pubdashDTO.PanelSettings
+	}
+	variableSettings := pd.VariableSettings
+	if pubdashDTO.VariableSettings != nil {
+		variableSettings = *pubdashDTO.VariableSettings
+	}
+
 	return &PublicDashboard{
 		Uid:                  pd.Uid,
 		IsEnabled:            isEnabled,
 		AnnotationsEnabled:   annotationsEnabled,
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         pd.TimeSettings,
+		PanelSettings:        panelSettings,
+		VariableSettings:     variableSettings,
 		Share:                share,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            time.Now(),
@@ -385,6 +385,7 @@ func TestGetPublicDashboardForView(t *testing.T) {
 		t.Run(test.Name, func(t *testing.T) {
 			fakeStore := &FakePublicDashboardStore{}
 			fakeStore.On("FindByAccessToken", mock.Anything, mock.Anything).Return(test.StoreResp.pd, test.StoreResp.err)
+			fakeStore.On("FindToken", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 			fakeDashboardService := &dashboards.FakeDashboardService{}
 			fakeDashboardService.On("GetDashboard", mock.Anything, mock.Anything, mock.Anything).Return(test.StoreResp.d, test.StoreResp.err)
 			service, _, _ := newPublicDashboardServiceImpl(t, fakeStore, fakeDashboardService, nil)
@@ -496,6 +497,7 @@ func TestGetPublicDashboard(t *testing.T) {
 			fakeDashboardService.On("GetDashboard", mock.Anything, mock.Anything, mock.Anything).Return(test.StoreResp.d, test.StoreResp.err)
 			fakeStore := &FakePublicDashboardStore{}
 			fakeStore.On("FindByAccessToken", mock.Anything, mock.Anything).Return(test.StoreResp.pd, test.StoreResp.err)
+			fakeStore.On("FindToken", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 			service, _, _ := newPublicDashboardServiceImpl(t, fakeStore, fakeDashboardService, nil)
 
 			pdc, dash, err := service.FindPublicDashboardAndDashboardByAccessToken(context.Background(), test.AccessToken)
@@ -557,6 +559,7 @@ func TestGetEnabledPublicDashboard(t *testing.T) {
 		t.Run(test.Name, func(t *testing.T) {
 			fakeStore := &FakePublicDashboardStore{}
 			fakeStore.On("FindByAccessToken", mock.Anything, mock.Anything).Return(test.StoreResp.pd, test.StoreResp.err)
+			fakeStore.On("FindToken", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 			fakeDashboardService := &dashboards.FakeDashboardService{}
 			fakeDashboardService.On("GetDashboard", mock.Anything, mock.Anything, mock.Anything).Return(test.StoreResp.d, test.StoreResp.err)
 			service, _, _ := newPublicDashboardServiceImpl(t, fakeStore, fakeDashboardService, nil)
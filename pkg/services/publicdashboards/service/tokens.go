@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// tokenUseDuration is how long the requests of a dashboard view, like its panel queries, are allowed after the view
+// counted as a use of a token with a usage limit
+const tokenUseDuration = time.Hour
+
+// maxTokenPasswordFailures wrong passwords are accepted for a token, then one more every tokenPasswordFailureInterval,
+// so that the password can't be guessed. The failures are counted by each Grafana instance.
+const (
+	maxTokenPasswordFailures     = 10
+	tokenPasswordFailureInterval = time.Minute
+)
+
+// AuthorizeAccessToken checks the restrictions of the token used to access a public dashboard. The access token of
+// the public dashboard itself is not restricted, in which case no token is returned.
+func (pd *PublicDashboardServiceImpl) AuthorizeAccessToken(ctx context.Context, accessToken string, req AccessTokenRequest) (*PublicDashboardToken, error) {
+	ctx, span := tracer.Start(ctx, "publicdashboards.AuthorizeAccessToken")
+	defer span.End()
+
+	pubdash, err := pd.store.FindByAccessToken(ctx, accessToken)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("AuthorizeAccessToken: failed to find a public dashboard: %w", err)
+	}
+	if pubdash != nil {
+		return nil, nil
+	}
+
+	token, err := pd.store.FindToken(ctx, accessToken)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("AuthorizeAccessToken: failed to find a public dashboard token: %w", err)
+	}
+	if token == nil || token.IsRevoked() {
+		return nil, ErrPublicDashboardNotFound.Errorf("AuthorizeAccessToken: Public dashboard not found accessToken: %s", accessToken)
+	}
+
+	now := time.Now()
+	if token.IsExpired(now) {
+		return nil, ErrTokenExpired.Errorf("AuthorizeAccessToken: token %s expired", token.Uid)
+	}
+
+	if !token.IPAllowlist.Allows(req.RemoteAddr) {
+		pd.log.Warn("Public dashboard token used from an address which is not allowed", "publicDashboardUid", token.PublicDashboardUid, "tokenUid", token.Uid, "remoteAddr", req.RemoteAddr)
+		return nil, ErrTokenIPNotAllowed.Errorf("AuthorizeAccessToken: address %s is not allowed for token %s", req.RemoteAddr, token.Uid)
+	}
+
+	if token.PasswordHash != "" {
+		// the right password is rejected too, otherwise it could be found with more attempts
+		if pd.passwordLimiter.Exhausted(token.Uid) {
+			return nil, ErrTooManyRequests.Errorf("AuthorizeAccessToken: too many wrong passwords for token %s", token.Uid)
+		}
+		hash, err := util.EncodePassword(req.Password, token.PasswordSalt)
+		if err != nil {
+			return nil, ErrInternalServerError.Errorf("AuthorizeAccessToken: failed to encode password: %w", err)
+		}
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(token.PasswordHash)) != 1 {
+			// viewers first open the dashboard without a password, only wrong passwords are counted
+			if req.Password != "" {
+				pd.passwordLimiter.Allow(token.Uid)
+				pd.log.Warn("Wrong password for public dashboard token", "publicDashboardUid", token.PublicDashboardUid, "tokenUid", token.Uid, "remoteAddr", req.RemoteAddr)
+			}
+			return nil, ErrTokenPasswordRequired.Errorf("AuthorizeAccessToken: invalid password for token %s", token.Uid)
+		}
+	}
+
+	if req.CountUse {
+		affectedRows, err := pd.store.UseToken(ctx, token.Uid, now)
+		if err != nil {
+			return nil, ErrInternalServerError.Errorf("AuthorizeAccessToken: failed to count the use of token %s: %w", token.Uid, err)
+		}
+		if affectedRows == 0 {
+			return nil, ErrTokenUsageLimit.Errorf("AuthorizeAccessToken: token %s reached its usage limit", token.Uid)
+		}
+		token.UseCount++
+		token.LastUsedAt = &now
+
+		pd.log.Info("Public dashboard viewed with token", "publicDashboardUid", token.PublicDashboardUid, "tokenUid", token.Uid, "tokenName", token.Name, "remoteAddr", req.RemoteAddr)
+	} else {
+		// the panel queries and annotations of a token with a usage limit are only allowed shortly after a view,
+		// otherwise they could be requested without ever using the token
+		if token.MaxUses > 0 && (token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > tokenUseDuration) {
+			return nil, ErrTokenUsageLimit.Errorf("AuthorizeAccessToken: token %s was not used to view the dashboard recently", token.Uid)
+		}
+		pd.log.Debug("Public dashboard accessed with token", "publicDashboardUid", token.PublicDashboardUid, "tokenUid", token.Uid, "tokenName", token.Name, "remoteAddr", req.RemoteAddr)
+	}
+
+	token.HasPassword = token.PasswordHash != ""
+	return token, nil
+}
+
+// FindTokens Returns the additional access tokens of a public dashboard
+func (pd *PublicDashboardServiceImpl) FindTokens(ctx context.Context, orgID int64, dashboardUid string, uid string) ([]*PublicDashboardToken, error) {
+	ctx, span := tracer.Start(ctx, "publicdashboards.FindTokens")
+	defer span.End()
+
+	if _, err := pd.findForTokens(ctx, orgID, dashboardUid, uid); err != nil {
+		return nil, err
+	}
+
+	tokens, err := pd.store.FindTokens(ctx, uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindTokens: failed to find the tokens of public dashboard %s: %w", uid, err)
+	}
+
+	for _, token := range tokens {
+		token.HasPassword = token.PasswordHash != ""
+	}
+
+	return tokens, nil
+}
+
+// CreateToken Creates an additional access token for a public dashboard. The access token is only returned once.
+func (pd *PublicDashboardServiceImpl) CreateToken(ctx context.Context, dto *SavePublicDashboardTokenDTO) (*PublicDashboardTokenWithSecret, error) {
+	ctx, span := tracer.Start(ctx, "publicdashboards.CreateToken")
+	defer span.End()
+
+	pubdash, err := pd.findForTokens(ctx, dto.OrgID, dto.DashboardUid, dto.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := validation.ValidatePublicDashboardToken(dto.Token, now); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := pd.NewPublicDashboardAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &PublicDashboardToken{
+		Uid:                util.GenerateShortUID(),
+		PublicDashboardUid: pubdash.Uid,
+		OrgId:              pubdash.OrgId,
+		Token:              accessToken,
+		Name:               dto.Token.Name,
+		ExpiresAt:          dto.Token.ExpiresAt,
+		MaxUses:            dto.Token.MaxUses,
+		IPAllowlist:        dto.Token.IPAllowlist,
+		CreatedBy:          dto.UserId,
+		CreatedAt:          now,
+	}
+
+	if dto.Token.Password != "" {
+		if token.PasswordSalt, err = util.GetRandomString(10); err != nil {
+			return nil, ErrInternalServerError.Errorf("CreateToken: failed to generate a salt: %w", err)
+		}
+		if token.PasswordHash, err = util.EncodePassword(dto.Token.Password, token.PasswordSalt); err != nil {
+			return nil, ErrInternalServerError.Errorf("CreateToken: failed to encode password: %w", err)
+		}
+		token.HasPassword = true
+	}
+
+	affectedRows, err := pd.store.CreateToken(ctx, token)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("CreateToken: failed to create the token: %w", err)
+	}
+	if affectedRows == 0 {
+		return nil, ErrInternalServerError.Errorf("CreateToken: failed to create the token")
+	}
+
+	pd.log.Info("Public dashboard token created", "publicDashboardUid", pubdash.Uid, "tokenUid", token.Uid, "tokenName", token.Name, "userId", dto.UserId)
+
+	return &PublicDashboardTokenWithSecret{PublicDashboardToken: token, AccessToken: accessToken}, nil
+}
+
+// RevokeToken Revokes an additional access token of a public dashboard
+func (pd *PublicDashboardServiceImpl) RevokeToken(ctx context.Context, orgID int64, dashboardUid string, uid string, tokenUid string) error {
+	ctx, span := tracer.Start(ctx, "publicdashboards.RevokeToken")
+	defer span.End()
+
+	if _, err := pd.findForTokens(ctx, orgID, dashboardUid, uid); err != nil {
+		return err
+	}
+
+	affectedRows, err := pd.store.RevokeToken(ctx, uid, tokenUid, time.Now())
+	if err != nil {
+		return ErrInternalServerError.Errorf("RevokeToken: failed to revoke token %s: %w", tokenUid, err)
+	}
+	if affectedRows == 0 {
+		return ErrTokenNotFound.Errorf("RevokeToken: token not found by uid: %s", tokenUid)
+	}
+
+	pd.log.Info("Public dashboard token revoked", "publicDashboardUid", uid, "tokenUid", tokenUid)
+
+	return nil
+}
+
+// findForTokens finds the public dashboard of the org and validates it belongs to the dashboard
+func (pd *PublicDashboardServiceImpl) findForTokens(ctx context.Context, orgID int64, dashboardUid string, uid string) (*PublicDashboard, error) {
+	pubdash, err := pd.store.Find(ctx, uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("findForTokens: failed to find public dashboard by uid: %s: %w", uid, err)
+	}
+	if pubdash == nil || pubdash.OrgId != orgID {
+		return nil, ErrPublicDashboardNotFound.Errorf("findForTokens: public dashboard not found by uid: %s", uid)
+	}
+	if pubdash.DashboardUid != dashboardUid {
+		return nil, ErrInvalidUid.Errorf("findForTokens: the public dashboard does not belong to the dashboard")
+	}
+	return pubdash, nil
+}
+
+// findByAdditionalToken returns the public dashboard of an additional access token which is neither revoked nor
+// expired. The other restrictions of the token are checked by AuthorizeAccessToken.
+func (pd *PublicDashboardServiceImpl) findByAdditionalToken(ctx context.Context, accessToken string) (*PublicDashboard, *PublicDashboardToken, error) {
+	token, err := pd.store.FindToken(ctx, accessToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	if token == nil || token.IsRevoked() || token.IsExpired(time.Now()) {
+		return nil, nil, nil
+	}
+
+	pubdash, err := pd.store.Find(ctx, token.PublicDashboardUid)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pubdash, token, nil
+}
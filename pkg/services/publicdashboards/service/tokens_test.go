@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/ratelimit"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestAuthorizeAccessToken(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	longAgo := time.Now().Add(-2 * time.Hour)
+	future := time.Now().Add(time.Hour)
+	hash, err := util.EncodePassword("secret", "salt")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		pubdash  *PublicDashboard
+		token    *PublicDashboardToken
+		req      AccessTokenRequest
+		used     int64
+		wantErr  error
+		useToken bool
+	}{
+		{
+			name:    "the access token of the public dashboard is not restricted",
+			pubdash: &PublicDashboard{Uid: "pubdash"},
+		},
+		{
+			name:    "unknown tokens are not found",
+			wantErr: ErrPublicDashboardNotFound,
+		},
+		{
+			name:    "revoked tokens are not found",
+			token:   &PublicDashboardToken{Uid: "token", RevokedAt: &past},
+			wantErr: ErrPublicDashboardNotFound,
+		},
+		{
+			name:    "expired tokens are rejected",
+			token:   &PublicDashboardToken{Uid: "token", ExpiresAt: &past},
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "addresses which are not allowed are rejected",
+			token:   &PublicDashboardToken{Uid: "token", IPAllowlist: IPAllowlist{"10.0.0.0/8"}},
+			req:     AccessTokenRequest{RemoteAddr: "192.168.0.1"},
+			wantErr: ErrTokenIPNotAllowed,
+		},
+		{
+			name:    "a wrong password is rejected",
+			token:   &PublicDashboardToken{Uid: "token", PasswordHash: hash, PasswordSalt: "salt"},
+			req:     AccessTokenRequest{Password: "wrong"},
+			wantErr: ErrTokenPasswordRequired,
+		},
+		{
+			name:     "the usage limit is enforced",
+			token:    &PublicDashboardToken{Uid: "token", MaxUses: 1},
+			req:      AccessTokenRequest{CountUse: true},
+			used:     0,
+			useToken: true,
+			wantErr:  ErrTokenUsageLimit,
+		},
+		{
+			name:     "a valid token is used",
+			token:    &PublicDashboardToken{Uid: "token", ExpiresAt: &future, IPAllowlist: IPAllowlist{"10.0.0.0/8"}, PasswordHash: hash, PasswordSalt: "salt"},
+			req:      AccessTokenRequest{RemoteAddr: "10.1.2.3", Password: "secret", CountUse: true},
+			used:     1,
+			useToken: true,
+		},
+		{
+			name:  "a valid token is not counted when it isn't a use",
+			token: &PublicDashboardToken{Uid: "token"},
+		},
+		{
+			name:    "the requests of a token with a usage limit are rejected when it was never used",
+			token:   &PublicDashboardToken{Uid: "token", MaxUses: 1},
+			wantErr: ErrTokenUsageLimit,
+		},
+		{
+			name:    "the requests of a token with a usage limit are rejected long after its last use",
+			token:   &PublicDashboardToken{Uid: "token", MaxUses: 1, UseCount: 1, LastUsedAt: &longAgo},
+			wantErr: ErrTokenUsageLimit,
+		},
+		{
+			name:  "the requests of a token with a usage limit are allowed shortly after its last use",
+			token: &PublicDashboardToken{Uid: "token", MaxUses: 1, UseCount: 1, LastUsedAt: &past},
+			used:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := NewFakePublicDashboardStore(t)
+			store.On("FindByAccessToken", mock.Anything, "accesstoken").Return(tc.pubdash, nil)
+			if tc.pubdash == nil {
+				store.On("FindToken", mock.Anything, "accesstoken").Return(tc.token, nil)
+			}
+			if tc.useToken {
+				store.On("UseToken", mock.Anything, "token", mock.Anything).Return(tc.used, nil)
+			}
+			service := &PublicDashboardServiceImpl{store: store, log: log.NewNopLogger()}
+
+			token, err := service.AuthorizeAccessToken(context.Background(), "accesstoken", tc.req)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.pubdash != nil {
+				assert.Nil(t, token)
+				return
+			}
+			require.NotNil(t, token)
+			assert.EqualValues(t, tc.used, token.UseCount)
+		})
+	}
+}
+
+func TestAuthorizeAccessTokenPasswordFailures(t *testing.T) {
+	hash, err := util.EncodePassword("secret", "salt")
+	require.NoError(t, err)
+	store := NewFakePublicDashboardStore(t)
+	store.On("FindByAccessToken", mock.Anything, "accesstoken").Return(nil, nil)
+	store.On("FindToken", mock.Anything, "accesstoken").Return(func(context.Context, string) *PublicDashboardToken {
+		return &PublicDashboardToken{Uid: "token", PasswordHash: hash, PasswordSalt: "salt"}
+	}, nil)
+	service := &PublicDashboardServiceImpl{
+		store:           store,
+		log:             log.NewNopLogger(),
+		passwordLimiter: ratelimit.NewEvery(tokenPasswordFailureInterval, maxTokenPasswordFailures),
+	}
+	authorize := func(password string) error {
+		_, err := service.AuthorizeAccessToken(context.Background(), "accesstoken", AccessTokenRequest{Password: password})
+		return err
+	}
+
+	// requests without a password are not counted
+	for i := 0; i < 2*maxTokenPasswordFailures; i++ {
+		require.ErrorIs(t, authorize(""), ErrTokenPasswordRequired)
+	}
+	require.NoError(t, authorize("secret"))
+
+	for i := 0; i < maxTokenPasswordFailures; i++ {
+		require.ErrorIs(t, authorize("wrong"), ErrTokenPasswordRequired)
+	}
+	require.ErrorIs(t, authorize("wrong"), ErrTooManyRequests)
+	require.ErrorIs(t, authorize("secret"), ErrTooManyRequests)
+}
+
+func TestCreateToken(t *testing.T) {
+	pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dashboard", OrgId: 1}
+
+	t.Run("creates a token with a hashed password", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		store.On("FindByAccessToken", mock.Anything, mock.Anything).Return(nil, nil)
+		var created *PublicDashboardToken
+		store.On("CreateToken", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			created = args.Get(1).(*PublicDashboardToken)
+		}).Return(int64(1), nil)
+		service := &PublicDashboardServiceImpl{store: store, log: log.NewNopLogger()}
+
+		token, err := service.CreateToken(context.Background(), &SavePublicDashboardTokenDTO{
+			Uid:          "pubdash",
+			DashboardUid: "dashboard",
+			OrgID:        1,
+			UserId:       7,
+			Token:        &PublicDashboardTokenDTO{Name: "partner", MaxUses: 5, Password: "secret"},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.AccessToken)
+		assert.Equal(t, token.AccessToken, created.Token)
+		assert.Equal(t, "pubdash", created.PublicDashboardUid)
+		assert.EqualValues(t, 5, created.MaxUses)
+		assert.EqualValues(t, 7, created.CreatedBy)
+		assert.True(t, token.HasPassword)
+		assert.NotEqual(t, "secret", created.PasswordHash)
+
+		hash, err := util.EncodePassword("secret", created.PasswordSalt)
+		require.NoError(t, err)
+		assert.Equal(t, hash, created.PasswordHash)
+	})
+
+	t.Run("rejects invalid tokens", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		service := &PublicDashboardServiceImpl{store: store, log: log.NewNopLogger()}
+
+		_, err := service.CreateToken(context.Background(), &SavePublicDashboardTokenDTO{
+			Uid:          "pubdash",
+			DashboardUid: "dashboard",
+			OrgID:        1,
+			Token:        &PublicDashboardTokenDTO{IPAllowlist: IPAllowlist{"invalid"}},
+		})
+		require.ErrorIs(t, err, ErrInvalidIPAllowlist)
+	})
+
+	t.Run("rejects public dashboards of another dashboard", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		service := &PublicDashboardServiceImpl{store: store, log: log.NewNopLogger()}
+
+		_, err := service.CreateToken(context.Background(), &SavePublicDashboardTokenDTO{
+			Uid:          "pubdash",
+			DashboardUid: "other",
+			OrgID:        1,
+			Token:        &PublicDashboardTokenDTO{},
+		})
+		require.ErrorIs(t, err, ErrInvalidUid)
+	})
+}
+
+func TestRevokeToken(t *testing.T) {
+	pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dashboard", OrgId: 1}
+
+	t.Run("revokes the token", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		store.On("RevokeToken", mock.Anything, "pubdash", "token", mock.Anything).Return(int64(1), nil)
+		service := &PublicDashboardServiceImpl{store: store, log: log.NewNopLogger()}
+
+		require.NoError(t, service.RevokeToken(context.Background(), 1, "dashboard", "pubdash", "token"))
+	})
+
+	t.Run("returns not found when the token doesn't exist or is already revoked", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		store.On("RevokeToken", mock.Anything, "pubdash", "token", mock.Anything).Return(int64(0), nil)
+		service := &PublicDashboardServiceImpl{store: store, log: log.NewNopLogger()}
+
+		require.ErrorIs(t, service.RevokeToken(context.Background(), 1, "dashboard", "pubdash", "token"), ErrTokenNotFound)
+	})
+
+	t.Run("returns not found when the public dashboard belongs to another org", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		service := &PublicDashboardServiceImpl{store: store, log: log.NewNopLogger()}
+
+		require.ErrorIs(t, service.RevokeToken(context.Background(), 2, "dashboard", "pubdash", "token"), ErrPublicDashboardNotFound)
+		store.AssertNotCalled(t, "RevokeToken")
+	})
+}
+
+func TestFindByAdditionalAccessToken(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dashboard", OrgId: 3, IsEnabled: true}
+
+	setup := func(t *testing.T, token *PublicDashboardToken) *PublicDashboardServiceImpl {
+		store := NewFakePublicDashboardStore(t)
+		store.On("FindByAccessToken", mock.Anything, "accesstoken").Return(nil, nil).Maybe()
+		store.On("ExistsEnabledByAccessToken", mock.Anything, "accesstoken").Return(false, nil).Maybe()
+		store.On("GetOrgIdByAccessToken", mock.Anything, "accesstoken").Return(int64(0), nil).Maybe()
+		store.On("FindToken", mock.Anything, "accesstoken").Return(token, nil)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil).Maybe()
+		return &PublicDashboardServiceImpl{store: store, log: log.NewNopLogger()}
+	}
+
+	t.Run("finds the public dashboard of a valid token", func(t *testing.T) {
+		service := setup(t, &PublicDashboardToken{Uid: "token", PublicDashboardUid: "pubdash", OrgId: 3})
+
+		found, err := service.FindByAccessToken(context.Background(), "accesstoken")
+		require.NoError(t, err)
+		assert.Equal(t, pubdash, found)
+
+		exists, err := service.ExistsEnabledByAccessToken(context.Background(), "accesstoken")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		orgId, err := service.GetOrgIdByAccessToken(context.Background(), "accesstoken")
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, orgId)
+	})
+
+	t.Run("ignores revoked tokens", func(t *testing.T) {
+		service := setup(t, &PublicDashboardToken{Uid: "token", PublicDashboardUid: "pubdash", OrgId: 3, RevokedAt: &past})
+
+		_, err := service.FindByAccessToken(context.Background(), "accesstoken")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+
+		exists, err := service.ExistsEnabledByAccessToken(context.Background(), "accesstoken")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		orgId, err := service.GetOrgIdByAccessToken(context.Background(), "accesstoken")
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, orgId)
+	})
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardquery"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// templateVariable is a template variable of the dashboard
+type templateVariable struct {
+	name     string
+	varType  string
+	current  []string
+	multi    bool
+	allValue string
+}
+
+func getTemplateVariables(dashboard *simplejson.Json) []templateVariable {
+	var variables []templateVariable
+	for _, obj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		variable := templateVariable{
+			name:     v.Get("name").MustString(),
+			varType:  v.Get("type").MustString(),
+			current:  dashboardquery.StringOrArray(v.GetPath("current", "value")),
+			multi:    v.Get("multi").MustBool() || v.Get("includeAll").MustBool(),
+			allValue: v.Get("allValue").MustString(),
+		}
+		if variable.name != "" {
+			variables = append(variables, variable)
+		}
+	}
+	return variables
+}
+
+// validateVariableSettings checks the allowed values refer to template variables of the dashboard
+func validateVariableSettings(dashboard *dashboards.Dashboard, settings *models.VariableSettings) error {
+	if settings == nil || dashboard == nil {
+		return nil
+	}
+	variables := make(map[string]templateVariable)
+	for _, v := range getTemplateVariables(dashboard.Data) {
+		variables[v.name] = v
+	}
+	for name, values := range settings.Allowed {
+		v, ok := variables[name]
+		if !ok {
+			return models.ErrInvalidVariableSettings.Errorf("validateVariableSettings: dashboard has no template variable %s", name)
+		}
+		if v.varType == "adhoc" {
+			return models.ErrInvalidVariableSettings.Errorf("validateVariableSettings: ad hoc filter %s can not be changed by viewers", name)
+		}
+		if len(values) == 0 {
+			return models.ErrInvalidVariableSettings.Errorf("validateVariableSettings: template variable %s has no allowed values", name)
+		}
+	}
+	return nil
+}
+
+// resolveVariables returns the value of each template variable. The dashboard values are used
+// unless the request selects values which are allowed by the public dashboard.
+func resolveVariables(dashboard *simplejson.Json, settings *models.VariableSettings, requested map[string][]string) (map[string]dashboardquery.Variable, error) {
+	variables := getTemplateVariables(dashboard)
+	known := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		known[v.name] = true
+	}
+	for name := range requested {
+		if !known[name] {
+			return nil, models.ErrInvalidVariables.Errorf("resolveVariables: unknown template variable %s", name)
+		}
+	}
+
+	values := make(map[string]dashboardquery.Variable, len(variables))
+	for _, v := range variables {
+		if v.varType == "adhoc" {
+			continue
+		}
+		selected := v.current
+		allowed, hasAllowed := settings.AllowedValues(v.name)
+		if req, ok := requested[v.name]; ok {
+			valid := allowed
+			if !hasAllowed {
+				// viewers send every variable, those which can't be changed must keep the dashboard value
+				if !slices.Equal(req, v.current) {
+					return nil, models.ErrInvalidVariables.Errorf("resolveVariables: template variable %s can not be changed", v.name)
+				}
+				valid = v.current
+			}
+			for _, value := range req {
+				if !slices.Contains(valid, value) {
+					return nil, models.ErrInvalidVariables.Errorf("resolveVariables: value is not allowed for template variable %s", v.name)
+				}
+			}
+			selected = req
+		}
+		variable := dashboardquery.Variable{Values: selected, Multi: v.multi}
+		if slices.Contains(selected, dashboardquery.AllValue) {
+			switch {
+			case v.allValue != "":
+				variable = dashboardquery.Variable{Values: []string{v.allValue}, Multi: v.multi, Raw: true}
+			case hasAllowed:
+				// all the values viewers can select, the options saved with the dashboard may be outdated
+				variable.Values = slices.DeleteFunc(slices.Clone(allowed), func(value string) bool { return value == dashboardquery.AllValue })
+			default:
+				return nil, models.ErrInvalidVariables.Errorf("resolveVariables: template variable %s has no values for all", v.name)
+			}
+			if len(variable.Values) == 0 {
+				return nil, models.ErrInvalidVariables.Errorf("resolveVariables: template variable %s has no values for all", v.name)
+			}
+		}
+		values[v.name] = variable
+	}
+	return values, nil
+}
+
+// sanitizeVariables removes the variable queries and limits the options of the template
+// variables to the values viewers can select
+func sanitizeVariables(data *simplejson.Json, settings *models.VariableSettings) {
+	for _, obj := range data.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		if v.Get("type").MustString() == "adhoc" {
+			continue
+		}
+		current := dashboardquery.StringOrArray(v.GetPath("current", "value"))
+		values, ok := settings.AllowedValues(v.Get("name").MustString())
+		if !ok {
+			// the value can't be changed, so only the current value is kept
+			values = current
+			v.Set("hide", 2)
+		}
+		options := make([]any, 0, len(values))
+		for _, value := range values {
+			options = append(options, map[string]any{"text": value, "value": value, "selected": slices.Contains(current, value)})
+		}
+		v.Set("type", "custom")
+		v.Set("query", strings.Join(values, ","))
+		v.Set("options", options)
+		v.Del("definition")
+		v.Del("datasource")
+		v.Del("regex")
+	}
+}
+
+// removeHiddenPanels removes the panels which are not part of the public dashboard
+func removeHiddenPanels(data *simplejson.Json, settings *models.PanelSettings) {
+	if settings == nil || len(settings.Hidden) == 0 {
+		return
+	}
+	panels := data.Get("panels").MustArray()
+	visible := make([]any, 0, len(panels))
+	for _, obj := range panels {
+		panel := simplejson.NewFromAny(obj)
+		if panel.Get("type").MustString() == "row" && panel.Get("collapsed").MustBool() {
+			removeHiddenPanels(panel, settings)
+		}
+		if settings.IsHidden(panel.Get("id").MustInt64()) {
+			continue
+		}
+		visible = append(visible, obj)
+	}
+	data.Set("panels", visible)
+}
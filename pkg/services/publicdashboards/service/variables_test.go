@@ -0,0 +1,186 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardquery"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const dashboardWithTemplateVariables = `
+{
+  "panels": [
+    {
+      "id": 1,
+      "datasource": {"type": "prometheus", "uid": "ds1"},
+      "targets": [
+        {
+          "expr": "up{job=~\"$job\", env=\"${env}\", region=~\"${region:regex}\"} [$__interval]",
+          "legendFormat": "[[job]]",
+          "refId": "A"
+        }
+      ]
+    },
+    {
+      "id": 2,
+      "type": "row",
+      "collapsed": true,
+      "panels": [
+        {"id": 3, "targets": [{"expr": "secret", "refId": "A"}]},
+        {"id": 4, "targets": [{"expr": "visible", "refId": "A"}]}
+      ]
+    }
+  ],
+  "templating": {
+    "list": [
+      {
+        "name": "job",
+        "type": "query",
+        "multi": true,
+        "query": "label_values(up, job)",
+        "definition": "label_values(up, job)",
+        "datasource": {"type": "prometheus", "uid": "ds1"},
+        "current": {"text": "api", "value": "api"},
+        "options": [{"text": "api", "value": "api"}, {"text": "web", "value": "web"}, {"text": "db", "value": "db"}]
+      },
+      {
+        "name": "env",
+        "type": "custom",
+        "query": "prod,dev",
+        "current": {"text": "prod", "value": "prod"}
+      },
+      {
+        "name": "region",
+        "type": "custom",
+        "includeAll": true,
+        "query": "eu.west,us.east",
+        "current": {"text": "All", "value": ["$__all"]},
+        "options": [{"text": "All", "value": "$__all"}, {"text": "eu.west", "value": "eu.west"}, {"text": "us.east", "value": "us.east"}]
+      },
+      {
+        "name": "filters",
+        "type": "adhoc"
+      }
+    ]
+  }
+}`
+
+func TestResolveVariables(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+	settings := &VariableSettings{Allowed: map[string][]string{
+		"job":    {"api", "web"},
+		"region": {"eu.west", "$__all"},
+	}}
+
+	t.Run("uses the dashboard values by default", func(t *testing.T) {
+		values, err := resolveVariables(data, settings, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]dashboardquery.Variable{
+			"job": {Values: []string{"api"}, Multi: true},
+			"env": {Values: []string{"prod"}},
+			// all the allowed values
+			"region": {Values: []string{"eu.west"}, Multi: true},
+		}, values)
+	})
+
+	t.Run("uses the allowed values from the request", func(t *testing.T) {
+		values, err := resolveVariables(data, settings, map[string][]string{"job": {"api", "web"}, "region": {"eu.west"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"api", "web"}, values["job"].Values)
+		assert.Equal(t, []string{"eu.west"}, values["region"].Values)
+	})
+
+	t.Run("rejects values which are not allowed", func(t *testing.T) {
+		_, err := resolveVariables(data, settings, map[string][]string{"job": {"db"}})
+		require.ErrorIs(t, err, ErrInvalidVariables)
+	})
+
+	t.Run("rejects variables which can't be changed", func(t *testing.T) {
+		_, err := resolveVariables(data, settings, map[string][]string{"env": {"dev"}})
+		require.ErrorIs(t, err, ErrInvalidVariables)
+
+		_, err = resolveVariables(data, nil, map[string][]string{"job": {"web"}})
+		require.ErrorIs(t, err, ErrInvalidVariables)
+
+		_, err = resolveVariables(data, nil, map[string][]string{"job": {"api", "web"}})
+		require.ErrorIs(t, err, ErrInvalidVariables)
+	})
+
+	t.Run("accepts the dashboard value of variables which can't be changed", func(t *testing.T) {
+		settings := &VariableSettings{Allowed: map[string][]string{"region": {"eu.west"}}}
+		values, err := resolveVariables(data, settings, map[string][]string{"job": {"api"}, "env": {"prod"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"api"}, values["job"].Values)
+		assert.Equal(t, []string{"prod"}, values["env"].Values)
+	})
+
+	t.Run("uses the custom all value", func(t *testing.T) {
+		data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+		require.NoError(t, err)
+		data.GetPath("templating", "list").GetIndex(2).Set("allValue", ".*")
+
+		values, err := resolveVariables(data, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, dashboardquery.Variable{Values: []string{".*"}, Multi: true, Raw: true}, values["region"])
+	})
+
+	t.Run("rejects all without allowed values or a custom all value", func(t *testing.T) {
+		_, err := resolveVariables(data, nil, nil)
+		require.ErrorIs(t, err, ErrInvalidVariables)
+
+		_, err = resolveVariables(data, &VariableSettings{Allowed: map[string][]string{"region": {"$__all"}}}, nil)
+		require.ErrorIs(t, err, ErrInvalidVariables)
+	})
+
+	t.Run("rejects unknown variables", func(t *testing.T) {
+		_, err := resolveVariables(data, settings, map[string][]string{"other": {"x"}})
+		require.ErrorIs(t, err, ErrInvalidVariables)
+	})
+}
+
+func TestValidateVariableSettings(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+	dash := &dashboards.Dashboard{Data: data}
+
+	require.NoError(t, validateVariableSettings(dash, nil))
+	require.NoError(t, validateVariableSettings(dash, &VariableSettings{Allowed: map[string][]string{"job": {"api"}}}))
+	require.ErrorIs(t, validateVariableSettings(dash, &VariableSettings{Allowed: map[string][]string{"other": {"api"}}}), ErrInvalidVariableSettings)
+	require.ErrorIs(t, validateVariableSettings(dash, &VariableSettings{Allowed: map[string][]string{"job": {}}}), ErrInvalidVariableSettings)
+	require.ErrorIs(t, validateVariableSettings(dash, &VariableSettings{Allowed: map[string][]string{"filters": {"a"}}}), ErrInvalidVariableSettings)
+}
+
+func TestSanitizeVariablesAndPanels(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	sanitizeVariables(data, &VariableSettings{Allowed: map[string][]string{"job": {"api", "web"}}})
+	removeHiddenPanels(data, &PanelSettings{Hidden: []int64{3}})
+
+	job := data.GetPath("templating", "list").GetIndex(0)
+	assert.Equal(t, "custom", job.Get("type").MustString())
+	assert.Equal(t, "api,web", job.Get("query").MustString())
+	assert.Len(t, job.Get("options").MustArray(), 2)
+	_, ok := job.CheckGet("definition")
+	assert.False(t, ok)
+	_, ok = job.CheckGet("datasource")
+	assert.False(t, ok)
+
+	env := data.GetPath("templating", "list").GetIndex(1)
+	assert.Equal(t, "prod", env.Get("query").MustString())
+	assert.Equal(t, 2, env.Get("hide").MustInt())
+
+	assert.Equal(t, "adhoc", data.GetPath("templating", "list").GetIndex(3).Get("type").MustString())
+
+	panels := data.Get("panels").MustArray()
+	require.Len(t, panels, 2)
+	row := simplejson.NewFromAny(panels[1])
+	require.Len(t, row.Get("panels").MustArray(), 1)
+	assert.Equal(t, int64(4), row.Get("panels").GetIndex(0).Get("id").MustInt64())
+}
@@ -1,6 +1,9 @@
 package validation
 
 import (
+	"net"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -16,6 +19,24 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 	return nil
 }
 
+func ValidatePublicDashboardToken(dto *PublicDashboardTokenDTO, now time.Time) error {
+	if dto.ExpiresAt != nil && !dto.ExpiresAt.After(now) {
+		return ErrInvalidTokenExpiration.Errorf("ValidatePublicDashboardToken: token expiration should be in the future")
+	}
+
+	if dto.MaxUses < 0 {
+		return ErrInvalidTokenMaxUses.Errorf("ValidatePublicDashboardToken: maxUses should be greater than 0")
+	}
+
+	for _, entry := range dto.IPAllowlist {
+		if !IsValidIPOrCIDR(entry) {
+			return ErrInvalidIPAllowlist.Errorf("ValidatePublicDashboardToken: invalid IP address or CIDR range %s", entry)
+		}
+	}
+
+	return nil
+}
+
 func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *PublicDashboard) error {
 	if req.IntervalMs < 0 {
 		return ErrInvalidInterval.Errorf("ValidateQueryPublicDashboardRequest: intervalMS should be greater than 0")
@@ -61,3 +82,11 @@ func IsValidShareType(shareType ShareType) bool {
 	}
 	return false
 }
+
+// IsValidIPOrCIDR checks that the value is an IP address or a CIDR range
+func IsValidIPOrCIDR(value string) bool {
+	if _, _, err := net.ParseCIDR(value); err == nil {
+		return true
+	}
+	return net.ParseIP(value) != nil
+}
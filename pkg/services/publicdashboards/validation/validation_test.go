@@ -2,6 +2,7 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/stretchr/testify/assert"
@@ -24,6 +25,33 @@ func TestValidatePublicDashboard(t *testing.T) {
 	})
 }
 
+func TestValidatePublicDashboardToken(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	t.Run("Returns no error when the token is valid", func(t *testing.T) {
+		dto := &PublicDashboardTokenDTO{Name: "partner", ExpiresAt: &future, MaxUses: 10, IPAllowlist: IPAllowlist{"10.0.0.1", "192.168.0.0/16", "::1"}}
+		require.NoError(t, ValidatePublicDashboardToken(dto, now))
+		require.NoError(t, ValidatePublicDashboardToken(&PublicDashboardTokenDTO{}, now))
+	})
+
+	t.Run("Returns error when the token already expired", func(t *testing.T) {
+		err := ValidatePublicDashboardToken(&PublicDashboardTokenDTO{ExpiresAt: &past}, now)
+		require.ErrorIs(t, err, ErrInvalidTokenExpiration)
+	})
+
+	t.Run("Returns error when maxUses is negative", func(t *testing.T) {
+		err := ValidatePublicDashboardToken(&PublicDashboardTokenDTO{MaxUses: -1}, now)
+		require.ErrorIs(t, err, ErrInvalidTokenMaxUses)
+	})
+
+	t.Run("Returns error when the IP allowlist is invalid", func(t *testing.T) {
+		err := ValidatePublicDashboardToken(&PublicDashboardTokenDTO{IPAllowlist: IPAllowlist{"10.0.0.300"}}, now)
+		require.ErrorIs(t, err, ErrInvalidIPAllowlist)
+	})
+}
+
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 	type args struct {
 		req PublicDashboardQueryDTO
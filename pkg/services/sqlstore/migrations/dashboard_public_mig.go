@@ -96,4 +96,47 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add panel_settings column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "panel_settings",
+		Type:     DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add variable_settings column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "variable_settings",
+		Type:     DB_Text,
+		Nullable: true,
+	}))
+
+	// additional access tokens of a public dashboard
+	dashboardPublicTokenV1 := Table{
+		Name: "dashboard_public_token",
+		Columns: []*Column{
+			{Name: "uid", Type: DB_NVarchar, Length: 40, IsPrimaryKey: true},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "token", Type: DB_NVarchar, Length: 32, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false, Default: "''"},
+
+			{Name: "expires_at", Type: DB_DateTime, Nullable: true},
+			{Name: "max_uses", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "use_count", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "last_used_at", Type: DB_DateTime, Nullable: true},
+			{Name: "ip_allowlist", Type: DB_Text, Nullable: true},
+			{Name: "password_hash", Type: DB_NVarchar, Length: 255, Nullable: true},
+			{Name: "password_salt", Type: DB_NVarchar, Length: 50, Nullable: true},
+
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+			{Name: "revoked_at", Type: DB_DateTime, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"token"}, Type: UniqueIndex},
+			{Cols: []string{"public_dashboard_uid"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public token table v1", NewAddTableMigration(dashboardPublicTokenV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicTokenV1)
 }
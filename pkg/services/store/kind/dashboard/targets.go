@@ -4,9 +4,20 @@ import (
 	jsoniter "github.com/json-iterator/go"
 )
 
+// targetQueryFields are the target properties holding the query expression in common data sources
+var targetQueryFields = map[string]bool{
+	"expr":       true, // prometheus, loki
+	"expression": true, // cloudwatch, server side expressions
+	"query":      true, // influxdb, elasticsearch, tempo, ...
+	"queryText":  true,
+	"rawSql":     true, // sql data sources
+	"target":     true, // graphite
+}
+
 type targetInfo struct {
-	lookup DatasourceLookup
-	uids   map[string]*DataSourceRef
+	lookup  DatasourceLookup
+	uids    map[string]*DataSourceRef
+	queries []string
 }
 
 func newTargetInfo(lookup DatasourceLookup) targetInfo {
@@ -76,6 +87,13 @@ func (s *targetInfo) addTarget(iter *jsoniter.Iterator) {
 			iter.Skip()
 
 		default:
+			if targetQueryFields[l1Field] && iter.WhatIsNext() == jsoniter.StringValue {
+				if q := iter.ReadString(); q != "" {
+					s.queries = append(s.queries, q)
+				}
+				continue
+			}
+
 			v := iter.Read()
 			logf("[Panel.TARGET] %s=%v\n", l1Field, v)
 		}
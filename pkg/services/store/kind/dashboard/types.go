@@ -9,6 +9,7 @@ type PanelSummaryInfo struct {
 	LibraryPanel  string          `json:"libraryPanel,omitempty"` // UID of referenced library panel
 	Datasource    []DataSourceRef `json:"datasource,omitempty"`   // UIDs
 	Transformer   []string        `json:"transformer,omitempty"`  // ids of the transformation steps
+	Queries       []string        `json:"queries,omitempty"`      // query expressions of the targets
 	// Rows define panels as sub objects
 	Collapsed []PanelSummaryInfo `json:"collapsed,omitempty"`
 }
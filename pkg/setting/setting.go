@@ -431,6 +431,14 @@ type Cfg struct {
 	// LiveHAEngineAddress is a connection address for Live HA engine.
 	LiveHAEngineAddress  string
 	LiveHAEnginePassword string
+	// LiveHistoryMaxFrames is a maximum number of recent frames kept per managed
+	// stream channel to be sent to new subscribers. 0 disables history and keeps only the last frame.
+	LiveHistoryMaxFrames int
+	// LiveHistoryMaxAge is a maximum age of frames kept in managed stream history.
+	LiveHistoryMaxAge time.Duration
+	// LivePipelineEnabled enables the Live pipeline which processes the data
+	// pushed to channels with channel rules.
+	LivePipelineEnabled bool
 	// LiveAllowedOrigins is a set of origins accepted by Live. If not provided
 	// then Live uses AppURL as the only allowed origin.
 	LiveAllowedOrigins []string
@@ -492,7 +500,14 @@ type Cfg struct {
 	DatabaseInstrumentQueries bool
 
 	// Public dashboards
-	PublicDashboardsEnabled bool
+	PublicDashboardsEnabled             bool
+	PublicDashboardsQueryCacheTTL       time.Duration
+	PublicDashboardsQueryCacheStep      time.Duration
+	PublicDashboardsClientRPS           int
+	PublicDashboardsClientBurst         int
+	PublicDashboardsDashboardQueryRPS   int
+	PublicDashboardsDashboardQueryBurst int
+	PublicDashboardsTrustedProxies      []string
 
 	// Cloud Migration
 	CloudMigration CloudMigrationSettings
@@ -533,6 +548,8 @@ type Cfg struct {
 	IndexMaxBatchSize  int
 	IndexFileThreshold int
 	IndexMinCount      int
+	// How often the history of unified storage is compacted, zero disables compaction
+	HistoryCompactionInterval time.Duration
 }
 
 type UnifiedStorageConfig struct {
@@ -542,6 +559,13 @@ type UnifiedStorageConfig struct {
 	DataSyncerInterval time.Duration
 	// DataSyncerRecordsLimit defines how many records will be processed at max during a sync invocation.
 	DataSyncerRecordsLimit int
+	// HistoryKeepVersions defines how many versions of each object are kept in the history, zero keeps all.
+	HistoryKeepVersions int
+	// HistoryKeepDuration defines how long versions are kept in the history, zero keeps all.
+	HistoryKeepDuration time.Duration
+	// HistoryKeepDeletions defines how long the history of deleted objects is kept, the deletion itself is always kept.
+	// Zero keeps the history like other versions.
+	HistoryKeepDeletions time.Duration
 }
 
 type InstallPlugin struct {
@@ -1975,6 +1999,15 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 	cfg.LiveHAPrefix = section.Key("ha_prefix").MustString("")
 	cfg.LiveHAEngineAddress = section.Key("ha_engine_address").MustString("127.0.0.1:6379")
 	cfg.LiveHAEnginePassword = section.Key("ha_engine_password").MustString("")
+	cfg.LiveHistoryMaxFrames = section.Key("history_max_frames").MustInt(0)
+	if cfg.LiveHistoryMaxFrames < 0 {
+		return fmt.Errorf("unexpected value %d for [live] history_max_frames", cfg.LiveHistoryMaxFrames)
+	}
+	cfg.LiveHistoryMaxAge = section.Key("history_max_age").MustDuration(5 * time.Minute)
+	if cfg.LiveHistoryMaxAge <= 0 {
+		return fmt.Errorf("unexpected value %s for [live] history_max_age", cfg.LiveHistoryMaxAge)
+	}
+	cfg.LivePipelineEnabled = section.Key("pipeline_enabled").MustBool(false)
 
 	allowedOrigins := section.Key("allowed_origins").MustString("")
 	origins := strings.Split(allowedOrigins, ",")
@@ -2000,6 +2033,13 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 func (cfg *Cfg) readPublicDashboardsSettings() {
 	publicDashboards := cfg.Raw.Section("public_dashboards")
 	cfg.PublicDashboardsEnabled = publicDashboards.Key("enabled").MustBool(true)
+	cfg.PublicDashboardsQueryCacheTTL = publicDashboards.Key("query_cache_ttl").MustDuration(0)
+	cfg.PublicDashboardsQueryCacheStep = publicDashboards.Key("query_cache_time_rounding").MustDuration(10 * time.Second)
+	cfg.PublicDashboardsClientRPS = publicDashboards.Key("client_requests_per_second_limit").MustInt(0)
+	cfg.PublicDashboardsClientBurst = publicDashboards.Key("client_burst_limit").MustInt(0)
+	cfg.PublicDashboardsDashboardQueryRPS = publicDashboards.Key("dashboard_queries_per_second_limit").MustInt(0)
+	cfg.PublicDashboardsDashboardQueryBurst = publicDashboards.Key("dashboard_queries_burst_limit").MustInt(0)
+	cfg.PublicDashboardsTrustedProxies = util.SplitString(publicDashboards.Key("trusted_proxies").MustString(""))
 }
 
 func (cfg *Cfg) DefaultOrgID() int64 {
@@ -36,11 +36,19 @@ func (cfg *Cfg) setUnifiedStorageConfig() {
 		// parse dataSyncerInterval from resource section
 		dataSyncerInterval := section.Key("dataSyncerInterval").MustDuration(time.Hour)
 
+		// parse the history retention from resource section
+		historyKeepVersions := section.Key("historyKeepVersions").MustInt(0)
+		historyKeepDuration := section.Key("historyKeepDuration").MustDuration(0)
+		historyKeepDeletions := section.Key("historyKeepDeletions").MustDuration(0)
+
 		storageConfig[resourceName] = UnifiedStorageConfig{
 			DualWriterMode:                       rest.DualWriterMode(dualWriterMode),
 			DualWriterPeriodicDataSyncJobEnabled: dualWriterPeriodicDataSyncJobEnabled,
 			DataSyncerRecordsLimit:               dataSyncerRecordsLimit,
 			DataSyncerInterval:                   dataSyncerInterval,
+			HistoryKeepVersions:                  historyKeepVersions,
+			HistoryKeepDuration:                  historyKeepDuration,
+			HistoryKeepDeletions:                 historyKeepDeletions,
 		}
 	}
 	cfg.UnifiedStorage = storageConfig
@@ -52,4 +60,5 @@ func (cfg *Cfg) setUnifiedStorageConfig() {
 	cfg.IndexMaxBatchSize = section.Key("index_max_batch_size").MustInt(100)
 	cfg.IndexFileThreshold = section.Key("index_file_threshold").MustInt(10)
 	cfg.IndexMinCount = section.Key("index_min_count").MustInt(1)
+	cfg.HistoryCompactionInterval = section.Key("history_compaction_interval").MustDuration(time.Hour)
 }
@@ -28,6 +28,12 @@ func TestCfg_setUnifiedStorageConfig(t *testing.T) {
 		_, err = s.NewKey("dataSyncerInterval", "10m")
 		assert.NoError(t, err)
 
+		_, err = s.NewKey("historyKeepVersions", "20")
+		assert.NoError(t, err)
+
+		_, err = s.NewKey("historyKeepDeletions", "720h")
+		assert.NoError(t, err)
+
 		cfg.setUnifiedStorageConfig()
 
 		value, exists := cfg.UnifiedStorage["playlists.playlist.grafana.app"]
@@ -38,6 +44,9 @@ func TestCfg_setUnifiedStorageConfig(t *testing.T) {
 			DualWriterPeriodicDataSyncJobEnabled: true,
 			DataSyncerRecordsLimit:               1001,
 			DataSyncerInterval:                   time.Minute * 10,
+			HistoryKeepVersions:                  20,
+			HistoryKeepDeletions:                 time.Hour * 720,
 		})
+		assert.Equal(t, time.Hour, cfg.HistoryCompactionInterval)
 	})
 }
@@ -0,0 +1,785 @@
+package resource
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/grafana/authlib/claims"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+)
+
+// ArchiveVersion is the version of the archive layout
+//
+//	manifest.json                             the ArchiveManifest, always the first file
+//	blobs/{group}/{resource}/{name}/{uid}     blob linked from the current value, before the value
+//	resources/{group}/{resource}/{name}.json  the current value
+//	history/{group}/{resource}/{rv}.json      every event of the resource (optional)
+const ArchiveVersion = "1"
+
+const (
+	archiveManifestFile = "manifest.json"
+
+	// The number of events written in one transaction when the backend supports it
+	archiveImportBatchSize = 100
+
+	// The size of the archive parts sent by the ExportNamespace RPC
+	archiveChunkSize = 64 * 1024
+
+	// Values which are not part of the file name are saved as PAX records
+	archiveRecordResourceVersion = "GRAFANA.rv"
+	archiveRecordName            = "GRAFANA.name"
+	archiveRecordAction          = "GRAFANA.action"
+	archiveRecordContentType     = "GRAFANA.content_type"
+
+	// Objects reference the folder they are in (and folders their parent) by name
+	archiveFolderGroup    = "folder.grafana.app"
+	archiveFolderResource = "folders"
+)
+
+// ArchiveManifest describes the content of a namespace archive
+type ArchiveManifest struct {
+	Version   string    `json:"version"`
+	Namespace string    `json:"namespace"`
+	Created   time.Time `json:"created"`
+
+	// The history of each resource is included
+	History bool `json:"history,omitempty"`
+
+	// The blobs of the current values are included
+	Blobs bool `json:"blobs,omitempty"`
+
+	// The group+resource pairs in the archive
+	Resources []ArchiveResource `json:"resources"`
+}
+
+type ArchiveResource struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+}
+
+type ExportOptions struct {
+	Namespace string
+
+	// The resources to export (the namespace is ignored)
+	// When empty, every resource with values in the namespace is exported
+	Resources []NamespacedResource
+
+	// Include every event of the resources, the backend must implement ResourceEventLister
+	History bool
+
+	// When set, the blobs linked from the current values are included
+	Blobs BlobSupport
+}
+
+// ExportNamespace writes all resources of a namespace to a tar archive
+func ExportNamespace(ctx context.Context, backend StorageBackend, opts ExportOptions, w io.Writer) (*ArchiveManifest, error) {
+	if opts.Namespace == "" {
+		return nil, fmt.Errorf("missing namespace")
+	}
+
+	var lister ResourceEventLister
+	if opts.History {
+		var ok bool
+		lister, ok = backend.(ResourceEventLister)
+		if !ok {
+			return nil, fmt.Errorf("the storage backend can not export the history")
+		}
+	}
+
+	resources := opts.Resources
+	if len(resources) == 0 {
+		stats, err := backend.GetResourceStats(ctx, opts.Namespace, 0)
+		if err != nil {
+			return nil, fmt.Errorf("list resources: %w", err)
+		}
+		for _, s := range stats {
+			resources = append(resources, s.NamespacedResource)
+		}
+	}
+
+	manifest := &ArchiveManifest{
+		Version:   ArchiveVersion,
+		Namespace: opts.Namespace,
+		Created:   time.Now().UTC().Truncate(time.Second),
+		History:   opts.History,
+		Blobs:     opts.Blobs != nil,
+		Resources: make([]ArchiveResource, 0, len(resources)),
+	}
+	for _, r := range resources {
+		manifest.Resources = append(manifest.Resources, ArchiveResource{Group: r.Group, Resource: r.Resource})
+	}
+	sort.Slice(manifest.Resources, func(i, j int) bool {
+		a, b := manifest.Resources[i], manifest.Resources[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		return a.Resource < b.Resource
+	})
+
+	ar := &archiveWriter{tw: tar.NewWriter(w), modTime: manifest.Created}
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err = ar.write(archiveManifestFile, raw, nil); err != nil {
+		return nil, err
+	}
+
+	for _, r := range manifest.Resources {
+		key := &ResourceKey{Namespace: opts.Namespace, Group: r.Group, Resource: r.Resource}
+		_, err = backend.ListIterator(ctx, &ListRequest{Options: &ListOptions{Key: key}}, func(iter ListIterator) error {
+			for iter.Next() {
+				if err := iter.Error(); err != nil {
+					return err
+				}
+				if opts.Blobs != nil {
+					if err := ar.writeBlob(ctx, opts.Blobs, &ResourceKey{
+						Namespace: key.Namespace,
+						Group:     key.Group,
+						Resource:  key.Resource,
+						Name:      iter.Name(),
+					}, iter.Value()); err != nil {
+						return err
+					}
+				}
+				if err := ar.write(path.Join("resources", r.Group, r.Resource, iter.Name()+".json"), iter.Value(), map[string]string{
+					archiveRecordResourceVersion: strconv.FormatInt(iter.ResourceVersion(), 10),
+				}); err != nil {
+					return err
+				}
+			}
+			return iter.Error()
+		})
+		if err != nil {
+			return nil, fmt.Errorf("export %s.%s: %w", r.Resource, r.Group, err)
+		}
+
+		if lister != nil {
+			_, err = lister.ListEventsSince(ctx, NamespacedResource{
+				Namespace: opts.Namespace,
+				Group:     r.Group,
+				Resource:  r.Resource,
+			}, 0, func(event *WrittenEvent) error {
+				return ar.write(path.Join("history", r.Group, r.Resource, fmt.Sprintf("%d.json", event.ResourceVersion)), event.Value, map[string]string{
+					archiveRecordName:   event.Key.Name,
+					archiveRecordAction: event.Type.String(),
+				})
+			})
+			if err != nil {
+				return nil, fmt.Errorf("export history of %s.%s: %w", r.Resource, r.Group, err)
+			}
+		}
+	}
+	return manifest, ar.tw.Close()
+}
+
+type archiveWriter struct {
+	tw      *tar.Writer
+	modTime time.Time
+}
+
+func (a *archiveWriter) write(name string, value []byte, records map[string]string) error {
+	hdr := &tar.Header{
+		Typeflag:   tar.TypeReg,
+		Name:       name,
+		Mode:       0644,
+		Size:       int64(len(value)),
+		ModTime:    a.modTime,
+		PAXRecords: records,
+	}
+	if len(records) > 0 {
+		hdr.Format = tar.FormatPAX
+	}
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := a.tw.Write(value)
+	return err
+}
+
+// writeBlob writes the blob linked from the value (if any)
+func (a *archiveWriter) writeBlob(ctx context.Context, blobs BlobSupport, key *ResourceKey, value []byte) error {
+	partial := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(value, partial); err != nil {
+		return err
+	}
+	obj, err := utils.MetaAccessor(partial)
+	if err != nil {
+		return err
+	}
+	info := obj.GetBlob()
+	if info == nil || info.UID == "" {
+		return nil
+	}
+	rsp, err := blobs.GetResourceBlob(ctx, key, info, true)
+	if err != nil {
+		return fmt.Errorf("read blob of %s: %w", key.Name, err)
+	}
+	if rsp.Error != nil {
+		return fmt.Errorf("read blob of %s: %s", key.Name, rsp.Error.Message)
+	}
+	return a.write(path.Join("blobs", key.Group, key.Resource, key.Name, info.UID), rsp.Value, map[string]string{
+		archiveRecordContentType: rsp.ContentType,
+	})
+}
+
+// ImportConflict decides what happens when an imported object already exists
+type ImportConflict string
+
+const (
+	// Stop the import, objects imported before the conflict are kept
+	ImportConflictFail ImportConflict = "fail"
+	// Keep the existing object
+	ImportConflictSkip ImportConflict = "skip"
+	// Replace the existing object, its history is kept
+	ImportConflictOverwrite ImportConflict = "overwrite"
+	// Import the object with a new name (UID), the objects in a renamed folder are moved to the new folder
+	ImportConflictRename ImportConflict = "rename"
+)
+
+type ImportOptions struct {
+	// The target namespace, defaults to the namespace of the archive
+	Namespace string
+
+	// Defaults to ImportConflictFail
+	OnConflict ImportConflict
+
+	// Replay the history instead of writing the current values (when included in the archive)
+	History bool
+
+	// When set, the blobs in the archive are written again
+	Blobs BlobSupport
+}
+
+type ImportSummary struct {
+	Manifest *ArchiveManifest
+
+	Created int
+	Updated int
+	Deleted int
+	Skipped int
+	Blobs   int
+
+	// The new names of renamed objects, the key is {group}/{resource}/{name}
+	Renamed map[string]string
+}
+
+// ImportNamespace writes the resources from an archive created with ExportNamespace
+func ImportNamespace(ctx context.Context, backend StorageBackend, r io.Reader, opts ImportOptions) (*ImportSummary, error) {
+	switch opts.OnConflict {
+	case "":
+		opts.OnConflict = ImportConflictFail
+	case ImportConflictFail, ImportConflictSkip, ImportConflictOverwrite, ImportConflictRename:
+	default:
+		return nil, fmt.Errorf("unknown conflict strategy %q", opts.OnConflict)
+	}
+
+	tr := tar.NewReader(r)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	if hdr.Name != archiveManifestFile {
+		return nil, fmt.Errorf("the archive must start with %s", archiveManifestFile)
+	}
+	manifest := &ArchiveManifest{}
+	if err = json.NewDecoder(tr).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	if manifest.Version != ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %q", manifest.Version)
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = manifest.Namespace
+	}
+
+	im := &archiveImporter{
+		backend:    backend,
+		opts:       opts,
+		objects:    make(map[string]*importObject),
+		blobs:      make(map[string]*utils.BlobInfo),
+		folderRefs: make(map[*importObject]string),
+		summary: &ImportSummary{
+			Manifest: manifest,
+			Renamed:  make(map[string]string),
+		},
+	}
+	im.bulk, _ = backend.(BulkStorageBackend)
+	resources := make(map[string]bool, len(manifest.Resources))
+	for _, r := range manifest.Resources {
+		resources[path.Join(r.Group, r.Resource)] = true
+	}
+	history := opts.History && manifest.History
+	for {
+		hdr, err = tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return im.summary, errors.Join(err, im.finish(ctx))
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		value, err := io.ReadAll(tr)
+		if err != nil {
+			return im.summary, errors.Join(err, im.finish(ctx))
+		}
+
+		// The group and resource in the path must be listed in the manifest
+		parts := strings.Split(hdr.Name, "/")
+		if len(parts) > 2 && !resources[path.Join(parts[1], parts[2])] {
+			return im.summary, errors.Join(fmt.Errorf("%s is not a resource listed in the manifest", hdr.Name), im.finish(ctx))
+		}
+		switch {
+		case parts[0] == "blobs" && len(parts) == 5:
+			if opts.Blobs == nil {
+				continue
+			}
+			err = im.writeBlob(ctx, parts[1], parts[2], parts[3], parts[4], hdr.PAXRecords[archiveRecordContentType], value)
+
+		case parts[0] == "resources" && len(parts) == 4 && !history:
+			err = im.write(ctx, parts[1], parts[2], strings.TrimSuffix(parts[3], ".json"), WatchEvent_ADDED, value)
+
+		case parts[0] == "history" && len(parts) == 4 && history:
+			action, ok := WatchEvent_Type_value[hdr.PAXRecords[archiveRecordAction]]
+			if !ok {
+				return im.summary, fmt.Errorf("unknown action in %s", hdr.Name)
+			}
+			err = im.write(ctx, parts[1], parts[2], hdr.PAXRecords[archiveRecordName], WatchEvent_Type(action), value)
+		}
+		if err != nil {
+			// The objects imported before the error are kept
+			return im.summary, errors.Join(fmt.Errorf("import %s: %w", hdr.Name, err), im.finish(ctx))
+		}
+	}
+	return im.summary, im.finish(ctx)
+}
+
+// importObject is the state of an object in the target namespace
+type importObject struct {
+	key     *ResourceKey
+	uid     string // new UID of renamed objects
+	skip    bool
+	exists  bool
+	rv      int64
+	pending bool // an event is waiting to be written
+}
+
+type importEvent struct {
+	obj   *importObject
+	event WriteEvent
+}
+
+type archiveImporter struct {
+	backend StorageBackend
+	bulk    BulkStorageBackend // nil when the backend can not write batches
+	opts    ImportOptions
+	objects map[string]*importObject
+	blobs   map[string]*utils.BlobInfo // the new blob for the uid in the archive
+	pending []importEvent
+	summary *ImportSummary
+
+	// The folder of objects written before the folder was seen, the folder may still be renamed
+	folderRefs map[*importObject]string
+}
+
+// object applies the conflict strategy the first time an object is seen
+func (im *archiveImporter) object(ctx context.Context, group, resource, name string) (*importObject, error) {
+	id := path.Join(group, resource, name)
+	if obj, ok := im.objects[id]; ok {
+		return obj, nil
+	}
+	if e := validateName(name); e != nil {
+		return nil, fmt.Errorf("%s: %s", name, e.Message)
+	}
+
+	obj := &importObject{key: &ResourceKey{
+		Namespace: im.opts.Namespace,
+		Group:     group,
+		Resource:  resource,
+		Name:      name,
+	}}
+	found := im.backend.ReadResource(ctx, &ReadRequest{Key: obj.key})
+	switch {
+	case found.Error != nil && found.Error.Code == http.StatusNotFound:
+	case found.Error != nil:
+		return nil, fmt.Errorf("read %s: %s", name, found.Error.Message)
+	default:
+		switch im.opts.OnConflict {
+		case ImportConflictSkip:
+			obj.skip = true
+			im.summary.Skipped++
+		case ImportConflictOverwrite:
+			obj.exists = true
+			obj.rv = found.ResourceVersion
+		case ImportConflictRename:
+			obj.key.Name = "i" + strings.ReplaceAll(uuid.NewString(), "-", "")[:13]
+			obj.uid = uuid.NewString()
+			im.summary.Renamed[id] = obj.key.Name
+		default:
+			return nil, fmt.Errorf("%s already exists", id)
+		}
+	}
+	im.objects[id] = obj
+	return obj, nil
+}
+
+func (im *archiveImporter) writeBlob(ctx context.Context, group, resource, name, uid, contentType string, value []byte) error {
+	obj, err := im.object(ctx, group, resource, name)
+	if err != nil || obj.skip {
+		return err
+	}
+	rsp, err := im.opts.Blobs.PutResourceBlob(ctx, &PutBlobRequest{
+		Resource:    obj.key,
+		Method:      PutBlobRequest_GRPC,
+		ContentType: contentType,
+		Value:       value,
+	})
+	if err != nil {
+		return err
+	}
+	if rsp.Error != nil {
+		return errors.New(rsp.Error.Message)
+	}
+	im.blobs[uid] = &utils.BlobInfo{
+		UID:      rsp.Uid,
+		Size:     rsp.Size,
+		Hash:     rsp.Hash,
+		MimeType: rsp.MimeType,
+		Charset:  rsp.Charset,
+	}
+	im.summary.Blobs++
+	return nil
+}
+
+func (im *archiveImporter) write(ctx context.Context, group, resource, name string, action WatchEvent_Type, value []byte) error {
+	obj, err := im.object(ctx, group, resource, name)
+	if err != nil || obj.skip {
+		return err
+	}
+	if obj.pending {
+		// The previous version must be written first
+		if err = im.flush(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Move the value to the target namespace
+	tmp := &unstructured.Unstructured{}
+	if err = tmp.UnmarshalJSON(value); err != nil {
+		return err
+	}
+	tmp.SetNamespace(obj.key.Namespace)
+	tmp.SetName(obj.key.Name)
+	tmp.SetResourceVersion("")
+	if obj.uid != "" {
+		tmp.SetUID(types.UID(obj.uid))
+	}
+	meta, err := utils.MetaAccessor(tmp)
+	if err != nil {
+		return err
+	}
+	if info := meta.GetBlob(); info != nil {
+		if blob, ok := im.blobs[info.UID]; ok {
+			meta.SetBlob(blob)
+		}
+	}
+	im.folderReference(obj, meta)
+	value, err = tmp.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	event := WriteEvent{
+		Key:    obj.key,
+		Type:   action,
+		Value:  value,
+		Object: meta,
+	}
+	switch {
+	case action == WatchEvent_DELETED:
+		if !obj.exists {
+			return nil // nothing to delete
+		}
+		event.PreviousRV = obj.rv
+	case obj.exists:
+		event.Type = WatchEvent_MODIFIED
+		event.PreviousRV = obj.rv
+	default:
+		event.Type = WatchEvent_ADDED
+	}
+
+	obj.exists = event.Type != WatchEvent_DELETED
+	obj.pending = true
+	im.pending = append(im.pending, importEvent{obj: obj, event: event})
+	if len(im.pending) >= archiveImportBatchSize {
+		return im.flush(ctx)
+	}
+	return nil
+}
+
+// folderReference points the object to the new name of its folder when the folder was renamed
+func (im *archiveImporter) folderReference(obj *importObject, meta utils.GrafanaMetaAccessor) {
+	delete(im.folderRefs, obj)
+	folder := meta.GetFolder()
+	if folder == "" {
+		return
+	}
+	id := path.Join(archiveFolderGroup, archiveFolderResource, folder)
+	if renamed, ok := im.summary.Renamed[id]; ok {
+		meta.SetFolder(renamed)
+		return
+	}
+	if _, ok := im.objects[id]; !ok {
+		im.folderRefs[obj] = folder
+	}
+}
+
+// finish writes the pending events and moves the objects written before their folder was renamed
+func (im *archiveImporter) finish(ctx context.Context) error {
+	if err := im.flush(ctx); err != nil {
+		return err
+	}
+	refs := im.folderRefs
+	im.folderRefs = make(map[*importObject]string)
+	for obj, folder := range refs {
+		renamed, ok := im.summary.Renamed[path.Join(archiveFolderGroup, archiveFolderResource, folder)]
+		if !ok || !obj.exists {
+			continue
+		}
+		if err := im.moveToFolder(ctx, obj, renamed); err != nil {
+			return fmt.Errorf("update folder of %s: %w", obj.key.Name, err)
+		}
+	}
+	return nil
+}
+
+func (im *archiveImporter) moveToFolder(ctx context.Context, obj *importObject, folder string) error {
+	found := im.backend.ReadResource(ctx, &ReadRequest{Key: obj.key})
+	if found.Error != nil {
+		return errors.New(found.Error.Message)
+	}
+	tmp := &unstructured.Unstructured{}
+	if err := tmp.UnmarshalJSON(found.Value); err != nil {
+		return err
+	}
+	tmp.SetResourceVersion("")
+	meta, err := utils.MetaAccessor(tmp)
+	if err != nil {
+		return err
+	}
+	meta.SetFolder(folder)
+	value, err := tmp.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	obj.rv, err = im.backend.WriteEvent(ctx, WriteEvent{
+		Key:        obj.key,
+		Type:       WatchEvent_MODIFIED,
+		Value:      value,
+		Object:     meta,
+		PreviousRV: found.ResourceVersion,
+	})
+	return err
+}
+
+// flush writes the pending events, in a single transaction when the backend supports it
+func (im *archiveImporter) flush(ctx context.Context) error {
+	pending := im.pending
+	im.pending = nil
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if im.bulk != nil {
+		events := make([]WriteEvent, len(pending))
+		for i, p := range pending {
+			events[i] = p.event
+		}
+		rvs, err := im.bulk.WriteEvents(ctx, events)
+		if err != nil {
+			return err
+		}
+		for i, p := range pending {
+			im.written(p, rvs[i])
+		}
+		return nil
+	}
+
+	for _, p := range pending {
+		rv, err := im.backend.WriteEvent(ctx, p.event)
+		if err != nil {
+			return fmt.Errorf("write %s: %w", p.event.Key.Name, err)
+		}
+		im.written(p, rv)
+	}
+	return nil
+}
+
+func (im *archiveImporter) written(p importEvent, rv int64) {
+	p.obj.rv = rv
+	p.obj.pending = false
+	switch p.event.Type {
+	case WatchEvent_ADDED:
+		im.summary.Created++
+	case WatchEvent_MODIFIED:
+		im.summary.Updated++
+	case WatchEvent_DELETED:
+		im.summary.Deleted++
+	}
+}
+
+// ExportNamespace streams the archive of a namespace, see ExportNamespace
+func (s *server) ExportNamespace(req *ExportNamespaceRequest, stream ResourceStore_ExportNamespaceServer) error {
+	ctx, span := s.tracer.Start(stream.Context(), "storage_server.ExportNamespace")
+	defer span.End()
+
+	if req.Namespace == "" {
+		return stream.Send(&ExportNamespaceResponse{Error: NewBadRequestError("missing namespace")})
+	}
+	if e := s.checkArchiveAccess(ctx, req.Blobs); e != nil {
+		return stream.Send(&ExportNamespaceResponse{Error: e})
+	}
+
+	opts := ExportOptions{
+		Namespace: req.Namespace,
+		History:   req.History,
+	}
+	for _, key := range req.Resources {
+		opts.Resources = append(opts.Resources, NamespacedResource{Group: key.Group, Resource: key.Resource})
+	}
+	if req.Blobs {
+		opts.Blobs = s.blob
+	}
+
+	w := bufio.NewWriterSize(&exportChunkWriter{stream: stream}, archiveChunkSize)
+	manifest, err := ExportNamespace(ctx, s.backend, opts, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		return stream.Send(&ExportNamespaceResponse{Error: AsErrorResult(err)})
+	}
+	s.log.Debug("server.ExportNamespace", "namespace", req.Namespace, "resources", len(manifest.Resources))
+	return nil
+}
+
+// ImportNamespace reads the options from the first message and imports the streamed archive, see ImportNamespace
+func (s *server) ImportNamespace(stream ResourceStore_ImportNamespaceServer) error {
+	ctx, span := s.tracer.Start(stream.Context(), "storage_server.ImportNamespace")
+	defer span.End()
+
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return stream.SendAndClose(&ImportNamespaceResponse{Error: NewBadRequestError("missing archive")})
+	}
+	if err != nil {
+		return err
+	}
+	if e := s.checkArchiveAccess(ctx, first.Blobs); e != nil {
+		return stream.SendAndClose(&ImportNamespaceResponse{Error: e})
+	}
+
+	opts := ImportOptions{
+		Namespace:  first.Namespace,
+		OnConflict: ImportConflict(first.OnConflict),
+		History:    first.History,
+	}
+	if first.Blobs {
+		opts.Blobs = s.blob
+	}
+
+	summary, err := ImportNamespace(ctx, s.backend, &importChunkReader{stream: stream, chunk: first.Chunk}, opts)
+	rsp := &ImportNamespaceResponse{Error: AsErrorResult(err)}
+	if summary != nil {
+		rsp.Created = int64(summary.Created)
+		rsp.Updated = int64(summary.Updated)
+		rsp.Deleted = int64(summary.Deleted)
+		rsp.Skipped = int64(summary.Skipped)
+		rsp.Blobs = int64(summary.Blobs)
+		rsp.Renamed = summary.Renamed
+	}
+	s.log.Debug("server.ImportNamespace", "created", rsp.Created, "updated", rsp.Updated, "deleted", rsp.Deleted, "skipped", rsp.Skipped)
+	return stream.SendAndClose(rsp)
+}
+
+// checkArchiveAccess verifies the user can export or import whole namespaces
+func (s *server) checkArchiveAccess(ctx context.Context, blobs bool) *ErrorResult {
+	user, ok := claims.From(ctx)
+	if !ok || user == nil {
+		return &ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+	}
+	if !isServerAdmin(ctx, user) {
+		return &ErrorResult{
+			Message: "exporting and importing namespaces requires a server admin",
+			Code:    http.StatusForbidden,
+		}
+	}
+	if blobs && s.blob == nil {
+		return &ErrorResult{
+			Message: "blob store not configured",
+			Code:    http.StatusNotImplemented,
+		}
+	}
+	if err := s.Init(ctx); err != nil {
+		return AsErrorResult(err)
+	}
+	return nil
+}
+
+// exportChunkWriter sends the writes as parts of the archive, no larger than archiveChunkSize
+type exportChunkWriter struct {
+	stream ResourceStore_ExportNamespaceServer
+}
+
+func (w *exportChunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), archiveChunkSize)]
+		if err := w.stream.Send(&ExportNamespaceResponse{Chunk: chunk}); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+// importChunkReader reads the archive parts from the stream
+type importChunkReader struct {
+	stream ResourceStore_ImportNamespaceServer
+	chunk  []byte
+}
+
+func (r *importChunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = req.Chunk
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
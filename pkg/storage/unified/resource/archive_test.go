@@ -0,0 +1,424 @@
+package resource
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/authlib/claims"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+)
+
+// testBulkBackend records the size of each batch
+type testBulkBackend struct {
+	StorageBackend
+	batches []int
+}
+
+func (b *testBulkBackend) WriteEvents(ctx context.Context, events []WriteEvent) ([]int64, error) {
+	b.batches = append(b.batches, len(events))
+	rvs := make([]int64, len(events))
+	for i, event := range events {
+		rv, err := b.WriteEvent(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		rvs[i] = rv
+	}
+	return rvs, nil
+}
+
+// testExportStream keeps the sent archive parts
+type testExportStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses []*ExportNamespaceResponse
+}
+
+func (s *testExportStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testExportStream) Send(rsp *ExportNamespaceResponse) error {
+	s.responses = append(s.responses, rsp)
+	return nil
+}
+
+// testImportStream sends the requests and keeps the response
+type testImportStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests []*ImportNamespaceRequest
+	response *ImportNamespaceResponse
+}
+
+func (s *testImportStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testImportStream) Recv() (*ImportNamespaceRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *testImportStream) SendAndClose(rsp *ImportNamespaceResponse) error {
+	s.response = rsp
+	return nil
+}
+
+func TestNamespaceArchive(t *testing.T) {
+	ctx := context.Background()
+	playlists := NamespacedResource{Group: "playlist.grafana.app", Resource: "playlists"}
+
+	newStore := func(t *testing.T) (StorageBackend, BlobSupport) {
+		t.Helper()
+		store, err := NewCDKBackend(ctx, CDKBackendOptions{
+			Bucket: memblob.OpenBucket(nil),
+		})
+		require.NoError(t, err)
+		blobs, err := NewCDKBlobSupport(ctx, CDKBlobSupportOptions{
+			Bucket: memblob.OpenBucket(nil),
+		})
+		require.NoError(t, err)
+		return store, blobs
+	}
+	key := func(ns, name string) *ResourceKey {
+		return &ResourceKey{
+			Namespace: ns,
+			Group:     playlists.Group,
+			Resource:  playlists.Resource,
+			Name:      name,
+		}
+	}
+	write := func(t *testing.T, store StorageBackend, ns, name, title string, blob *utils.BlobInfo) {
+		t.Helper()
+		obj := &unstructured.Unstructured{}
+		err := obj.UnmarshalJSON([]byte(fmt.Sprintf(`{
+			"apiVersion": "playlist.grafana.app/v0alpha1",
+			"kind": "Playlist",
+			"metadata": {"name": %q, "uid": "uid-%s", "namespace": %q},
+			"spec": {"title": %q}
+		}`, name, name, ns, title)))
+		require.NoError(t, err)
+		meta, err := utils.MetaAccessor(obj)
+		require.NoError(t, err)
+		meta.SetBlob(blob)
+		value, err := obj.MarshalJSON()
+		require.NoError(t, err)
+
+		event := WriteEvent{Key: key(ns, name), Type: WatchEvent_ADDED, Value: value, Object: meta}
+		found := store.ReadResource(ctx, &ReadRequest{Key: event.Key})
+		if found.Error == nil {
+			event.Type = WatchEvent_MODIFIED
+			event.PreviousRV = found.ResourceVersion
+		}
+		_, err = store.WriteEvent(ctx, event)
+		require.NoError(t, err)
+	}
+	title := func(t *testing.T, store StorageBackend, ns, name string) string {
+		t.Helper()
+		found := store.ReadResource(ctx, &ReadRequest{Key: key(ns, name)})
+		require.Nil(t, found.Error)
+		obj := &unstructured.Unstructured{}
+		require.NoError(t, obj.UnmarshalJSON(found.Value))
+		require.Equal(t, ns, obj.GetNamespace())
+		v, _, _ := unstructured.NestedString(obj.Object, "spec", "title")
+		return v
+	}
+
+	// The source namespace has two playlists, one of them links a blob
+	source, sourceBlobs := newStore(t)
+	blob, err := sourceBlobs.PutResourceBlob(ctx, &PutBlobRequest{
+		Resource:    key("source", "a"),
+		Method:      PutBlobRequest_GRPC,
+		ContentType: "text/plain",
+		Value:       []byte("hello"),
+	})
+	require.NoError(t, err)
+	write(t, source, "source", "a", "A", &utils.BlobInfo{UID: blob.Uid, Size: blob.Size, Hash: blob.Hash, MimeType: blob.MimeType})
+	write(t, source, "source", "b", "B", nil)
+	write(t, source, "other", "c", "C", nil)
+
+	archive := &bytes.Buffer{}
+	manifest, err := ExportNamespace(ctx, source, ExportOptions{
+		Namespace: "source",
+		Resources: []NamespacedResource{playlists},
+		Blobs:     sourceBlobs,
+	}, archive)
+	require.NoError(t, err)
+	require.Equal(t, ArchiveVersion, manifest.Version)
+	require.Equal(t, []ArchiveResource{{Group: playlists.Group, Resource: playlists.Resource}}, manifest.Resources)
+	require.True(t, manifest.Blobs)
+
+	t.Run("history requires an event lister", func(t *testing.T) {
+		_, err := ExportNamespace(ctx, source, ExportOptions{Namespace: "source", History: true}, &bytes.Buffer{})
+		require.Error(t, err)
+	})
+
+	t.Run("import into an empty namespace", func(t *testing.T) {
+		target, targetBlobs := newStore(t)
+		summary, err := ImportNamespace(ctx, target, bytes.NewReader(archive.Bytes()), ImportOptions{
+			Namespace: "target",
+			Blobs:     targetBlobs,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "source", summary.Manifest.Namespace)
+		require.Equal(t, 2, summary.Created)
+		require.Equal(t, 1, summary.Blobs)
+		require.Equal(t, "A", title(t, target, "target", "a"))
+		require.Equal(t, "B", title(t, target, "target", "b"))
+
+		// The blob is linked to the new copy
+		found := target.ReadResource(ctx, &ReadRequest{Key: key("target", "a")})
+		obj := &unstructured.Unstructured{}
+		require.NoError(t, obj.UnmarshalJSON(found.Value))
+		meta, err := utils.MetaAccessor(obj)
+		require.NoError(t, err)
+		info := meta.GetBlob()
+		require.NotNil(t, info)
+		require.NotEqual(t, blob.Uid, info.UID)
+		rsp, err := targetBlobs.GetResourceBlob(ctx, key("target", "a"), info, true)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(rsp.Value))
+	})
+
+	t.Run("import in batches with a bulk backend", func(t *testing.T) {
+		target, _ := newStore(t)
+		bulk := &testBulkBackend{StorageBackend: target}
+		summary, err := ImportNamespace(ctx, bulk, bytes.NewReader(archive.Bytes()), ImportOptions{Namespace: "target"})
+		require.NoError(t, err)
+		require.Equal(t, 2, summary.Created)
+		require.Equal(t, []int{2}, bulk.batches)
+		require.Equal(t, "A", title(t, target, "target", "a"))
+		require.Equal(t, "B", title(t, target, "target", "b"))
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		tests := []struct {
+			strategy ImportConflict
+			check    func(t *testing.T, target StorageBackend, summary *ImportSummary, err error)
+		}{
+			{
+				strategy: ImportConflictFail,
+				check: func(t *testing.T, target StorageBackend, summary *ImportSummary, err error) {
+					require.ErrorContains(t, err, "already exists")
+					require.Equal(t, "existing", title(t, target, "target", "a"))
+				},
+			},
+			{
+				strategy: ImportConflictSkip,
+				check: func(t *testing.T, target StorageBackend, summary *ImportSummary, err error) {
+					require.NoError(t, err)
+					require.Equal(t, 1, summary.Skipped)
+					require.Equal(t, 1, summary.Created)
+					require.Equal(t, "existing", title(t, target, "target", "a"))
+					require.Equal(t, "B", title(t, target, "target", "b"))
+				},
+			},
+			{
+				strategy: ImportConflictOverwrite,
+				check: func(t *testing.T, target StorageBackend, summary *ImportSummary, err error) {
+					require.NoError(t, err)
+					require.Equal(t, 1, summary.Updated)
+					require.Equal(t, 1, summary.Created)
+					require.Equal(t, "A", title(t, target, "target", "a"))
+				},
+			},
+			{
+				strategy: ImportConflictRename,
+				check: func(t *testing.T, target StorageBackend, summary *ImportSummary, err error) {
+					require.NoError(t, err)
+					require.Equal(t, 2, summary.Created)
+					renamed := summary.Renamed["playlist.grafana.app/playlists/a"]
+					require.NotEmpty(t, renamed)
+					require.Equal(t, "existing", title(t, target, "target", "a"))
+					require.Equal(t, "A", title(t, target, "target", renamed))
+
+					found := target.ReadResource(ctx, &ReadRequest{Key: key("target", renamed)})
+					obj := &unstructured.Unstructured{}
+					require.NoError(t, obj.UnmarshalJSON(found.Value))
+					require.Equal(t, renamed, obj.GetName())
+					require.NotEqual(t, "uid-a", string(obj.GetUID()))
+				},
+			},
+		}
+		for _, tc := range tests {
+			t.Run(string(tc.strategy), func(t *testing.T) {
+				target, _ := newStore(t)
+				write(t, target, "target", "a", "existing", nil)
+				summary, err := ImportNamespace(ctx, target, bytes.NewReader(archive.Bytes()), ImportOptions{
+					Namespace:  "target",
+					OnConflict: tc.strategy,
+				})
+				tc.check(t, target, summary, err)
+			})
+		}
+	})
+
+	t.Run("invalid archives", func(t *testing.T) {
+		target, _ := newStore(t)
+		_, err := ImportNamespace(ctx, target, bytes.NewReader(archive.Bytes()), ImportOptions{OnConflict: "merge"})
+		require.ErrorContains(t, err, "unknown conflict strategy")
+
+		_, err = ImportNamespace(ctx, target, bytes.NewReader([]byte("not a tar file")), ImportOptions{})
+		require.Error(t, err)
+
+		other := &bytes.Buffer{}
+		ar := &archiveWriter{tw: tar.NewWriter(other)}
+		raw, _ := json.Marshal(ArchiveManifest{Version: "0", Namespace: "x"})
+		require.NoError(t, ar.write(archiveManifestFile, raw, nil))
+		require.NoError(t, ar.tw.Close())
+		_, err = ImportNamespace(ctx, target, other, ImportOptions{})
+		require.ErrorContains(t, err, "unsupported archive version")
+
+		// The resource in the path is not listed in the manifest
+		other = &bytes.Buffer{}
+		ar = &archiveWriter{tw: tar.NewWriter(other)}
+		raw, _ = json.Marshal(ArchiveManifest{
+			Version:   ArchiveVersion,
+			Namespace: "x",
+			Resources: []ArchiveResource{{Group: playlists.Group, Resource: playlists.Resource}},
+		})
+		require.NoError(t, ar.write(archiveManifestFile, raw, nil))
+		require.NoError(t, ar.write("resources/dashboard.grafana.app/dashboards/a.json", []byte(`{}`), nil))
+		require.NoError(t, ar.tw.Close())
+		_, err = ImportNamespace(ctx, target, other, ImportOptions{})
+		require.ErrorContains(t, err, "not a resource listed in the manifest")
+
+		found := target.ReadResource(ctx, &ReadRequest{Key: key("source", "a")})
+		require.Equal(t, int32(http.StatusNotFound), found.Error.Code)
+	})
+
+	t.Run("export and import with the API", func(t *testing.T) {
+		admin := claims.WithClaims(ctx, &identity.StaticRequester{
+			Type:           claims.TypeUser,
+			UserUID:        "admin",
+			IsGrafanaAdmin: true,
+		})
+		server, err := NewResourceServer(ResourceServerOptions{
+			Backend: source,
+			Blob:    BlobConfig{Backend: sourceBlobs},
+		})
+		require.NoError(t, err)
+
+		viewer := claims.WithClaims(ctx, &identity.StaticRequester{Type: claims.TypeUser, UserUID: "viewer"})
+		denied := &testExportStream{ctx: viewer}
+		require.NoError(t, server.ExportNamespace(&ExportNamespaceRequest{Namespace: "source"}, denied))
+		require.Len(t, denied.responses, 1)
+		require.Equal(t, int32(http.StatusForbidden), denied.responses[0].Error.Code)
+
+		exported := &testExportStream{ctx: admin}
+		require.NoError(t, server.ExportNamespace(&ExportNamespaceRequest{
+			Namespace: "source",
+			Resources: []*ResourceKey{{Group: playlists.Group, Resource: playlists.Resource}},
+		}, exported))
+		imported := &testImportStream{ctx: admin}
+		for i, rsp := range exported.responses {
+			require.Nil(t, rsp.Error)
+			req := &ImportNamespaceRequest{Chunk: rsp.Chunk}
+			if i == 0 {
+				req.Namespace = "copy"
+			}
+			imported.requests = append(imported.requests, req)
+		}
+		require.NoError(t, server.ImportNamespace(imported))
+		require.Nil(t, imported.response.Error)
+		require.Equal(t, int64(2), imported.response.Created)
+		require.Equal(t, "A", title(t, source, "copy", "a"))
+		require.Equal(t, "B", title(t, source, "copy", "b"))
+	})
+}
+
+func TestNamespaceArchiveRenamedFolder(t *testing.T) {
+	ctx := context.Background()
+	dashboards := NamespacedResource{Group: "dashboard.grafana.app", Resource: "dashboards"}
+	folders := NamespacedResource{Group: archiveFolderGroup, Resource: archiveFolderResource}
+
+	newStore := func(t *testing.T) StorageBackend {
+		t.Helper()
+		store, err := NewCDKBackend(ctx, CDKBackendOptions{
+			Bucket: memblob.OpenBucket(nil),
+		})
+		require.NoError(t, err)
+		return store
+	}
+	key := func(r NamespacedResource, ns, name string) *ResourceKey {
+		return &ResourceKey{Namespace: ns, Group: r.Group, Resource: r.Resource, Name: name}
+	}
+	write := func(t *testing.T, store StorageBackend, r NamespacedResource, ns, name, folder string) {
+		t.Helper()
+		obj := &unstructured.Unstructured{}
+		err := obj.UnmarshalJSON([]byte(fmt.Sprintf(`{
+			"apiVersion": "%s/v0alpha1",
+			"kind": "Object",
+			"metadata": {"name": %q, "uid": "uid-%s", "namespace": %q},
+			"spec": {"title": %q}
+		}`, r.Group, name, name, ns, name)))
+		require.NoError(t, err)
+		meta, err := utils.MetaAccessor(obj)
+		require.NoError(t, err)
+		meta.SetFolder(folder)
+		value, err := obj.MarshalJSON()
+		require.NoError(t, err)
+		_, err = store.WriteEvent(ctx, WriteEvent{Key: key(r, ns, name), Type: WatchEvent_ADDED, Value: value, Object: meta})
+		require.NoError(t, err)
+	}
+	folder := func(t *testing.T, store StorageBackend, r NamespacedResource, ns, name string) string {
+		t.Helper()
+		found := store.ReadResource(ctx, &ReadRequest{Key: key(r, ns, name)})
+		require.Nil(t, found.Error)
+		obj := &unstructured.Unstructured{}
+		require.NoError(t, obj.UnmarshalJSON(found.Value))
+		meta, err := utils.MetaAccessor(obj)
+		require.NoError(t, err)
+		return meta.GetFolder()
+	}
+
+	// The dashboards are exported before the folders, so "d" is imported before its folder is renamed
+	source := newStore(t)
+	write(t, source, folders, "source", "f", "")
+	write(t, source, folders, "source", "g", "f")
+	write(t, source, dashboards, "source", "d", "f")
+	write(t, source, dashboards, "source", "e", "shared")
+
+	archive := &bytes.Buffer{}
+	_, err := ExportNamespace(ctx, source, ExportOptions{
+		Namespace: "source",
+		Resources: []NamespacedResource{folders, dashboards},
+	}, archive)
+	require.NoError(t, err)
+
+	target := newStore(t)
+	write(t, target, folders, "target", "f", "")
+	write(t, target, folders, "target", "shared", "")
+	summary, err := ImportNamespace(ctx, target, bytes.NewReader(archive.Bytes()), ImportOptions{
+		Namespace:  "target",
+		OnConflict: ImportConflictRename,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, summary.Created)
+	renamed := summary.Renamed["folder.grafana.app/folders/f"]
+	require.NotEmpty(t, renamed)
+	require.Len(t, summary.Renamed, 1)
+
+	require.Equal(t, renamed, folder(t, target, dashboards, "target", "d"))
+	require.Equal(t, renamed, folder(t, target, folders, "target", "g"))
+	require.Equal(t, "", folder(t, target, folders, "target", renamed))
+	// Folders which are not in the archive are not changed
+	require.Equal(t, "shared", folder(t, target, dashboards, "target", "e"))
+}
@@ -20,12 +20,14 @@ type BleveIndexMetrics struct {
 	Backend  SearchBackend
 
 	// metrics
-	IndexLatency      *prometheus.HistogramVec
-	IndexSize         prometheus.Gauge
-	IndexedDocs       prometheus.Gauge
-	IndexedKinds      *prometheus.GaugeVec
-	IndexCreationTime *prometheus.HistogramVec
-	IndexTenants      *prometheus.CounterVec
+	IndexLatency       *prometheus.HistogramVec
+	IndexSize          prometheus.Gauge
+	IndexedDocs        prometheus.Gauge
+	IndexedKinds       *prometheus.GaugeVec
+	IndexCreationTime  *prometheus.HistogramVec
+	IndexTenants       *prometheus.CounterVec
+	IndexLag           *prometheus.GaugeVec
+	IndexCatchUpEvents *prometheus.CounterVec
 }
 
 var IndexCreationBuckets = []float64{1, 5, 10, 25, 50, 75, 100, 200, 300, 400, 500, 600, 700, 800, 900, 1000}
@@ -73,6 +75,16 @@ func NewIndexMetrics(indexDir string, searchBackend SearchBackend) *BleveIndexMe
 				Name:      "index_tenants",
 				Help:      "Number of tenants in the index",
 			}, []string{"namespace", "index_storage"}), // index_storage is either "file" or "memory"
+			IndexLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: "index_server",
+				Name:      "index_lag_seconds",
+				Help:      "Time (in seconds) between writing the last indexed event and indexing it",
+			}, []string{"resource"}),
+			IndexCatchUpEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "index_server",
+				Name:      "index_catchup_events_total",
+				Help:      "Number of events replayed to catch up persisted indexes",
+			}, []string{"resource"}),
 		}
 	})
 
@@ -84,6 +96,8 @@ func (s *BleveIndexMetrics) Collect(ch chan<- prometheus.Metric) {
 	s.IndexCreationTime.Collect(ch)
 	s.IndexedKinds.Collect(ch)
 	s.IndexTenants.Collect(ch)
+	s.IndexLag.Collect(ch)
+	s.IndexCatchUpEvents.Collect(ch)
 
 	// collect index size
 	totalSize, err := getTotalIndexSize(s.IndexDir)
@@ -104,6 +118,8 @@ func (s *BleveIndexMetrics) Describe(ch chan<- *prometheus.Desc) {
 	s.IndexedKinds.Describe(ch)
 	s.IndexCreationTime.Describe(ch)
 	s.IndexTenants.Describe(ch)
+	s.IndexLag.Describe(ch)
+	s.IndexCatchUpEvents.Describe(ch)
 }
 
 // getTotalIndexSize returns the total size of all file-based indices.
@@ -0,0 +1,157 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/grafana/authlib/claims"
+)
+
+// bulkItem is a single request of a bulk write
+type bulkItem struct {
+	index int
+	event *WriteEvent
+}
+
+// BulkWrite reads all requests from the stream before writing anything. Requests for the same
+// namespace and resource are written in a single transaction when the backend supports it.
+func (s *server) BulkWrite(stream ResourceStore_BulkWriteServer) error {
+	ctx, span := s.tracer.Start(stream.Context(), "storage_server.BulkWrite")
+	defer span.End()
+
+	rsp := &BulkWriteResponse{}
+	user, ok := claims.From(ctx)
+	if !ok || user == nil {
+		rsp.Error = &ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+		return stream.SendAndClose(rsp)
+	}
+	if err := s.Init(ctx); err != nil {
+		rsp.Error = AsErrorResult(err)
+		return stream.SendAndClose(rsp)
+	}
+
+	// Keep the order of the resources so the results are predictable
+	groups := make(map[NamespacedResource][]bulkItem)
+	order := []NamespacedResource{}
+	seen := make(map[string]bool)
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		result := &BulkWriteResponse_Result{Key: req.Key}
+		rsp.Results = append(rsp.Results, result)
+		if e := verifyObjectKey(req.Key); e != nil {
+			result.Error = e
+			continue
+		}
+		id := req.Key.SearchID()
+		if seen[id] {
+			result.Error = NewBadRequestError("duplicate key in bulk request")
+			continue
+		}
+		seen[id] = true
+
+		event, e := s.newBulkEvent(ctx, user, req)
+		if e != nil {
+			result.Error = e
+			continue
+		}
+		key := NamespacedResource{
+			Namespace: req.Key.Namespace,
+			Group:     req.Key.Group,
+			Resource:  req.Key.Resource,
+		}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], bulkItem{index: len(rsp.Results) - 1, event: event})
+	}
+
+	bulk, isBulk := s.backend.(BulkStorageBackend)
+	for _, key := range order {
+		items := groups[key]
+		if isBulk {
+			events := make([]WriteEvent, len(items))
+			for i, item := range items {
+				events[i] = *item.event
+			}
+			rvs, err := bulk.WriteEvents(ctx, events)
+			for i, item := range items {
+				if err != nil {
+					rsp.Results[item.index].Error = AsErrorResult(err)
+					continue
+				}
+				rsp.Results[item.index].ResourceVersion = rvs[i]
+			}
+			continue
+		}
+
+		// Without transactions each event is written on its own
+		for _, item := range items {
+			rv, err := s.backend.WriteEvent(ctx, *item.event)
+			if err != nil {
+				rsp.Results[item.index].Error = AsErrorResult(err)
+				continue
+			}
+			rsp.Results[item.index].ResourceVersion = rv
+		}
+	}
+
+	for _, result := range rsp.Results {
+		rsp.ResourceVersion = max(rsp.ResourceVersion, result.ResourceVersion)
+	}
+	s.log.Debug("server.BulkWrite", "items", len(rsp.Results), "rv", rsp.ResourceVersion)
+	return stream.SendAndClose(rsp)
+}
+
+// newBulkEvent checks the request against the current value and creates the event to write
+func (s *server) newBulkEvent(ctx context.Context, user claims.AuthInfo, req *BulkWriteRequest) (*WriteEvent, *ErrorResult) {
+	switch req.Action {
+	case BulkWriteRequest_CREATE:
+		found := s.backend.ReadResource(ctx, &ReadRequest{Key: req.Key})
+		if found != nil && len(found.Value) > 0 {
+			return nil, &ErrorResult{
+				Code:    http.StatusConflict,
+				Message: "key already exists",
+			}
+		}
+		return s.newEvent(ctx, user, req.Key, req.Value, nil)
+
+	case BulkWriteRequest_UPDATE, BulkWriteRequest_DELETE:
+		if req.ResourceVersion < 0 {
+			return nil, NewBadRequestError("update must include the previous version")
+		}
+		latest := s.backend.ReadResource(ctx, &ReadRequest{Key: req.Key})
+		if latest.Error != nil {
+			return nil, latest.Error
+		}
+		if latest.Value == nil {
+			return nil, NewBadRequestError("current value does not exist")
+		}
+		if req.ResourceVersion > 0 && latest.ResourceVersion != req.ResourceVersion {
+			return nil, AsErrorResult(ErrOptimisticLockingFailed)
+		}
+		if req.Action == BulkWriteRequest_DELETE {
+			return s.newDeleteEvent(ctx, user, req.Key, latest)
+		}
+
+		event, e := s.newEvent(ctx, user, req.Key, req.Value, latest.Value)
+		if e != nil {
+			return nil, e
+		}
+		event.Type = WatchEvent_MODIFIED
+		event.PreviousRV = latest.ResourceVersion
+		return event, nil
+	}
+	return nil, NewBadRequestError("unknown bulk action")
+}
@@ -0,0 +1,122 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/authlib/claims"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+// testBulkStream sends the requests and keeps the response
+type testBulkStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests []*BulkWriteRequest
+	response *BulkWriteResponse
+}
+
+func (s *testBulkStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testBulkStream) Recv() (*BulkWriteRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *testBulkStream) SendAndClose(rsp *BulkWriteResponse) error {
+	s.response = rsp
+	return nil
+}
+
+func TestBulkWrite(t *testing.T) {
+	ctx := claims.WithClaims(context.Background(), &identity.StaticRequester{
+		Type:           claims.TypeUser,
+		Login:          "testuser",
+		UserID:         123,
+		UserUID:        "u123",
+		OrgRole:        identity.RoleAdmin,
+		IsGrafanaAdmin: true, // can do anything
+	})
+
+	store, err := NewCDKBackend(ctx, CDKBackendOptions{
+		Bucket: memblob.OpenBucket(nil),
+	})
+	require.NoError(t, err)
+	server, err := NewResourceServer(ResourceServerOptions{
+		Backend: store,
+	})
+	require.NoError(t, err)
+
+	key := func(name string) *ResourceKey {
+		return &ResourceKey{
+			Group:     "playlist.grafana.app",
+			Resource:  "playlists",
+			Namespace: "default",
+			Name:      name,
+		}
+	}
+	value := func(name string) []byte {
+		return []byte(fmt.Sprintf(`{
+			"apiVersion": "playlist.grafana.app/v0alpha1",
+			"kind": "Playlist",
+			"metadata": {"name": %q, "uid": "uid-%s", "namespace": "default"},
+			"spec": {"title": %q}
+		}`, name, name, name))
+	}
+	bulk := func(t *testing.T, requests ...*BulkWriteRequest) *BulkWriteResponse {
+		t.Helper()
+		stream := &testBulkStream{ctx: ctx, requests: requests}
+		require.NoError(t, server.BulkWrite(stream))
+		require.NotNil(t, stream.response)
+		require.Nil(t, stream.response.Error)
+		require.Len(t, stream.response.Results, len(requests))
+		return stream.response
+	}
+
+	created := bulk(t,
+		&BulkWriteRequest{Action: BulkWriteRequest_CREATE, Key: key("a"), Value: value("a")},
+		&BulkWriteRequest{Action: BulkWriteRequest_CREATE, Key: key("b"), Value: value("b")},
+		&BulkWriteRequest{Action: BulkWriteRequest_CREATE, Key: key("b"), Value: value("b")},
+		&BulkWriteRequest{Action: BulkWriteRequest_CREATE, Key: &ResourceKey{Group: "playlist.grafana.app", Resource: "playlists"}, Value: value("c")},
+	)
+	require.Nil(t, created.Results[0].Error)
+	require.Nil(t, created.Results[1].Error)
+	require.Equal(t, int32(http.StatusBadRequest), created.Results[2].Error.Code) // duplicate
+	require.Equal(t, int32(http.StatusBadRequest), created.Results[3].Error.Code) // missing namespace
+	require.Greater(t, created.Results[1].ResourceVersion, created.Results[0].ResourceVersion)
+	require.Equal(t, created.Results[1].ResourceVersion, created.ResourceVersion)
+
+	changed := bulk(t,
+		&BulkWriteRequest{Action: BulkWriteRequest_CREATE, Key: key("a"), Value: value("a")},
+		&BulkWriteRequest{Action: BulkWriteRequest_UPDATE, Key: key("a"), Value: value("a"), ResourceVersion: created.Results[1].ResourceVersion},
+		&BulkWriteRequest{Action: BulkWriteRequest_DELETE, Key: key("b"), ResourceVersion: created.Results[1].ResourceVersion},
+		&BulkWriteRequest{Action: BulkWriteRequest_UNKNOWN, Key: key("c")},
+	)
+	require.Equal(t, int32(http.StatusConflict), changed.Results[0].Error.Code)
+	require.NotNil(t, changed.Results[1].Error) // wrong resource version
+	require.Nil(t, changed.Results[2].Error)
+	require.Equal(t, int32(http.StatusBadRequest), changed.Results[3].Error.Code)
+	require.Equal(t, changed.Results[2].ResourceVersion, changed.ResourceVersion)
+
+	found, err := server.Read(ctx, &ReadRequest{Key: key("b")})
+	require.NoError(t, err)
+	require.Equal(t, int32(http.StatusNotFound), found.Error.Code)
+
+	found, err = server.Read(ctx, &ReadRequest{Key: key("a")})
+	require.NoError(t, err)
+	require.Nil(t, found.Error)
+	require.Equal(t, created.Results[0].ResourceVersion, found.ResourceVersion)
+}
@@ -140,58 +140,57 @@ func (s *cdkBackend) WriteEvent(ctx context.Context, event WriteEvent) (rv int64
 }
 
 func (s *cdkBackend) ReadResource(ctx context.Context, req *ReadRequest) *BackendReadResponse {
-	rv := req.ResourceVersion
-
-	path := s.getPath(req.Key, rv)
-	if rv < 1 {
-		iter := s.bucket.List(&blob.ListOptions{Prefix: path + "/", Delimiter: "/"})
-		for {
-			obj, err := iter.Next(ctx)
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			if strings.HasSuffix(obj.Key, ".json") {
-				idx := strings.LastIndex(obj.Key, "/") + 1
-				edx := strings.LastIndex(obj.Key, ".")
-				if idx > 0 {
-					v, err := strconv.ParseInt(obj.Key[idx:edx], 10, 64)
-					if err == nil && v > rv {
-						rv = v
-						path = obj.Key // find the path with biggest resource version
-					}
-				}
-			}
-		}
-	}
-
-	raw, err := s.bucket.ReadAll(ctx, path)
-	if raw == nil && req.ResourceVersion > 0 {
-		if req.ResourceVersion > s.rv.Load() {
-			return &BackendReadResponse{
-				Error: &ErrorResult{
-					Code:    http.StatusGatewayTimeout,
-					Reason:  string(metav1.StatusReasonTimeout), // match etcd behavior
-					Message: "ResourceVersion is larger than max",
-					Details: &ErrorDetails{
-						Causes: []*ErrorCause{
-							{
-								Reason:  string(metav1.CauseTypeResourceVersionTooLarge),
-								Message: fmt.Sprintf("requested: %d, current %d", req.ResourceVersion, s.rv.Load()),
-							},
+	if req.ResourceVersion > s.rv.Load() {
+		return &BackendReadResponse{
+			Error: &ErrorResult{
+				Code:    http.StatusGatewayTimeout,
+				Reason:  string(metav1.StatusReasonTimeout), // match etcd behavior
+				Message: "ResourceVersion is larger than max",
+				Details: &ErrorDetails{
+					Causes: []*ErrorCause{
+						{
+							Reason:  string(metav1.CauseTypeResourceVersionTooLarge),
+							Message: fmt.Sprintf("requested: %d, current %d", req.ResourceVersion, s.rv.Load()),
 						},
 					},
 				},
-			}
+			},
 		}
+	}
 
-		// If the there was an explicit request, get the latest
-		rsp := s.ReadResource(ctx, &ReadRequest{Key: req.Key})
-		if rsp != nil && len(rsp.Value) > 0 {
-			raw = rsp.Value
-			rv = rsp.ResourceVersion
-			err = nil
+	// Find the biggest resource version that is not after the requested version
+	rv := int64(0)
+	path := ""
+	iter := s.bucket.List(&blob.ListOptions{Prefix: s.getPath(req.Key, 0) + "/", Delimiter: "/"})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return &BackendReadResponse{Error: AsErrorResult(err)}
 		}
+		if strings.HasSuffix(obj.Key, ".json") {
+			idx := strings.LastIndex(obj.Key, "/") + 1
+			edx := strings.LastIndex(obj.Key, ".")
+			if idx > 0 {
+				v, err := strconv.ParseInt(obj.Key[idx:edx], 10, 64)
+				if err == nil && v > rv && (req.ResourceVersion < 1 || v <= req.ResourceVersion) {
+					rv = v
+					path = obj.Key
+				}
+			}
+		}
+	}
+	if path == "" {
+		// If the there was an explicit request before the first version, get the latest
+		if req.ResourceVersion > 0 {
+			return s.ReadResource(ctx, &ReadRequest{Key: req.Key})
+		}
+		return &BackendReadResponse{Error: NewNotFoundError(req.Key)}
 	}
+
+	raw, err := s.bucket.ReadAll(ctx, path)
 	if err == nil && isDeletedMarker(raw) {
 		raw = nil
 	}
@@ -317,12 +316,26 @@ func (c *cdkListIterator) ContinueToken() string {
 
 // Name implements ListIterator.
 func (c *cdkListIterator) Name() string {
-	return c.currentKey // TODO (parse name from key)
+	return c.keyPart(2)
 }
 
 // Namespace implements ListIterator.
 func (c *cdkListIterator) Namespace() string {
-	return c.currentKey // TODO (parse namespace from key)
+	ns := c.keyPart(3)
+	if ns == "__cluster__" {
+		return ""
+	}
+	return ns
+}
+
+// keyPart returns a part of the current key, counted from the end
+// The keys are {group}/{resource}/{namespace}/{name}/{rv}.json
+func (c *cdkListIterator) keyPart(fromEnd int) string {
+	parts := strings.Split(c.currentKey, "/")
+	if len(parts) < fromEnd {
+		return ""
+	}
+	return parts[len(parts)-fromEnd]
 }
 
 func (c *cdkListIterator) Folder() string {
@@ -225,6 +225,7 @@ const SEARCH_FIELD_NAMESPACE = "namespace"
 const SEARCH_FIELD_NAME = "name"
 const SEARCH_FIELD_RV = "rv"
 const SEARCH_FIELD_TITLE = "title"
+const SEARCH_FIELD_TITLE_PHRASE = "title_phrase" // the whole title in lower case, used for sorting
 const SEARCH_FIELD_DESCRIPTION = "description"
 const SEARCH_FIELD_TAGS = "tags"
 const SEARCH_FIELD_LABELS = "labels" // All labels, not a specific one
@@ -237,8 +238,9 @@ const SEARCH_FIELD_UPDATED_BY = "updatedBy"
 const SEARCH_FIELD_REPOSITORY = "repository"
 const SEARCH_FIELD_REPOSITORY_HASH = "repository_hash"
 
-const SEARCH_FIELD_SCORE = "_score"     // the match score
-const SEARCH_FIELD_EXPLAIN = "_explain" // score explanation as JSON object
+const SEARCH_FIELD_SCORE = "_score"         // the match score
+const SEARCH_FIELD_EXPLAIN = "_explain"     // score explanation as JSON object
+const SEARCH_FIELD_HIGHLIGHT = "_highlight" // highlighted query matches as JSON object (field name to fragments)
 
 var standardSearchFieldsInit sync.Once
 var standardSearchFields SearchableDocumentFields
@@ -316,6 +318,21 @@ func StandardSearchFields() SearchableDocumentFields {
 				Type:        ResourceTableColumnDefinition_INT64,
 				Description: "created timestamp", // date?
 			},
+			{
+				Name:        SEARCH_FIELD_SCORE,
+				Type:        ResourceTableColumnDefinition_DOUBLE,
+				Description: "The match score",
+			},
+			{
+				Name:        SEARCH_FIELD_EXPLAIN,
+				Type:        ResourceTableColumnDefinition_OBJECT,
+				Description: "Explanation of the match score",
+			},
+			{
+				Name:        SEARCH_FIELD_HIGHLIGHT,
+				Type:        ResourceTableColumnDefinition_OBJECT,
+				Description: "Highlighted query matches by field",
+			},
 		})
 		if err != nil {
 			panic("failed to initialize standard search fields")
@@ -13,6 +13,10 @@ import (
 var (
 	ErrOptimisticLockingFailed = errors.New("optimistic locking failed")
 	ErrNotImplementedYet       = errors.New("not implemented yet")
+
+	// ErrHistoryCompacted is returned when events after the requested resource version were
+	// removed from the history, they can not be replayed and the state must be rebuilt
+	ErrHistoryCompacted = errors.New("the history was compacted after the resource version")
 )
 
 func NewBadRequestError(msg string) *ErrorResult {
@@ -18,6 +18,20 @@ func verifyRequestKey(key *ResourceKey) *ErrorResult {
 	return nil
 }
 
+// verifyObjectKey requires all key fields to be set
+func verifyObjectKey(key *ResourceKey) *ErrorResult {
+	if r := verifyRequestKey(key); r != nil {
+		return r
+	}
+	if key.Namespace == "" {
+		return NewBadRequestError("request key is missing namespace")
+	}
+	if key.Name == "" {
+		return NewBadRequestError("request key is missing name")
+	}
+	return nil
+}
+
 func matchesQueryKey(query *ResourceKey, key *ResourceKey) bool {
 	if query.Group != key.Group {
 		return false
@@ -19,12 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	ResourceStore_Read_FullMethodName   = "/resource.ResourceStore/Read"
-	ResourceStore_Create_FullMethodName = "/resource.ResourceStore/Create"
-	ResourceStore_Update_FullMethodName = "/resource.ResourceStore/Update"
-	ResourceStore_Delete_FullMethodName = "/resource.ResourceStore/Delete"
-	ResourceStore_List_FullMethodName   = "/resource.ResourceStore/List"
-	ResourceStore_Watch_FullMethodName  = "/resource.ResourceStore/Watch"
+	ResourceStore_Read_FullMethodName            = "/resource.ResourceStore/Read"
+	ResourceStore_Create_FullMethodName          = "/resource.ResourceStore/Create"
+	ResourceStore_Update_FullMethodName          = "/resource.ResourceStore/Update"
+	ResourceStore_Delete_FullMethodName          = "/resource.ResourceStore/Delete"
+	ResourceStore_Restore_FullMethodName         = "/resource.ResourceStore/Restore"
+	ResourceStore_List_FullMethodName            = "/resource.ResourceStore/List"
+	ResourceStore_Watch_FullMethodName           = "/resource.ResourceStore/Watch"
+	ResourceStore_BulkWrite_FullMethodName       = "/resource.ResourceStore/BulkWrite"
+	ResourceStore_ExportNamespace_FullMethodName = "/resource.ResourceStore/ExportNamespace"
+	ResourceStore_ImportNamespace_FullMethodName = "/resource.ResourceStore/ImportNamespace"
 )
 
 // ResourceStoreClient is the client API for ResourceStore service.
@@ -40,6 +44,9 @@ type ResourceStoreClient interface {
 	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
 	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Write the value of a previous resource version as the latest value
+	// Deleted objects are created again, otherwise the current value is replaced
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error)
 	// The results *may* include values that should not be returned to the user
 	// This will perform best-effort filtering to increase performace.
 	// NOTE: storage.Interface is ultimatly responsible for the final filtering
@@ -48,6 +55,14 @@ type ResourceStoreClient interface {
 	// This will perform best-effort filtering to increase performace.
 	// NOTE: storage.Interface is ultimatly responsible for the final filtering
 	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ResourceStore_WatchClient, error)
+	// Write many items in one request, eg: to migrate or restore a namespace
+	// The items are written in a single transaction for each namespace+group+resource
+	// and each key may only be written once in a request
+	BulkWrite(ctx context.Context, opts ...grpc.CallOption) (ResourceStore_BulkWriteClient, error)
+	// Write all resources of a namespace to a tar archive, this requires a server admin
+	ExportNamespace(ctx context.Context, in *ExportNamespaceRequest, opts ...grpc.CallOption) (ResourceStore_ExportNamespaceClient, error)
+	// Load a tar archive created by ExportNamespace, this requires a server admin
+	ImportNamespace(ctx context.Context, opts ...grpc.CallOption) (ResourceStore_ImportNamespaceClient, error)
 }
 
 type resourceStoreClient struct {
@@ -98,6 +113,16 @@ func (c *resourceStoreClient) Delete(ctx context.Context, in *DeleteRequest, opt
 	return out, nil
 }
 
+func (c *resourceStoreClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreResponse)
+	err := c.cc.Invoke(ctx, ResourceStore_Restore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *resourceStoreClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListResponse)
@@ -141,6 +166,109 @@ func (x *resourceStoreWatchClient) Recv() (*WatchEvent, error) {
 	return m, nil
 }
 
+func (c *resourceStoreClient) BulkWrite(ctx context.Context, opts ...grpc.CallOption) (ResourceStore_BulkWriteClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ResourceStore_ServiceDesc.Streams[1], ResourceStore_BulkWrite_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &resourceStoreBulkWriteClient{ClientStream: stream}
+	return x, nil
+}
+
+type ResourceStore_BulkWriteClient interface {
+	Send(*BulkWriteRequest) error
+	CloseAndRecv() (*BulkWriteResponse, error)
+	grpc.ClientStream
+}
+
+type resourceStoreBulkWriteClient struct {
+	grpc.ClientStream
+}
+
+func (x *resourceStoreBulkWriteClient) Send(m *BulkWriteRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *resourceStoreBulkWriteClient) CloseAndRecv() (*BulkWriteResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(BulkWriteResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *resourceStoreClient) ExportNamespace(ctx context.Context, in *ExportNamespaceRequest, opts ...grpc.CallOption) (ResourceStore_ExportNamespaceClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ResourceStore_ServiceDesc.Streams[2], ResourceStore_ExportNamespace_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &resourceStoreExportNamespaceClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ResourceStore_ExportNamespaceClient interface {
+	Recv() (*ExportNamespaceResponse, error)
+	grpc.ClientStream
+}
+
+type resourceStoreExportNamespaceClient struct {
+	grpc.ClientStream
+}
+
+func (x *resourceStoreExportNamespaceClient) Recv() (*ExportNamespaceResponse, error) {
+	m := new(ExportNamespaceResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *resourceStoreClient) ImportNamespace(ctx context.Context, opts ...grpc.CallOption) (ResourceStore_ImportNamespaceClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ResourceStore_ServiceDesc.Streams[3], ResourceStore_ImportNamespace_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &resourceStoreImportNamespaceClient{ClientStream: stream}
+	return x, nil
+}
+
+type ResourceStore_ImportNamespaceClient interface {
+	Send(*ImportNamespaceRequest) error
+	CloseAndRecv() (*ImportNamespaceResponse, error)
+	grpc.ClientStream
+}
+
+type resourceStoreImportNamespaceClient struct {
+	grpc.ClientStream
+}
+
+func (x *resourceStoreImportNamespaceClient) Send(m *ImportNamespaceRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *resourceStoreImportNamespaceClient) CloseAndRecv() (*ImportNamespaceResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportNamespaceResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ResourceStoreServer is the server API for ResourceStore service.
 // All implementations should embed UnimplementedResourceStoreServer
 // for forward compatibility
@@ -154,6 +282,9 @@ type ResourceStoreServer interface {
 	Create(context.Context, *CreateRequest) (*CreateResponse, error)
 	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Write the value of a previous resource version as the latest value
+	// Deleted objects are created again, otherwise the current value is replaced
+	Restore(context.Context, *RestoreRequest) (*RestoreResponse, error)
 	// The results *may* include values that should not be returned to the user
 	// This will perform best-effort filtering to increase performace.
 	// NOTE: storage.Interface is ultimatly responsible for the final filtering
@@ -162,6 +293,14 @@ type ResourceStoreServer interface {
 	// This will perform best-effort filtering to increase performace.
 	// NOTE: storage.Interface is ultimatly responsible for the final filtering
 	Watch(*WatchRequest, ResourceStore_WatchServer) error
+	// Write many items in one request, eg: to migrate or restore a namespace
+	// The items are written in a single transaction for each namespace+group+resource
+	// and each key may only be written once in a request
+	BulkWrite(ResourceStore_BulkWriteServer) error
+	// Write all resources of a namespace to a tar archive, this requires a server admin
+	ExportNamespace(*ExportNamespaceRequest, ResourceStore_ExportNamespaceServer) error
+	// Load a tar archive created by ExportNamespace, this requires a server admin
+	ImportNamespace(ResourceStore_ImportNamespaceServer) error
 }
 
 // UnimplementedResourceStoreServer should be embedded to have forward compatible implementations.
@@ -180,12 +319,24 @@ func (UnimplementedResourceStoreServer) Update(context.Context, *UpdateRequest)
 func (UnimplementedResourceStoreServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedResourceStoreServer) Restore(context.Context, *RestoreRequest) (*RestoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
 func (UnimplementedResourceStoreServer) List(context.Context, *ListRequest) (*ListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
 func (UnimplementedResourceStoreServer) Watch(*WatchRequest, ResourceStore_WatchServer) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
+func (UnimplementedResourceStoreServer) BulkWrite(ResourceStore_BulkWriteServer) error {
+	return status.Errorf(codes.Unimplemented, "method BulkWrite not implemented")
+}
+func (UnimplementedResourceStoreServer) ExportNamespace(*ExportNamespaceRequest, ResourceStore_ExportNamespaceServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportNamespace not implemented")
+}
+func (UnimplementedResourceStoreServer) ImportNamespace(ResourceStore_ImportNamespaceServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportNamespace not implemented")
+}
 
 // UnsafeResourceStoreServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to ResourceStoreServer will
@@ -270,6 +421,24 @@ func _ResourceStore_Delete_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ResourceStore_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceStoreServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceStore_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceStoreServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ResourceStore_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListRequest)
 	if err := dec(in); err != nil {
@@ -309,6 +478,79 @@ func (x *resourceStoreWatchServer) Send(m *WatchEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ResourceStore_BulkWrite_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ResourceStoreServer).BulkWrite(&resourceStoreBulkWriteServer{ServerStream: stream})
+}
+
+type ResourceStore_BulkWriteServer interface {
+	SendAndClose(*BulkWriteResponse) error
+	Recv() (*BulkWriteRequest, error)
+	grpc.ServerStream
+}
+
+type resourceStoreBulkWriteServer struct {
+	grpc.ServerStream
+}
+
+func (x *resourceStoreBulkWriteServer) SendAndClose(m *BulkWriteResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *resourceStoreBulkWriteServer) Recv() (*BulkWriteRequest, error) {
+	m := new(BulkWriteRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _ResourceStore_ExportNamespace_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportNamespaceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ResourceStoreServer).ExportNamespace(m, &resourceStoreExportNamespaceServer{ServerStream: stream})
+}
+
+type ResourceStore_ExportNamespaceServer interface {
+	Send(*ExportNamespaceResponse) error
+	grpc.ServerStream
+}
+
+type resourceStoreExportNamespaceServer struct {
+	grpc.ServerStream
+}
+
+func (x *resourceStoreExportNamespaceServer) Send(m *ExportNamespaceResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ResourceStore_ImportNamespace_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ResourceStoreServer).ImportNamespace(&resourceStoreImportNamespaceServer{ServerStream: stream})
+}
+
+type ResourceStore_ImportNamespaceServer interface {
+	SendAndClose(*ImportNamespaceResponse) error
+	Recv() (*ImportNamespaceRequest, error)
+	grpc.ServerStream
+}
+
+type resourceStoreImportNamespaceServer struct {
+	grpc.ServerStream
+}
+
+func (x *resourceStoreImportNamespaceServer) SendAndClose(m *ImportNamespaceResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *resourceStoreImportNamespaceServer) Recv() (*ImportNamespaceRequest, error) {
+	m := new(ImportNamespaceRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ResourceStore_ServiceDesc is the grpc.ServiceDesc for ResourceStore service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -332,6 +574,10 @@ var ResourceStore_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _ResourceStore_Delete_Handler,
 		},
+		{
+			MethodName: "Restore",
+			Handler:    _ResourceStore_Restore_Handler,
+		},
 		{
 			MethodName: "List",
 			Handler:    _ResourceStore_List_Handler,
@@ -343,6 +589,21 @@ var ResourceStore_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ResourceStore_Watch_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "BulkWrite",
+			Handler:       _ResourceStore_BulkWrite_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportNamespace",
+			Handler:       _ResourceStore_ExportNamespace_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportNamespace",
+			Handler:       _ResourceStore_ImportNamespace_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "resource.proto",
 }
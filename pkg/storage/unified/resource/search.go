@@ -2,6 +2,7 @@ package resource
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -78,6 +79,15 @@ type SearchBackend interface {
 	TotalDocs() int64
 }
 
+// PersistentSearchBackend is implemented by search backends that keep indexes between restarts
+type PersistentSearchBackend interface {
+	SearchBackend
+
+	// Open an index that was persisted before and return the resource version it was written at.
+	// This will return nil when no index exists or it was written with a different schema
+	OpenIndex(ctx context.Context, key NamespacedResource, fields SearchableDocumentFields) (ResourceIndex, int64, error)
+}
+
 const tracingPrexfixSearch = "unified_search."
 
 // This supports indexing+search regardless of implementation
@@ -141,6 +151,10 @@ func (s *searchSupport) Origin(context.Context, *OriginRequest) (*OriginResponse
 
 // Search implements ResourceIndexServer.
 func (s *searchSupport) Search(ctx context.Context, req *ResourceSearchRequest) (*ResourceSearchResponse, error) {
+	if len(req.Namespaces) > 0 {
+		return s.searchNamespaces(ctx, req)
+	}
+
 	nsr := NamespacedResource{
 		Group:     req.Options.Key.Group,
 		Namespace: req.Options.Key.Namespace,
@@ -328,42 +342,57 @@ func (s *searchSupport) handleEvent(ctx context.Context, evt *WrittenEvent) {
 		return
 	}
 
-	doc, err := builder.BuildDocument(ctx, evt.Key, evt.ResourceVersion, evt.Value)
+	err = s.applyEvent(ctx, index, builder, evt)
 	if err != nil {
-		s.log.Warn("error building document watch event", "error", err)
+		s.log.Warn("error indexing watch event", "type", evt.Type, "error", err)
 		return
 	}
 
+	// record latency from when event was created to when it was indexed
+	latencySeconds := indexLagSeconds(evt.ResourceVersion)
+	if latencySeconds > 5 {
+		s.log.Warn("high index latency", "latency", latencySeconds)
+	}
+	if IndexMetrics != nil {
+		IndexMetrics.IndexLatency.WithLabelValues(evt.Key.Resource).Observe(latencySeconds)
+		IndexMetrics.IndexLag.WithLabelValues(evt.Key.Resource).Set(latencySeconds)
+	}
+}
+
+// applyEvent writes or deletes the document of an event
+func (s *searchSupport) applyEvent(ctx context.Context, index ResourceIndex, builder DocumentBuilder, evt *WrittenEvent) error {
+	doc, err := builder.BuildDocument(ctx, evt.Key, evt.ResourceVersion, evt.Value)
+	if err != nil {
+		return err
+	}
+
 	switch evt.Type {
 	case WatchEvent_ADDED, WatchEvent_MODIFIED:
 		err = index.Write(doc)
 		if err != nil {
-			s.log.Warn("error writing document watch event", "error", err)
-			return
+			return err
 		}
-		if evt.Type == WatchEvent_ADDED {
+		if evt.Type == WatchEvent_ADDED && IndexMetrics != nil {
 			IndexMetrics.IndexedKinds.WithLabelValues(evt.Key.Resource).Inc()
 		}
 	case WatchEvent_DELETED:
 		err = index.Delete(evt.Key)
 		if err != nil {
-			s.log.Warn("error deleting document watch event", "error", err)
-			return
+			return err
+		}
+		if IndexMetrics != nil {
+			IndexMetrics.IndexedKinds.WithLabelValues(evt.Key.Resource).Dec()
 		}
-		IndexMetrics.IndexedKinds.WithLabelValues(evt.Key.Resource).Dec()
 	default:
 		// do nothing
 		s.log.Warn("unknown watch event", "type", evt.Type)
 	}
+	return nil
+}
 
-	// record latency from when event was created to when it was indexed
-	latencySeconds := float64(time.Now().UnixMicro()-evt.ResourceVersion) / 1e6
-	if latencySeconds > 5 {
-		s.log.Warn("high index latency", "latency", latencySeconds)
-	}
-	if IndexMetrics != nil {
-		IndexMetrics.IndexLatency.WithLabelValues(evt.Key.Resource).Observe(latencySeconds)
-	}
+// The resource versions are the microseconds when the event was written
+func indexLagSeconds(rv int64) float64 {
+	return float64(time.Now().UnixMicro()-rv) / 1e6
 }
 
 func (s *searchSupport) getOrCreateIndex(ctx context.Context, key NamespacedResource) (ResourceIndex, error) {
@@ -398,14 +427,31 @@ func (s *searchSupport) build(ctx context.Context, nsr NamespacedResource, size
 	}
 	fields := s.builders.GetFields(nsr)
 
-	s.log.Debug(fmt.Sprintf("TODO, build %+v (size:%d, rv:%d) // builder:%+v\n", nsr, size, rv, builder))
-
 	key := &ResourceKey{
 		Group:     nsr.Group,
 		Resource:  nsr.Resource,
 		Namespace: nsr.Namespace,
 	}
-	index, err := s.search.BuildIndex(ctx, nsr, size, rv, fields, func(index ResourceIndex) (int64, error) {
+
+	// A persisted index only needs the events written since it was last updated
+	index, indexRV, err := s.open(ctx, nsr, fields, builder)
+	if errors.Is(err, ErrHistoryCompacted) {
+		s.log.Info("history compacted since the persisted index was updated, it will be rebuilt", "namespace", nsr.Namespace, "group", nsr.Group, "resource", nsr.Resource)
+	} else if err != nil {
+		s.log.Warn("error opening persisted index, it will be rebuilt", "namespace", nsr.Namespace, "group", nsr.Group, "resource", nsr.Resource, "error", err)
+	}
+	if index != nil {
+		docCount, err := index.DocCount(ctx, "")
+		if err != nil {
+			s.log.Warn("error getting doc count", "error", err)
+		}
+		if IndexMetrics != nil {
+			IndexMetrics.IndexedKinds.WithLabelValues(key.Resource).Add(float64(docCount))
+		}
+		return index, indexRV, nil
+	}
+
+	index, err = s.search.BuildIndex(ctx, nsr, size, rv, fields, func(index ResourceIndex) (int64, error) {
 		rv, err = s.storage.ListIterator(ctx, &ListRequest{
 			Limit: 1000000000000, // big number
 			Options: &ListOptions{
@@ -458,6 +504,56 @@ func (s *searchSupport) build(ctx context.Context, nsr NamespacedResource, size
 	return index, rv, err
 }
 
+// open reopens a persisted index and replays the events written since it was last updated.
+// This will return nil when the index needs to be built from scratch
+func (s *searchSupport) open(ctx context.Context, nsr NamespacedResource, fields SearchableDocumentFields, builder DocumentBuilder) (ResourceIndex, int64, error) {
+	backend, ok := s.search.(PersistentSearchBackend)
+	if !ok {
+		return nil, 0, nil
+	}
+	// Without the events we can not know what was deleted in the meantime
+	events, ok := s.storage.(ResourceEventLister)
+	if !ok {
+		return nil, 0, nil
+	}
+
+	ctx, span := s.tracer.Start(ctx, tracingPrexfixSearch+"Open")
+	defer span.End()
+
+	index, indexRV, err := backend.OpenIndex(ctx, nsr, fields)
+	if err != nil || index == nil {
+		return nil, 0, err
+	}
+	if IndexMetrics != nil {
+		IndexMetrics.IndexLag.WithLabelValues(nsr.Resource).Set(indexLagSeconds(indexRV))
+	}
+
+	count := 0
+	rv, err := events.ListEventsSince(ctx, nsr, indexRV, func(evt *WrittenEvent) error {
+		count++
+		return s.applyEvent(ctx, index, builder, evt)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if err = index.Flush(); err != nil {
+		return nil, 0, err
+	}
+	if rv < indexRV {
+		rv = indexRV // nothing changed
+	}
+
+	span.AddEvent("caught up", trace.WithAttributes(attribute.Int("events", count)))
+	s.log.Debug("opened persisted index", "namespace", nsr.Namespace, "group", nsr.Group, "resource", nsr.Resource, "rv", indexRV, "events", count)
+	if IndexMetrics != nil {
+		IndexMetrics.IndexCatchUpEvents.WithLabelValues(nsr.Resource).Add(float64(count))
+		if count > 0 {
+			IndexMetrics.IndexLag.WithLabelValues(nsr.Resource).Set(indexLagSeconds(rv))
+		}
+	}
+	return index, rv, nil
+}
+
 type builderCache struct {
 	// The default builder
 	defaultBuilder DocumentBuilder
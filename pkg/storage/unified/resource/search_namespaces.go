@@ -0,0 +1,456 @@
+package resource
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/grafana/authlib/authz"
+	"github.com/grafana/authlib/claims"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+// Use in ResourceSearchRequest.Namespaces to search all namespaces with indexed resources
+const SearchAllNamespaces = "*"
+
+// The most namespaces a single search may cover, each of them needs an index
+const maxSearchNamespaces = 100
+
+// The number of hits read at once while checking access in a namespace
+const namespaceSearchPageSize = 1000
+
+// searchNamespaces runs the search in every requested namespace and merges the results.
+// Scores are relative to the best match in each namespace, so they can be ranked together.
+// The facet counts include the matches the user can not see, access is only checked for the hits
+func (s *searchSupport) searchNamespaces(ctx context.Context, req *ResourceSearchRequest) (*ResourceSearchResponse, error) {
+	ctx, span := s.tracer.Start(ctx, tracingPrexfixSearch+"SearchNamespaces")
+	defer span.End()
+
+	user, ok := claims.From(ctx)
+	if !ok || user == nil {
+		return &ResourceSearchResponse{
+			Error: &ErrorResult{
+				Message: "no user found in context",
+				Code:    http.StatusUnauthorized,
+			}}, nil
+	}
+	if !isServerAdmin(ctx, user) {
+		return &ResourceSearchResponse{
+			Error: &ErrorResult{
+				Message: "searching multiple namespaces requires a server admin",
+				Code:    http.StatusForbidden,
+			}}, nil
+	}
+	if req.Options == nil || req.Options.Key == nil {
+		return &ResourceSearchResponse{
+			Error: NewBadRequestError("missing query key"),
+		}, nil
+	}
+	if len(req.Fields) == 0 {
+		return &ResourceSearchResponse{
+			Error: NewBadRequestError("fields are required when searching multiple namespaces"),
+		}, nil
+	}
+
+	namespaces, err := s.resolveNamespaces(ctx, req)
+	if err != nil {
+		return &ResourceSearchResponse{
+			Error: AsErrorResult(err),
+		}, nil
+	}
+
+	// Each namespace returns the values needed to check access and merge the results
+	fields := slices.Clone(req.Fields)
+	added := map[string]bool{}
+	needed := []string{SEARCH_FIELD_SCORE, SEARCH_FIELD_FOLDER}
+	for _, sort := range req.SortBy {
+		needed = append(needed, sort.Field)
+	}
+	for _, f := range needed {
+		if f != SEARCH_FIELD_ID && !slices.Contains(fields, f) && !added[f] {
+			fields = append(fields, f)
+			added[f] = true
+		}
+	}
+
+	responses := make([]*ResourceSearchResponse, len(namespaces))
+	group, gctx := errgroup.WithContext(ctx)
+	group.SetLimit(s.initWorkers)
+	for i, ns := range namespaces {
+		group.Go(func() error {
+			nsreq := &ResourceSearchRequest{
+				Options: &ListOptions{
+					Key: &ResourceKey{
+						Namespace: ns,
+						Group:     req.Options.Key.Group,
+						Resource:  req.Options.Key.Resource,
+					},
+					Labels: req.Options.Labels,
+					Fields: req.Options.Fields,
+				},
+				Federated: req.Federated,
+				Query:     req.Query,
+				Limit:     req.Offset + req.Limit, // the page is selected after merging
+				SortBy:    req.SortBy,
+				Facet:     req.Facet,
+				Fields:    fields,
+				Explain:   req.Explain,
+				Fuzziness: req.Fuzziness,
+				Prefix:    req.Prefix,
+				Boost:     req.Boost,
+				Highlight: req.Highlight,
+			}
+			rsp, err := s.searchNamespace(gctx, user, nsreq)
+			if err != nil {
+				return fmt.Errorf("search namespace %s: %w", ns, err)
+			}
+			responses[i] = rsp
+			return nil
+		})
+	}
+	if err = group.Wait(); err != nil {
+		return &ResourceSearchResponse{
+			Error: AsErrorResult(err),
+		}, nil
+	}
+	for _, rsp := range responses {
+		if rsp != nil && rsp.Error != nil {
+			return &ResourceSearchResponse{Error: rsp.Error}, nil
+		}
+	}
+
+	return mergeSearchResponses(req, responses, added)
+}
+
+// isServerAdmin checks if the user may work across namespaces.
+// Access within each namespace is still checked by the access client when searching
+func isServerAdmin(ctx context.Context, user claims.AuthInfo) bool {
+	requester, ok := user.(identity.Requester)
+	if !ok {
+		var err error
+		requester, err = identity.GetRequester(ctx)
+		if err != nil {
+			return false
+		}
+	}
+	return requester.GetIsGrafanaAdmin()
+}
+
+func (s *searchSupport) resolveNamespaces(ctx context.Context, req *ResourceSearchRequest) ([]string, error) {
+	found := map[string]bool{}
+	for _, ns := range req.Namespaces {
+		if ns != SearchAllNamespaces {
+			found[ns] = true
+			continue
+		}
+		stats, err := s.storage.GetResourceStats(ctx, "", 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, stat := range stats {
+			if stat.Group == req.Options.Key.Group && stat.Resource == req.Options.Key.Resource {
+				found[stat.Namespace] = true
+			}
+		}
+	}
+	delete(found, "")
+
+	namespaces := make([]string, 0, len(found))
+	for ns := range found {
+		namespaces = append(namespaces, ns)
+	}
+	if len(namespaces) > maxSearchNamespaces {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("searching more than %d namespaces is not supported", maxSearchNamespaces))
+	}
+	slices.Sort(namespaces)
+	return namespaces, nil
+}
+
+// searchNamespace searches a single namespace and removes the results the user can not see.
+// The hits are read in pages until all of them are checked, so the first req.Limit visible
+// results are returned and the total only counts the visible hits.
+// This will return nil when the user can not see anything in the namespace
+func (s *searchSupport) searchNamespace(ctx context.Context, user claims.AuthInfo, req *ResourceSearchRequest) (*ResourceSearchResponse, error) {
+	checkers := map[string]authz.ItemChecker{}
+	keys := append([]*ResourceKey{req.Options.Key}, req.Federated...)
+	for _, key := range keys {
+		checker, err := s.access.Compile(ctx, user, authz.ListRequest{
+			Group:     key.Group,
+			Resource:  key.Resource,
+			Namespace: req.Options.Key.Namespace,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if checker != nil {
+			checkers[key.Group+"/"+key.Resource] = checker
+		}
+	}
+	if checkers[req.Options.Key.Group+"/"+req.Options.Key.Resource] == nil {
+		return nil, nil
+	}
+
+	want := int(req.Limit)
+	req.Offset = 0
+	req.Limit = int64(max(want, namespaceSearchPageSize))
+
+	var result *ResourceSearchResponse
+	visible := int64(0)
+	for {
+		rsp, err := s.Search(ctx, req)
+		if err != nil || rsp.Error != nil || rsp.Results == nil {
+			return rsp, err
+		}
+		page := rsp.Results
+		if result == nil {
+			result = rsp
+			result.Results = &ResourceTable{Columns: page.Columns}
+		} else {
+			result.QueryCost += rsp.QueryCost
+		}
+
+		folder := slices.IndexFunc(page.Columns, func(c *ResourceTableColumnDefinition) bool {
+			return c.Name == SEARCH_FIELD_FOLDER
+		})
+		for _, row := range page.Rows {
+			checker := checkers[row.Key.Group+"/"+row.Key.Resource]
+			if checker == nil {
+				continue
+			}
+			f := ""
+			if folder >= 0 {
+				f = string(row.Cells[folder])
+			}
+			if !checker(row.Key.Namespace, row.Key.Name, f) {
+				continue
+			}
+			visible++
+			if want <= 0 || len(result.Results.Rows) < want {
+				result.Results.Rows = append(result.Results.Rows, row)
+			}
+		}
+
+		if int64(len(page.Rows)) < req.Limit || req.Offset+req.Limit >= rsp.TotalHits {
+			break
+		}
+		req.Offset += req.Limit
+	}
+	result.TotalHits = visible
+	return result, nil
+}
+
+type mergedSearchRow struct {
+	row    *ResourceTableRow
+	score  float64 // relative to the namespace max score
+	values []any   // decoded sort values
+}
+
+func mergeSearchResponses(req *ResourceSearchRequest, responses []*ResourceSearchResponse, added map[string]bool) (*ResourceSearchResponse, error) {
+	merged := &ResourceSearchResponse{}
+	rows := []*mergedSearchRow{}
+	var columns []*ResourceTableColumnDefinition
+	for _, rsp := range responses {
+		if rsp == nil || rsp.Results == nil {
+			continue
+		}
+		merged.TotalHits += rsp.TotalHits
+		merged.QueryCost += rsp.QueryCost
+		mergeSearchFacets(req, merged, rsp.Facet)
+
+		if columns == nil {
+			columns = rsp.Results.Columns
+		}
+		decoders, err := newSearchColumns(rsp.Results.Columns)
+		if err != nil {
+			return nil, err
+		}
+		score := slices.IndexFunc(rsp.Results.Columns, func(c *ResourceTableColumnDefinition) bool {
+			return c.Name == SEARCH_FIELD_SCORE
+		})
+		for _, row := range rsp.Results.Rows {
+			r := &mergedSearchRow{row: row}
+			if score >= 0 && rsp.MaxScore > 0 {
+				v, _ := decoders[score].Decode(row.Cells[score])
+				if f, ok := v.(float64); ok {
+					r.score = f / rsp.MaxScore
+				}
+			}
+			for _, sort := range req.SortBy {
+				idx := slices.IndexFunc(rsp.Results.Columns, func(c *ResourceTableColumnDefinition) bool {
+					return c.Name == sort.Field
+				})
+				var v any
+				switch {
+				case sort.Field == SEARCH_FIELD_SCORE:
+					v = r.score
+				case sort.Field == SEARCH_FIELD_ID:
+					v = row.Key.SearchID()
+				case idx >= 0:
+					v, _ = decoders[idx].Decode(row.Cells[idx])
+				}
+				r.values = append(r.values, v)
+			}
+			rows = append(rows, r)
+		}
+	}
+	if req.Query != "" && len(rows) > 0 {
+		merged.MaxScore = 1
+	}
+
+	slices.SortStableFunc(rows, func(a, b *mergedSearchRow) int {
+		for i, sort := range req.SortBy {
+			c := compareSearchValues(a.values[i], b.values[i])
+			if sort.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		if len(req.SortBy) == 0 && req.Query != "" {
+			if c := cmp.Compare(b.score, a.score); c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(a.row.Key.SearchID(), b.row.Key.SearchID())
+	})
+
+	// Select the page
+	offset := min(int(req.Offset), len(rows))
+	end := len(rows)
+	if req.Limit > 0 {
+		end = min(offset+int(req.Limit), end)
+	}
+	rows = rows[offset:end]
+
+	// Remove the values that were only added for merging
+	keep := []int{}
+	merged.Results = &ResourceTable{}
+	for i, c := range columns {
+		if !added[c.Name] {
+			keep = append(keep, i)
+			merged.Results.Columns = append(merged.Results.Columns, c)
+		}
+	}
+	score := slices.IndexFunc(merged.Results.Columns, func(c *ResourceTableColumnDefinition) bool {
+		return c.Name == SEARCH_FIELD_SCORE
+	})
+	var scoreColumn *resourceTableColumn
+	if score >= 0 {
+		var err error
+		scoreColumn, err = newResourceTableColumn(merged.Results.Columns[score], score)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range rows {
+		row := &ResourceTableRow{
+			Key:             r.row.Key,
+			ResourceVersion: r.row.ResourceVersion,
+			Object:          r.row.Object,
+			Cells:           make([][]byte, len(keep)),
+		}
+		for i, idx := range keep {
+			row.Cells[i] = r.row.Cells[idx]
+		}
+		if scoreColumn != nil {
+			// the score used for ranking
+			cell, err := scoreColumn.Encode(r.score)
+			if err != nil {
+				return nil, err
+			}
+			row.Cells[score] = cell
+		}
+		merged.Results.Rows = append(merged.Results.Rows, row)
+	}
+	return merged, nil
+}
+
+func newSearchColumns(defs []*ResourceTableColumnDefinition) ([]*resourceTableColumn, error) {
+	columns := make([]*resourceTableColumn, len(defs))
+	for i, def := range defs {
+		c, err := newResourceTableColumn(def, i)
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = c
+	}
+	return columns, nil
+}
+
+// mergeSearchFacets adds the term counts of a namespace
+func mergeSearchFacets(req *ResourceSearchRequest, merged *ResourceSearchResponse, facets map[string]*ResourceSearchResponse_Facet) {
+	for k, f := range facets {
+		if merged.Facet == nil {
+			merged.Facet = make(map[string]*ResourceSearchResponse_Facet)
+		}
+		m, ok := merged.Facet[k]
+		if !ok {
+			m = &ResourceSearchResponse_Facet{Field: f.Field}
+			merged.Facet[k] = m
+		}
+		m.Total += f.Total
+		m.Missing += f.Missing
+		for _, t := range f.Terms {
+			idx := slices.IndexFunc(m.Terms, func(v *ResourceSearchResponse_TermFacet) bool {
+				return v.Term == t.Term
+			})
+			if idx < 0 {
+				m.Terms = append(m.Terms, &ResourceSearchResponse_TermFacet{Term: t.Term, Count: t.Count})
+			} else {
+				m.Terms[idx].Count += t.Count
+			}
+		}
+		slices.SortStableFunc(m.Terms, func(a, b *ResourceSearchResponse_TermFacet) int {
+			if c := cmp.Compare(b.Count, a.Count); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Term, b.Term)
+		})
+		if r, ok := req.Facet[k]; ok && r.Limit > 0 && len(m.Terms) > int(r.Limit) {
+			m.Terms = m.Terms[:r.Limit]
+		}
+	}
+}
+
+// compareSearchValues orders decoded cell values, missing values are last
+func compareSearchValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(strings.ToLower(av), strings.ToLower(bv))
+		}
+	case float64:
+		if bv, ok := b.(float64); ok {
+			return cmp.Compare(av, bv)
+		}
+	case int64:
+		if bv, ok := b.(int64); ok {
+			return cmp.Compare(av, bv)
+		}
+	case int32:
+		if bv, ok := b.(int32); ok {
+			return cmp.Compare(av, bv)
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			return av.Compare(bv)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
@@ -0,0 +1,284 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/grafana/authlib/authz"
+	"github.com/grafana/authlib/claims"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+type testSearchHit struct {
+	name   string
+	title  string
+	folder string
+	score  float64
+}
+
+// testSearchIndex returns the same hits for every query, paged by the offset and limit
+type testSearchIndex struct {
+	ResourceIndex
+	namespace string
+	hits      []testSearchHit
+}
+
+func (x *testSearchIndex) Search(ctx context.Context, access authz.AccessClient, req *ResourceSearchRequest, federate []ResourceIndex) (*ResourceSearchResponse, error) {
+	columns := []*ResourceTableColumnDefinition{}
+	for _, name := range req.Fields {
+		columns = append(columns, StandardSearchFields().Field(name))
+	}
+	encoders, err := newSearchColumns(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &ResourceSearchResponse{
+		Results:   &ResourceTable{Columns: columns},
+		TotalHits: int64(len(x.hits)),
+	}
+	for _, hit := range x.hits {
+		rsp.MaxScore = max(rsp.MaxScore, hit.score)
+	}
+	hits := x.hits[min(int(req.Offset), len(x.hits)):]
+	if req.Limit > 0 {
+		hits = hits[:min(int(req.Limit), len(hits))]
+	}
+	for _, hit := range hits {
+		row := &ResourceTableRow{
+			Key: &ResourceKey{
+				Namespace: x.namespace,
+				Group:     req.Options.Key.Group,
+				Resource:  req.Options.Key.Resource,
+				Name:      hit.name,
+			},
+		}
+		for i, c := range columns {
+			var v any
+			switch c.Name {
+			case SEARCH_FIELD_TITLE:
+				v = hit.title
+			case SEARCH_FIELD_FOLDER:
+				v = hit.folder
+			case SEARCH_FIELD_SCORE:
+				v = hit.score
+			}
+			cell, err := encoders[i].Encode(v)
+			if err != nil {
+				return nil, err
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		rsp.Results.Rows = append(rsp.Results.Rows, row)
+	}
+	return rsp, nil
+}
+
+// testStatsBackend returns the stats used to find all namespaces
+type testStatsBackend struct {
+	StorageBackend
+	stats []ResourceStats
+}
+
+func (x *testStatsBackend) GetResourceStats(ctx context.Context, namespace string, minCount int) ([]ResourceStats, error) {
+	return x.stats, nil
+}
+
+type testSearchBackend struct {
+	SearchBackend
+	indexes map[string]*testSearchIndex
+}
+
+func (x *testSearchBackend) GetIndex(ctx context.Context, key NamespacedResource) (ResourceIndex, error) {
+	return x.indexes[key.Namespace], nil
+}
+
+// testSearchAccess hides the namespace "blocked" and the folder "secret"
+type testSearchAccess struct {
+	staticAuthzClient
+}
+
+func (x *testSearchAccess) Compile(ctx context.Context, id claims.AuthInfo, req authz.ListRequest) (authz.ItemChecker, error) {
+	if req.Namespace == "blocked" {
+		return nil, nil
+	}
+	return func(namespace string, name, folder string) bool {
+		return folder != "secret"
+	}, nil
+}
+
+func TestSearchNamespaces(t *testing.T) {
+	support := &searchSupport{
+		tracer: noop.NewTracerProvider().Tracer("test"),
+		log:    slog.Default(),
+		search: &testSearchBackend{
+			indexes: map[string]*testSearchIndex{
+				"org-1": {namespace: "org-1", hits: []testSearchHit{
+					{name: "a", title: "CPU", folder: "f1", score: 4},
+					{name: "b", title: "Memory", folder: "secret", score: 3},
+					{name: "c", title: "Network", folder: "f1", score: 1},
+				}},
+				"org-2": {namespace: "org-2", hits: []testSearchHit{
+					{name: "d", title: "Disk", folder: "f2", score: 0.5},
+					{name: "e", title: "Alerts", folder: "f2", score: 0.4},
+				}},
+				"blocked": {namespace: "blocked", hits: []testSearchHit{
+					{name: "x", title: "Hidden", score: 10},
+				}},
+			},
+		},
+		access:      &testSearchAccess{},
+		initWorkers: 2,
+	}
+
+	admin := &identity.StaticRequester{
+		Type:           claims.TypeUser,
+		UserUID:        "admin",
+		IsGrafanaAdmin: true,
+	}
+	search := func(t *testing.T, user *identity.StaticRequester, req *ResourceSearchRequest) *ResourceSearchResponse {
+		t.Helper()
+		req.Options = &ListOptions{Key: &ResourceKey{Group: "dashboard.grafana.app", Resource: "dashboards"}}
+		req.Namespaces = []string{"org-1", "org-2", "blocked"}
+		if req.Fields == nil {
+			req.Fields = []string{SEARCH_FIELD_TITLE}
+		}
+		rsp, err := support.Search(claims.WithClaims(context.Background(), user), req)
+		require.NoError(t, err)
+		return rsp
+	}
+	titles := func(rsp *ResourceSearchResponse) []string {
+		titles := []string{}
+		for _, row := range rsp.Results.Rows {
+			titles = append(titles, string(row.Cells[0]))
+		}
+		return titles
+	}
+
+	t.Run("requires a server admin", func(t *testing.T) {
+		rsp := search(t, &identity.StaticRequester{Type: claims.TypeUser, UserUID: "viewer"}, &ResourceSearchRequest{Limit: 10})
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(403), rsp.Error.Code)
+	})
+
+	t.Run("ranks by the relative score", func(t *testing.T) {
+		rsp := search(t, admin, &ResourceSearchRequest{Query: "x", Limit: 10})
+		require.Nil(t, rsp.Error)
+		require.Equal(t, []string{"CPU", "Disk", "Alerts", "Network"}, titles(rsp))
+		require.Equal(t, int64(4), rsp.TotalHits)
+		require.Len(t, rsp.Results.Columns, 1) // the values used for merging are removed
+		require.Equal(t, "org-2", rsp.Results.Rows[1].Key.Namespace)
+	})
+
+	t.Run("counts the visible hits", func(t *testing.T) {
+		rsp := search(t, admin, &ResourceSearchRequest{Limit: 1})
+		require.Nil(t, rsp.Error)
+		require.Len(t, rsp.Results.Rows, 1)
+		require.Equal(t, int64(4), rsp.TotalHits)
+	})
+
+	t.Run("sorts and pages the merged results", func(t *testing.T) {
+		rsp := search(t, admin, &ResourceSearchRequest{
+			Limit:  2,
+			Offset: 1,
+			SortBy: []*ResourceSearchRequest_Sort{{Field: SEARCH_FIELD_TITLE}},
+		})
+		require.Nil(t, rsp.Error)
+		require.Equal(t, []string{"CPU", "Disk"}, titles(rsp))
+	})
+
+	t.Run("returns the merged score", func(t *testing.T) {
+		rsp := search(t, admin, &ResourceSearchRequest{
+			Query:  "x",
+			Limit:  1,
+			Fields: []string{SEARCH_FIELD_TITLE, SEARCH_FIELD_SCORE},
+		})
+		require.Nil(t, rsp.Error)
+		require.Len(t, rsp.Results.Columns, 2)
+		score, err := newResourceTableColumn(rsp.Results.Columns[1], 1)
+		require.NoError(t, err)
+		v, err := score.Decode(rsp.Results.Rows[0].Cells[1])
+		require.NoError(t, err)
+		require.Equal(t, 1.0, v)
+	})
+}
+
+func TestSearchNamespacesChecksAccessBeforePaging(t *testing.T) {
+	// the visible hits come after more than a page of hidden hits
+	hits := []testSearchHit{}
+	for i := range namespaceSearchPageSize + 10 {
+		hits = append(hits, testSearchHit{name: fmt.Sprintf("hidden-%d", i), title: "Hidden", folder: "secret"})
+	}
+	hits = append(hits,
+		testSearchHit{name: "a", title: "CPU", folder: "f1"},
+		testSearchHit{name: "b", title: "Memory", folder: "f1"},
+		testSearchHit{name: "c", title: "Network", folder: "f1"},
+	)
+	support := &searchSupport{
+		tracer: noop.NewTracerProvider().Tracer("test"),
+		log:    slog.Default(),
+		search: &testSearchBackend{
+			indexes: map[string]*testSearchIndex{
+				"org-1": {namespace: "org-1", hits: hits},
+			},
+		},
+		access:      &testSearchAccess{},
+		initWorkers: 2,
+	}
+	ctx := claims.WithClaims(context.Background(), &identity.StaticRequester{
+		Type:           claims.TypeUser,
+		UserUID:        "admin",
+		IsGrafanaAdmin: true,
+	})
+
+	rsp, err := support.Search(ctx, &ResourceSearchRequest{
+		Options:    &ListOptions{Key: &ResourceKey{Group: "dashboard.grafana.app", Resource: "dashboards"}},
+		Namespaces: []string{"org-1"},
+		Fields:     []string{SEARCH_FIELD_TITLE},
+		Limit:      2,
+	})
+	require.NoError(t, err)
+	require.Nil(t, rsp.Error)
+	require.Len(t, rsp.Results.Rows, 2)
+	require.Equal(t, "CPU", string(rsp.Results.Rows[0].Cells[0]))
+	require.Equal(t, "Memory", string(rsp.Results.Rows[1].Cells[0]))
+	require.Equal(t, int64(3), rsp.TotalHits)
+}
+
+func TestSearchAllNamespacesLimit(t *testing.T) {
+	stats := []ResourceStats{}
+	for i := range maxSearchNamespaces + 1 {
+		stats = append(stats, ResourceStats{NamespacedResource: NamespacedResource{
+			Namespace: fmt.Sprintf("org-%d", i),
+			Group:     "dashboard.grafana.app",
+			Resource:  "dashboards",
+		}})
+	}
+	support := &searchSupport{
+		tracer:  noop.NewTracerProvider().Tracer("test"),
+		log:     slog.Default(),
+		storage: &testStatsBackend{stats: stats},
+		search:  &testSearchBackend{},
+		access:  &testSearchAccess{},
+	}
+	ctx := claims.WithClaims(context.Background(), &identity.StaticRequester{
+		Type:           claims.TypeUser,
+		UserUID:        "admin",
+		IsGrafanaAdmin: true,
+	})
+
+	rsp, err := support.Search(ctx, &ResourceSearchRequest{
+		Options:    &ListOptions{Key: &ResourceKey{Group: "dashboard.grafana.app", Resource: "dashboards"}},
+		Namespaces: []string{SearchAllNamespaces},
+		Fields:     []string{SEARCH_FIELD_TITLE},
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, rsp.Error)
+	require.Equal(t, int32(400), rsp.Error.Code)
+}
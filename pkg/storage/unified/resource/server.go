@@ -99,6 +99,22 @@ type StorageBackend interface {
 	GetResourceStats(ctx context.Context, namespace string, minCount int) ([]ResourceStats, error)
 }
 
+// ResourceEventLister is implemented by storage backends that can replay the events written
+// after a resource version.  It is used to catch up search indexes that were persisted
+type ResourceEventLister interface {
+	// Call cb for each event after since (in resource version order) and return the last resource version.
+	// The namespace of the key is optional.  ErrHistoryCompacted is returned when some events
+	// after since are no longer in the history
+	ListEventsSince(ctx context.Context, key NamespacedResource, since int64, cb func(*WrittenEvent) error) (int64, error)
+}
+
+// BulkStorageBackend is implemented by storage backends that can write a batch of events
+// in a single transaction.  Either all events are written or none of them are.
+type BulkStorageBackend interface {
+	// Write the events in order and return the resource version of each event
+	WriteEvents(ctx context.Context, events []WriteEvent) ([]int64, error)
+}
+
 type ResourceStats struct {
 	NamespacedResource
 
@@ -565,13 +581,59 @@ func (s *server) Delete(ctx context.Context, req *DeleteRequest) (*DeleteRespons
 		return rsp, nil
 	}
 
+	event, e := s.newDeleteEvent(ctx, user, req.Key, latest)
+	if e != nil {
+		rsp.Error = e
+		return rsp, nil
+	}
+
+	var err error
+	rsp.ResourceVersion, err = s.backend.WriteEvent(ctx, *event)
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+	}
+	return rsp, nil
+}
+
+func (s *server) Restore(ctx context.Context, req *RestoreRequest) (*RestoreResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "storage_server.Restore")
+	defer span.End()
+
+	rsp := &RestoreResponse{}
+	user, ok := claims.From(ctx)
+	if !ok || user == nil {
+		rsp.Error = &ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+		return rsp, nil
+	}
+	if e := verifyObjectKey(req.Key); e != nil {
+		rsp.Error = e
+		return rsp, nil
+	}
+	if req.ResourceVersion < 1 {
+		rsp.Error = NewBadRequestError("restore must include the resource version to restore")
+		return rsp, nil
+	}
+
+	previous := s.backend.ReadResource(ctx, &ReadRequest{
+		Key:             req.Key,
+		ResourceVersion: req.ResourceVersion,
+	})
+	if previous.Error != nil {
+		rsp.Error = previous.Error
+		return rsp, nil
+	}
+
+	// The restored value must be readable by the user
 	access, err := s.access.Check(ctx, user, authz.CheckRequest{
-		Verb:      "delete",
+		Verb:      "get",
 		Group:     req.Key.Group,
 		Resource:  req.Key.Resource,
 		Namespace: req.Key.Namespace,
 		Name:      req.Key.Name,
-		Folder:    latest.Folder,
+		Folder:    previous.Folder,
 	})
 	if err != nil {
 		rsp.Error = AsErrorResult(err)
@@ -584,31 +646,96 @@ func (s *server) Delete(ctx context.Context, req *DeleteRequest) (*DeleteRespons
 		return rsp, nil
 	}
 
+	tmp := &unstructured.Unstructured{}
+	err = tmp.UnmarshalJSON(previous.Value)
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+		return rsp, nil
+	}
+	obj, err := utils.MetaAccessor(tmp)
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+		return rsp, nil
+	}
+	now := time.UnixMilli(s.now())
+	obj.SetResourceVersion("")
+	obj.SetDeletionTimestamp(nil)
+	obj.SetUpdatedTimestamp(&now)
+	obj.SetUpdatedBy(user.GetUID())
+	value, err := json.Marshal(tmp)
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+		return rsp, nil
+	}
+
+	// A deleted object is created again
+	var oldValue []byte
+	latest := s.backend.ReadResource(ctx, &ReadRequest{Key: req.Key})
+	if latest.Error != nil && latest.Error.Code != http.StatusNotFound {
+		rsp.Error = latest.Error
+		return rsp, nil
+	}
+	if latest.Error == nil {
+		oldValue = latest.Value
+	}
+
+	event, e := s.newEvent(ctx, user, req.Key, value, oldValue)
+	if e != nil {
+		rsp.Error = e
+		return rsp, nil
+	}
+	if oldValue != nil {
+		event.PreviousRV = latest.ResourceVersion
+	}
+
+	rsp.ResourceVersion, err = s.backend.WriteEvent(ctx, *event)
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+	}
+	s.log.Debug("server.Restore", "type", event.Type, "rv", rsp.ResourceVersion, "restored", req.ResourceVersion, "group", req.Key.Group, "namespace", req.Key.Namespace, "name", req.Key.Name, "resource", req.Key.Resource)
+	return rsp, nil
+}
+
+// newDeleteEvent checks access and creates the deletion marker for the latest value
+func (s *server) newDeleteEvent(ctx context.Context, user claims.AuthInfo, key *ResourceKey, latest *BackendReadResponse) (*WriteEvent, *ErrorResult) {
+	access, err := s.access.Check(ctx, user, authz.CheckRequest{
+		Verb:      "delete",
+		Group:     key.Group,
+		Resource:  key.Resource,
+		Namespace: key.Namespace,
+		Name:      key.Name,
+		Folder:    latest.Folder,
+	})
+	if err != nil {
+		return nil, AsErrorResult(err)
+	}
+	if !access.Allowed {
+		return nil, &ErrorResult{
+			Code: http.StatusForbidden,
+		}
+	}
+
 	now := metav1.NewTime(time.UnixMilli(s.now()))
-	event := WriteEvent{
-		Key:        req.Key,
+	event := &WriteEvent{
+		Key:        key,
 		Type:       WatchEvent_DELETED,
 		PreviousRV: latest.ResourceVersion,
 	}
-	requester, ok := claims.From(ctx)
-	if !ok {
-		return nil, apierrors.NewBadRequest("unable to get user")
-	}
 	marker := &DeletedMarker{}
 	err = json.Unmarshal(latest.Value, marker)
 	if err != nil {
-		return nil, apierrors.NewBadRequest(
+		return nil, NewBadRequestError(
 			fmt.Sprintf("unable to read previous object, %v", err))
 	}
 	obj, err := utils.MetaAccessor(marker)
 	if err != nil {
-		return nil, err
+		return nil, AsErrorResult(err)
 	}
 	obj.SetDeletionTimestamp(&now)
 	obj.SetUpdatedTimestamp(&now.Time)
 	obj.SetManagedFields(nil)
 	obj.SetFinalizers(nil)
-	obj.SetUpdatedBy(requester.GetUID())
+	obj.SetUpdatedBy(user.GetUID())
 	marker.TypeMeta = metav1.TypeMeta{
 		Kind:       "DeletedMarker",
 		APIVersion: "common.grafana.app/v0alpha1", // ?? or can we stick this in common?
@@ -616,15 +743,10 @@ func (s *server) Delete(ctx context.Context, req *DeleteRequest) (*DeleteRespons
 	marker.Annotations["RestoreResourceVersion"] = fmt.Sprintf("%d", event.PreviousRV)
 	event.Value, err = json.Marshal(marker)
 	if err != nil {
-		return nil, apierrors.NewBadRequest(
+		return nil, NewBadRequestError(
 			fmt.Sprintf("unable creating deletion marker, %v", err))
 	}
-
-	rsp.ResourceVersion, err = s.backend.WriteEvent(ctx, event)
-	if err != nil {
-		rsp.Error = AsErrorResult(err)
-	}
-	return rsp, nil
+	return event, nil
 }
 
 func (s *server) Read(ctx context.Context, req *ReadRequest) (*ReadResponse, error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"testing"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"gocloud.dev/blob/memblob"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/grafana/authlib/authz"
 	"github.com/grafana/authlib/claims"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
@@ -220,4 +222,104 @@ func TestSimpleServer(t *testing.T) {
 			ResourceVersion: created.ResourceVersion})
 		require.ErrorIs(t, err, ErrOptimisticLockingFailed)
 	})
+
+	t.Run("read and restore from history", func(t *testing.T) {
+		key := &ResourceKey{
+			Group:     "playlist.grafana.app",
+			Resource:  "playlists",
+			Namespace: "default",
+			Name:      "restored",
+		}
+		value := func(title string) []byte {
+			return []byte(fmt.Sprintf(`{
+				"apiVersion": "playlist.grafana.app/v0alpha1",
+				"kind": "Playlist",
+				"metadata": {"name": "restored", "uid": "abc", "namespace": "default"},
+				"spec": {"title": %q}
+			}`, title))
+		}
+		title := func(raw []byte) string {
+			tmp := &unstructured.Unstructured{}
+			require.NoError(t, json.Unmarshal(raw, tmp))
+			v, _, _ := unstructured.NestedString(tmp.Object, "spec", "title")
+			return v
+		}
+
+		created, err := server.Create(ctx, &CreateRequest{Key: key, Value: value("first")})
+		require.NoError(t, err)
+		require.Nil(t, created.Error)
+		updated, err := server.Update(ctx, &UpdateRequest{Key: key, Value: value("second"), ResourceVersion: created.ResourceVersion})
+		require.NoError(t, err)
+		require.Nil(t, updated.Error)
+		deleted, err := server.Delete(ctx, &DeleteRequest{Key: key, ResourceVersion: updated.ResourceVersion})
+		require.NoError(t, err)
+		require.Nil(t, deleted.Error)
+
+		// Read the value as of a resource version
+		found, err := server.Read(ctx, &ReadRequest{Key: key, ResourceVersion: created.ResourceVersion})
+		require.NoError(t, err)
+		require.Nil(t, found.Error)
+		require.Equal(t, created.ResourceVersion, found.ResourceVersion)
+		require.Equal(t, "first", title(found.Value))
+
+		found, err = server.Read(ctx, &ReadRequest{Key: key, ResourceVersion: deleted.ResourceVersion})
+		require.NoError(t, err)
+		require.Equal(t, int32(http.StatusNotFound), found.Error.Code)
+
+		// Deleted versions can not be restored
+		restored, err := server.Restore(ctx, &RestoreRequest{Key: key, ResourceVersion: deleted.ResourceVersion})
+		require.NoError(t, err)
+		require.Equal(t, int32(http.StatusNotFound), restored.Error.Code)
+
+		// Restore the deleted object
+		restored, err = server.Restore(ctx, &RestoreRequest{Key: key, ResourceVersion: updated.ResourceVersion})
+		require.NoError(t, err)
+		require.Nil(t, restored.Error)
+		require.Greater(t, restored.ResourceVersion, deleted.ResourceVersion)
+
+		found, err = server.Read(ctx, &ReadRequest{Key: key})
+		require.NoError(t, err)
+		require.Nil(t, found.Error)
+		require.Equal(t, restored.ResourceVersion, found.ResourceVersion)
+		require.Equal(t, "second", title(found.Value))
+
+		// Restore overwrites the current value
+		restored, err = server.Restore(ctx, &RestoreRequest{Key: key, ResourceVersion: created.ResourceVersion})
+		require.NoError(t, err)
+		require.Nil(t, restored.Error)
+
+		found, err = server.Read(ctx, &ReadRequest{Key: key})
+		require.NoError(t, err)
+		require.Equal(t, "first", title(found.Value))
+
+		// Versions before the first one fall back to the latest value
+		latest, err := server.Read(ctx, &ReadRequest{Key: key, ResourceVersion: created.ResourceVersion - 1})
+		require.NoError(t, err)
+		require.Nil(t, latest.Error)
+		require.Equal(t, restored.ResourceVersion, latest.ResourceVersion)
+
+		// The restored version must be readable
+		denied, err := NewResourceServer(ResourceServerOptions{
+			Backend:      store,
+			AccessClient: &testVerbAccess{denied: "get"},
+		})
+		require.NoError(t, err)
+		restored, err = denied.Restore(ctx, &RestoreRequest{Key: key, ResourceVersion: updated.ResourceVersion})
+		require.NoError(t, err)
+		require.Equal(t, int32(http.StatusForbidden), restored.Error.Code)
+
+		found, err = server.Read(ctx, &ReadRequest{Key: key})
+		require.NoError(t, err)
+		require.Equal(t, latest.ResourceVersion, found.ResourceVersion)
+	})
+}
+
+// testVerbAccess denies a single verb
+type testVerbAccess struct {
+	staticAuthzClient
+	denied string
+}
+
+func (x *testVerbAccess) Check(ctx context.Context, id claims.AuthInfo, req authz.CheckRequest) (authz.CheckResponse, error) {
+	return authz.CheckResponse{Allowed: req.Verb != x.denied}, nil
 }
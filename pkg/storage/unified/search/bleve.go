@@ -2,14 +2,22 @@ package search
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
 	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
 	"github.com/blevesearch/bleve/v2/search/query"
 	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/selection"
@@ -21,8 +29,19 @@ import (
 const tracingPrexfixBleve = "unified_search.bleve."
 
 var _ resource.SearchBackend = &bleveBackend{}
+var _ resource.PersistentSearchBackend = &bleveBackend{}
 var _ resource.ResourceIndex = &bleveIndex{}
 
+// indexSchemaVersion must change when the documents change in a way that is not covered by
+// the fields and the mappings, persisted indexes with another version are rebuilt
+const indexSchemaVersion = "1"
+
+// Values saved with file based indexes
+var (
+	internalSchemaKey = []byte("schema")
+	internalRVKey     = []byte("rv")
+)
+
 type BleveOptions struct {
 	// The root folder where file objects are saved
 	Root string
@@ -93,12 +112,24 @@ func (b *bleveBackend) BuildIndex(ctx context.Context,
 
 	mapper := getBleveMappings(fields)
 
-	if size > b.opts.FileThreshold {
-		dir := filepath.Join(b.opts.Root, key.Namespace, fmt.Sprintf("%s.%s", key.Resource, key.Group))
-		index, err = bleve.New(dir, mapper)
+	schema, err := getIndexSchema(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// A previously opened index is replaced
+	b.closeIndex(key)
 
-		// TODO, check last RV so we can see if the numbers have changed
+	file := size > b.opts.FileThreshold
+	if file {
+		dir := b.indexDir(key)
 
+		// Anything left is outdated or was not completely built
+		err = os.RemoveAll(dir)
+		if err != nil {
+			return nil, err
+		}
+		index, err = bleve.New(dir, mapper)
 		resource.IndexMetrics.IndexTenants.WithLabelValues(key.Namespace, "file").Inc()
 	} else {
 		index, err = bleve.NewMemOnly(mapper)
@@ -109,24 +140,20 @@ func (b *bleveBackend) BuildIndex(ctx context.Context,
 	}
 
 	// Batch all the changes
-	idx := &bleveIndex{
-		key:       key,
-		index:     index,
-		batch:     index.NewBatch(),
-		batchSize: b.opts.BatchSize,
-		fields:    fields,
-		standard:  resource.StandardSearchFields(),
-	}
-
-	idx.allFields, err = getAllFields(idx.standard, fields)
+	idx, err := newBleveIndex(key, index, fields)
 	if err != nil {
 		return nil, err
 	}
+	idx.batch = index.NewBatch()
+	idx.batchSize = b.opts.BatchSize
 
-	_, err = builder(idx)
+	rv, err := builder(idx)
 	if err != nil {
 		return nil, err
 	}
+	if rv > idx.rv {
+		idx.rv = rv
+	}
 
 	// Flush the batch
 	err = idx.Flush()
@@ -134,12 +161,110 @@ func (b *bleveBackend) BuildIndex(ctx context.Context,
 		return nil, err
 	}
 
+	// The schema is saved last, so partially built indexes are not opened
+	if file {
+		err = index.SetInternal(internalSchemaKey, []byte(schema))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	b.cacheMu.Lock()
 	b.cache[key] = idx
 	b.cacheMu.Unlock()
 	return idx, nil
 }
 
+// OpenIndex opens an index that was saved to disk by BuildIndex.
+// This will return nil when the index does not exist or was built with a different schema
+func (b *bleveBackend) OpenIndex(ctx context.Context, key resource.NamespacedResource, fields resource.SearchableDocumentFields) (resource.ResourceIndex, int64, error) {
+	_, span := b.tracer.Start(ctx, tracingPrexfixBleve+"OpenIndex")
+	defer span.End()
+
+	dir := b.indexDir(key)
+	if _, err := os.Stat(dir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+
+	schema, err := getIndexSchema(fields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	b.closeIndex(key)
+	index, err := bleve.Open(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	saved, err := index.GetInternal(internalSchemaKey)
+	if err != nil || string(saved) != schema {
+		b.log.Info("persisted index schema changed", "namespace", key.Namespace, "group", key.Group, "resource", key.Resource)
+		return nil, 0, errors.Join(err, index.Close())
+	}
+
+	idx, err := newBleveIndex(key, index, fields)
+	if err != nil {
+		return nil, 0, errors.Join(err, index.Close())
+	}
+	v, err := index.GetInternal(internalRVKey)
+	if err == nil && len(v) > 0 {
+		idx.rv, err = strconv.ParseInt(string(v), 10, 64)
+	}
+	if err != nil {
+		return nil, 0, errors.Join(err, index.Close())
+	}
+	resource.IndexMetrics.IndexTenants.WithLabelValues(key.Namespace, "file").Inc()
+
+	b.cacheMu.Lock()
+	b.cache[key] = idx
+	b.cacheMu.Unlock()
+	return idx, idx.rv, nil
+}
+
+func (b *bleveBackend) indexDir(key resource.NamespacedResource) string {
+	return filepath.Join(b.opts.Root, key.Namespace, fmt.Sprintf("%s.%s", key.Resource, key.Group))
+}
+
+// closeIndex closes and forgets the cached index, so the files can be opened again
+func (b *bleveBackend) closeIndex(key resource.NamespacedResource) {
+	b.cacheMu.Lock()
+	defer b.cacheMu.Unlock()
+
+	idx, ok := b.cache[key]
+	if !ok {
+		return
+	}
+	delete(b.cache, key)
+	if err := idx.index.Close(); err != nil {
+		b.log.Warn("error closing index", "namespace", key.Namespace, "group", key.Group, "resource", key.Resource, "error", err)
+	}
+}
+
+// getIndexSchema identifies the fields and the mappings of an index
+func getIndexSchema(fields resource.SearchableDocumentFields) (string, error) {
+	defs := []*resource.ResourceTableColumnDefinition{}
+	if fields != nil {
+		for _, name := range fields.Fields() {
+			defs = append(defs, fields.Field(name))
+		}
+	}
+	body, err := json.Marshal(struct {
+		Fields   []*resource.ResourceTableColumnDefinition `json:"fields"`
+		Mappings mapping.IndexMapping                      `json:"mappings"`
+	}{
+		Fields:   defs,
+		Mappings: getBleveMappings(fields),
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%x", indexSchemaVersion, sha256.Sum256(body)), nil
+}
+
 // TotalDocs returns the total number of documents across all indices
 func (b *bleveBackend) TotalDocs() int64 {
 	var totalDocs int64
@@ -166,12 +291,31 @@ type bleveIndex struct {
 	// only valid in single thread
 	batch     *bleve.Batch
 	batchSize int // ??? not totally sure the units here
+
+	// The highest resource version written, saved with the documents
+	rv int64
+}
+
+func newBleveIndex(key resource.NamespacedResource, index bleve.Index, fields resource.SearchableDocumentFields) (*bleveIndex, error) {
+	idx := &bleveIndex{
+		key:      key,
+		index:    index,
+		fields:   fields,
+		standard: resource.StandardSearchFields(),
+	}
+
+	var err error
+	idx.allFields, err = getAllFields(idx.standard, fields)
+	return idx, err
 }
 
 // Write implements resource.DocumentIndex.
 func (b *bleveIndex) Write(v *resource.IndexableDocument) error {
 	// remove references (for now!)
 	v.References = nil
+	if v.RV > b.rv {
+		b.rv = v.RV
+	}
 	if b.batch != nil {
 		err := b.batch.Index(v.Key.SearchID(), v)
 		if err != nil {
@@ -183,7 +327,15 @@ func (b *bleveIndex) Write(v *resource.IndexableDocument) error {
 		}
 		return err // nil
 	}
-	return b.index.Index(v.Key.SearchID(), v)
+
+	// Save the document with the resource version, so a reopened index knows where to continue
+	batch := b.index.NewBatch()
+	err := batch.Index(v.Key.SearchID(), v)
+	if err != nil {
+		return err
+	}
+	batch.SetInternal(internalRVKey, []byte(strconv.FormatInt(b.rv, 10)))
+	return b.index.Batch(batch)
 }
 
 // Delete implements resource.DocumentIndex.
@@ -197,6 +349,7 @@ func (b *bleveIndex) Delete(key *resource.ResourceKey) error {
 // Flush implements resource.DocumentIndex.
 func (b *bleveIndex) Flush() (err error) {
 	if b.batch != nil {
+		b.batch.SetInternal(internalRVKey, []byte(strconv.FormatInt(b.rv, 10)))
 		err = b.index.Batch(b.batch)
 		b.batch.Reset()
 		b.batch = nil
@@ -236,7 +389,7 @@ func (b *bleveIndex) Search(
 	}
 
 	// convert protobuf request to bleve request
-	searchrequest, e := toBleveSearchRequest(req, access)
+	searchrequest, e := toBleveSearchRequest(req, access, b.defaultBoosts())
 	if e != nil {
 		response.Error = e
 		return response, nil
@@ -260,7 +413,7 @@ func (b *bleveIndex) Search(
 	response.QueryCost = float64(res.Cost)
 	response.MaxScore = res.MaxScore
 
-	response.Results, err = b.hitsToTable(searchrequest.Fields, res.Hits, req.Explain)
+	response.Results, err = b.hitsToTable(searchrequest.Fields, res.Hits, req.Explain, searchrequest.Highlight != nil)
 	if err != nil {
 		return nil, err
 	}
@@ -356,7 +509,63 @@ func (b *bleveIndex) getIndex(
 	return b.index, nil
 }
 
-func toBleveSearchRequest(req *resource.ResourceSearchRequest, access authz.AccessClient) (*bleve.SearchRequest, *resource.ErrorResult) {
+// defaultBoosts weights title matches over description matches over matches in free text fields
+func (b *bleveIndex) defaultBoosts() map[string]float64 {
+	boosts := map[string]float64{
+		resource.SEARCH_FIELD_TITLE:       3,
+		resource.SEARCH_FIELD_DESCRIPTION: 2,
+	}
+	if b.fields != nil {
+		for _, name := range b.fields.Fields() {
+			f := b.fields.Field(name)
+			if f.Properties != nil && f.Properties.FreeText {
+				boosts["fields."+name] = 1
+			}
+		}
+	}
+	return boosts
+}
+
+// textQuery matches the query text in each boosted field. Fuzzy and prefix matching only apply to titles
+func textQuery(req *resource.ResourceSearchRequest, boosts map[string]float64) (query.Query, *resource.ErrorResult) {
+	if req.Fuzziness < 0 || req.Fuzziness > 2 {
+		return nil, resource.NewBadRequestError("fuzziness must be between 0 and 2")
+	}
+
+	fields := make([]string, 0, len(boosts))
+	for field := range boosts {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields) // stable query for the same request
+
+	queries := make([]query.Query, 0, len(fields)+1)
+	for _, field := range fields {
+		q := bleve.NewMatchQuery(req.Query)
+		q.SetField(field)
+		q.SetBoost(boosts[field])
+		if field == resource.SEARCH_FIELD_TITLE {
+			q.SetFuzziness(int(req.Fuzziness))
+		}
+		queries = append(queries, q)
+	}
+
+	if req.Prefix {
+		terms := strings.Fields(strings.ToLower(req.Query))
+		if len(terms) > 0 {
+			q := bleve.NewPrefixQuery(terms[len(terms)-1])
+			q.SetField(resource.SEARCH_FIELD_TITLE)
+			boost, ok := boosts[resource.SEARCH_FIELD_TITLE]
+			if !ok {
+				boost = 1
+			}
+			q.SetBoost(boost)
+			queries = append(queries, q)
+		}
+	}
+	return bleve.NewDisjunctionQuery(queries...), nil // OR
+}
+
+func toBleveSearchRequest(req *resource.ResourceSearchRequest, access authz.AccessClient, defaultBoosts map[string]float64) (*bleve.SearchRequest, *resource.ErrorResult) {
 	searchrequest := &bleve.SearchRequest{
 		Fields:  req.Fields,
 		Size:    int(req.Limit),
@@ -364,6 +573,11 @@ func toBleveSearchRequest(req *resource.ResourceSearchRequest, access authz.Acce
 		Explain: req.Explain,
 	}
 
+	if len(req.Highlight) > 0 {
+		searchrequest.Highlight = bleve.NewHighlightWithStyle(html.Name)
+		searchrequest.Highlight.Fields = req.Highlight
+	}
+
 	// Currently everything is within an AND query
 	queries := []query.Query{}
 	if len(req.Options.Labels) > 0 {
@@ -381,15 +595,33 @@ func toBleveSearchRequest(req *resource.ResourceSearchRequest, access authz.Acce
 			if err != nil {
 				return nil, err
 			}
+			// The terms of free text fields must match in order, not any of them
+			if match, ok := q.(*query.MatchQuery); ok && phraseFields[strings.TrimPrefix(v.Key, "fields.")] {
+				phrase := query.NewMatchPhraseQuery(match.Match)
+				phrase.FieldVal = match.FieldVal
+				q = phrase
+			}
 			queries = append(queries, q)
 		}
 	}
 
 	if req.Query != "" {
-		// ??? Should expose the full power of query parsing here?
-		// it is great for exploration, but also hard to change in the future
-		q := bleve.NewQueryStringQuery(req.Query)
-		queries = append(queries, q)
+		if req.Fuzziness != 0 || req.Prefix || len(req.Boost) > 0 {
+			boosts := req.Boost
+			if len(boosts) == 0 {
+				boosts = defaultBoosts
+			}
+			q, err := textQuery(req, boosts)
+			if err != nil {
+				return nil, err
+			}
+			queries = append(queries, q)
+		} else {
+			// ??? Should expose the full power of query parsing here?
+			// it is great for exploration, but also hard to change in the future
+			q := bleve.NewQueryStringQuery(req.Query)
+			queries = append(queries, q)
+		}
 	}
 
 	if access != nil {
@@ -431,10 +663,15 @@ func toBleveSearchRequest(req *resource.ResourceSearchRequest, access authz.Acce
 			continue
 		}
 
-		// Default support
+		// Titles sort by the whole phrase, not by the terms
 		input := sort.Field
+		if input == resource.SEARCH_FIELD_TITLE {
+			input = resource.SEARCH_FIELD_TITLE_PHRASE
+		}
+
+		// Default support, including _score and _id
 		if sort.Desc {
-			input = "-" + sort.Field
+			input = "-" + input
 		}
 		s := search.ParseSearchSortString(input)
 		searchrequest.Sort = append(searchrequest.Sort, s)
@@ -442,6 +679,12 @@ func toBleveSearchRequest(req *resource.ResourceSearchRequest, access authz.Acce
 
 	// Always sort by *something*, otherwise the order is unstable
 	if len(searchrequest.Sort) == 0 {
+		// The best matches first
+		if req.Query != "" {
+			searchrequest.Sort = append(searchrequest.Sort, &search.SortScore{
+				Desc: true,
+			})
+		}
 		searchrequest.Sort = append(searchrequest.Sort, &search.SortDocID{
 			Desc: false,
 		})
@@ -474,7 +717,7 @@ func requirementQuery(req *resource.Requirement, prefix string) (query.Query, *r
 	)
 }
 
-func (b *bleveIndex) hitsToTable(selectFields []string, hits search.DocumentMatchCollection, explain bool) (*resource.ResourceTable, error) {
+func (b *bleveIndex) hitsToTable(selectFields []string, hits search.DocumentMatchCollection, explain bool, highlight bool) (*resource.ResourceTable, error) {
 	fields := []*resource.ResourceTableColumnDefinition{}
 	for _, name := range selectFields {
 		if name == "_all" {
@@ -505,6 +748,9 @@ func (b *bleveIndex) hitsToTable(selectFields []string, hits search.DocumentMatc
 	if explain {
 		fields = append(fields, b.standard.Field(resource.SEARCH_FIELD_EXPLAIN))
 	}
+	if highlight {
+		fields = append(fields, b.standard.Field(resource.SEARCH_FIELD_HIGHLIGHT))
+	}
 
 	builder, err := resource.NewTableBuilder(fields)
 	if err != nil {
@@ -529,13 +775,25 @@ func (b *bleveIndex) hitsToTable(selectFields []string, hits search.DocumentMatc
 		}
 
 		for i, f := range fields {
-			if f.Name == resource.SEARCH_FIELD_ID {
+			var v any
+			switch f.Name {
+			case resource.SEARCH_FIELD_ID:
 				row.Cells[i] = []byte(match.ID)
 				continue
+			case resource.SEARCH_FIELD_SCORE:
+				v = match.Score
+			case resource.SEARCH_FIELD_EXPLAIN:
+				if match.Expl != nil {
+					v = match.Expl
+				}
+			case resource.SEARCH_FIELD_HIGHLIGHT:
+				if len(match.Fragments) > 0 {
+					v = match.Fragments
+				}
+			default:
+				// QUICK QUICK... more options yes
+				v = match.Fields[f.Name]
 			}
-
-			// QUICK QUICK... more options yes
-			v := match.Fields[f.Name]
 			if v != nil {
 				// Encode the value to protobuf
 				row.Cells[i], err = encoders[i](v)
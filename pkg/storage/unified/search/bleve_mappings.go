@@ -2,33 +2,61 @@ package search
 
 import (
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
 	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/standard"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/single"
 	"github.com/blevesearch/bleve/v2/mapping"
 
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
 )
 
+// phraseAnalyzer indexes the whole value in lower case
+const phraseAnalyzer = "phrase"
+
 func getBleveMappings(fields resource.SearchableDocumentFields) mapping.IndexMapping {
 	mapper := bleve.NewIndexMapping()
+	err := mapper.AddCustomAnalyzer(phraseAnalyzer, map[string]any{
+		"type":          custom.Name,
+		"tokenizer":     single.Name,
+		"token_filters": []string{lowercase.Name},
+	})
+	if err != nil {
+		panic("failed to add phrase analyzer")
+	}
 	mapper.DefaultMapping = getBleveDocMappings(fields)
 	return mapper
 }
 
-func getBleveDocMappings(_ resource.SearchableDocumentFields) *mapping.DocumentMapping {
+func getBleveDocMappings(fields resource.SearchableDocumentFields) *mapping.DocumentMapping {
 	mapper := bleve.NewDocumentStaticMapping()
-	mapper.AddFieldMapping(&mapping.FieldMapping{
-		Name: resource.SEARCH_FIELD_TITLE,
-		Type: "text",
-		// TODO - if we don't want title to be a keyword, we can use this
-		// set the title field to use keyword analyzer so it sorts by the whole phrase
-		// https://github.com/blevesearch/bleve/issues/417#issuecomment-245273022
-		Analyzer:           keyword.Name,
-		Store:              true,
-		Index:              true,
-		IncludeTermVectors: true,
-		IncludeInAll:       true,
-		DocValues:          false,
-	})
+
+	// The title is indexed twice: as terms for full text, fuzzy and prefix matching,
+	// and as a single lower case phrase so it sorts by the whole title
+	// https://github.com/blevesearch/bleve/issues/417#issuecomment-245273022
+	mapper.AddFieldMappingsAt(resource.SEARCH_FIELD_TITLE,
+		&mapping.FieldMapping{
+			Name:               resource.SEARCH_FIELD_TITLE,
+			Type:               "text",
+			Analyzer:           standard.Name,
+			Store:              true,
+			Index:              true,
+			IncludeTermVectors: true, // required for highlighting
+			IncludeInAll:       true,
+			DocValues:          false,
+		},
+		&mapping.FieldMapping{
+			Name:               resource.SEARCH_FIELD_TITLE_PHRASE,
+			Type:               "text",
+			Analyzer:           phraseAnalyzer,
+			Store:              false,
+			Index:              true,
+			IncludeTermVectors: false,
+			IncludeInAll:       false,
+			DocValues:          true,
+		},
+	)
 
 	mapper.AddFieldMapping(&mapping.FieldMapping{
 		Name:               resource.SEARCH_FIELD_DESCRIPTION,
@@ -73,7 +101,60 @@ func getBleveDocMappings(_ resource.SearchableDocumentFields) *mapping.DocumentM
 		DocValues:          true,
 	})
 
+	if fields != nil {
+		mapper.AddSubDocumentMapping("fields", getBleveCustomFieldMappings(fields))
+	}
+
 	mapper.Dynamic = true
 
 	return mapper
 }
+
+// keywordFields are custom fields indexed as keywords, so filters match the whole value
+var keywordFields = map[string]bool{
+	DASHBOARD_DS_UID:        true,
+	DASHBOARD_VARIABLES:     true,
+	DASHBOARD_LIBRARY_PANEL: true,
+}
+
+// phraseFields are custom free text fields which are filtered with phrase queries, their
+// term positions are indexed
+var phraseFields = map[string]bool{
+	DASHBOARD_PANEL_QUERY: true,
+}
+
+// The keyword and phrase fields have explicit mappings, all other custom fields are indexed dynamically
+func getBleveCustomFieldMappings(fields resource.SearchableDocumentFields) *mapping.DocumentMapping {
+	mapper := bleve.NewDocumentMapping()
+	for _, name := range fields.Fields() {
+		def := fields.Field(name)
+		if def == nil || def.Type != resource.ResourceTableColumnDefinition_STRING {
+			continue
+		}
+		switch {
+		case keywordFields[name]:
+			mapper.AddFieldMappingsAt(name, &mapping.FieldMapping{
+				Name:               name,
+				Type:               "text",
+				Analyzer:           keyword.Name,
+				Store:              true,
+				Index:              true,
+				IncludeTermVectors: false,
+				IncludeInAll:       false,
+				DocValues:          true,
+			})
+		case phraseFields[name]:
+			mapper.AddFieldMappingsAt(name, &mapping.FieldMapping{
+				Name:               name,
+				Type:               "text",
+				Analyzer:           standard.Name,
+				Store:              true,
+				Index:              true,
+				IncludeTermVectors: true,
+				IncludeInAll:       true,
+				DocValues:          true,
+			})
+		}
+	}
+	return mapper
+}
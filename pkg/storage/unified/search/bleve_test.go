@@ -3,8 +3,10 @@ package search
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -302,3 +304,364 @@ func TestBleveBackend(t *testing.T) {
 		}`, string(disp))
 	})
 }
+
+func TestBleveSearchRelevance(t *testing.T) {
+	key := &resource.ResourceKey{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	backend := NewBleveBackend(BleveOptions{
+		Root:          t.TempDir(),
+		FileThreshold: 1000, // memory only
+	}, tracing.NewNoopTracerService())
+	resource.NewIndexMetrics(backend.opts.Root, backend)
+
+	info, err := DashboardBuilder(nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	index, err := backend.BuildIndex(ctx, resource.NamespacedResource{
+		Namespace: key.Namespace,
+		Group:     key.Group,
+		Resource:  key.Resource,
+	}, 3, 1, info.Fields, func(index resource.ResourceIndex) (int64, error) {
+		docs := []*resource.IndexableDocument{
+			{
+				Title:       "Kubernetes cluster",
+				Description: "Nodes and pods",
+			},
+			{
+				Title:       "Node exporter",
+				Description: "Host metrics from every kubernetes node",
+			},
+			{
+				Title: "Business overview",
+				Fields: map[string]any{
+					DASHBOARD_PANEL_TITLE: []string{"Kubernetes costs"},
+				},
+			},
+			{
+				Title:       "alerts",
+				Description: "Alert rule states",
+			},
+		}
+		for i, doc := range docs {
+			doc.RV = int64(i + 1)
+			doc.Key = &resource.ResourceKey{
+				Name:      fmt.Sprintf("d%d", i),
+				Namespace: key.Namespace,
+				Group:     key.Group,
+				Resource:  key.Resource,
+			}
+			if err := index.Write(doc); err != nil {
+				return 0, err
+			}
+		}
+		return 1, nil
+	})
+	require.NoError(t, err)
+
+	search := func(t *testing.T, req *resource.ResourceSearchRequest) *resource.ResourceSearchResponse {
+		t.Helper()
+		req.Options = &resource.ListOptions{Key: key}
+		req.Limit = 10
+		if req.Fields == nil {
+			req.Fields = []string{resource.SEARCH_FIELD_TITLE}
+		}
+		rsp, err := index.Search(ctx, nil, req, nil)
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		return rsp
+	}
+	titles := func(rsp *resource.ResourceSearchResponse) []string {
+		titles := []string{}
+		for _, row := range rsp.Results.Rows {
+			titles = append(titles, string(row.Cells[0]))
+		}
+		return titles
+	}
+
+	t.Run("boosts title over description over panel titles", func(t *testing.T) {
+		rsp := search(t, &resource.ResourceSearchRequest{
+			Query:  "kubernetes",
+			Prefix: true,
+		})
+		require.Equal(t, []string{"Kubernetes cluster", "Node exporter", "Business overview"}, titles(rsp))
+
+		// custom boosts put description matches first
+		rsp = search(t, &resource.ResourceSearchRequest{
+			Query: "kubernetes",
+			Boost: map[string]float64{
+				resource.SEARCH_FIELD_TITLE:       1,
+				resource.SEARCH_FIELD_DESCRIPTION: 10,
+			},
+		})
+		require.Equal(t, []string{"Node exporter", "Kubernetes cluster"}, titles(rsp))
+	})
+
+	t.Run("fuzzy title matching", func(t *testing.T) {
+		rsp := search(t, &resource.ResourceSearchRequest{
+			Query:     "kubernets",
+			Fuzziness: 1,
+		})
+		require.Equal(t, []string{"Kubernetes cluster"}, titles(rsp))
+
+		rsp, err := index.Search(ctx, nil, &resource.ResourceSearchRequest{
+			Options:   &resource.ListOptions{Key: key},
+			Query:     "kubernets",
+			Fuzziness: 3,
+		}, nil)
+		require.NoError(t, err)
+		require.NotNil(t, rsp.Error)
+	})
+
+	t.Run("prefix title matching", func(t *testing.T) {
+		rsp := search(t, &resource.ResourceSearchRequest{
+			Query:  "node exp",
+			Prefix: true,
+		})
+		require.Equal(t, "Node exporter", titles(rsp)[0])
+	})
+
+	t.Run("highlight matches", func(t *testing.T) {
+		rsp := search(t, &resource.ResourceSearchRequest{
+			Query:     "exporter",
+			Prefix:    true,
+			Highlight: []string{resource.SEARCH_FIELD_TITLE},
+		})
+		require.Len(t, rsp.Results.Rows, 1)
+		require.Equal(t, resource.SEARCH_FIELD_HIGHLIGHT, rsp.Results.Columns[1].Name)
+		require.JSONEq(t, `{"title": ["Node <mark>exporter</mark>"]}`, string(rsp.Results.Rows[0].Cells[1]))
+	})
+
+	t.Run("sort by whole title or score", func(t *testing.T) {
+		rsp := search(t, &resource.ResourceSearchRequest{
+			SortBy: []*resource.ResourceSearchRequest_Sort{
+				{Field: resource.SEARCH_FIELD_TITLE},
+			},
+		})
+		require.Equal(t, []string{"alerts", "Business overview", "Kubernetes cluster", "Node exporter"}, titles(rsp))
+
+		rsp = search(t, &resource.ResourceSearchRequest{
+			Query:  "kubernetes",
+			Fields: []string{resource.SEARCH_FIELD_TITLE, resource.SEARCH_FIELD_SCORE},
+			Prefix: true,
+			SortBy: []*resource.ResourceSearchRequest_Sort{
+				{Field: resource.SEARCH_FIELD_SCORE},
+			},
+		})
+		require.Equal(t, []string{"Business overview", "Node exporter", "Kubernetes cluster"}, titles(rsp))
+		require.Len(t, rsp.Results.Rows[0].Cells[1], 8) // encoded score
+	})
+}
+
+func TestBleveSearchDashboardQueries(t *testing.T) {
+	key := &resource.ResourceKey{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	backend := NewBleveBackend(BleveOptions{
+		Root:          t.TempDir(),
+		FileThreshold: 1000, // memory only
+	}, tracing.NewNoopTracerService())
+	resource.NewIndexMetrics(backend.opts.Root, backend)
+
+	info, err := DashboardBuilder(nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	index, err := backend.BuildIndex(ctx, resource.NamespacedResource{
+		Namespace: key.Namespace,
+		Group:     key.Group,
+		Resource:  key.Resource,
+	}, 3, 1, info.Fields, func(index resource.ResourceIndex) (int64, error) {
+		docs := []*resource.IndexableDocument{
+			{
+				Title: "HTTP",
+				Fields: map[string]any{
+					DASHBOARD_PANEL_QUERY: []string{`sum(rate(http_requests_total{job="api"}[5m]))`},
+					DASHBOARD_DS_UID:      []string{"prom-EU"},
+					DASHBOARD_VARIABLES:   []string{"job"},
+				},
+			},
+			{
+				Title: "Database",
+				Fields: map[string]any{
+					DASHBOARD_PANEL_QUERY:   []string{"SELECT count(*) FROM requests"},
+					DASHBOARD_DS_UID:        []string{"mysql-1"},
+					DASHBOARD_LIBRARY_PANEL: []string{"lib-a"},
+				},
+			},
+			{
+				Title: "Mixed",
+				Fields: map[string]any{
+					DASHBOARD_DS_UID:        []string{"mysql-1", "prom-EU"},
+					DASHBOARD_LIBRARY_PANEL: []string{"lib-a", "lib-b"},
+				},
+			},
+		}
+		for i, doc := range docs {
+			doc.RV = int64(i + 1)
+			doc.Key = &resource.ResourceKey{
+				Name:      fmt.Sprintf("d%d", i),
+				Namespace: key.Namespace,
+				Group:     key.Group,
+				Resource:  key.Resource,
+			}
+			if err := index.Write(doc); err != nil {
+				return 0, err
+			}
+		}
+		return 1, nil
+	})
+	require.NoError(t, err)
+
+	search := func(t *testing.T, query string, requirements ...*resource.Requirement) []string {
+		t.Helper()
+		rsp, err := index.Search(ctx, nil, &resource.ResourceSearchRequest{
+			Options: &resource.ListOptions{Key: key, Fields: requirements},
+			Query:   query,
+			Fields:  []string{resource.SEARCH_FIELD_TITLE},
+			SortBy: []*resource.ResourceSearchRequest_Sort{
+				{Field: resource.SEARCH_FIELD_TITLE},
+			},
+			Limit: 10,
+		}, nil)
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		titles := []string{}
+		for _, row := range rsp.Results.Rows {
+			titles = append(titles, string(row.Cells[0]))
+		}
+		return titles
+	}
+	field := func(name string, value string) *resource.Requirement {
+		return &resource.Requirement{Key: "fields." + name, Operator: "=", Values: []string{value}}
+	}
+
+	t.Run("full text search in panel queries", func(t *testing.T) {
+		require.Equal(t, []string{"HTTP"}, search(t, "http_requests_total"))
+		require.Equal(t, []string{"Database"}, search(t, "select"))
+	})
+
+	t.Run("filter by data source uid", func(t *testing.T) {
+		require.Equal(t, []string{"HTTP", "Mixed"}, search(t, "", field(DASHBOARD_DS_UID, "prom-EU")))
+		require.Equal(t, []string{"Database", "Mixed"}, search(t, "", field(DASHBOARD_DS_UID, "mysql-1")))
+		require.Empty(t, search(t, "", field(DASHBOARD_DS_UID, "prom")))
+	})
+
+	t.Run("filter by variable and library panel", func(t *testing.T) {
+		require.Equal(t, []string{"HTTP"}, search(t, "", field(DASHBOARD_VARIABLES, "job")))
+		require.Equal(t, []string{"Mixed"}, search(t, "", field(DASHBOARD_LIBRARY_PANEL, "lib-b")))
+		require.Equal(t, []string{"Database"}, search(t, "", field(DASHBOARD_LIBRARY_PANEL, "lib-a"), field(DASHBOARD_DS_UID, "mysql-1"), field(DASHBOARD_PANEL_QUERY, "requests")))
+	})
+
+	t.Run("filter by panel query phrase", func(t *testing.T) {
+		require.Equal(t, []string{"HTTP"}, search(t, "", field(DASHBOARD_PANEL_QUERY, "rate(http_requests_total")))
+		require.Equal(t, []string{"Database"}, search(t, "", field(DASHBOARD_PANEL_QUERY, "select COUNT")))
+		require.Empty(t, search(t, "", field(DASHBOARD_PANEL_QUERY, "requests select")))
+		require.Empty(t, search(t, "", field(DASHBOARD_PANEL_QUERY, "rate requests")))
+	})
+
+	t.Run("only the keyword and phrase fields have explicit mappings", func(t *testing.T) {
+		mapper := getBleveCustomFieldMappings(info.Fields)
+		names := []string{}
+		for name := range mapper.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		require.Equal(t, []string{DASHBOARD_DS_UID, DASHBOARD_LIBRARY_PANEL, DASHBOARD_PANEL_QUERY, DASHBOARD_VARIABLES}, names)
+	})
+}
+
+func TestBlevePersistentIndex(t *testing.T) {
+	key := resource.NamespacedResource{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	opts := BleveOptions{
+		Root:          t.TempDir(),
+		FileThreshold: 5, // file based
+	}
+	backend := NewBleveBackend(opts, tracing.NewNoopTracerService())
+	resource.NewIndexMetrics(backend.opts.Root, backend)
+
+	info, err := DashboardBuilder(nil)
+	require.NoError(t, err)
+
+	doc := func(name string, rv int64) *resource.IndexableDocument {
+		return &resource.IndexableDocument{
+			Title: name,
+			RV:    rv,
+			Key: &resource.ResourceKey{
+				Name:      name,
+				Namespace: key.Namespace,
+				Group:     key.Group,
+				Resource:  key.Resource,
+			},
+		}
+	}
+
+	ctx := context.Background()
+	index, err := backend.BuildIndex(ctx, key, 10, 5, info.Fields, func(index resource.ResourceIndex) (int64, error) {
+		require.NoError(t, index.Write(doc("a", 1)))
+		require.NoError(t, index.Write(doc("b", 2)))
+		return 5, nil // the list RV
+	})
+	require.NoError(t, err)
+	require.NoError(t, index.Write(doc("c", 7)))
+	require.NoError(t, index.Delete(doc("a", 0).Key))
+
+	t.Run("reopen with the last resource version", func(t *testing.T) {
+		backend.closeIndex(key) // release the files
+		backend := NewBleveBackend(opts, tracing.NewNoopTracerService())
+
+		index, rv, err := backend.OpenIndex(ctx, key, info.Fields)
+		require.NoError(t, err)
+		require.NotNil(t, index)
+		require.Equal(t, int64(7), rv)
+
+		count, err := index.DocCount(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+
+		found, err := backend.GetIndex(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, index, found)
+		backend.closeIndex(key)
+	})
+
+	t.Run("schema changes are not opened", func(t *testing.T) {
+		backend := NewBleveBackend(opts, tracing.NewNoopTracerService())
+		fields, err := resource.NewSearchableDocumentFields([]*resource.ResourceTableColumnDefinition{{
+			Name: "other",
+			Type: resource.ResourceTableColumnDefinition_STRING,
+		}})
+		require.NoError(t, err)
+
+		index, _, err := backend.OpenIndex(ctx, key, fields)
+		require.NoError(t, err)
+		require.Nil(t, index)
+
+		// so it is built again
+		index, err = backend.BuildIndex(ctx, key, 10, 8, fields, func(index resource.ResourceIndex) (int64, error) {
+			return 8, index.Write(doc("d", 8))
+		})
+		require.NoError(t, err)
+		count, err := index.DocCount(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+		backend.closeIndex(key)
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		backend := NewBleveBackend(BleveOptions{Root: t.TempDir()}, tracing.NewNoopTracerService())
+		index, rv, err := backend.OpenIndex(ctx, key, info.Fields)
+		require.NoError(t, err)
+		require.Nil(t, index)
+		require.Zero(t, rv)
+	})
+}
@@ -22,6 +22,11 @@ const DASHBOARD_LEGACY_ID = "legacy_id"
 const DASHBOARD_SCHEMA_VERSION = "schema_version"
 const DASHBOARD_LINK_COUNT = "link_count"
 const DASHBOARD_PANEL_TYPES = "panel_types"
+const DASHBOARD_PANEL_TITLE = "panel_title"
+const DASHBOARD_PANEL_QUERY = "panel_query"
+const DASHBOARD_DS_UID = "ds_uid"
+const DASHBOARD_VARIABLES = "variables"
+const DASHBOARD_LIBRARY_PANEL = "library_panel"
 const DASHBOARD_DS_TYPES = "ds_types"
 const DASHBOARD_TRANSFORMATIONS = "transformation"
 
@@ -69,6 +74,51 @@ func DashboardBuilder(namespaced resource.NamespacedDocumentSupplier) (resource.
 				Filterable: true,
 			},
 		},
+		{
+			Name:        DASHBOARD_PANEL_TITLE,
+			Type:        resource.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "Titles of the panels",
+			Properties: &resource.ResourceTableColumnDefinition_Properties{
+				FreeText: true,
+			},
+		},
+		{
+			Name:        DASHBOARD_PANEL_QUERY,
+			Type:        resource.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "Query expressions of the panels, eg: PromQL or SQL",
+			Properties: &resource.ResourceTableColumnDefinition_Properties{
+				FreeText: true,
+			},
+		},
+		{
+			Name:        DASHBOARD_DS_UID,
+			Type:        resource.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "UIDs of the data sources used by the dashboard",
+			Properties: &resource.ResourceTableColumnDefinition_Properties{
+				Filterable: true,
+			},
+		},
+		{
+			Name:        DASHBOARD_VARIABLES,
+			Type:        resource.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "Names of the template variables",
+			Properties: &resource.ResourceTableColumnDefinition_Properties{
+				Filterable: true,
+			},
+		},
+		{
+			Name:        DASHBOARD_LIBRARY_PANEL,
+			Type:        resource.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "UIDs of the library panels used by the dashboard",
+			Properties: &resource.ResourceTableColumnDefinition_Properties{
+				Filterable: true,
+			},
+		},
 	})
 	if namespaced == nil {
 		namespaced = func(ctx context.Context, namespace string, blob resource.BlobSupport) (resource.DocumentBuilder, error) {
@@ -156,10 +206,30 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 	doc.Tags = summary.Tags
 
 	panelTypes := []string{}
+	panelTitles := []string{}
+	panelQueries := []string{}
+	libraryPanels := []string{}
 	transformations := []string{}
 	dsTypes := []string{}
+	dsUIDs := []string{}
 
 	for _, p := range summary.Panels {
+		if p.Title != "" {
+			panelTitles = append(panelTitles, p.Title)
+		}
+		panelQueries = append(panelQueries, p.Queries...)
+		if p.LibraryPanel != "" {
+			libraryPanels = append(libraryPanels, p.LibraryPanel)
+		}
+		for _, c := range p.Collapsed {
+			if c.Title != "" {
+				panelTitles = append(panelTitles, c.Title)
+			}
+			panelQueries = append(panelQueries, c.Queries...)
+			if c.LibraryPanel != "" {
+				libraryPanels = append(libraryPanels, c.LibraryPanel)
+			}
+		}
 		if p.Type != "" {
 			panelTypes = append(panelTypes, p.Type)
 		}
@@ -178,6 +248,9 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 
 	for _, ds := range summary.Datasource {
 		dsTypes = append(dsTypes, ds.Type)
+		if ds.UID != "" {
+			dsUIDs = append(dsUIDs, ds.UID)
+		}
 		doc.References = append(doc.References, resource.ResourceReference{
 			Group:    ds.Type,
 			Kind:     "DataSource",
@@ -201,6 +274,23 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 		sort.Strings(panelTypes)
 		doc.Fields[DASHBOARD_PANEL_TYPES] = panelTypes
 	}
+	if len(panelTitles) > 0 {
+		doc.Fields[DASHBOARD_PANEL_TITLE] = panelTitles
+	}
+	if len(panelQueries) > 0 {
+		doc.Fields[DASHBOARD_PANEL_QUERY] = panelQueries
+	}
+	if len(libraryPanels) > 0 {
+		sort.Strings(libraryPanels)
+		doc.Fields[DASHBOARD_LIBRARY_PANEL] = libraryPanels
+	}
+	if len(summary.TemplateVars) > 0 {
+		doc.Fields[DASHBOARD_VARIABLES] = summary.TemplateVars
+	}
+	if len(dsUIDs) > 0 {
+		sort.Strings(dsUIDs)
+		doc.Fields[DASHBOARD_DS_UID] = dsUIDs
+	}
 	if len(dsTypes) > 0 {
 		sort.Strings(dsTypes)
 		doc.Fields[DASHBOARD_DS_TYPES] = dsTypes
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
@@ -27,16 +28,27 @@ import (
 const tracePrefix = "sql.resource."
 const defaultPollingInterval = 100 * time.Millisecond
 
+// The number of history entries read in each query when listing the history
+const historyPageSize = 1000
+
 type Backend interface {
 	resource.StorageBackend
 	resource.DiagnosticsServer
 	resource.LifecycleHooks
+	resource.ResourceEventLister
+	resource.BulkStorageBackend
 }
 
 type BackendOptions struct {
 	DBProvider      db.DBProvider
 	Tracer          trace.Tracer
+	Reg             prometheus.Registerer
 	PollingInterval time.Duration
+
+	// History retention for each resource, the key is "{resource}.{group}"
+	HistoryRetention map[string]HistoryRetention
+	// How often the history is compacted, zero disables compaction
+	CompactionInterval time.Duration
 }
 
 func NewBackend(opts BackendOptions) (Backend, error) {
@@ -59,6 +71,10 @@ func NewBackend(opts BackendOptions) (Backend, error) {
 		tracer:          opts.Tracer,
 		dbProvider:      opts.DBProvider,
 		pollingInterval: pollingInterval,
+
+		retention:          opts.HistoryRetention,
+		compactionInterval: opts.CompactionInterval,
+		compactorMetrics:   newCompactorMetrics(opts.Reg),
 	}, nil
 }
 
@@ -81,6 +97,11 @@ type backend struct {
 	// watch streaming
 	//stream chan *resource.WatchEvent
 	pollingInterval time.Duration
+
+	// history compaction
+	retention          map[string]HistoryRetention
+	compactionInterval time.Duration
+	compactorMetrics   *compactorMetrics
 }
 
 func (b *backend) Init(ctx context.Context) error {
@@ -103,7 +124,14 @@ func (b *backend) initLocked(ctx context.Context) error {
 		return fmt.Errorf("no dialect for driver %q", driverName)
 	}
 
-	return b.db.PingContext(ctx)
+	if err := b.db.PingContext(ctx); err != nil {
+		return err
+	}
+
+	if b.compactionInterval > 0 && len(b.retention) > 0 {
+		go b.compactor()
+	}
+	return nil
 }
 
 func (b *backend) IsHealthy(ctx context.Context, r *resource.HealthCheckRequest) (*resource.HealthCheckResponse, error) {
@@ -174,179 +202,218 @@ func (b *backend) create(ctx context.Context, event resource.WriteEvent) (int64,
 	ctx, span := b.tracer.Start(ctx, tracePrefix+"Create")
 	defer span.End()
 	var newVersion int64
-	guid := uuid.New().String()
 	err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
-		folder := ""
-		if event.Object != nil {
-			folder = event.Object.GetFolder()
-		}
-		// 1. Insert into resource
-		if _, err := dbutil.Exec(ctx, tx, sqlResourceInsert, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			Folder:      folder,
-			GUID:        guid,
-		}); err != nil {
-			return fmt.Errorf("insert into resource: %w", err)
-		}
+		var err error
+		newVersion, err = b.createTx(ctx, tx, event)
+		return err
+	})
+	return newVersion, err
+}
 
-		// 2. Insert into resource history
-		if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			Folder:      folder,
-			GUID:        guid,
-		}); err != nil {
-			return fmt.Errorf("insert into resource history: %w", err)
-		}
+func (b *backend) createTx(ctx context.Context, tx db.Tx, event resource.WriteEvent) (int64, error) {
+	guid := uuid.New().String()
+	folder := ""
+	if event.Object != nil {
+		folder = event.Object.GetFolder()
+	}
+	// 1. Insert into resource
+	if _, err := dbutil.Exec(ctx, tx, sqlResourceInsert, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		Folder:      folder,
+		GUID:        guid,
+	}); err != nil {
+		return 0, fmt.Errorf("insert into resource: %w", err)
+	}
 
-		// 3. TODO: Rebuild the whole folder tree structure if we're creating a folder
+	// 2. Insert into resource history
+	if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		Folder:      folder,
+		GUID:        guid,
+	}); err != nil {
+		return 0, fmt.Errorf("insert into resource history: %w", err)
+	}
 
-		// 4. Atomically increment resource version for this kind
-		rv, err := b.resourceVersionAtomicInc(ctx, tx, event.Key)
-		if err != nil {
-			return fmt.Errorf("increment resource version: %w", err)
-		}
+	// 3. TODO: Rebuild the whole folder tree structure if we're creating a folder
 
-		// 5. Update the RV in both resource and resource_history
-		if _, err = dbutil.Exec(ctx, tx, sqlResourceHistoryUpdateRV, sqlResourceUpdateRVRequest{
-			SQLTemplate:     sqltemplate.New(b.dialect),
-			GUID:            guid,
-			ResourceVersion: rv,
-		}); err != nil {
-			return fmt.Errorf("update resource_history rv: %w", err)
-		}
+	// 4. Atomically increment resource version for this kind
+	rv, err := b.resourceVersionAtomicInc(ctx, tx, event.Key)
+	if err != nil {
+		return 0, fmt.Errorf("increment resource version: %w", err)
+	}
 
-		if _, err = dbutil.Exec(ctx, tx, sqlResourceUpdateRV, sqlResourceUpdateRVRequest{
-			SQLTemplate:     sqltemplate.New(b.dialect),
-			GUID:            guid,
-			ResourceVersion: rv,
-		}); err != nil {
-			return fmt.Errorf("update resource rv: %w", err)
-		}
-		newVersion = rv
+	// 5. Update the RV in both resource and resource_history
+	if _, err = dbutil.Exec(ctx, tx, sqlResourceHistoryUpdateRV, sqlResourceUpdateRVRequest{
+		SQLTemplate:     sqltemplate.New(b.dialect),
+		GUID:            guid,
+		ResourceVersion: rv,
+	}); err != nil {
+		return 0, fmt.Errorf("update resource_history rv: %w", err)
+	}
 
-		return nil
-	})
-	return newVersion, err
+	if _, err = dbutil.Exec(ctx, tx, sqlResourceUpdateRV, sqlResourceUpdateRVRequest{
+		SQLTemplate:     sqltemplate.New(b.dialect),
+		GUID:            guid,
+		ResourceVersion: rv,
+	}); err != nil {
+		return 0, fmt.Errorf("update resource rv: %w", err)
+	}
+	return rv, nil
 }
 
 func (b *backend) update(ctx context.Context, event resource.WriteEvent) (int64, error) {
 	ctx, span := b.tracer.Start(ctx, tracePrefix+"Update")
 	defer span.End()
 	var newVersion int64
-	guid := uuid.New().String()
 	err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
-		folder := ""
-		if event.Object != nil {
-			folder = event.Object.GetFolder()
-		}
-		// 1. Update resource
-		_, err := dbutil.Exec(ctx, tx, sqlResourceUpdate, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			Folder:      folder,
-			GUID:        guid,
-		})
-		if err != nil {
-			return fmt.Errorf("initial resource update: %w", err)
-		}
-
-		// 2. Insert into resource history
-		if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			Folder:      folder,
-			GUID:        guid,
-		}); err != nil {
-			return fmt.Errorf("insert into resource history: %w", err)
-		}
+		var err error
+		newVersion, err = b.updateTx(ctx, tx, event)
+		return err
+	})
+	return newVersion, err
+}
 
-		// 3. TODO: Rebuild the whole folder tree structure if we're creating a folder
+func (b *backend) updateTx(ctx context.Context, tx db.Tx, event resource.WriteEvent) (int64, error) {
+	guid := uuid.New().String()
+	folder := ""
+	if event.Object != nil {
+		folder = event.Object.GetFolder()
+	}
+	// 1. Update resource
+	_, err := dbutil.Exec(ctx, tx, sqlResourceUpdate, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		Folder:      folder,
+		GUID:        guid,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("initial resource update: %w", err)
+	}
 
-		// 4. Atomically increment resource version for this kind
-		rv, err := b.resourceVersionAtomicInc(ctx, tx, event.Key)
-		if err != nil {
-			return fmt.Errorf("increment resource version: %w", err)
-		}
+	// 2. Insert into resource history
+	if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		Folder:      folder,
+		GUID:        guid,
+	}); err != nil {
+		return 0, fmt.Errorf("insert into resource history: %w", err)
+	}
 
-		// 5. Update the RV in both resource and resource_history
-		if _, err = dbutil.Exec(ctx, tx, sqlResourceHistoryUpdateRV, sqlResourceUpdateRVRequest{
-			SQLTemplate:     sqltemplate.New(b.dialect),
-			GUID:            guid,
-			ResourceVersion: rv,
-		}); err != nil {
-			return fmt.Errorf("update history rv: %w", err)
-		}
+	// 3. TODO: Rebuild the whole folder tree structure if we're creating a folder
 
-		if _, err = dbutil.Exec(ctx, tx, sqlResourceUpdateRV, sqlResourceUpdateRVRequest{
-			SQLTemplate:     sqltemplate.New(b.dialect),
-			GUID:            guid,
-			ResourceVersion: rv,
-		}); err != nil {
-			return fmt.Errorf("update resource rv: %w", err)
-		}
-		newVersion = rv
+	// 4. Atomically increment resource version for this kind
+	rv, err := b.resourceVersionAtomicInc(ctx, tx, event.Key)
+	if err != nil {
+		return 0, fmt.Errorf("increment resource version: %w", err)
+	}
 
-		return nil
-	})
+	// 5. Update the RV in both resource and resource_history
+	if _, err = dbutil.Exec(ctx, tx, sqlResourceHistoryUpdateRV, sqlResourceUpdateRVRequest{
+		SQLTemplate:     sqltemplate.New(b.dialect),
+		GUID:            guid,
+		ResourceVersion: rv,
+	}); err != nil {
+		return 0, fmt.Errorf("update history rv: %w", err)
+	}
 
-	return newVersion, err
+	if _, err = dbutil.Exec(ctx, tx, sqlResourceUpdateRV, sqlResourceUpdateRVRequest{
+		SQLTemplate:     sqltemplate.New(b.dialect),
+		GUID:            guid,
+		ResourceVersion: rv,
+	}); err != nil {
+		return 0, fmt.Errorf("update resource rv: %w", err)
+	}
+	return rv, nil
 }
 
 func (b *backend) delete(ctx context.Context, event resource.WriteEvent) (int64, error) {
 	ctx, span := b.tracer.Start(ctx, tracePrefix+"Delete")
 	defer span.End()
 	var newVersion int64
+	err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		var err error
+		newVersion, err = b.deleteTx(ctx, tx, event)
+		return err
+	})
+	return newVersion, err
+}
+
+func (b *backend) deleteTx(ctx context.Context, tx db.Tx, event resource.WriteEvent) (int64, error) {
 	guid := uuid.New().String()
+	folder := ""
+	if event.Object != nil {
+		folder = event.Object.GetFolder()
+	}
+	// 1. delete from resource
+	_, err := dbutil.Exec(ctx, tx, sqlResourceDelete, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		GUID:        guid,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("delete resource: %w", err)
+	}
 
-	err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
-		folder := ""
-		if event.Object != nil {
-			folder = event.Object.GetFolder()
-		}
-		// 1. delete from resource
-		_, err := dbutil.Exec(ctx, tx, sqlResourceDelete, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			GUID:        guid,
-		})
-		if err != nil {
-			return fmt.Errorf("delete resource: %w", err)
-		}
+	// 2. Add event to resource history
+	if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		Folder:      folder,
+		GUID:        guid,
+	}); err != nil {
+		return 0, fmt.Errorf("insert into resource history: %w", err)
+	}
 
-		// 2. Add event to resource history
-		if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			Folder:      folder,
-			GUID:        guid,
-		}); err != nil {
-			return fmt.Errorf("insert into resource history: %w", err)
-		}
+	// 3. TODO: Rebuild the whole folder tree structure if we're creating a folder
 
-		// 3. TODO: Rebuild the whole folder tree structure if we're creating a folder
+	// 4. Atomically increment resource version for this kind
+	rv, err := b.resourceVersionAtomicInc(ctx, tx, event.Key)
+	if err != nil {
+		return 0, fmt.Errorf("increment resource version: %w", err)
+	}
 
-		// 4. Atomically increment resource version for this kind
-		rv, err := b.resourceVersionAtomicInc(ctx, tx, event.Key)
-		if err != nil {
-			return fmt.Errorf("increment resource version: %w", err)
-		}
+	// 5. Update the RV in resource_history
+	if _, err = dbutil.Exec(ctx, tx, sqlResourceHistoryUpdateRV, sqlResourceUpdateRVRequest{
+		SQLTemplate:     sqltemplate.New(b.dialect),
+		GUID:            guid,
+		ResourceVersion: rv,
+	}); err != nil {
+		return 0, fmt.Errorf("update history rv: %w", err)
+	}
+	return rv, nil
+}
 
-		// 5. Update the RV in resource_history
-		if _, err = dbutil.Exec(ctx, tx, sqlResourceHistoryUpdateRV, sqlResourceUpdateRVRequest{
-			SQLTemplate:     sqltemplate.New(b.dialect),
-			GUID:            guid,
-			ResourceVersion: rv,
-		}); err != nil {
-			return fmt.Errorf("update history rv: %w", err)
+// WriteEvents writes all events in a single transaction
+func (b *backend) WriteEvents(ctx context.Context, events []resource.WriteEvent) ([]int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"WriteEvents")
+	defer span.End()
+	rvs := make([]int64, len(events))
+	err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		for i, event := range events {
+			var err error
+			switch event.Type {
+			case resource.WatchEvent_ADDED:
+				rvs[i], err = b.createTx(ctx, tx, event)
+			case resource.WatchEvent_MODIFIED:
+				rvs[i], err = b.updateTx(ctx, tx, event)
+			case resource.WatchEvent_DELETED:
+				rvs[i], err = b.deleteTx(ctx, tx, event)
+			default:
+				err = fmt.Errorf("unsupported event type")
+			}
+			if err != nil {
+				return fmt.Errorf("write %s: %w", event.Key.Name, err)
+			}
 		}
-		newVersion = rv
-
 		return nil
 	})
-
-	return newVersion, err
+	if err != nil {
+		return nil, err
+	}
+	return rvs, nil
 }
 
 func (b *backend) ReadResource(ctx context.Context, req *resource.ReadRequest) *resource.BackendReadResponse {
@@ -380,6 +447,12 @@ func (b *backend) ReadResource(ctx context.Context, req *resource.ReadRequest) *
 	} else if err != nil {
 		return &resource.BackendReadResponse{Error: resource.AsErrorResult(err)}
 	}
+	if readReq.Action == int(resource.WatchEvent_DELETED) {
+		// the object was deleted at the requested version
+		return &resource.BackendReadResponse{
+			Error: resource.NewNotFoundError(req.Key),
+		}
+	}
 
 	return res
 }
@@ -663,22 +736,98 @@ func fetchLatestRV(ctx context.Context, x db.ContextExecer, d sqltemplate.Dialec
 func (b *backend) poll(ctx context.Context, grp string, res string, since int64, stream chan<- *resource.WrittenEvent) (int64, error) {
 	ctx, span := b.tracer.Start(ctx, tracePrefix+"poll")
 	defer span.End()
+
+	return b.listHistorySince(ctx, resource.NamespacedResource{Group: grp, Resource: res}, since, func(evt *resource.WrittenEvent) error {
+		stream <- evt
+		return nil
+	})
+}
+
+// ListEventsSince implements resource.ResourceEventLister.
+func (b *backend) ListEventsSince(ctx context.Context, key resource.NamespacedResource, since int64, cb func(*resource.WrittenEvent) error) (int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"ListEventsSince")
+	defer span.End()
+
+	if err := b.checkNotCompacted(ctx, key, since); err != nil {
+		return 0, err
+	}
+	rv, err := b.listHistorySince(ctx, key, since, cb)
+	if err != nil {
+		return rv, err
+	}
+	// The history may have been compacted while it was read
+	return rv, b.checkNotCompacted(ctx, key, since)
+}
+
+// checkNotCompacted returns resource.ErrHistoryCompacted when history entries after since were
+// removed by the compactor.  The whole history is requested when since is zero.
+func (b *backend) checkNotCompacted(ctx context.Context, key resource.NamespacedResource, since int64) error {
+	if since <= 0 {
+		return nil
+	}
+	res, err := dbutil.QueryRow(ctx, b.db, sqlResourceVersionCompactedGet, sqlResourceVersionCompactedGetRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		Group:       key.Group,
+		Resource:    key.Resource,
+		Response:    new(compactedVersionResponse),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get compacted resource version: %w", err)
+	}
+	if res.CompactedRV != nil && *res.CompactedRV > since {
+		return resource.ErrHistoryCompacted
+	}
+	return nil
+}
+
+// listHistorySince calls cb for each history entry after since, ordered by resource version.
+// The history is read in pages of historyPageSize entries.  The namespace of the key is optional.
+func (b *backend) listHistorySince(ctx context.Context, key resource.NamespacedResource, since int64, cb func(*resource.WrittenEvent) error) (int64, error) {
+	var nextRV int64
+	for {
+		records, err := b.listHistoryPage(ctx, key, since)
+		if err != nil {
+			return nextRV, err
+		}
+		rv, err := emitHistory(records, cb)
+		if rv > 0 {
+			nextRV = rv
+		}
+		if err != nil {
+			return nextRV, err
+		}
+		if len(records) < historyPageSize {
+			return nextRV, nil
+		}
+		since = nextRV
+	}
+}
+
+func (b *backend) listHistoryPage(ctx context.Context, key resource.NamespacedResource, since int64) ([]*historyPollResponse, error) {
 	var records []*historyPollResponse
 	err := b.db.WithTx(ctx, ReadCommittedRO, func(ctx context.Context, tx db.Tx) error {
 		var err error
 		records, err = dbutil.Query(ctx, tx, sqlResourceHistoryPoll, &sqlResourceHistoryPollRequest{
 			SQLTemplate:          sqltemplate.New(b.dialect),
-			Resource:             res,
-			Group:                grp,
+			Resource:             key.Resource,
+			Group:                key.Group,
+			Namespace:            key.Namespace,
 			SinceResourceVersion: since,
+			Limit:                historyPageSize,
 			Response:             &historyPollResponse{},
 		})
 		return err
 	})
 	if err != nil {
-		return 0, fmt.Errorf("poll history: %w", err)
+		return nil, fmt.Errorf("poll history: %w", err)
 	}
+	return records, nil
+}
 
+// emitHistory calls cb for each record and returns the resource version of the last record it was called for
+func emitHistory(records []*historyPollResponse, cb func(*resource.WrittenEvent) error) (int64, error) {
 	var nextRV int64
 	for _, rec := range records {
 		if rec.Key.Group == "" || rec.Key.Resource == "" || rec.Key.Name == "" {
@@ -689,7 +838,7 @@ func (b *backend) poll(ctx context.Context, grp string, res string, since int64,
 		if prevRV == nil {
 			prevRV = new(int64)
 		}
-		stream <- &resource.WrittenEvent{
+		err := cb(&resource.WrittenEvent{
 			WriteEvent: resource.WriteEvent{
 				Value: rec.Value,
 				Key: &resource.ResourceKey{
@@ -704,6 +853,9 @@ func (b *backend) poll(ctx context.Context, grp string, res string, since int64,
 			Folder:          rec.Folder,
 			ResourceVersion: rec.ResourceVersion,
 			// Timestamp:  , // TODO: add timestamp
+		})
+		if err != nil {
+			return nextRV, err
 		}
 	}
 
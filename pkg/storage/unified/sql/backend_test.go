@@ -592,3 +592,119 @@ func TestBackend_delete(t *testing.T) {
 		require.ErrorContains(t, err, "update history rv")
 	})
 }
+
+func TestBackend_WriteEvents(t *testing.T) {
+	t.Parallel()
+	meta, err := utils.MetaAccessor(&unstructured.Unstructured{
+		Object: map[string]any{},
+	})
+	require.NoError(t, err)
+	events := []resource.WriteEvent{
+		{Type: resource.WatchEvent_ADDED, Key: resKey, Object: meta},
+		{Type: resource.WatchEvent_DELETED, Key: resKey, Object: meta},
+	}
+
+	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("insert resource", 0, 1)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		expectSuccessfulResourceVersionAtomicInc(t, b)
+		b.ExecWithResult("update resource_history", 0, 1)
+		b.ExecWithResult("update resource", 0, 1)
+		b.ExecWithResult("delete resource", 0, 1)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		expectSuccessfulResourceVersionAtomicInc(t, b)
+		b.ExecWithResult("update resource_history", 0, 1)
+		b.SQLMock.ExpectCommit()
+
+		rvs, err := b.WriteEvents(ctx, events)
+		require.NoError(t, err)
+		require.Equal(t, []int64{23456, 23456}, rvs)
+	})
+
+	t.Run("rolls back all events on error", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("insert resource", 0, 1)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		expectSuccessfulResourceVersionAtomicInc(t, b)
+		b.ExecWithResult("update resource_history", 0, 1)
+		b.ExecWithResult("update resource", 0, 1)
+		b.ExecWithErr("delete resource", errTest)
+		b.SQLMock.ExpectRollback()
+
+		rvs, err := b.WriteEvents(ctx, events)
+		require.Nil(t, rvs)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "delete resource:")
+	})
+}
+
+func TestBackend_ListEventsSince(t *testing.T) {
+	t.Parallel()
+	key := resource.NamespacedResource{Group: "gr", Resource: "rs"}
+	historyRows := func(first, count int64) Rows {
+		rows := Rows{}
+		for rv := first; rv < first+count; rv++ {
+			rows = append(rows, []driver.Value{rv, "ns", "gr", "rs", "nm", "", []byte("{}"), int64(resource.WatchEvent_MODIFIED), nil})
+		}
+		return rows
+	}
+
+	t.Run("reads the history in pages", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select compacted_resource_version", 1, Rows{{nil}})
+		b.SQLMock.ExpectBegin()
+		b.QueryWithResult("select resource_history", 9, historyRows(11, historyPageSize))
+		b.SQLMock.ExpectCommit()
+		b.SQLMock.ExpectBegin()
+		b.QueryWithResult("select resource_history", 9, historyRows(11+historyPageSize, 1))
+		b.SQLMock.ExpectCommit()
+		b.QueryWithResult("select compacted_resource_version", 1, Rows{{nil}})
+
+		var count int
+		rv, err := b.ListEventsSince(ctx, key, 10, func(evt *resource.WrittenEvent) error {
+			count++
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, historyPageSize+1, count)
+		require.Equal(t, int64(11+historyPageSize), rv)
+	})
+
+	t.Run("history compacted after the resource version", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select compacted_resource_version", 1, Rows{{int64(20)}})
+
+		_, err := b.ListEventsSince(ctx, key, 10, func(evt *resource.WrittenEvent) error {
+			return nil
+		})
+		require.ErrorIs(t, err, resource.ErrHistoryCompacted)
+	})
+
+	t.Run("history compacted before the resource version", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select compacted_resource_version", 1, Rows{{int64(5)}})
+		b.SQLMock.ExpectBegin()
+		b.QueryWithResult("select resource_history", 9, historyRows(11, 1))
+		b.SQLMock.ExpectCommit()
+		b.QueryWithResult("select compacted_resource_version", 1, Rows{{int64(5)}})
+
+		rv, err := b.ListEventsSince(ctx, key, 10, func(evt *resource.WrittenEvent) error {
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(11), rv)
+	})
+}
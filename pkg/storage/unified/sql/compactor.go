@@ -0,0 +1,300 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/dbutil"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+)
+
+const (
+	// Versions newer than this are never removed, so watchers that are behind still see them
+	compactionMinAge = time.Minute
+
+	// The number of history rows removed in each transaction
+	compactionBatchSize = 100
+
+	// The number of history rows read in each query
+	compactionPageSize = 1000
+)
+
+// HistoryRetention limits the history kept for a resource.  A version is removed when it is
+// neither one of the newest KeepVersions nor newer than KeepDuration.  The current value of an
+// object is never removed.
+type HistoryRetention struct {
+	// Keep the newest versions of each object, zero keeps all versions
+	KeepVersions int
+
+	// Keep the versions newer than this, zero keeps all versions
+	KeepDuration time.Duration
+
+	// The history of deleted objects is removed once the deletion is older than this, only the
+	// deletion is kept so it is still replayed.  When zero, deleted objects follow the rules above
+	KeepDeletions time.Duration
+}
+
+func (r HistoryRetention) enabled() bool {
+	return r.KeepVersions > 0 || r.KeepDuration > 0 || r.KeepDeletions > 0
+}
+
+type compactorMetrics struct {
+	ReclaimedRows *prometheus.CounterVec
+	Duration      *prometheus.HistogramVec
+}
+
+func newCompactorMetrics(reg prometheus.Registerer) *compactorMetrics {
+	m := &compactorMetrics{
+		ReclaimedRows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "storage_server",
+			Name:      "history_compacted_rows_total",
+			Help:      "Number of history rows removed by the retention policy",
+		}, []string{"group", "resource"}),
+		Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "storage_server",
+			Name:      "history_compaction_duration_seconds",
+			Help:      "Time (in seconds) spent compacting the history of a resource",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"group", "resource"}),
+	}
+	if reg != nil {
+		m.ReclaimedRows = registerOrExisting(reg, m.ReclaimedRows)
+		m.Duration = registerOrExisting(reg, m.Duration)
+	}
+	return m
+}
+
+// registerOrExisting returns the registered collector when several backends share a registerer
+func registerOrExisting[T prometheus.Collector](reg prometheus.Registerer, c T) T {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+	}
+	return c
+}
+
+// compactor removes the history rows that are no longer kept by the retention policies
+func (b *backend) compactor() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-b.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	t := time.NewTicker(b.compactionInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-t.C:
+			if _, err := b.compactHistory(ctx); err != nil {
+				b.log.Error("history compaction failed", "err", err)
+			}
+		}
+	}
+}
+
+// compactHistory applies the retention policies once and returns the number of removed rows
+func (b *backend) compactHistory(ctx context.Context) (int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"compactHistory")
+	defer span.End()
+
+	latest, err := b.listLatestRVs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get the latest resource version: %w", err)
+	}
+
+	var total int64
+	for group, items := range latest {
+		for res, rv := range items {
+			policy, ok := b.retention[res+"."+group]
+			if !ok || !policy.enabled() {
+				continue
+			}
+			start := time.Now()
+			count, err := b.compactResourceHistory(ctx, resource.NamespacedResource{Group: group, Resource: res}, rv, policy)
+			total += count
+			b.compactorMetrics.ReclaimedRows.WithLabelValues(group, res).Add(float64(count))
+			b.compactorMetrics.Duration.WithLabelValues(group, res).Observe(time.Since(start).Seconds())
+			if err != nil {
+				return total, fmt.Errorf("compact %s.%s: %w", res, group, err)
+			}
+			if count > 0 {
+				b.log.Info("compacted history", "group", group, "resource", res, "rows", count)
+			}
+		}
+	}
+	return total, nil
+}
+
+// compactResourceHistory removes the expired history of one resource.  The resource versions
+// are microsecond timestamps, so the versions are compared with the current time.  The history
+// is read in pages of compactionPageSize rows.
+func (b *backend) compactResourceHistory(ctx context.Context, key resource.NamespacedResource, latestRV int64, policy HistoryRetention) (int64, error) {
+	c := newHistoryCompaction(time.Now().UnixMicro(), policy)
+	var count int64
+	var after *historyVersion
+	for {
+		var versions []historyVersion
+		err := b.db.WithTx(ctx, ReadCommittedRO, func(ctx context.Context, tx db.Tx) error {
+			req := &sqlResourceHistoryVersionsRequest{
+				SQLTemplate:        sqltemplate.New(b.dialect),
+				Group:              key.Group,
+				Resource:           key.Resource,
+				MaxResourceVersion: latestRV,
+				Limit:              compactionPageSize,
+				Response:           new(historyVersion),
+			}
+			if after != nil {
+				req.AfterNamespace = after.Namespace
+				req.AfterName = after.Name
+				req.AfterResourceVersion = after.ResourceVersion
+			}
+			var err error
+			versions, err = dbutil.Query(ctx, tx, sqlResourceHistoryVersions, req)
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("list history: %w", err)
+		}
+
+		var expired []historyVersion
+		for _, v := range versions {
+			if c.expired(v) {
+				expired = append(expired, v)
+			}
+		}
+		removed, err := b.deleteHistoryVersions(ctx, key, expired)
+		count += removed
+		if err != nil {
+			return count, err
+		}
+
+		if len(versions) < compactionPageSize {
+			return count, nil
+		}
+		after = &versions[len(versions)-1]
+	}
+}
+
+// deleteHistoryVersions removes the versions in batches of compactionBatchSize rows, and records
+// the highest removed resource version so readers replaying the history know it is incomplete
+func (b *backend) deleteHistoryVersions(ctx context.Context, key resource.NamespacedResource, versions []historyVersion) (int64, error) {
+	var count int64
+	for len(versions) > 0 {
+		batch := versions[:min(compactionBatchSize, len(versions))]
+		versions = versions[len(batch):]
+		guids := make([]string, 0, len(batch))
+		var compactedRV int64
+		for _, v := range batch {
+			guids = append(guids, v.GUID)
+			compactedRV = max(compactedRV, v.ResourceVersion)
+		}
+		err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+			res, err := dbutil.Exec(ctx, tx, sqlResourceHistoryDelete, sqlResourceHistoryDeleteRequest{
+				SQLTemplate: sqltemplate.New(b.dialect),
+				GUIDs:       guids,
+			})
+			if err != nil {
+				return err
+			}
+			rows, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			count += rows
+			_, err = dbutil.Exec(ctx, tx, sqlResourceVersionCompactedUpdate, sqlResourceVersionUpsertRequest{
+				SQLTemplate:     sqltemplate.New(b.dialect),
+				Group:           key.Group,
+				Resource:        key.Resource,
+				ResourceVersion: compactedRV,
+			})
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("delete history: %w", err)
+		}
+	}
+	return count, nil
+}
+
+// historyCompaction decides which history rows are not kept by the policy.  The rows must be
+// passed sorted by object with the newest version of each object first, they may span pages.
+type historyCompaction struct {
+	now    int64
+	policy HistoryRetention
+
+	prev            *historyVersion
+	rank            int
+	expiredDeletion bool
+}
+
+func newHistoryCompaction(now int64, policy HistoryRetention) *historyCompaction {
+	return &historyCompaction{now: now, policy: policy}
+}
+
+func (c *historyCompaction) before(d time.Duration) int64 {
+	return c.now - d.Microseconds()
+}
+
+func (c *historyCompaction) expired(v historyVersion) bool {
+	if c.prev == nil || v.Namespace != c.prev.Namespace || v.Name != c.prev.Name {
+		c.rank = 0
+		c.expiredDeletion = c.policy.KeepDeletions > 0 &&
+			v.Action == int(resource.WatchEvent_DELETED) &&
+			v.ResourceVersion < c.before(c.policy.KeepDeletions)
+	} else {
+		c.rank++
+	}
+	c.prev = &v
+
+	if v.ResourceVersion >= c.before(compactionMinAge) {
+		return false
+	}
+	if c.rank == 0 {
+		// The current value, or the deletion which must still be replayed to remove the object
+		return false
+	}
+	if c.expiredDeletion {
+		return true
+	}
+	if c.policy.KeepVersions == 0 && c.policy.KeepDuration == 0 {
+		return false
+	}
+	if c.policy.KeepVersions > 0 && c.rank < c.policy.KeepVersions {
+		return false
+	}
+	if c.policy.KeepDuration > 0 && v.ResourceVersion >= c.before(c.policy.KeepDuration) {
+		return false
+	}
+	return true
+}
+
+// expiredVersions returns the history rows which are not kept by the policy.  The versions
+// must be sorted by object with the newest version of each object first.
+func expiredVersions(versions []historyVersion, now int64, policy HistoryRetention) []string {
+	c := newHistoryCompaction(now, policy)
+	var guids []string
+	for _, v := range versions {
+		if c.expired(v) {
+			guids = append(guids, v.GUID)
+		}
+	}
+	return guids
+}
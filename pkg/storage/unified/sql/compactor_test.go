@@ -0,0 +1,162 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+)
+
+func TestExpiredVersions(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC)
+	rv := func(age time.Duration) int64 {
+		return now.Add(-age).UnixMicro()
+	}
+	day := 24 * time.Hour
+
+	// newest version of each object first
+	versions := []historyVersion{
+		{GUID: "a1", Namespace: "ns", Name: "a", ResourceVersion: rv(time.Second), Action: int(resource.WatchEvent_MODIFIED)},
+		{GUID: "a2", Namespace: "ns", Name: "a", ResourceVersion: rv(30 * time.Second), Action: int(resource.WatchEvent_MODIFIED)},
+		{GUID: "a3", Namespace: "ns", Name: "a", ResourceVersion: rv(2 * day), Action: int(resource.WatchEvent_MODIFIED)},
+		{GUID: "a4", Namespace: "ns", Name: "a", ResourceVersion: rv(10 * day), Action: int(resource.WatchEvent_ADDED)},
+		{GUID: "b1", Namespace: "ns", Name: "b", ResourceVersion: rv(40 * day), Action: int(resource.WatchEvent_DELETED)},
+		{GUID: "b2", Namespace: "ns", Name: "b", ResourceVersion: rv(50 * day), Action: int(resource.WatchEvent_ADDED)},
+		{GUID: "c1", Namespace: "ns", Name: "c", ResourceVersion: rv(3 * day), Action: int(resource.WatchEvent_DELETED)},
+		{GUID: "c2", Namespace: "ns", Name: "c", ResourceVersion: rv(5 * day), Action: int(resource.WatchEvent_ADDED)},
+		{GUID: "d1", Namespace: "other", Name: "a", ResourceVersion: rv(20 * day), Action: int(resource.WatchEvent_ADDED)},
+	}
+
+	tests := []struct {
+		name     string
+		policy   HistoryRetention
+		expected []string
+	}{
+		{
+			name:   "no policy",
+			policy: HistoryRetention{},
+		},
+		{
+			name:     "keep versions",
+			policy:   HistoryRetention{KeepVersions: 2},
+			expected: []string{"a3", "a4"},
+		},
+		{
+			name:     "keep one version",
+			policy:   HistoryRetention{KeepVersions: 1},
+			expected: []string{"a3", "a4", "b2", "c2"}, // a2 is too new
+		},
+		{
+			name:     "keep duration",
+			policy:   HistoryRetention{KeepDuration: 7 * day},
+			expected: []string{"a4", "b2"},
+		},
+		{
+			name:     "keep versions and duration",
+			policy:   HistoryRetention{KeepVersions: 3, KeepDuration: 7 * day},
+			expected: []string{"a4"},
+		},
+		{
+			name:     "keep deletions",
+			policy:   HistoryRetention{KeepDeletions: 30 * day},
+			expected: []string{"b2"}, // the deletion is kept
+		},
+		{
+			name:     "keep deletions and versions",
+			policy:   HistoryRetention{KeepDeletions: day, KeepVersions: 2},
+			expected: []string{"a3", "a4", "b2", "c2"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, expiredVersions(versions, now.UnixMicro(), tc.policy))
+		})
+	}
+
+	t.Run("versions of an object split across pages", func(t *testing.T) {
+		policy := HistoryRetention{KeepVersions: 2}
+		c := newHistoryCompaction(now.UnixMicro(), policy)
+		var expired []string
+		for _, page := range [][]historyVersion{versions[:2], versions[2:]} {
+			for _, v := range page {
+				if c.expired(v) {
+					expired = append(expired, v.GUID)
+				}
+			}
+		}
+		require.Equal(t, expiredVersions(versions, now.UnixMicro(), policy), expired)
+	})
+}
+
+func TestBackend_compactResourceHistory(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	key := resource.NamespacedResource{Group: "gr", Resource: "rs"}
+	policy := HistoryRetention{KeepVersions: 1}
+	rows := Rows{
+		{"g1", "ns", "nm", now.Add(-time.Hour).UnixMicro(), 2},
+		{"g2", "ns", "nm", now.Add(-2 * time.Hour).UnixMicro(), 1},
+	}
+
+	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.QueryWithResult("select resource_history", 5, rows)
+		b.SQLMock.ExpectCommit()
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("delete resource_history", 0, 1)
+		b.ExecWithResult("update resource_version", 0, 1)
+		b.SQLMock.ExpectCommit()
+
+		count, err := b.compactResourceHistory(ctx, key, now.UnixMicro(), policy)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("reads the history in pages", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		page := Rows{}
+		for i := 0; i < compactionPageSize; i++ {
+			page = append(page, []driver.Value{fmt.Sprintf("g%d", i), "ns", fmt.Sprintf("nm%04d", i), now.Add(-time.Hour).UnixMicro(), 1})
+		}
+		b.SQLMock.ExpectBegin()
+		b.QueryWithResult("select resource_history", 5, page)
+		b.SQLMock.ExpectCommit()
+		b.SQLMock.ExpectBegin()
+		b.QueryWithResult("select resource_history", 5, rows)
+		b.SQLMock.ExpectCommit()
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("delete resource_history", 0, 1)
+		b.ExecWithResult("update resource_version", 0, 1)
+		b.SQLMock.ExpectCommit()
+
+		count, err := b.compactResourceHistory(ctx, key, now.UnixMicro(), policy)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("error deleting history", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.QueryWithResult("select resource_history", 5, rows)
+		b.SQLMock.ExpectCommit()
+		b.SQLMock.ExpectBegin()
+		b.ExecWithErr("delete resource_history", errTest)
+		b.SQLMock.ExpectRollback()
+
+		count, err := b.compactResourceHistory(ctx, key, now.UnixMicro(), policy)
+		require.Zero(t, count)
+		require.ErrorContains(t, err, "delete history:")
+	})
+}
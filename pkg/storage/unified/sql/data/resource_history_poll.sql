@@ -13,6 +13,12 @@ SELECT
     WHERE 1 = 1
     AND {{ .Ident "group" }} = {{ .Arg .Group }}
     AND {{ .Ident "resource" }} = {{ .Arg .Resource }}
+    {{ if .Namespace }}
+    AND {{ .Ident "namespace" }} = {{ .Arg .Namespace }}
+    {{ end }}
     AND {{ .Ident "resource_version" }} > {{ .Arg .SinceResourceVersion }}
     ORDER BY {{ .Ident "resource_version" }} ASC
+    {{ if (gt .Limit 0) }}
+    LIMIT {{ .Arg .Limit }}
+    {{ end }}
 ;
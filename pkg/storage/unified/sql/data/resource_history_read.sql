@@ -5,7 +5,8 @@ SELECT
     {{ .Ident "name" | .Into .Response.Key.Name }},
     {{ .Ident "folder" | .Into .Response.Folder }},
     {{ .Ident "resource_version" | .Into .Response.ResourceVersion }},
-    {{ .Ident "value" | .Into .Response.Value }}
+    {{ .Ident "value" | .Into .Response.Value }},
+    {{ .Ident "action" | .Into .Action }}
 
     FROM {{ .Ident "resource_history" }}
 
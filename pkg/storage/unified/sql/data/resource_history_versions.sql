@@ -0,0 +1,24 @@
+SELECT
+    {{ .Ident "guid" | .Into .Response.GUID }},
+    {{ .Ident "namespace" | .Into .Response.Namespace }},
+    {{ .Ident "name" | .Into .Response.Name }},
+    {{ .Ident "resource_version" | .Into .Response.ResourceVersion }},
+    {{ .Ident "action" | .Into .Response.Action }}
+
+    FROM {{ .Ident "resource_history" }}
+    WHERE 1 = 1
+    AND {{ .Ident "group" }} = {{ .Arg .Group }}
+    AND {{ .Ident "resource" }} = {{ .Arg .Resource }}
+    AND {{ .Ident "resource_version" }} <= {{ .Arg .MaxResourceVersion }}
+    {{ if .AfterName }}
+    AND (
+        {{ .Ident "namespace" }} > {{ .Arg .AfterNamespace }}
+        OR ({{ .Ident "namespace" }} = {{ .Arg .AfterNamespace }} AND {{ .Ident "name" }} > {{ .Arg .AfterName }})
+        OR ({{ .Ident "namespace" }} = {{ .Arg .AfterNamespace }} AND {{ .Ident "name" }} = {{ .Arg .AfterName }} AND {{ .Ident "resource_version" }} < {{ .Arg .AfterResourceVersion }})
+    )
+    {{ end }}
+    ORDER BY {{ .Ident "namespace" }} ASC, {{ .Ident "name" }} ASC, {{ .Ident "resource_version" }} DESC
+    {{ if (gt .Limit 0) }}
+    LIMIT {{ .Arg .Limit }}
+    {{ end }}
+;
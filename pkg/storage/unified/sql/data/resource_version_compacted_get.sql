@@ -0,0 +1,7 @@
+SELECT
+        {{ .Ident "compacted_resource_version" | .Into .Response.CompactedRV }}
+    FROM {{ .Ident "resource_version" }}
+    WHERE 1 = 1
+        AND {{ .Ident "group" }}    = {{ .Arg .Group }}
+        AND {{ .Ident "resource" }} = {{ .Arg .Resource }}
+;
@@ -0,0 +1,11 @@
+UPDATE {{ .Ident "resource_version" }}
+SET
+    {{ .Ident "compacted_resource_version" }} = {{ .Arg .ResourceVersion }}
+WHERE 1 = 1
+    AND {{ .Ident "group" }}    = {{ .Arg .Group }}
+    AND {{ .Ident "resource" }} = {{ .Arg .Resource }}
+    AND (
+        {{ .Ident "compacted_resource_version" }} IS NULL
+        OR {{ .Ident "compacted_resource_version" }} < {{ .Arg .ResourceVersion }}
+    )
+;
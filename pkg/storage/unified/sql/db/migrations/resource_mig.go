@@ -121,5 +121,9 @@ func initResourceTables(mg *migrator.Migrator) string {
 		Name: "folder", Type: migrator.DB_NVarchar, Length: 253, Nullable: false, Default: "''",
 	}))
 
+	mg.AddMigration("Add column compacted_resource_version in resource_version", migrator.NewAddColumnMigration(migrator.Table{Name: "resource_version"}, &migrator.Column{
+		Name: "compacted_resource_version", Type: migrator.DB_BigInt, Nullable: true,
+	}))
+
 	return marker
 }
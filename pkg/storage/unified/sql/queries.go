@@ -39,12 +39,17 @@ var (
 	sqlResourceHistoryUpdateRV = mustTemplate("resource_history_update_rv.sql")
 	sqlResourceHistoryInsert   = mustTemplate("resource_history_insert.sql")
 	sqlResourceHistoryPoll     = mustTemplate("resource_history_poll.sql")
+	sqlResourceHistoryVersions = mustTemplate("resource_history_versions.sql")
+	sqlResourceHistoryDelete   = mustTemplate("resource_history_delete.sql")
 
 	// sqlResourceLabelsInsert = mustTemplate("resource_labels_insert.sql")
 	sqlResourceVersionGet    = mustTemplate("resource_version_get.sql")
 	sqlResourceVersionUpdate = mustTemplate("resource_version_update.sql")
 	sqlResourceVersionInsert = mustTemplate("resource_version_insert.sql")
 	sqlResourceVersionList   = mustTemplate("resource_version_list.sql")
+
+	sqlResourceVersionCompactedGet    = mustTemplate("resource_version_compacted_get.sql")
+	sqlResourceVersionCompactedUpdate = mustTemplate("resource_version_compacted_update.sql")
 )
 
 // TxOptions.
@@ -101,11 +106,59 @@ func (r *historyPollResponse) Results() (*historyPollResponse, error) {
 
 type groupResourceRV map[string]map[string]int64
 
+// historyVersion is a row of the history without the value
+type historyVersion struct {
+	GUID            string
+	Namespace       string
+	Name            string
+	ResourceVersion int64
+	Action          int
+}
+
+// sqlResourceHistoryVersionsRequest lists the history of a resource, newest version
+// of each object first
+type sqlResourceHistoryVersionsRequest struct {
+	sqltemplate.SQLTemplate
+	Group              string
+	Resource           string
+	MaxResourceVersion int64
+	Limit              int64 // optional
+
+	// Continue after this version, the versions are sorted by object and newest version first
+	AfterNamespace       string
+	AfterName            string
+	AfterResourceVersion int64
+
+	Response *historyVersion
+}
+
+func (r *sqlResourceHistoryVersionsRequest) Validate() error {
+	return nil // TODO
+}
+
+func (r *sqlResourceHistoryVersionsRequest) Results() (historyVersion, error) {
+	return *r.Response, nil
+}
+
+type sqlResourceHistoryDeleteRequest struct {
+	sqltemplate.SQLTemplate
+	GUIDs []string
+}
+
+func (r sqlResourceHistoryDeleteRequest) Validate() error {
+	if len(r.GUIDs) == 0 {
+		return fmt.Errorf("missing guids")
+	}
+	return nil
+}
+
 type sqlResourceHistoryPollRequest struct {
 	sqltemplate.SQLTemplate
 	Resource             string
 	Group                string
+	Namespace            string // optional
 	SinceResourceVersion int64
+	Limit                int64 // optional
 	Response             *historyPollResponse
 }
 
@@ -144,6 +197,7 @@ type sqlResourceReadRequest struct {
 	sqltemplate.SQLTemplate
 	Request  *resource.ReadRequest
 	Response *resource.BackendReadResponse
+	Action   int // only read from the history
 }
 
 func (r *sqlResourceReadRequest) Validate() error {
@@ -245,6 +299,26 @@ func (r sqlResourceVersionGetRequest) Results() (*resourceVersionResponse, error
 	}, nil
 }
 
+// compactedVersionResponse is the highest resource version removed from the history by
+// the compactor, nil when the history was never compacted
+type compactedVersionResponse struct {
+	CompactedRV *int64
+}
+
+type sqlResourceVersionCompactedGetRequest struct {
+	sqltemplate.SQLTemplate
+	Group, Resource string
+	Response        *compactedVersionResponse
+}
+
+func (r sqlResourceVersionCompactedGetRequest) Validate() error {
+	return nil // TODO
+}
+
+func (r sqlResourceVersionCompactedGetRequest) Results() (*compactedVersionResponse, error) {
+	return &compactedVersionResponse{CompactedRV: r.Response.CompactedRV}, nil
+}
+
 type sqlResourceVersionListRequest struct {
 	sqltemplate.SQLTemplate
 	*groupResourceVersion
@@ -127,6 +127,28 @@ func TestUnifiedStorageQueries(t *testing.T) {
 						Response:             new(historyPollResponse),
 					},
 				},
+				{
+					Name: "namespace",
+					Data: &sqlResourceHistoryPollRequest{
+						SQLTemplate:          mocks.NewTestingSQLTemplate(),
+						Resource:             "res",
+						Group:                "group",
+						Namespace:            "ns",
+						SinceResourceVersion: 1234,
+						Response:             new(historyPollResponse),
+					},
+				},
+				{
+					Name: "limit",
+					Data: &sqlResourceHistoryPollRequest{
+						SQLTemplate:          mocks.NewTestingSQLTemplate(),
+						Resource:             "res",
+						Group:                "group",
+						SinceResourceVersion: 1234,
+						Limit:                100,
+						Response:             new(historyPollResponse),
+					},
+				},
 			},
 
 			sqlResourceUpdateRV: {
@@ -138,6 +160,43 @@ func TestUnifiedStorageQueries(t *testing.T) {
 				},
 			},
 
+			sqlResourceHistoryVersions: {
+				{
+					Name: "single path",
+					Data: &sqlResourceHistoryVersionsRequest{
+						SQLTemplate:        mocks.NewTestingSQLTemplate(),
+						Group:              "group",
+						Resource:           "res",
+						MaxResourceVersion: 1234,
+						Response:           new(historyVersion),
+					},
+				},
+				{
+					Name: "page",
+					Data: &sqlResourceHistoryVersionsRequest{
+						SQLTemplate:          mocks.NewTestingSQLTemplate(),
+						Group:                "group",
+						Resource:             "res",
+						MaxResourceVersion:   1234,
+						Limit:                100,
+						AfterNamespace:       "ns",
+						AfterName:            "name",
+						AfterResourceVersion: 1000,
+						Response:             new(historyVersion),
+					},
+				},
+			},
+
+			sqlResourceHistoryDelete: {
+				{
+					Name: "guids",
+					Data: &sqlResourceHistoryDeleteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						GUIDs:       []string{"a", "b"},
+					},
+				},
+			},
+
 			sqlResourceHistoryRead: {
 				{
 					Name: "single path",
@@ -210,6 +269,30 @@ func TestUnifiedStorageQueries(t *testing.T) {
 				},
 			},
 
+			sqlResourceVersionCompactedGet: {
+				{
+					Name: "single path",
+					Data: &sqlResourceVersionCompactedGetRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Resource:    "resource",
+						Group:       "group",
+						Response:    new(compactedVersionResponse),
+					},
+				},
+			},
+
+			sqlResourceVersionCompactedUpdate: {
+				{
+					Name: "single path",
+					Data: &sqlResourceVersionUpsertRequest{
+						SQLTemplate:     mocks.NewTestingSQLTemplate(),
+						Resource:        "resource",
+						Group:           "group",
+						ResourceVersion: int64(12354),
+					},
+				},
+			},
+
 			sqlResourceVersionInsert: {
 				{
 					Name: "single path",
@@ -47,7 +47,21 @@ func NewResourceServer(ctx context.Context, db infraDB.DB, cfg *setting.Cfg,
 	if err != nil {
 		return nil, err
 	}
-	store, err := NewBackend(BackendOptions{DBProvider: eDB, Tracer: tracer})
+	retention := make(map[string]HistoryRetention)
+	for name, c := range cfg.UnifiedStorage {
+		retention[name] = HistoryRetention{
+			KeepVersions:  c.HistoryKeepVersions,
+			KeepDuration:  c.HistoryKeepDuration,
+			KeepDeletions: c.HistoryKeepDeletions,
+		}
+	}
+	store, err := NewBackend(BackendOptions{
+		DBProvider:         eDB,
+		Tracer:             tracer,
+		Reg:                reg,
+		HistoryRetention:   retention,
+		CompactionInterval: cfg.HistoryCompactionInterval,
+	})
 	if err != nil {
 		return nil, err
 	}
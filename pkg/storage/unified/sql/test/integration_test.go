@@ -1,7 +1,9 @@
 package test
 
 import (
+	"bytes"
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -135,6 +137,23 @@ func TestIntegrationBackendHappyPath(t *testing.T) {
 		require.Equal(t, "item2 ADDED", string(resp.Value))
 	})
 
+	t.Run("Read item1 before and after it was deleted", func(t *testing.T) {
+		resp := backend.ReadResource(ctx, &resource.ReadRequest{
+			Key:             resourceKey("item1"),
+			ResourceVersion: rv4,
+		})
+		require.Nil(t, resp.Error)
+		require.Equal(t, rv1, resp.ResourceVersion)
+		require.Equal(t, "item1 ADDED", string(resp.Value))
+
+		resp = backend.ReadResource(ctx, &resource.ReadRequest{
+			Key:             resourceKey("item1"),
+			ResourceVersion: rv5,
+		})
+		require.NotNil(t, resp.Error)
+		require.Equal(t, int32(http.StatusNotFound), resp.Error.Code)
+	})
+
 	t.Run("PrepareList latest", func(t *testing.T) {
 		resp, err := server.List(ctx, &resource.ListRequest{
 			Options: &resource.ListOptions{
@@ -353,6 +372,81 @@ func TestIntegrationBackendList(t *testing.T) {
 		require.Equal(t, int64(4), continueToken.StartOffset)
 	})
 }
+func TestIntegrationNamespaceArchive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := testutil.NewTestContext(t, time.Now().Add(10*time.Second))
+	backend, _ := newServer(t, nil)
+
+	write := func(ns, name, title string, action resource.WatchEvent_Type, previousRV int64) int64 {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "group/v1",
+			"kind":       "Resource",
+			"metadata":   map[string]any{"name": name, "namespace": ns},
+			"spec":       map[string]any{"title": title},
+		}}
+		meta, err := utils.MetaAccessor(obj)
+		require.NoError(t, err)
+		value, err := obj.MarshalJSON()
+		require.NoError(t, err)
+		key := resourceKey(name)
+		key.Namespace = ns
+		rv, err := backend.WriteEvent(ctx, resource.WriteEvent{
+			Type:       action,
+			Value:      value,
+			Key:        key,
+			Object:     meta,
+			PreviousRV: previousRV,
+		})
+		require.NoError(t, err)
+		return rv
+	}
+	rv1 := write("source", "item1", "one", resource.WatchEvent_ADDED, 0)
+	rv2 := write("source", "item2", "two", resource.WatchEvent_ADDED, 0)
+	write("source", "item2", "two v2", resource.WatchEvent_MODIFIED, rv2)
+	write("source", "item1", "one", resource.WatchEvent_DELETED, rv1)
+
+	archive := &bytes.Buffer{}
+	manifest, err := resource.ExportNamespace(ctx, backend, resource.ExportOptions{
+		Namespace: "source",
+		Resources: []resource.NamespacedResource{{Group: "group", Resource: "resource"}},
+		History:   true,
+	}, archive)
+	require.NoError(t, err)
+	require.True(t, manifest.History)
+
+	summary, err := resource.ImportNamespace(ctx, backend, archive, resource.ImportOptions{
+		Namespace: "copy",
+		History:   true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, summary.Created)
+	require.Equal(t, 1, summary.Updated)
+	require.Equal(t, 1, summary.Deleted)
+
+	key := resourceKey("item1")
+	key.Namespace = "copy"
+	resp := backend.ReadResource(ctx, &resource.ReadRequest{Key: key})
+	require.NotNil(t, resp.Error)
+	require.Equal(t, int32(http.StatusNotFound), resp.Error.Code)
+
+	key = resourceKey("item2")
+	key.Namespace = "copy"
+	resp = backend.ReadResource(ctx, &resource.ReadRequest{Key: key})
+	require.Nil(t, resp.Error)
+	require.Contains(t, string(resp.Value), "two v2")
+
+	events := 0
+	_, err = backend.ListEventsSince(ctx, resource.NamespacedResource{Namespace: "copy", Group: "group", Resource: "resource"}, 0, func(*resource.WrittenEvent) error {
+		events++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, events)
+}
+
 func TestClientServer(t *testing.T) {
 	if infraDB.IsTestDbSQLite() {
 		t.Skip("TODO: test blocking, skipping to unblock Enterprise until we fix this")
@@ -0,0 +1,3 @@
+DELETE FROM `resource_history`
+    WHERE `guid` IN ('a', 'b')
+;
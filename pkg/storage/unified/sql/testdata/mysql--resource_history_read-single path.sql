@@ -5,7 +5,8 @@ SELECT
     `name`,
     `folder`,
     `resource_version`,
-    `value`
+    `value`,
+    `action`
     FROM `resource_history`
     WHERE 1 = 1
         AND `namespace` = 'ns'
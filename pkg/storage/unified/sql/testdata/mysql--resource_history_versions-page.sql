@@ -0,0 +1,19 @@
+SELECT
+    `guid`,
+    `namespace`,
+    `name`,
+    `resource_version`,
+    `action`
+    FROM `resource_history`
+    WHERE 1 = 1
+    AND `group` = 'group'
+    AND `resource` = 'res'
+    AND `resource_version` <= 1234
+    AND (
+        `namespace` > 'ns'
+        OR (`namespace` = 'ns' AND `name` > 'name')
+        OR (`namespace` = 'ns' AND `name` = 'name' AND `resource_version` < 1000)
+    )
+    ORDER BY `namespace` ASC, `name` ASC, `resource_version` DESC
+    LIMIT 100
+;
@@ -0,0 +1,7 @@
+SELECT
+        `compacted_resource_version`
+    FROM `resource_version`
+    WHERE 1 = 1
+        AND `group`    = 'group'
+        AND `resource` = 'resource'
+;
@@ -0,0 +1,13 @@
+SELECT
+    "guid",
+    "namespace",
+    "name",
+    "resource_version",
+    "action"
+    FROM "resource_history"
+    WHERE 1 = 1
+    AND "group" = 'group'
+    AND "resource" = 'res'
+    AND "resource_version" <= 1234
+    ORDER BY "namespace" ASC, "name" ASC, "resource_version" DESC
+;
@@ -0,0 +1,11 @@
+UPDATE "resource_version"
+SET
+    "compacted_resource_version" = 12354
+WHERE 1 = 1
+    AND "group"    = 'group'
+    AND "resource" = 'resource'
+    AND (
+        "compacted_resource_version" IS NULL
+        OR "compacted_resource_version" < 12354
+    )
+;
@@ -50,6 +50,7 @@ type ConfiguredFields struct {
 type Client interface {
 	GetConfiguredFields() ConfiguredFields
 	ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearchResponse, error)
+	ExecuteColumnarQuery(r *ColumnarQueryRequest) (*ColumnarQueryResponse, error)
 	MultiSearch() *MultiSearchRequestBuilder
 }
 
@@ -131,18 +132,16 @@ func (c *baseClientImpl) encodeBatchRequests(requests []*multiRequest) ([]byte,
 
 func (c *baseClientImpl) executeRequest(method, uriPath, uriQuery string, body []byte) (*http.Response, error) {
 	c.logger.Debug("Sending request to Elasticsearch", "url", c.ds.URL)
-	u, err := url.Parse(c.ds.URL)
+	u, err := c.requestURL(uriPath, uriQuery)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, uriPath)
-	u.RawQuery = uriQuery
 
 	var req *http.Request
 	if method == http.MethodPost {
-		req, err = http.NewRequestWithContext(c.ctx, http.MethodPost, u.String(), bytes.NewBuffer(body))
+		req, err = http.NewRequestWithContext(c.ctx, http.MethodPost, u, bytes.NewBuffer(body))
 	} else {
-		req, err = http.NewRequestWithContext(c.ctx, http.MethodGet, u.String(), nil)
+		req, err = http.NewRequestWithContext(c.ctx, http.MethodGet, u, nil)
 	}
 	if err != nil {
 		return nil, err
@@ -158,6 +157,16 @@ func (c *baseClientImpl) executeRequest(method, uriPath, uriQuery string, body [
 	return resp, nil
 }
 
+func (c *baseClientImpl) requestURL(uriPath, uriQuery string) (string, error) {
+	u, err := url.Parse(c.ds.URL)
+	if err != nil {
+		return "", err
+	}
+	u.Path = path.Join(u.Path, uriPath)
+	u.RawQuery = uriQuery
+	return u.String(), nil
+}
+
 func (c *baseClientImpl) ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearchResponse, error) {
 	var err error
 	multiRequests := c.createMultiSearchRequests(r.Requests)
@@ -325,3 +325,60 @@ func createMultisearchWithMultipleQueriesForTest(t *testing.T, c Client, firstTi
 
 	return msb.Build()
 }
+
+func TestClient_ExecuteColumnarQuery(t *testing.T) {
+	var request *http.Request
+	var requestBody []byte
+	responses := map[string]string{
+		"/_query":        `{"columns":[{"name":"@timestamp","type":"date"},{"name":"count","type":"long"}],"values":[["2018-05-15T17:50:00.000Z",3]]}`,
+		"/_plugins/_ppl": `{"schema":[{"name":"@timestamp","type":"timestamp"},{"name":"count","type":"integer"}],"datarows":[["2018-05-15 17:50:00",3]],"total":1,"size":1,"status":200}`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		request = r
+		buf, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requestBody = buf
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, err = rw.Write([]byte(responses[r.URL.Path]))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(ts.Close)
+
+	ds := DatasourceInfo{
+		URL:        ts.URL,
+		HTTPClient: ts.Client(),
+		Database:   "metrics",
+	}
+	c, err := NewClient(context.Background(), &ds, log.New())
+	require.NoError(t, err)
+
+	for _, language := range []string{QueryLanguageESQL, QueryLanguagePPL} {
+		t.Run(language, func(t *testing.T) {
+			res, err := c.ExecuteColumnarQuery(&ColumnarQueryRequest{
+				Language: language,
+				Query:    "FROM metrics | STATS count = COUNT(*)",
+				Filter:   &Query{Bool: &BoolQuery{Filters: []Filter{&RangeFilter{Key: "@timestamp", Gte: 1, Lte: 2}}}},
+			})
+			require.NoError(t, err)
+
+			require.Equal(t, http.MethodPost, request.Method)
+			require.Equal(t, columnarQueryPaths[language], request.URL.Path[1:])
+			body, err := simplejson.NewJson(requestBody)
+			require.NoError(t, err)
+			require.Equal(t, "FROM metrics | STATS count = COUNT(*)", body.Get("query").MustString())
+			require.Equal(t, int64(1), body.GetPath("filter", "bool", "filter", "range", "@timestamp", "gte").MustInt64())
+
+			require.Equal(t, 200, res.Status)
+			require.Equal(t, []ColumnarQueryColumn{{Name: "@timestamp", Type: res.Columns[0].Type}, {Name: "count", Type: res.Columns[1].Type}}, res.Columns)
+			require.Len(t, res.Values, 1)
+			require.Equal(t, float64(3), res.Values[0][1])
+		})
+	}
+
+	t.Run("unsupported language", func(t *testing.T) {
+		_, err := c.ExecuteColumnarQuery(&ColumnarQueryRequest{Language: "sql", Query: "SELECT 1"})
+		require.Error(t, err)
+	})
+}
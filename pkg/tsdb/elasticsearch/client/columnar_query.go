@@ -0,0 +1,160 @@
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/tracing"
+)
+
+// Query languages that return columnar results
+const (
+	QueryLanguageESQL = "esql"
+	QueryLanguagePPL  = "ppl"
+)
+
+var columnarQueryPaths = map[string]string{
+	// ES|QL endpoint of Elasticsearch 8.11+
+	QueryLanguageESQL: "_query",
+	// PPL endpoint of the OpenSearch SQL plugin
+	QueryLanguagePPL: "_plugins/_ppl",
+}
+
+// ColumnarQueryRequest represents an ES|QL or PPL query request
+type ColumnarQueryRequest struct {
+	Language string
+	Query    string
+	// Filter restricts the documents the query runs against, e.g. to the time range
+	Filter *Query
+}
+
+// MarshalJSON returns the JSON encoding of the request.
+func (r *ColumnarQueryRequest) MarshalJSON() ([]byte, error) {
+	root := map[string]any{
+		"query": r.Query,
+	}
+	if r.Filter != nil {
+		root["filter"] = r.Filter
+	}
+	return json.Marshal(root)
+}
+
+// ColumnarQueryColumn describes a column of a columnar query response
+type ColumnarQueryColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ColumnarQueryResponse represents the response of an ES|QL or PPL query.
+// ES|QL returns "columns" and "values" while PPL returns "schema" and
+// "datarows"; both are normalized into Columns and Values.
+type ColumnarQueryResponse struct {
+	Status  int                    `json:"status,omitempty"`
+	Error   map[string]interface{} `json:"error,omitempty"`
+	Columns []ColumnarQueryColumn  `json:"columns"`
+	Values  [][]interface{}        `json:"values"`
+}
+
+// UnmarshalJSON decodes both the ES|QL and the PPL response format.
+func (r *ColumnarQueryResponse) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Status   int                    `json:"status"`
+		Error    map[string]interface{} `json:"error"`
+		Columns  []ColumnarQueryColumn  `json:"columns"`
+		Values   [][]interface{}        `json:"values"`
+		Schema   []ColumnarQueryColumn  `json:"schema"`
+		Datarows [][]interface{}        `json:"datarows"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	r.Status = raw.Status
+	r.Error = raw.Error
+	r.Columns = raw.Columns
+	r.Values = raw.Values
+	if len(raw.Schema) > 0 {
+		r.Columns = raw.Schema
+		r.Values = raw.Datarows
+	}
+	return nil
+}
+
+func (c *baseClientImpl) ExecuteColumnarQuery(r *ColumnarQueryRequest) (*ColumnarQueryResponse, error) {
+	var err error
+	uriPath, ok := columnarQueryPaths[r.Language]
+	if !ok {
+		return nil, backend.DownstreamError(fmt.Errorf("unsupported query language: %q", r.Language))
+	}
+
+	_, span := tracing.DefaultTracer().Start(c.ctx, "datasource.elasticsearch.queryData.executeColumnarQuery", trace.WithAttributes(
+		attribute.String("language", r.Language),
+		attribute.String("url", c.ds.URL),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	body, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	clientRes, err := c.executeJSONRequest(uriPath, body)
+	if err != nil {
+		status := "error"
+		if errors.Is(err, context.Canceled) {
+			status = "cancelled"
+		}
+		c.logger.Error("Error received from Elasticsearch", "error", err, "status", status, "duration", time.Since(start), "stage", StageDatabaseRequest, "language", r.Language)
+		return nil, err
+	}
+	defer func() {
+		if err := clientRes.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	c.logger.Info("Response received from Elasticsearch", "status", "ok", "statusCode", clientRes.StatusCode, "contentLength", clientRes.ContentLength, "duration", time.Since(start), "stage", StageDatabaseRequest, "language", r.Language)
+
+	var res ColumnarQueryResponse
+	err = json.NewDecoder(clientRes.Body).Decode(&res)
+	if err != nil {
+		c.logger.Error("Failed to decode response from Elasticsearch", "error", err, "duration", time.Since(start))
+		return nil, err
+	}
+	res.Status = clientRes.StatusCode
+
+	return &res, nil
+}
+
+func (c *baseClientImpl) executeJSONRequest(uriPath string, body []byte) (*http.Response, error) {
+	u, err := c.requestURL(uriPath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, u, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	//nolint:bodyclose
+	return c.ds.HTTPClient.Do(req)
+}
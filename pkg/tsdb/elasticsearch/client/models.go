@@ -245,6 +245,40 @@ type TermsAggregation struct {
 	Missing     *string                `json:"missing,omitempty"`
 }
 
+// CompositeAggregation represents a composite aggregation with a single terms source
+type CompositeAggregation struct {
+	Field         string
+	Size          int
+	Order         string
+	MissingBucket bool
+	After         map[string]interface{}
+}
+
+// MarshalJSON returns the JSON encoding of the composite aggregation
+func (a *CompositeAggregation) MarshalJSON() ([]byte, error) {
+	terms := map[string]interface{}{
+		"field": a.Field,
+	}
+	if a.Order != "" {
+		terms["order"] = a.Order
+	}
+	if a.MissingBucket {
+		terms["missing_bucket"] = true
+	}
+
+	root := map[string]interface{}{
+		"size": a.Size,
+		"sources": []map[string]interface{}{
+			{a.Field: map[string]interface{}{"terms": terms}},
+		},
+	}
+	if len(a.After) > 0 {
+		root["after"] = a.After
+	}
+
+	return json.Marshal(root)
+}
+
 // NestedAggregation represents a nested aggregation
 type NestedAggregation struct {
 	Path string `json:"path"`
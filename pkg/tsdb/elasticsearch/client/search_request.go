@@ -82,6 +82,30 @@ func (b *SearchRequestBuilder) Build() (*SearchRequest, error) {
 	return &sr, nil
 }
 
+// CompositeAgg returns the top level composite aggregation with the given key, if any
+func (r *SearchRequest) CompositeAgg(key string) *CompositeAggregation {
+	for _, agg := range r.Aggs {
+		if agg.Key != key || agg.Aggregation == nil {
+			continue
+		}
+		if composite, ok := agg.Aggregation.Aggregation.(*CompositeAggregation); ok {
+			return composite
+		}
+	}
+	return nil
+}
+
+// SetCompositeAfter sets the after key of the top level composite aggregation with the given key,
+// so that executing the request again returns the next page of buckets
+func (r *SearchRequest) SetCompositeAfter(key string, after map[string]any) bool {
+	composite := r.CompositeAgg(key)
+	if composite == nil {
+		return false
+	}
+	composite.After = after
+	return true
+}
+
 // Size sets the size of the search request
 func (b *SearchRequestBuilder) Size(size int) *SearchRequestBuilder {
 	b.size = size
@@ -305,6 +329,7 @@ type AggBuilder interface {
 	Histogram(key, field string, fn func(a *HistogramAgg, b AggBuilder)) AggBuilder
 	DateHistogram(key, field string, fn func(a *DateHistogramAgg, b AggBuilder)) AggBuilder
 	Terms(key, field string, fn func(a *TermsAggregation, b AggBuilder)) AggBuilder
+	Composite(key, field string, fn func(a *CompositeAggregation, b AggBuilder)) AggBuilder
 	Nested(key, path string, fn func(a *NestedAggregation, b AggBuilder)) AggBuilder
 	Filters(key string, fn func(a *FiltersAggregation, b AggBuilder)) AggBuilder
 	GeoHashGrid(key, field string, fn func(a *GeoHashGridAggregation, b AggBuilder)) AggBuilder
@@ -416,6 +441,26 @@ func (b *aggBuilderImpl) Terms(key, field string, fn func(a *TermsAggregation, b
 	return b
 }
 
+func (b *aggBuilderImpl) Composite(key, field string, fn func(a *CompositeAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &CompositeAggregation{
+		Field: field,
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "composite",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
 func (b *aggBuilderImpl) Nested(key, field string, fn func(a *NestedAggregation, b AggBuilder)) AggBuilder {
 	innerAgg := &NestedAggregation{
 		Path: field,
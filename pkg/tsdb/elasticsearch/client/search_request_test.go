@@ -224,6 +224,48 @@ func TestSearchRequest(t *testing.T) {
 		})
 	})
 
+	t.Run("and adding top level composite agg with child agg", func(t *testing.T) {
+		b := setup()
+		aggBuilder := b.Agg()
+		aggBuilder.Composite("1", "@hostname", func(a *CompositeAggregation, ib AggBuilder) {
+			a.Size = 100
+			a.Order = "desc"
+			ib.DateHistogram("2", "@timestamp", nil)
+		})
+
+		t.Run("When building search request", func(t *testing.T) {
+			sr, err := b.Build()
+			require.Nil(t, err)
+
+			t.Run("Should generate composite agg json", func(t *testing.T) {
+				body, err := json.Marshal(sr)
+				require.Nil(t, err)
+				json, err := simplejson.NewJson(body)
+				require.Nil(t, err)
+
+				firstLevelAgg := json.GetPath("aggs", "1")
+				require.Equal(t, 100, firstLevelAgg.GetPath("composite", "size").MustInt())
+				source := firstLevelAgg.GetPath("composite", "sources").GetIndex(0)
+				require.Equal(t, "@hostname", source.GetPath("@hostname", "terms", "field").MustString())
+				require.Equal(t, "desc", source.GetPath("@hostname", "terms", "order").MustString())
+				_, hasAfter := firstLevelAgg.Get("composite").CheckGet("after")
+				require.False(t, hasAfter)
+				require.Equal(t, "@timestamp", firstLevelAgg.GetPath("aggs", "2", "date_histogram", "field").MustString())
+			})
+
+			t.Run("Should set after key of next page", func(t *testing.T) {
+				require.False(t, sr.SetCompositeAfter("2", map[string]any{"@hostname": "server1"}))
+				require.True(t, sr.SetCompositeAfter("1", map[string]any{"@hostname": "server1"}))
+
+				body, err := json.Marshal(sr)
+				require.Nil(t, err)
+				json, err := simplejson.NewJson(body)
+				require.Nil(t, err)
+				require.Equal(t, "server1", json.GetPath("aggs", "1", "composite", "after", "@hostname").MustString())
+			})
+		})
+	})
+
 	t.Run("and adding two top level aggs with child agg", func(t *testing.T) {
 		b := setup()
 		aggBuilder := b.Agg()
@@ -0,0 +1,256 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+// timeLayouts are the layouts of date columns in ES|QL and PPL responses
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+var luceneSpecialCharsRegex = regexp.MustCompile(`([+\-&|!(){}\[\]^"~*?:\\/ ])`)
+
+func isColumnarQueryType(queryType string) bool {
+	return queryType == es.QueryLanguageESQL || queryType == es.QueryLanguagePPL
+}
+
+func (e *elasticsearchDataQuery) executeColumnarQuery(q *Query) backend.DataResponse {
+	req, err := buildColumnarQuery(q, e.client.GetConfiguredFields().TimeField)
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(backend.DownstreamError(err))
+	}
+
+	res, err := e.client.ExecuteColumnarQuery(req)
+	if err != nil {
+		if backend.IsDownstreamHTTPError(err) {
+			err = backend.DownstreamError(err)
+		}
+		return backend.ErrorResponseWithErrorSource(err)
+	}
+
+	if res.Status >= 400 || res.Error != nil {
+		statusErr := fmt.Errorf("unexpected status code: %d", res.Status)
+		if reason := getColumnarErrorReason(res); reason != "" {
+			statusErr = fmt.Errorf("%s", reason)
+		}
+		if backend.ErrorSourceFromHTTPStatus(res.Status) == backend.ErrorSourceDownstream {
+			return backend.ErrorResponseWithErrorSource(backend.DownstreamError(statusErr))
+		}
+		return backend.ErrorResponseWithErrorSource(backend.PluginError(statusErr))
+	}
+
+	frame := processColumnarResponse(res)
+	frame.RefID = q.RefID
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// buildColumnarQuery interpolates the interval macros of an ES|QL or PPL query
+// and restricts it to the time range and ad-hoc filters of the query.
+func buildColumnarQuery(q *Query, timeField string) (*es.ColumnarQueryRequest, error) {
+	interval := q.Interval
+	if q.IntervalMs > 0 {
+		interval = time.Duration(q.IntervalMs) * time.Millisecond
+	}
+	intervalMs := interval.Milliseconds()
+
+	intervalLiteral := fmt.Sprintf("%d milliseconds", intervalMs)
+	if q.QueryType == es.QueryLanguagePPL {
+		intervalLiteral = fmt.Sprintf("%dms", intervalMs)
+	}
+
+	rawQuery := strings.ReplaceAll(q.RawQuery, "$__interval_ms", strconv.FormatInt(intervalMs, 10))
+	rawQuery = strings.ReplaceAll(rawQuery, "$__interval", intervalLiteral)
+
+	from := q.TimeRange.From.UnixNano() / int64(time.Millisecond)
+	to := q.TimeRange.To.UnixNano() / int64(time.Millisecond)
+
+	qb := es.NewQueryBuilder()
+	filters := qb.Bool().Filter()
+	filters.AddDateRangeFilter(timeField, to, from, es.DateFormatEpochMS)
+
+	adHocQuery, err := adHocFiltersToQueryString(q.AdHocFilters)
+	if err != nil {
+		return nil, err
+	}
+	filters.AddQueryStringFilter(adHocQuery, false)
+
+	filter, err := qb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &es.ColumnarQueryRequest{
+		Language: q.QueryType,
+		Query:    rawQuery,
+		Filter:   filter,
+	}, nil
+}
+
+// adHocFiltersToQueryString converts ad-hoc filters to a Lucene query the same
+// way the frontend applies them to Lucene queries.
+func adHocFiltersToQueryString(filters []*AdHocFilter) (string, error) {
+	parts := make([]string, 0, len(filters))
+	for _, f := range filters {
+		key := luceneSpecialCharsRegex.ReplaceAllString(f.Key, `\$1`)
+		value := f.Value
+		switch f.Operator {
+		case "=":
+			parts = append(parts, fmt.Sprintf(`%s:"%s"`, key, escapeQuoted(value)))
+		case "!=":
+			parts = append(parts, fmt.Sprintf(`-%s:"%s"`, key, escapeQuoted(value)))
+		case "=~":
+			parts = append(parts, fmt.Sprintf(`%s:/%s/`, key, strings.ReplaceAll(value, "/", `\/`)))
+		case "!~":
+			parts = append(parts, fmt.Sprintf(`-%s:/%s/`, key, strings.ReplaceAll(value, "/", `\/`)))
+		case ">":
+			parts = append(parts, fmt.Sprintf(`%s:>%s`, key, luceneSpecialCharsRegex.ReplaceAllString(value, `\$1`)))
+		case "<":
+			parts = append(parts, fmt.Sprintf(`%s:<%s`, key, luceneSpecialCharsRegex.ReplaceAllString(value, `\$1`)))
+		default:
+			return "", fmt.Errorf("unsupported ad-hoc filter operator %q", f.Operator)
+		}
+	}
+	return strings.Join(parts, " AND "), nil
+}
+
+func escapeQuoted(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
+
+func getColumnarErrorReason(res *es.ColumnarQueryResponse) string {
+	if res.Error == nil {
+		return ""
+	}
+	if reason, ok := res.Error["reason"].(string); ok {
+		return reason
+	}
+	// PPL errors are reported as {"error": {"reason": ..., "details": ...}} or as plain strings
+	if details, ok := res.Error["details"].(string); ok {
+		return details
+	}
+	return ""
+}
+
+// processColumnarResponse converts an ES|QL or PPL response to a data frame.
+// Frames with a time column and numeric columns are marked as time series.
+func processColumnarResponse(res *es.ColumnarQueryResponse) *data.Frame {
+	fields := make([]*data.Field, 0, len(res.Columns))
+	for i, column := range res.Columns {
+		fields = append(fields, newColumnarField(column, i, res.Values))
+	}
+
+	frame := data.NewFrame("", fields...)
+	switch frame.TimeSeriesSchema().Type {
+	case data.TimeSeriesTypeWide:
+		frame.Meta = &data.FrameMeta{Type: data.FrameTypeTimeSeriesWide, TypeVersion: data.FrameTypeVersion{0, 1}}
+	case data.TimeSeriesTypeLong:
+		frame.Meta = &data.FrameMeta{Type: data.FrameTypeTimeSeriesLong, TypeVersion: data.FrameTypeVersion{0, 1}}
+	default:
+		frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	}
+	return frame
+}
+
+func newColumnarField(column es.ColumnarQueryColumn, index int, rows [][]interface{}) *data.Field {
+	value := func(row []interface{}) interface{} {
+		if index < len(row) {
+			return row[index]
+		}
+		return nil
+	}
+
+	switch columnFieldType(column.Type) {
+	case data.FieldTypeNullableTime:
+		values := make([]*time.Time, len(rows))
+		for i, row := range rows {
+			values[i] = parseColumnarTime(value(row))
+		}
+		return data.NewField(column.Name, nil, values)
+	case data.FieldTypeNullableFloat64:
+		values := make([]*float64, len(rows))
+		for i, row := range rows {
+			values[i] = parseColumnarNumber(value(row))
+		}
+		return data.NewField(column.Name, nil, values)
+	case data.FieldTypeNullableBool:
+		values := make([]*bool, len(rows))
+		for i, row := range rows {
+			if b, ok := value(row).(bool); ok {
+				values[i] = &b
+			}
+		}
+		return data.NewField(column.Name, nil, values)
+	default:
+		values := make([]*string, len(rows))
+		for i, row := range rows {
+			switch v := value(row).(type) {
+			case nil:
+			case string:
+				values[i] = &v
+			default:
+				if b, err := json.Marshal(v); err == nil {
+					s := string(b)
+					values[i] = &s
+				}
+			}
+		}
+		return data.NewField(column.Name, nil, values)
+	}
+}
+
+func columnFieldType(columnType string) data.FieldType {
+	switch strings.ToLower(columnType) {
+	case "date", "date_nanos", "datetime", "timestamp":
+		return data.FieldTypeNullableTime
+	case "long", "integer", "int", "short", "byte", "double", "float", "half_float", "scaled_float",
+		"unsigned_long", "counter_long", "counter_integer", "counter_double":
+		return data.FieldTypeNullableFloat64
+	case "boolean":
+		return data.FieldTypeNullableBool
+	default:
+		return data.FieldTypeNullableString
+	}
+}
+
+func parseColumnarTime(v interface{}) *time.Time {
+	switch t := v.(type) {
+	case string:
+		for _, layout := range timeLayouts {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				parsed = parsed.UTC()
+				return &parsed
+			}
+		}
+	case float64:
+		parsed := time.UnixMilli(int64(t)).UTC()
+		return &parsed
+	}
+	return nil
+}
+
+func parseColumnarNumber(v interface{}) *float64 {
+	switch n := v.(type) {
+	case float64:
+		return &n
+	case string:
+		if f, err := strconv.ParseFloat(n, 64); err == nil {
+			return &f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+func TestColumnarQuery(t *testing.T) {
+	from := time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC)
+	to := time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC)
+
+	t.Run("Should build ES|QL request with macros, time range and ad-hoc filters", func(t *testing.T) {
+		q := &Query{
+			QueryType: es.QueryLanguageESQL,
+			RawQuery:  "FROM logs | STATS c = COUNT(*) BY b = BUCKET(@timestamp, $__interval) | EVAL ms = $__interval_ms",
+			Interval:  time.Minute,
+			TimeRange: backend.TimeRange{From: from, To: to},
+			AdHocFilters: []*AdHocFilter{
+				{Key: "host", Operator: "=", Value: `web "01"`},
+				{Key: "level", Operator: "!~", Value: "debug|trace"},
+			},
+		}
+
+		req, err := buildColumnarQuery(q, "@timestamp")
+		require.NoError(t, err)
+		require.Equal(t, es.QueryLanguageESQL, req.Language)
+		require.Equal(t, "FROM logs | STATS c = COUNT(*) BY b = BUCKET(@timestamp, 60000 milliseconds) | EVAL ms = 60000", req.Query)
+
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"query": "FROM logs | STATS c = COUNT(*) BY b = BUCKET(@timestamp, 60000 milliseconds) | EVAL ms = 60000",
+			"filter": {"bool": {"filter": [
+				{"range": {"@timestamp": {"gte": 1526406600000, "lte": 1526406900000, "format": "epoch_millis"}}},
+				{"query_string": {"query": "host:\"web \\\"01\\\"\" AND -level:/debug|trace/", "analyze_wildcard": false}}
+			]}}
+		}`, string(body))
+	})
+
+	t.Run("Should use PPL interval literals", func(t *testing.T) {
+		q := &Query{
+			QueryType:  es.QueryLanguagePPL,
+			RawQuery:   "source=logs | stats count() by span(@timestamp, $__interval)",
+			IntervalMs: 30000,
+			TimeRange:  backend.TimeRange{From: from, To: to},
+		}
+
+		req, err := buildColumnarQuery(q, "@timestamp")
+		require.NoError(t, err)
+		require.Equal(t, "source=logs | stats count() by span(@timestamp, 30000ms)", req.Query)
+	})
+
+	t.Run("Should reject unknown ad-hoc filter operators", func(t *testing.T) {
+		_, err := adHocFiltersToQueryString([]*AdHocFilter{{Key: "a", Operator: "~~", Value: "b"}})
+		require.Error(t, err)
+	})
+
+	t.Run("Should convert response to a wide time series frame", func(t *testing.T) {
+		frame := processColumnarResponse(&es.ColumnarQueryResponse{
+			Columns: []es.ColumnarQueryColumn{{Name: "@timestamp", Type: "date"}, {Name: "count", Type: "long"}},
+			Values:  [][]any{{"2018-05-15T17:50:00.000Z", float64(3)}, {"2018-05-15T17:51:00.000Z", nil}},
+		})
+
+		require.Equal(t, data.FrameTypeTimeSeriesWide, frame.Meta.Type)
+		require.Equal(t, 2, frame.Rows())
+		ts := from
+		require.Equal(t, &ts, frame.Fields[0].At(0))
+		v := float64(3)
+		require.Equal(t, &v, frame.Fields[1].At(0))
+		require.Nil(t, frame.Fields[1].At(1))
+	})
+
+	t.Run("Should convert response with string columns to a long time series frame", func(t *testing.T) {
+		frame := processColumnarResponse(&es.ColumnarQueryResponse{
+			Columns: []es.ColumnarQueryColumn{{Name: "@timestamp", Type: "timestamp"}, {Name: "host", Type: "keyword"}, {Name: "count", Type: "integer"}},
+			Values:  [][]any{{"2018-05-15 17:50:00", "web01", float64(3)}},
+		})
+
+		require.Equal(t, data.FrameTypeTimeSeriesLong, frame.Meta.Type)
+		ts := from
+		require.Equal(t, &ts, frame.Fields[0].At(0))
+	})
+
+	t.Run("Should convert response without time column to a table", func(t *testing.T) {
+		frame := processColumnarResponse(&es.ColumnarQueryResponse{
+			Columns: []es.ColumnarQueryColumn{{Name: "host", Type: "keyword"}, {Name: "tags", Type: "keyword"}, {Name: "up", Type: "boolean"}},
+			Values:  [][]any{{"web01", []any{"a", "b"}, true}},
+		})
+
+		require.Equal(t, data.VisType(data.VisTypeTable), frame.Meta.PreferredVisualization)
+		tags := `["a","b"]`
+		require.Equal(t, &tags, frame.Fields[1].At(0))
+		up := true
+		require.Equal(t, &up, frame.Fields[2].At(0))
+	})
+
+	t.Run("Should execute ES|QL queries next to Query DSL queries", func(t *testing.T) {
+		c := newFakeClient()
+		c.columnarResponse = &es.ColumnarQueryResponse{
+			Status:  200,
+			Columns: []es.ColumnarQueryColumn{{Name: "count", Type: "long"}},
+			Values:  [][]any{{float64(3)}},
+		}
+		c.multiSearchResponse = &es.MultiSearchResponse{Responses: []*es.SearchResponse{{Aggregations: map[string]any{}}}}
+
+		dataRequest := backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					RefID:     "A",
+					QueryType: es.QueryLanguageESQL,
+					JSON:      json.RawMessage(`{"query": "FROM logs | STATS count = COUNT(*)"}`),
+					TimeRange: backend.TimeRange{From: from, To: to},
+				},
+				{
+					RefID:     "B",
+					JSON:      json.RawMessage(`{"bucketAggs": [{"type": "date_histogram", "field": "@timestamp", "id": "2"}], "metrics": [{"type": "count", "id": "1"}]}`),
+					TimeRange: backend.TimeRange{From: from, To: to},
+				},
+			},
+		}
+
+		res, err := newElasticsearchDataQuery(context.Background(), c, &dataRequest, log.New()).execute()
+		require.NoError(t, err)
+		require.Len(t, c.columnarRequests, 1)
+		require.Len(t, c.multisearchRequests, 1)
+		require.Len(t, c.multisearchRequests[0].Requests, 1)
+
+		require.NoError(t, res.Responses["A"].Error)
+		require.Len(t, res.Responses["A"].Frames, 1)
+		require.Equal(t, "A", res.Responses["A"].Frames[0].RefID)
+		require.Contains(t, res.Responses, "B")
+	})
+
+	t.Run("Should return the error reason of failed queries", func(t *testing.T) {
+		c := newFakeClient()
+		c.columnarResponse = &es.ColumnarQueryResponse{
+			Status: 400,
+			Error:  map[string]any{"type": "verification_exception", "reason": "Unknown index [nope]"},
+		}
+
+		dataRequest := backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{RefID: "A", QueryType: es.QueryLanguageESQL, JSON: json.RawMessage(`{"query": "FROM nope"}`)}},
+		}
+
+		res, err := newElasticsearchDataQuery(context.Background(), c, &dataRequest, log.New()).execute()
+		require.NoError(t, err)
+		require.ErrorContains(t, res.Responses["A"].Error, "Unknown index [nope]")
+		require.Equal(t, backend.ErrorSourceDownstream, res.Responses["A"].ErrorSource)
+	})
+}
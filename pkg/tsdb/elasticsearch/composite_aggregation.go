@@ -0,0 +1,171 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+// compositeMaxPages is the maximum number of pages fetched for a single composite aggregation
+const compositeMaxPages = 10
+
+// isCompositeTermsAgg returns true if the terms bucket aggregation should be paginated as a composite aggregation
+func isCompositeTermsAgg(bucketAgg *BucketAgg) bool {
+	if bucketAgg.Settings == nil {
+		return false
+	}
+	setting := bucketAgg.Settings.Get("composite")
+	if enabled, err := setting.Bool(); err == nil {
+		return enabled
+	}
+	return setting.MustString() == "true"
+}
+
+// compositeBucketAgg returns the top level terms bucket aggregation of a time series query
+// if it is paginated as a composite aggregation
+func compositeBucketAgg(q *Query) *BucketAgg {
+	if len(q.BucketAggs) == 0 || isLogsQuery(q) || isDocumentQuery(q) {
+		return nil
+	}
+	bucketAgg := q.BucketAggs[0]
+	if bucketAgg.Type != termsType || !isCompositeTermsAgg(bucketAgg) {
+		return nil
+	}
+	return bucketAgg
+}
+
+// compositePageSize returns the number of buckets fetched per page, which uses the terms size setting
+func compositePageSize(bucketAgg *BucketAgg) int {
+	if size, err := bucketAgg.Settings.Get("size").Int(); err == nil && size > 0 {
+		return size
+	}
+	return stringToIntWithDefaultValue(bucketAgg.Settings.Get("size").MustString(), defaultSize)
+}
+
+// paginateCompositeAggs fetches the remaining pages of every composite aggregation following its after_key,
+// merges the buckets into the original response and normalizes them so they can be parsed as terms buckets.
+// It returns a notice for each query whose buckets are still incomplete.
+func (e *elasticsearchDataQuery) paginateCompositeAggs(req *es.MultiSearchRequest, res *es.MultiSearchResponse, queries []*Query) map[string]data.Notice {
+	truncated := make(map[string]data.Notice)
+
+	for i, q := range queries {
+		bucketAgg := compositeBucketAgg(q)
+		if bucketAgg == nil || i >= len(req.Requests) || i >= len(res.Responses) {
+			continue
+		}
+		searchRes := res.Responses[i]
+		if searchRes == nil || searchRes.Error != nil {
+			continue
+		}
+		agg, ok := searchRes.Aggregations[bucketAgg.ID].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		pageSize := compositePageSize(bucketAgg)
+		buckets, _ := agg["buckets"].([]any)
+		afterKey, _ := agg["after_key"].(map[string]any)
+		pageLength := len(buckets)
+		pages := 1
+
+		// A page with fewer buckets than requested is the last one
+		for len(afterKey) > 0 && pageLength >= pageSize {
+			if pages >= compositeMaxPages {
+				truncated[q.RefID] = data.Notice{
+					Severity: data.NoticeSeverityWarning,
+					Text:     fmt.Sprintf("Results were truncated to the first %d terms of %s after fetching %d pages. Narrow down the query or increase the size to get all terms.", len(buckets), bucketAgg.Field, pages),
+				}
+				break
+			}
+
+			pageAgg, err := e.fetchCompositePage(req.Requests[i], bucketAgg.ID, afterKey)
+			if err != nil {
+				e.logger.Warn("Failed to fetch composite aggregation page", "error", err, "page", pages+1, "refId", q.RefID, "stage", es.StageDatabaseRequest)
+				truncated[q.RefID] = data.Notice{
+					Severity: data.NoticeSeverityWarning,
+					Text:     fmt.Sprintf("Results are incomplete, failed to fetch page %d of terms of %s: %s", pages+1, bucketAgg.Field, err),
+				}
+				break
+			}
+
+			pageBuckets, _ := pageAgg["buckets"].([]any)
+			buckets = append(buckets, pageBuckets...)
+			afterKey, _ = pageAgg["after_key"].(map[string]any)
+			pageLength = len(pageBuckets)
+			pages++
+		}
+
+		searchRes.Aggregations[bucketAgg.ID] = map[string]any{
+			"buckets": normalizeCompositeBuckets(buckets, bucketAgg),
+		}
+	}
+
+	return truncated
+}
+
+// fetchCompositePage executes the search request again starting after the given key
+// and returns the composite aggregation of the response
+func (e *elasticsearchDataQuery) fetchCompositePage(searchReq *es.SearchRequest, aggID string, afterKey map[string]any) (map[string]any, error) {
+	if !searchReq.SetCompositeAfter(aggID, afterKey) {
+		return nil, fmt.Errorf("composite aggregation %s not found in request", aggID)
+	}
+	defer searchReq.SetCompositeAfter(aggID, nil)
+
+	res, err := e.client.ExecuteMultisearch(&es.MultiSearchRequest{Requests: []*es.SearchRequest{searchReq}})
+	if err != nil {
+		if backend.IsDownstreamHTTPError(err) {
+			err = backend.DownstreamError(err)
+		}
+		return nil, err
+	}
+	if res.Status >= 400 {
+		return nil, fmt.Errorf("unexpected status code: %d", res.Status)
+	}
+	if len(res.Responses) != 1 || res.Responses[0] == nil {
+		return nil, fmt.Errorf("unexpected number of responses: %d", len(res.Responses))
+	}
+	if res.Responses[0].Error != nil {
+		return nil, fmt.Errorf("%s", getErrorFromElasticResponse(res.Responses[0]))
+	}
+
+	agg, ok := res.Responses[0].Aggregations[aggID].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("composite aggregation %s not found in response", aggID)
+	}
+	return agg, nil
+}
+
+// normalizeCompositeBuckets replaces the object key of composite buckets with the value of its single source,
+// so the buckets have the same shape as terms buckets
+func normalizeCompositeBuckets(buckets []any, bucketAgg *BucketAgg) []any {
+	missing, missingErr := bucketAgg.Settings.Get("missing").String()
+	for _, b := range buckets {
+		bucket, ok := b.(map[string]any)
+		if !ok {
+			continue
+		}
+		key, ok := bucket["key"].(map[string]any)
+		if !ok {
+			continue
+		}
+		value := key[bucketAgg.Field]
+		if value == nil && missingErr == nil {
+			value = missing
+		}
+		bucket["key"] = value
+	}
+	return buckets
+}
+
+// addNoticeToFrames attaches a notice to every frame of a data response
+func addNoticeToFrames(res backend.DataResponse, notice data.Notice) {
+	for _, frame := range res.Frames {
+		if frame.Meta == nil {
+			frame.Meta = &data.FrameMeta{}
+		}
+		frame.Meta.Notices = append(frame.Meta.Notices, notice)
+	}
+}
@@ -0,0 +1,156 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+// pagingFakeClient returns one multisearch response per call and records the request bodies
+type pagingFakeClient struct {
+	*fakeClient
+	responses []*es.MultiSearchResponse
+	bodies    []*simplejson.Json
+}
+
+func (c *pagingFakeClient) ExecuteMultisearch(r *es.MultiSearchRequest) (*es.MultiSearchResponse, error) {
+	body, err := json.Marshal(r.Requests[0])
+	if err != nil {
+		return nil, err
+	}
+	sj, err := simplejson.NewJson(body)
+	if err != nil {
+		return nil, err
+	}
+	c.bodies = append(c.bodies, sj)
+
+	if len(c.bodies) > len(c.responses) {
+		return nil, fmt.Errorf("unexpected request %d", len(c.bodies))
+	}
+	return c.responses[len(c.bodies)-1], nil
+}
+
+func compositePage(afterKey string, keys ...string) *es.MultiSearchResponse {
+	buckets := make([]any, 0, len(keys))
+	for _, k := range keys {
+		buckets = append(buckets, map[string]any{
+			"key":       map[string]any{"host": k},
+			"doc_count": float64(1),
+		})
+	}
+	agg := map[string]any{"buckets": buckets}
+	if afterKey != "" {
+		agg["after_key"] = map[string]any{"host": afterKey}
+	}
+	return &es.MultiSearchResponse{
+		Responses: []*es.SearchResponse{
+			{Aggregations: map[string]any{"2": agg}},
+		},
+	}
+}
+
+func TestCompositeAggregation(t *testing.T) {
+	from := time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC)
+	to := time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC)
+
+	query := `{
+		"metrics": [{ "type": "count", "id": "1" }],
+		"bucketAggs": [
+			{ "type": "terms", "field": "host", "id": "2", "settings": { "size": "2", "composite": true, "orderBy": "_term", "order": "asc" } }
+		]
+	}`
+
+	t.Run("With composite setting should build a composite aggregation", func(t *testing.T) {
+		c := &pagingFakeClient{fakeClient: newFakeClient(), responses: []*es.MultiSearchResponse{compositePage("", "a")}}
+		_, err := executeElasticsearchDataQuery(c, query, from, to)
+		require.NoError(t, err)
+
+		require.Len(t, c.bodies, 1)
+		composite := c.bodies[0].GetPath("aggs", "2", "composite")
+		require.Equal(t, 2, composite.Get("size").MustInt())
+		require.Equal(t, "asc", composite.Get("sources").GetIndex(0).GetPath("host", "terms", "order").MustString())
+		_, hasAfter := composite.CheckGet("after")
+		require.False(t, hasAfter)
+	})
+
+	t.Run("Should follow after_key until the last page", func(t *testing.T) {
+		c := &pagingFakeClient{fakeClient: newFakeClient(), responses: []*es.MultiSearchResponse{
+			compositePage("b", "a", "b"),
+			compositePage("d", "c", "d"),
+			compositePage("e", "e"),
+		}}
+		result, err := executeElasticsearchDataQuery(c, query, from, to)
+		require.NoError(t, err)
+
+		require.Len(t, c.bodies, 3)
+		require.Equal(t, "b", c.bodies[1].GetPath("aggs", "2", "composite", "after", "host").MustString())
+		require.Equal(t, "d", c.bodies[2].GetPath("aggs", "2", "composite", "after", "host").MustString())
+
+		frames := result.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 5, frames[0].Rows())
+		hosts := make([]string, 0, 5)
+		for i := 0; i < frames[0].Rows(); i++ {
+			hosts = append(hosts, *frames[0].Fields[0].At(i).(*string))
+		}
+		require.Equal(t, []string{"a", "b", "c", "d", "e"}, hosts)
+		if frames[0].Meta != nil {
+			require.Empty(t, frames[0].Meta.Notices)
+		}
+	})
+
+	t.Run("Should stop after the maximum number of pages and add a notice", func(t *testing.T) {
+		responses := make([]*es.MultiSearchResponse, 0, compositeMaxPages)
+		for i := 0; i < compositeMaxPages; i++ {
+			k1, k2 := fmt.Sprintf("%02d-a", i), fmt.Sprintf("%02d-b", i)
+			responses = append(responses, compositePage(k2, k1, k2))
+		}
+		c := &pagingFakeClient{fakeClient: newFakeClient(), responses: responses}
+		result, err := executeElasticsearchDataQuery(c, query, from, to)
+		require.NoError(t, err)
+
+		require.Len(t, c.bodies, compositeMaxPages)
+		frames := result.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 2*compositeMaxPages, frames[0].Rows())
+		require.Len(t, frames[0].Meta.Notices, 1)
+		require.Equal(t, data.NoticeSeverityWarning, frames[0].Meta.Notices[0].Severity)
+		require.Contains(t, frames[0].Meta.Notices[0].Text, "truncated")
+	})
+
+	t.Run("Should add a notice when fetching a page fails", func(t *testing.T) {
+		c := &pagingFakeClient{fakeClient: newFakeClient(), responses: []*es.MultiSearchResponse{
+			compositePage("b", "a", "b"),
+		}}
+		result, err := executeElasticsearchDataQuery(c, query, from, to)
+		require.NoError(t, err)
+
+		frames := result.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 2, frames[0].Rows())
+		require.Len(t, frames[0].Meta.Notices, 1)
+		require.Contains(t, frames[0].Meta.Notices[0].Text, "failed to fetch page 2")
+	})
+
+	t.Run("Terms aggregation that is not top level should not be composite", func(t *testing.T) {
+		c := newFakeClient()
+		_, err := executeElasticsearchDataQuery(c, `{
+			"metrics": [{ "type": "count", "id": "1" }],
+			"bucketAggs": [
+				{ "type": "date_histogram", "field": "@timestamp", "id": "2" },
+				{ "type": "terms", "field": "host", "id": "3", "settings": { "composite": true } }
+			]
+		}`, from, to)
+		require.NoError(t, err)
+
+		sr := c.multisearchRequests[0].Requests[0]
+		require.Equal(t, "terms", sr.Aggs[0].Aggregation.Aggs[0].Aggregation.Type)
+	})
+}
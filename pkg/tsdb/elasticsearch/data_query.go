@@ -55,6 +55,20 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 		return response, nil
 	}
 
+	// ES|QL and PPL queries don't go through the multisearch API and are executed one by one
+	searchQueries := make([]*Query, 0, len(queries))
+	for _, q := range queries {
+		if isColumnarQueryType(q.QueryType) {
+			response.Responses[q.RefID] = e.executeColumnarQuery(q)
+			continue
+		}
+		searchQueries = append(searchQueries, q)
+	}
+	if len(searchQueries) == 0 {
+		return response, nil
+	}
+	queries = searchQueries
+
 	ms := e.client.MultiSearch()
 
 	for _, q := range queries {
@@ -72,7 +86,7 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 	if err != nil {
 		mqs, _ := json.Marshal(e.dataQueries)
 		e.logger.Error("Failed to build multisearch request", "error", err, "queriesLength", len(queries), "queries", string(mqs), "duration", time.Since(start), "stage", es.StagePrepareRequest)
-		response.Responses[e.dataQueries[0].RefID] = backend.ErrorResponseWithErrorSource(err)
+		response.Responses[queries[0].RefID] = backend.ErrorResponseWithErrorSource(err)
 		return response, nil
 	}
 
@@ -82,21 +96,33 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 		if backend.IsDownstreamHTTPError(err) {
 			err = backend.DownstreamError(err)
 		}
-		response.Responses[e.dataQueries[0].RefID] = backend.ErrorResponseWithErrorSource(err)
+		response.Responses[queries[0].RefID] = backend.ErrorResponseWithErrorSource(err)
 		return response, nil
 	}
 
 	if res.Status >= 400 {
 		statusErr := fmt.Errorf("unexpected status code: %d", res.Status)
 		if backend.ErrorSourceFromHTTPStatus(res.Status) == backend.ErrorSourceDownstream {
-			response.Responses[e.dataQueries[0].RefID] = backend.ErrorResponseWithErrorSource(backend.DownstreamError(statusErr))
+			response.Responses[queries[0].RefID] = backend.ErrorResponseWithErrorSource(backend.DownstreamError(statusErr))
 		} else {
-			response.Responses[e.dataQueries[0].RefID] = backend.ErrorResponseWithErrorSource(backend.PluginError(statusErr))
+			response.Responses[queries[0].RefID] = backend.ErrorResponseWithErrorSource(backend.PluginError(statusErr))
 		}
 		return response, nil
 	}
 
-	return parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.keepLabelsInResponse, e.logger)
+	truncated := e.paginateCompositeAggs(req, res, queries)
+
+	result, err := parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.keepLabelsInResponse, e.logger)
+	if err != nil {
+		return result, err
+	}
+	for refID, notice := range truncated {
+		addNoticeToFrames(result.Responses[refID], notice)
+	}
+	for refID, res := range response.Responses {
+		result.Responses[refID] = res
+	}
+	return result, nil
 }
 
 func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilder, from, to int64) error {
@@ -284,6 +310,24 @@ func addTermsAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, metrics []*Metr
 	return aggBuilder
 }
 
+func addCompositeAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	aggBuilder.Composite(bucketAgg.ID, bucketAgg.Field, func(a *es.CompositeAggregation, b es.AggBuilder) {
+		a.Size = compositePageSize(bucketAgg)
+
+		// Composite buckets are always sorted by key, so ordering by a metric or count is ignored
+		if orderBy, err := bucketAgg.Settings.Get("orderBy").String(); err == nil && (orderBy == "_term" || orderBy == "_key") {
+			a.Order = bucketAgg.Settings.Get("order").MustString("desc")
+		}
+		if _, err := bucketAgg.Settings.Get("missing").String(); err == nil {
+			a.MissingBucket = true
+		}
+
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
 func addNestedAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
 	aggBuilder.Nested(bucketAgg.ID, bucketAgg.Field, func(a *es.NestedAggregation, b es.AggBuilder) {
 		aggBuilder = b
@@ -419,7 +463,7 @@ func processTimeSeriesQuery(q *Query, b *es.SearchRequestBuilder, from, to int64
 	aggBuilder := b.Agg()
 	// Process buckets
 	// iterate backwards to create aggregations bottom-down
-	for _, bucketAgg := range q.BucketAggs {
+	for i, bucketAgg := range q.BucketAggs {
 		bucketAgg.Settings = simplejson.NewFromAny(
 			bucketAgg.generateSettingsForDSL(),
 		)
@@ -431,7 +475,12 @@ func processTimeSeriesQuery(q *Query, b *es.SearchRequestBuilder, from, to int64
 		case filtersType:
 			aggBuilder = addFiltersAgg(aggBuilder, bucketAgg)
 		case termsType:
-			aggBuilder = addTermsAgg(aggBuilder, bucketAgg, q.Metrics)
+			// Only a top level terms aggregation can be paginated as a composite aggregation
+			if i == 0 && isCompositeTermsAgg(bucketAgg) {
+				aggBuilder = addCompositeAgg(aggBuilder, bucketAgg)
+			} else {
+				aggBuilder = addTermsAgg(aggBuilder, bucketAgg, q.Metrics)
+			}
 		case geohashGridType:
 			aggBuilder = addGeoHashGridAgg(aggBuilder, bucketAgg)
 		case nestedType:
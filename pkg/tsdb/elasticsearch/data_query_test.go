@@ -1804,6 +1804,8 @@ type fakeClient struct {
 	multiSearchError    error
 	builder             *es.MultiSearchRequestBuilder
 	multisearchRequests []*es.MultiSearchRequest
+	columnarResponse    *es.ColumnarQueryResponse
+	columnarRequests    []*es.ColumnarQueryRequest
 }
 
 func newFakeClient() *fakeClient {
@@ -1829,6 +1831,11 @@ func (c *fakeClient) ExecuteMultisearch(r *es.MultiSearchRequest) (*es.MultiSear
 	return c.multiSearchResponse, c.multiSearchError
 }
 
+func (c *fakeClient) ExecuteColumnarQuery(r *es.ColumnarQueryRequest) (*es.ColumnarQueryResponse, error) {
+	c.columnarRequests = append(c.columnarRequests, r)
+	return c.columnarResponse, nil
+}
+
 func (c *fakeClient) MultiSearch() *es.MultiSearchRequestBuilder {
 	c.builder = es.NewMultiSearchRequestBuilder()
 	return c.builder
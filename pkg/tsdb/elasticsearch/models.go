@@ -9,10 +9,12 @@ import (
 
 // Query represents the time series query model of the datasource
 type Query struct {
-	RawQuery      string       `json:"query"`
-	BucketAggs    []*BucketAgg `json:"bucketAggs"`
-	Metrics       []*MetricAgg `json:"metrics"`
-	Alias         string       `json:"alias"`
+	RawQuery      string         `json:"query"`
+	BucketAggs    []*BucketAgg   `json:"bucketAggs"`
+	Metrics       []*MetricAgg   `json:"metrics"`
+	Alias         string         `json:"alias"`
+	QueryType     string         `json:"queryType"`
+	AdHocFilters  []*AdHocFilter `json:"adhocFilters"`
 	Interval      time.Duration
 	IntervalMs    int64
 	RefID         string
@@ -20,6 +22,13 @@ type Query struct {
 	TimeRange     backend.TimeRange
 }
 
+// AdHocFilter represents an ad-hoc filter applied to ES|QL and PPL queries
+type AdHocFilter struct {
+	Key      string `json:"key"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
 // BucketAgg represents a bucket aggregation of the time series query model of the datasource
 type BucketAgg struct {
 	Field    string           `json:"field"`
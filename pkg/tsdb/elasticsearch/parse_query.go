@@ -21,6 +21,24 @@ func parseQuery(tsdbQuery []backend.DataQuery, logger log.Logger) ([]*Query, err
 		// please do not create a new field with that name, to avoid potential problems with old, persisted queries.
 
 		rawQuery := model.Get("query").MustString()
+		queryType := q.QueryType
+		if queryType == "" {
+			queryType = model.Get("queryType").MustString()
+		}
+		if isColumnarQueryType(queryType) {
+			queries = append(queries, &Query{
+				RawQuery:      rawQuery,
+				QueryType:     queryType,
+				AdHocFilters:  parseAdHocFilters(model),
+				Interval:      q.Interval,
+				IntervalMs:    model.Get("intervalMs").MustInt64(0),
+				RefID:         q.RefID,
+				MaxDataPoints: q.MaxDataPoints,
+				TimeRange:     q.TimeRange,
+			})
+			continue
+		}
+
 		bucketAggs, err := parseBucketAggs(model)
 		if err != nil {
 			logger.Error("Failed to parse bucket aggs in query", "error", err, "model", string(q.JSON))
@@ -118,3 +136,17 @@ func parseMetrics(model *simplejson.Json) ([]*MetricAgg, error) {
 	}
 	return result, nil
 }
+
+func parseAdHocFilters(model *simplejson.Json) []*AdHocFilter {
+	filters := model.Get("adhocFilters").MustArray()
+	result := make([]*AdHocFilter, 0, len(filters))
+	for _, f := range filters {
+		filterJSON := simplejson.NewFromAny(f)
+		result = append(result, &AdHocFilter{
+			Key:      filterJSON.Get("key").MustString(),
+			Operator: filterJSON.Get("operator").MustString(),
+			Value:    filterJSON.Get("value").MustString(),
+		})
+	}
+	return result
+}
@@ -7,9 +7,9 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
-	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/tsdb/grafana-postgresql-datasource/sqleng"
+	"github.com/grafana/grafana/pkg/tsdb/sqlmacros"
 )
 
 const rsIdentifier = `([_a-zA-Z0-9]+)`
@@ -28,6 +28,11 @@ func newPostgresMacroEngine(timescaledb bool) sqleng.SQLMacroEngine {
 }
 
 func (m *postgresMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	sql, err := sqlmacros.ExpandIn(sql, quoteString)
+	if err != nil {
+		return "", err
+	}
+
 	// TODO: Handle error
 	rExp, _ := regexp.Compile(sExpr)
 	var macroError error
@@ -85,6 +90,15 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		}
 
 		return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339Nano), timeRange.To.UTC().Format(time.RFC3339Nano)), nil
+	case "__timeShift":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and time shift", name)
+		}
+		shifted, err := sqlmacros.ShiftTimeRange(timeRange, args[1])
+		if err != nil {
+			return "", err
+		}
+		return m.evaluateMacro(shifted, query, "__timeFilter", args[:1])
 	case "__timeFrom":
 		return fmt.Sprintf("'%s'", timeRange.From.UTC().Format(time.RFC3339Nano)), nil
 	case "__timeTo":
@@ -93,9 +107,12 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
 		}
-		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'`))
+		if unit := sqlmacros.CalendarUnit(args[1]); unit != "" {
+			return m.calendarTimeGroup(args[0], unit, args[2:])
+		}
+		interval, err := sqlmacros.ParseInterval(args[1])
 		if err != nil {
-			return "", fmt.Errorf("error parsing interval %v", args[1])
+			return "", err
 		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
@@ -137,9 +154,9 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
 		}
-		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'`))
+		interval, err := sqlmacros.ParseInterval(args[1])
 		if err != nil {
-			return "", fmt.Errorf("error parsing interval %v", args[1])
+			return "", err
 		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
@@ -158,3 +175,22 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		return "", fmt.Errorf("unknown macro %q", name)
 	}
 }
+
+// calendarTimeGroup rounds the time column down to the start of a calendar
+// unit. Without a time zone argument the session time zone is used.
+func (m *postgresMacroEngine) calendarTimeGroup(column string, unit string, args []string) (string, error) {
+	if len(args) > 0 && args[0] != "" {
+		name, err := sqlmacros.Timezone(args[0])
+		if err != nil {
+			return "", err
+		}
+		tz := quoteString(name)
+		return fmt.Sprintf("extract(epoch from date_trunc('%s', %s AT TIME ZONE %s) AT TIME ZONE %s)", unit, column, tz, tz), nil
+	}
+	return fmt.Sprintf("extract(epoch from date_trunc('%s', %s))", unit, column), nil
+}
+
+// quoteString returns value as a PostgreSQL string literal.
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -144,6 +144,59 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, "SELECT floor((time_column+time_adjustment)/300)*300", sql)
 			require.Equal(t, sql2, sql+" AS \"time\"")
 		})
+		t.Run("interpolate __timeGroup function with $__interval_ms", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,300000)")
+			require.NoError(t, err)
+
+			require.Equal(t, "GROUP BY floor(extract(epoch from time_column)/300)*300", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with calendar unit", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'week')")
+			require.NoError(t, err)
+
+			require.Equal(t, "GROUP BY extract(epoch from date_trunc('week', time_column))", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with calendar unit and time zone", func(t *testing.T) {
+			sql, err := engineTS.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'month','Europe/Berlin')")
+			require.NoError(t, err)
+
+			require.Equal(t, "GROUP BY extract(epoch from date_trunc('month', time_column AT TIME ZONE 'Europe/Berlin') AT TIME ZONE 'Europe/Berlin')", sql)
+		})
+
+		t.Run("interpolate __timeShift function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeShift(time_column, -5m)")
+			require.NoError(t, err)
+
+			require.Equal(t, fmt.Sprintf("WHERE time_column BETWEEN '%s' AND '%s'", from.Add(5*time.Minute).Format(time.RFC3339Nano), to.Add(5*time.Minute).Format(time.RFC3339Nano)), sql)
+		})
+
+		t.Run("interpolate __in function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__in(host, 'a','it''s')")
+			require.NoError(t, err)
+
+			require.Equal(t, "WHERE host IN ('a','it''s')", sql)
+		})
+
+		t.Run("interpolate __in function with commas and parentheses in quoted values", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__in(host, 'a,b','c)','d''),e') AND up = 1")
+			require.NoError(t, err)
+
+			require.Equal(t, "WHERE host IN ('a,b','c)','d''),e') AND up = 1", sql)
+		})
+
+		t.Run("interpolate __in function with double quoted values", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, `WHERE $__in(host, "a,b", "c)")`)
+			require.NoError(t, err)
+
+			require.Equal(t, "WHERE host IN ('a,b','c)')", sql)
+		})
+
+		t.Run("interpolate __in function without closing parenthesis", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "WHERE $__in(host, 'a)'")
+			require.Error(t, err)
+		})
 	})
 
 	t.Run("Given a time range between 1960-02-01 07:00 and 1965-02-03 08:00", func(t *testing.T) {
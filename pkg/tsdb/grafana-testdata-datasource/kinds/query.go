@@ -58,12 +58,15 @@ const (
 	TestDataQueryTypeArrow                        TestDataQueryType = "arrow"
 	TestDataQueryTypeCsvContent                   TestDataQueryType = "csv_content"
 	TestDataQueryTypeCsvFile                      TestDataQueryType = "csv_file"
+	TestDataQueryTypeCounterReset                 TestDataQueryType = "counter_reset"
 	TestDataQueryTypeCsvMetricValues              TestDataQueryType = "csv_metric_values"
 	TestDataQueryTypeDatapointsOutsideRange       TestDataQueryType = "datapoints_outside_range"
 	TestDataQueryTypeErrorWithSource              TestDataQueryType = "error_with_source"
 	TestDataQueryTypeExponentialHeatmapBucketData TestDataQueryType = "exponential_heatmap_bucket_data"
 	TestDataQueryTypeFlameGraph                   TestDataQueryType = "flame_graph"
+	TestDataQueryTypeFlapping                     TestDataQueryType = "flapping"
 	TestDataQueryTypeGrafanaApi                   TestDataQueryType = "grafana_api"
+	TestDataQueryTypeHighCardinality              TestDataQueryType = "high_cardinality"
 	TestDataQueryTypeLinearHeatmapBucketData      TestDataQueryType = "linear_heatmap_bucket_data"
 	TestDataQueryTypeLive                         TestDataQueryType = "live"
 	TestDataQueryTypeLogs                         TestDataQueryType = "logs"
@@ -76,10 +79,12 @@ const (
 	TestDataQueryTypeRandomWalkTable              TestDataQueryType = "random_walk_table"
 	TestDataQueryTypeRandomWalkWithError          TestDataQueryType = "random_walk_with_error"
 	TestDataQueryTypeRawFrame                     TestDataQueryType = "raw_frame"
+	TestDataQueryTypeSeededLogs                   TestDataQueryType = "seeded_logs"
 	TestDataQueryTypeServerError500               TestDataQueryType = "server_error_500"
 	TestDataQueryTypeSimulation                   TestDataQueryType = "simulation"
 	TestDataQueryTypeSlowQuery                    TestDataQueryType = "slow_query"
 	TestDataQueryTypeStreamingClient              TestDataQueryType = "streaming_client"
+	TestDataQueryTypeSyntheticTraces              TestDataQueryType = "synthetic_traces"
 	TestDataQueryTypeTableStatic                  TestDataQueryType = "table_static"
 	TestDataQueryTypeTrace                        TestDataQueryType = "trace"
 	TestDataQueryTypeUsa                          TestDataQueryType = "usa"
@@ -120,11 +125,18 @@ type TestDataQuery struct {
 	SpanCount       int         `json:"spanCount,omitempty"`
 	ErrorSource     ErrorSource `json:"errorSource,omitempty"`
 
-	Nodes     *NodesQuery      `json:"nodes,omitempty"`
-	PulseWave *PulseWaveQuery  `json:"pulseWave,omitempty"`
-	Sim       *SimulationQuery `json:"sim,omitempty"`
-	Stream    *StreamingQuery  `json:"stream,omitempty"`
-	Usa       *USAQuery        `json:"usa,omitempty"`
+	// Seed for generated data, the same seed and time range return the same data.
+	// A random seed is used when not set.
+	Seed int64 `json:"seed,omitempty"`
+
+	Nodes           *NodesQuery           `json:"nodes,omitempty"`
+	PulseWave       *PulseWaveQuery       `json:"pulseWave,omitempty"`
+	Sim             *SimulationQuery      `json:"sim,omitempty"`
+	Stream          *StreamingQuery       `json:"stream,omitempty"`
+	Usa             *USAQuery             `json:"usa,omitempty"`
+	HighCardinality *HighCardinalityQuery `json:"highCardinality,omitempty"`
+	Flapping        *FlappingQuery        `json:"flapping,omitempty"`
+	CounterReset    *CounterResetQuery    `json:"counterReset,omitempty"`
 }
 
 // CSVWave defines model for CSVWave.
@@ -173,6 +185,30 @@ type StreamingQuery struct {
 	Url    string             `json:"url,omitempty"`
 }
 
+// HighCardinalityQuery defines model for HighCardinalityQuery.
+type HighCardinalityQuery struct {
+	// Number of labels of each series
+	LabelCount int `json:"labelCount,omitempty"`
+	// Number of distinct values of each label
+	ValuesPerLabel int `json:"valuesPerLabel,omitempty"`
+}
+
+// FlappingQuery defines model for FlappingQuery.
+type FlappingQuery struct {
+	Low  float64 `json:"low,omitempty"`
+	High float64 `json:"high,omitempty"`
+	// Chance the series changes state at each point (0-1)
+	FlapProbability float64 `json:"flapProbability,omitempty"`
+}
+
+// CounterResetQuery defines model for CounterResetQuery.
+type CounterResetQuery struct {
+	// Average increase per second
+	Rate float64 `json:"rate,omitempty"`
+	// Chance the counter resets to zero at each point (0-1)
+	ResetProbability float64 `json:"resetProbability,omitempty"`
+}
+
 // USAQuery defines model for USAQuery.
 type USAQuery struct {
 	Fields []string `json:"fields,omitempty"`
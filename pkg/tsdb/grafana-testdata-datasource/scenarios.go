@@ -205,6 +205,41 @@ Timestamps will line up evenly on timeStepSeconds (For example, 60 seconds means
 		handler: s.handleErrorWithSourceScenario,
 	})
 
+	s.registerScenario(&Scenario{
+		ID:          kinds.TestDataQueryTypeHighCardinality,
+		Name:        "High cardinality",
+		Description: "Series with distinct label sets, the same seed returns the same data",
+		handler:     s.handleSeededScenario(highCardinalitySeries),
+	})
+
+	s.registerScenario(&Scenario{
+		ID:          kinds.TestDataQueryTypeSeededLogs,
+		Name:        "Seeded logs",
+		Description: "Log lines with levels, the same seed returns the same data",
+		handler:     s.handleSeededScenario(seededLogs),
+	})
+
+	s.registerScenario(&Scenario{
+		ID:          kinds.TestDataQueryTypeSyntheticTraces,
+		Name:        "Synthetic traces",
+		Description: "A trace with nested spans, the same seed returns the same data",
+		handler:     s.handleSeededScenario(syntheticTrace),
+	})
+
+	s.registerScenario(&Scenario{
+		ID:          kinds.TestDataQueryTypeFlapping,
+		Name:        "Flapping",
+		Description: "Series switching between a low and a high value, the same seed returns the same data",
+		handler:     s.handleSeededScenario(flappingSeries),
+	})
+
+	s.registerScenario(&Scenario{
+		ID:          kinds.TestDataQueryTypeCounterReset,
+		Name:        "Counter reset",
+		Description: "Counters which reset to zero from time to time, the same seed returns the same data",
+		handler:     s.handleSeededScenario(counterResetSeries),
+	})
+
 	s.queryMux.HandleFunc("", s.handleFallbackScenario)
 }
 
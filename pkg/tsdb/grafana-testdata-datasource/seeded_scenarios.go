@@ -0,0 +1,390 @@
+package testdatasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/tsdb/grafana-testdata-datasource/kinds"
+)
+
+const (
+	// maxSeededSeries limits the number of series of a single query.
+	maxSeededSeries = 10000
+	// maxSeededPoints limits the number of points of all series of a single query.
+	maxSeededPoints = 1000000
+	// maxHighCardinalityLabels limits the number of labels of each high cardinality series.
+	maxHighCardinalityLabels = 20
+)
+
+// newSeededRand returns a random source for the query. The same seed and offset
+// return the same values, a random seed is used when the query has none.
+func newSeededRand(model kinds.TestDataQuery, offset int) *rand.Rand {
+	seed := model.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed + int64(offset)))
+}
+
+// seededTimes returns timestamps of the query range aligned to the query interval,
+// so the same seed and time range always return the same points.
+func seededTimes(query backend.DataQuery, maxPoints int64) []time.Time {
+	interval := query.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if query.MaxDataPoints > 0 && query.MaxDataPoints < maxPoints {
+		maxPoints = query.MaxDataPoints
+	}
+	times := make([]time.Time, 0)
+	for t := query.TimeRange.From.Truncate(interval); t.Before(query.TimeRange.To) && int64(len(times)) < maxPoints; t = t.Add(interval) {
+		if t.Before(query.TimeRange.From) {
+			continue
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+func (s *Service) handleSeededScenario(generate func(query backend.DataQuery, model kinds.TestDataQuery) ([]*data.Frame, error)) backend.QueryDataHandlerFunc {
+	return func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+		resp := backend.NewQueryDataResponse()
+
+		for _, q := range req.Queries {
+			model, err := GetJSONModel(q.JSON)
+			if err != nil {
+				continue
+			}
+			seedStartValue(q.JSON, &model)
+
+			respD := resp.Responses[q.RefID]
+			respD.Frames, err = generate(q, model)
+			if err != nil {
+				respD.Error = backend.DownstreamError(err)
+			}
+			resp.Responses[q.RefID] = respD
+		}
+
+		return resp, nil
+	}
+}
+
+// seedStartValue replaces the random default start value with one from the seed.
+func seedStartValue(j json.RawMessage, model *kinds.TestDataQuery) {
+	var startValue struct {
+		StartValue *float64 `json:"startValue"`
+	}
+	_ = json.Unmarshal(j, &startValue)
+	if startValue.StartValue == nil && model.Seed != 0 {
+		model.StartValue = newSeededRand(*model, -1).Float64() * 100
+	}
+}
+
+var highCardinalityLabelNames = []string{
+	"cluster",
+	"namespace",
+	"pod",
+	"container",
+	"instance",
+	"job",
+	"region",
+	"zone",
+}
+
+// highCardinalitySeries returns a series per label set. Label values of each series
+// are a distinct combination, so the series count is limited by the number of combinations.
+func highCardinalitySeries(query backend.DataQuery, model kinds.TestDataQuery) ([]*data.Frame, error) {
+	labelCount, valuesPerLabel := 3, 10
+	if model.HighCardinality != nil {
+		if model.HighCardinality.LabelCount > 0 {
+			labelCount = min(model.HighCardinality.LabelCount, maxHighCardinalityLabels)
+		}
+		if model.HighCardinality.ValuesPerLabel > 0 {
+			valuesPerLabel = model.HighCardinality.ValuesPerLabel
+		}
+	}
+	seriesCount := model.SeriesCount
+	if seriesCount < 1 || seriesCount > maxSeededSeries {
+		return nil, fmt.Errorf("series count must be between 1 and %d", maxSeededSeries)
+	}
+	combinations := 1
+	for i := 0; i < labelCount && combinations < seriesCount; i++ {
+		combinations *= valuesPerLabel
+	}
+	if combinations < seriesCount {
+		return nil, fmt.Errorf("%d labels with %d values each can't make %d distinct series", labelCount, valuesPerLabel, seriesCount)
+	}
+
+	times := seededTimes(query, int64(maxSeededPoints/seriesCount))
+	frames := make([]*data.Frame, 0, seriesCount)
+	for i := 0; i < seriesCount; i++ {
+		labels := make(data.Labels, labelCount)
+		rest := i
+		for l := 0; l < labelCount; l++ {
+			name := "label_" + strconv.Itoa(l)
+			if l < len(highCardinalityLabelNames) {
+				name = highCardinalityLabelNames[l]
+			}
+			labels[name] = name + "-" + strconv.Itoa(rest%valuesPerLabel)
+			rest /= valuesPerLabel
+		}
+
+		r := newSeededRand(model, i)
+		walker := model.StartValue
+		spread := model.Spread
+		values := make([]float64, len(times))
+		for j := range times {
+			values[j] = walker
+			walker += (r.Float64() - 0.5) * spread
+		}
+		frames = append(frames, data.NewFrame(frameNameForQuery(query, model, i),
+			data.NewField(data.TimeSeriesTimeFieldName, nil, times),
+			data.NewField(data.TimeSeriesValueFieldName, labels, values),
+		))
+	}
+	return frames, nil
+}
+
+// flappingSeries returns series which switch between a low and a high value.
+func flappingSeries(query backend.DataQuery, model kinds.TestDataQuery) ([]*data.Frame, error) {
+	options := kinds.FlappingQuery{High: 1, FlapProbability: 0.1}
+	if model.Flapping != nil {
+		// unset fields keep their default
+		options.Low = model.Flapping.Low
+		if model.Flapping.High != 0 {
+			options.High = model.Flapping.High
+		}
+		if model.Flapping.FlapProbability != 0 {
+			options.FlapProbability = model.Flapping.FlapProbability
+		}
+	}
+	return seededSeries(query, model, func(r *rand.Rand, times []time.Time) []float64 {
+		values := make([]float64, len(times))
+		high := r.Float64() < 0.5
+		for i := range times {
+			if r.Float64() < options.FlapProbability {
+				high = !high
+			}
+			values[i] = options.Low
+			if high {
+				values[i] = options.High
+			}
+			values[i] += r.Float64() * model.Noise
+		}
+		return values
+	})
+}
+
+// counterResetSeries returns monotonic counters which reset to zero from time to time.
+func counterResetSeries(query backend.DataQuery, model kinds.TestDataQuery) ([]*data.Frame, error) {
+	options := kinds.CounterResetQuery{Rate: 1, ResetProbability: 0.05}
+	if model.CounterReset != nil {
+		// unset fields keep their default
+		if model.CounterReset.Rate != 0 {
+			options.Rate = model.CounterReset.Rate
+		}
+		if model.CounterReset.ResetProbability != 0 {
+			options.ResetProbability = model.CounterReset.ResetProbability
+		}
+	}
+	interval := query.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return seededSeries(query, model, func(r *rand.Rand, times []time.Time) []float64 {
+		values := make([]float64, len(times))
+		counter := model.StartValue
+		for i := range times {
+			if i > 0 && r.Float64() < options.ResetProbability {
+				counter = 0
+			}
+			values[i] = counter
+			counter += options.Rate * interval.Seconds() * (0.5 + r.Float64())
+		}
+		return values
+	})
+}
+
+func seededSeries(query backend.DataQuery, model kinds.TestDataQuery, generate func(r *rand.Rand, times []time.Time) []float64) ([]*data.Frame, error) {
+	seriesCount := model.SeriesCount
+	if seriesCount < 1 || seriesCount > maxSeededSeries {
+		return nil, fmt.Errorf("series count must be between 1 and %d", maxSeededSeries)
+	}
+	times := seededTimes(query, int64(maxSeededPoints/seriesCount))
+	frames := make([]*data.Frame, 0, seriesCount)
+	for i := 0; i < seriesCount; i++ {
+		values := generate(newSeededRand(model, i), times)
+		frames = append(frames, data.NewFrame("",
+			data.NewField(data.TimeSeriesTimeFieldName, nil, times),
+			data.NewField(frameNameForQuery(query, model, i), parseLabels(model, i), values),
+		))
+	}
+	return frames, nil
+}
+
+var seededLogLevels = []string{"debug", "info", "info", "info", "info", "warn", "warn", "error", "critical"}
+
+var seededLogServices = []string{"api", "auth", "checkout", "frontend", "payments", "search"}
+
+var seededLogRequests = []struct {
+	method string
+	path   string
+}{
+	{"GET", "/api/products"},
+	{"GET", "/api/cart"},
+	{"POST", "/api/cart/items"},
+	{"POST", "/api/checkout"},
+	{"GET", "/api/search"},
+	{"DELETE", "/api/cart/items"},
+}
+
+// seededLogs returns log lines at random times of the query range, newest first. Error
+// levels come with server error statuses so log volume and error rate panels match.
+func seededLogs(query backend.DataQuery, model kinds.TestDataQuery) ([]*data.Frame, error) {
+	r := newSeededRand(model, 0)
+	lines := model.Lines
+	if lines < 0 || lines > maxSeededPoints {
+		return nil, fmt.Errorf("lines must be between 0 and %d", maxSeededPoints)
+	}
+
+	frame := data.NewFrame(query.RefID,
+		data.NewField("time", nil, []time.Time{}),
+		data.NewField("message", nil, []string{}),
+		data.NewField("service", nil, []string{}),
+	).SetMeta(&data.FrameMeta{
+		PreferredVisualization: data.VisTypeLogs,
+	})
+	if model.LevelColumn {
+		frame.Fields = append(frame.Fields, data.NewField("level", nil, []string{}))
+	}
+
+	from := query.TimeRange.From.UnixMilli()
+	rangeMs := query.TimeRange.To.UnixMilli() - from
+	if rangeMs <= 0 {
+		return []*data.Frame{frame}, nil
+	}
+
+	offsets := make([]int64, lines)
+	for i := range offsets {
+		offsets[i] = r.Int63n(rangeMs)
+	}
+	// Newest first like the logs scenario.
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] > offsets[j] })
+
+	for _, offset := range offsets {
+		level := seededLogLevels[r.Intn(len(seededLogLevels))]
+		service := seededLogServices[r.Intn(len(seededLogServices))]
+		request := seededLogRequests[r.Intn(len(seededLogRequests))]
+		status := 200
+		switch level {
+		case "warn":
+			status = 404
+		case "error", "critical":
+			status = 500 + r.Intn(4)
+		}
+		lvl := ""
+		if !model.LevelColumn {
+			lvl = "level=" + level + " "
+		}
+		message := fmt.Sprintf("%smsg=\"request completed\" method=%s path=%s status=%d duration_ms=%d user_id=%d",
+			lvl, request.method, request.path, status, 1+r.Intn(2000), 1+r.Intn(100))
+
+		t := time.UnixMilli(from + offset)
+		if model.LevelColumn {
+			frame.AppendRow(t, message, service, level)
+		} else {
+			frame.AppendRow(t, message, service)
+		}
+	}
+	return []*data.Frame{frame}, nil
+}
+
+var syntheticTraceOperations = []string{"HTTP GET", "HTTP POST", "SELECT", "INSERT", "cache get", "publish", "render"}
+
+// syntheticTrace returns a trace frame with a root span and child spans. Each child span
+// starts within its parent span and ends before it.
+func syntheticTrace(query backend.DataQuery, model kinds.TestDataQuery) ([]*data.Frame, error) {
+	r := newSeededRand(model, 0)
+	spanCount := model.SpanCount
+	if spanCount == 0 {
+		spanCount = 10
+	}
+	if spanCount < 0 || spanCount > maxSeededSeries {
+		return nil, fmt.Errorf("span count must be between 1 and %d", maxSeededSeries)
+	}
+
+	frame := data.NewFrame("Trace",
+		data.NewField("traceID", nil, []string{}),
+		data.NewField("spanID", nil, []string{}),
+		data.NewField("parentSpanID", nil, []string{}),
+		data.NewField("operationName", nil, []string{}),
+		data.NewField("serviceName", nil, []string{}),
+		data.NewField("kind", nil, []string{}),
+		data.NewField("statusCode", nil, []int64{}),
+		data.NewField("serviceTags", nil, []json.RawMessage{}),
+		data.NewField("startTime", nil, []float64{}),
+		data.NewField("duration", nil, []float64{}),
+		data.NewField("tags", nil, []json.RawMessage{}),
+	).SetMeta(&data.FrameMeta{
+		PreferredVisualization: data.VisTypeTrace,
+	})
+
+	type span struct {
+		id       string
+		start    float64
+		duration float64
+	}
+	traceID := fmt.Sprintf("%016x%016x", r.Uint64(), r.Uint64())
+	// The root span starts at a random time of the query range.
+	rangeMs := float64(query.TimeRange.To.Sub(query.TimeRange.From).Milliseconds())
+	root := span{
+		id:       fmt.Sprintf("%016x", r.Uint64()),
+		start:    float64(query.TimeRange.From.UnixMilli()) + r.Float64()*rangeMs/2,
+		duration: 100 + r.Float64()*1900,
+	}
+	spans := []span{root}
+
+	for i := 0; i < spanCount; i++ {
+		current := root
+		parentID := ""
+		kind := "server"
+		if i > 0 {
+			parent := spans[r.Intn(len(spans))]
+			offset := r.Float64() * parent.duration / 2
+			current = span{
+				id:       fmt.Sprintf("%016x", r.Uint64()),
+				start:    parent.start + offset,
+				duration: (parent.duration - offset) * (0.1 + 0.8*r.Float64()),
+			}
+			spans = append(spans, current)
+			parentID = parent.id
+			kind = "client"
+		}
+
+		service := seededLogServices[r.Intn(len(seededLogServices))]
+		operation := syntheticTraceOperations[r.Intn(len(syntheticTraceOperations))]
+		statusCode := int64(0)
+		if r.Float64() < 0.05 {
+			statusCode = 2
+		}
+		serviceTags, err := json.Marshal([]map[string]any{{"key": "service.name", "value": service}})
+		if err != nil {
+			return nil, err
+		}
+		tags, err := json.Marshal([]map[string]any{{"key": "span.index", "value": i}})
+		if err != nil {
+			return nil, err
+		}
+		frame.AppendRow(traceID, current.id, parentID, operation, service, kind, statusCode,
+			json.RawMessage(serviceTags), current.start, current.duration, json.RawMessage(tags))
+	}
+	return []*data.Frame{frame}, nil
+}
@@ -0,0 +1,182 @@
+package testdatasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/grafana-testdata-datasource/kinds"
+)
+
+func TestSeededScenarios(t *testing.T) {
+	s := &Service{}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	query := func(scenario func(query backend.DataQuery, model kinds.TestDataQuery) ([]*data.Frame, error), model string) backend.DataResponse {
+		t.Helper()
+		q := backend.DataQuery{
+			RefID:         "A",
+			TimeRange:     backend.TimeRange{From: from, To: from.Add(10 * time.Minute)},
+			Interval:      10 * time.Second,
+			MaxDataPoints: 1000,
+			JSON:          []byte(model),
+		}
+		resp, err := s.handleSeededScenario(scenario)(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{q},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("Should return the same data for the same seed", func(t *testing.T) {
+		scenarios := map[string]func(query backend.DataQuery, model kinds.TestDataQuery) ([]*data.Frame, error){
+			"high cardinality": highCardinalitySeries,
+			"flapping":         flappingSeries,
+			"counter reset":    counterResetSeries,
+			"seeded logs":      seededLogs,
+			"synthetic traces": syntheticTrace,
+		}
+		for name, scenario := range scenarios {
+			t.Run(name, func(t *testing.T) {
+				first := query(scenario, `{"seed": 42, "seriesCount": 5, "lines": 20}`)
+				second := query(scenario, `{"seed": 42, "seriesCount": 5, "lines": 20}`)
+				other := query(scenario, `{"seed": 43, "seriesCount": 5, "lines": 20}`)
+				require.NoError(t, first.Error)
+				require.Equal(t, first.Frames, second.Frames)
+				require.NotEqual(t, first.Frames, other.Frames)
+			})
+		}
+	})
+
+	t.Run("high cardinality", func(t *testing.T) {
+		t.Run("Should return series with distinct label sets", func(t *testing.T) {
+			resp := query(highCardinalitySeries, `{"seed": 1, "seriesCount": 100, "highCardinality": {"labelCount": 2, "valuesPerLabel": 10}}`)
+			require.NoError(t, resp.Error)
+			require.Len(t, resp.Frames, 100)
+
+			seen := map[string]bool{}
+			for _, frame := range resp.Frames {
+				labels := frame.Fields[1].Labels
+				require.Len(t, labels, 2)
+				require.False(t, seen[labels.String()], "duplicate labels %s", labels)
+				seen[labels.String()] = true
+				require.Equal(t, 60, frame.Rows())
+			}
+		})
+
+		t.Run("Should limit the number of labels", func(t *testing.T) {
+			resp := query(highCardinalitySeries, `{"seed": 1, "seriesCount": 2, "highCardinality": {"labelCount": 1000000000, "valuesPerLabel": 2}}`)
+			require.NoError(t, resp.Error)
+			require.Len(t, resp.Frames, 2)
+			for _, frame := range resp.Frames {
+				require.Len(t, frame.Fields[1].Labels, maxHighCardinalityLabels)
+			}
+		})
+
+		t.Run("Should fail when labels can't make enough series", func(t *testing.T) {
+			resp := query(highCardinalitySeries, `{"seed": 1, "seriesCount": 101, "highCardinality": {"labelCount": 2, "valuesPerLabel": 10}}`)
+			require.Error(t, resp.Error)
+		})
+	})
+
+	t.Run("flapping", func(t *testing.T) {
+		t.Run("Should only return low and high values", func(t *testing.T) {
+			resp := query(flappingSeries, `{"seed": 1, "flapping": {"low": 2, "high": 5, "flapProbability": 0.5}}`)
+			require.NoError(t, resp.Error)
+			require.Len(t, resp.Frames, 1)
+
+			values := map[float64]bool{}
+			field := resp.Frames[0].Fields[1]
+			for i := 0; i < field.Len(); i++ {
+				values[field.At(i).(float64)] = true
+			}
+			require.Equal(t, map[float64]bool{2: true, 5: true}, values)
+		})
+
+		t.Run("Should use the defaults for the fields which aren't set", func(t *testing.T) {
+			resp := query(flappingSeries, `{"seed": 1, "flapping": {"high": 5}}`)
+			require.NoError(t, resp.Error)
+
+			values := map[float64]bool{}
+			field := resp.Frames[0].Fields[1]
+			for i := 0; i < field.Len(); i++ {
+				values[field.At(i).(float64)] = true
+			}
+			require.Equal(t, map[float64]bool{0: true, 5: true}, values)
+		})
+	})
+
+	t.Run("counter reset", func(t *testing.T) {
+		t.Run("Should only decrease on reset", func(t *testing.T) {
+			resp := query(counterResetSeries, `{"seed": 1, "startValue": 0, "counterReset": {"rate": 1, "resetProbability": 0.1}}`)
+			require.NoError(t, resp.Error)
+
+			field := resp.Frames[0].Fields[1]
+			resets := 0
+			for i := 1; i < field.Len(); i++ {
+				prev, cur := field.At(i-1).(float64), field.At(i).(float64)
+				if cur < prev {
+					require.Equal(t, 0.0, cur)
+					resets++
+				}
+			}
+			require.Greater(t, resets, 0)
+		})
+	})
+
+	t.Run("seeded logs", func(t *testing.T) {
+		t.Run("Should return log lines in range with levels, newest first", func(t *testing.T) {
+			resp := query(seededLogs, `{"seed": 1, "lines": 50, "levelColumn": true}`)
+			require.NoError(t, resp.Error)
+			require.Len(t, resp.Frames, 1)
+
+			frame := resp.Frames[0]
+			require.Equal(t, data.VisTypeLogs, string(frame.Meta.PreferredVisualization))
+			require.Equal(t, 50, frame.Rows())
+			require.Equal(t, "level", frame.Fields[3].Name)
+			for i := 0; i < frame.Rows(); i++ {
+				ts := frame.Fields[0].At(i).(time.Time)
+				require.False(t, ts.Before(from))
+				if i > 0 {
+					require.False(t, ts.After(frame.Fields[0].At(i-1).(time.Time)))
+				}
+			}
+		})
+	})
+
+	t.Run("synthetic traces", func(t *testing.T) {
+		t.Run("Should return spans with existing parents within parent time", func(t *testing.T) {
+			resp := query(syntheticTrace, `{"seed": 1, "spanCount": 30}`)
+			require.NoError(t, resp.Error)
+			require.Len(t, resp.Frames, 1)
+
+			frame := resp.Frames[0]
+			require.Equal(t, 30, frame.Rows())
+
+			type span struct{ start, end float64 }
+			spans := map[string]span{}
+			for i := 0; i < frame.Rows(); i++ {
+				start := frame.Fields[8].At(i).(float64)
+				spans[frame.Fields[1].At(i).(string)] = span{start, start + frame.Fields[9].At(i).(float64)}
+			}
+			roots := 0
+			for i := 0; i < frame.Rows(); i++ {
+				parentID := frame.Fields[2].At(i).(string)
+				if parentID == "" {
+					roots++
+					continue
+				}
+				parent, ok := spans[parentID]
+				require.True(t, ok)
+				child := spans[frame.Fields[1].At(i).(string)]
+				require.GreaterOrEqual(t, child.start, parent.start)
+				require.LessOrEqual(t, child.end, parent.end)
+			}
+			require.Equal(t, 1, roots)
+		})
+	})
+}
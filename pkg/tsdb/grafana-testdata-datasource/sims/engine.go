@@ -66,6 +66,7 @@ func NewSimulationEngine() (*SimulationEngine, error) {
 		newFlightSimInfo,
 		newSinewaveInfo,
 		newTankSimInfo,
+		newModelSimInfo,
 	}
 
 	for _, init := range initializers {
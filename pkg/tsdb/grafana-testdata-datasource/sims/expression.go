@@ -0,0 +1,203 @@
+package sims
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// expressionEnv holds the values an expression can read while it is evaluated.
+type expressionEnv struct {
+	vars map[string]float64
+	rand *rand.Rand
+}
+
+// expression is a compiled arithmetic expression of model simulations. Booleans are
+// numbers: comparisons return 1 or 0 and any non zero value is true.
+type expression func(env *expressionEnv) float64
+
+// expressionFunc is the implementation of a function, it's kept in parse.Func.F.
+type expressionFunc func(env *expressionEnv, args []float64) float64
+
+func mathFunc(f func(float64) float64) parse.Func {
+	return expressionFuncDef(1, func(_ *expressionEnv, a []float64) float64 { return f(a[0]) })
+}
+
+func expressionFuncDef(args int, f expressionFunc) parse.Func {
+	if args == 0 {
+		return parse.Func{Return: parse.TypeScalar, F: f}
+	}
+	types := make([]parse.ReturnType, args)
+	for i := range types {
+		types[i] = parse.TypeVariantSet
+	}
+	return parse.Func{Args: types, VariantReturn: true, F: f}
+}
+
+var expressionFuncs = map[string]parse.Func{
+	"abs":   mathFunc(math.Abs),
+	"sqrt":  mathFunc(math.Sqrt),
+	"exp":   mathFunc(math.Exp),
+	"log":   mathFunc(math.Log),
+	"sin":   mathFunc(math.Sin),
+	"cos":   mathFunc(math.Cos),
+	"floor": mathFunc(math.Floor),
+	"ceil":  mathFunc(math.Ceil),
+	"round": mathFunc(math.Round),
+	"min":   expressionFuncDef(2, func(_ *expressionEnv, a []float64) float64 { return math.Min(a[0], a[1]) }),
+	"max":   expressionFuncDef(2, func(_ *expressionEnv, a []float64) float64 { return math.Max(a[0], a[1]) }),
+	"clamp": expressionFuncDef(3, func(_ *expressionEnv, a []float64) float64 { return math.Max(a[1], math.Min(a[2], a[0])) }),
+	// if returns the second argument when the first one is true, otherwise the third one.
+	// Only the returned argument is evaluated.
+	"if": expressionFuncDef(3, nil),
+	// random returns a uniform random number in [0, 1).
+	"random": expressionFuncDef(0, func(env *expressionEnv, _ []float64) float64 { return env.rand.Float64() }),
+	// normal returns a normally distributed random number with mean and standard deviation.
+	"normal": expressionFuncDef(2, func(env *expressionEnv, a []float64) float64 { return a[0] + env.rand.NormFloat64()*a[1] }),
+	// poisson returns a poisson distributed random number of events with mean, e.g. queue arrivals in a step.
+	"poisson": expressionFuncDef(1, func(env *expressionEnv, a []float64) float64 { return poisson(env.rand, a[0]) }),
+}
+
+func poisson(r *rand.Rand, mean float64) float64 {
+	if mean <= 0 {
+		return 0
+	}
+	// Normal approximation keeps large means cheap.
+	if mean > 500 {
+		return math.Max(0, math.Round(mean+r.NormFloat64()*math.Sqrt(mean)))
+	}
+	limit := math.Exp(-mean)
+	k, p := 0.0, r.Float64()
+	for p > limit {
+		k++
+		p *= r.Float64()
+	}
+	return k
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func binaryOperation(op string, l, r expression) expression {
+	switch op {
+	case "||":
+		return func(env *expressionEnv) float64 { return boolValue(l(env) != 0 || r(env) != 0) }
+	case "&&":
+		return func(env *expressionEnv) float64 { return boolValue(l(env) != 0 && r(env) != 0) }
+	case "==":
+		return func(env *expressionEnv) float64 { return boolValue(l(env) == r(env)) }
+	case "!=":
+		return func(env *expressionEnv) float64 { return boolValue(l(env) != r(env)) }
+	case "<":
+		return func(env *expressionEnv) float64 { return boolValue(l(env) < r(env)) }
+	case "<=":
+		return func(env *expressionEnv) float64 { return boolValue(l(env) <= r(env)) }
+	case ">":
+		return func(env *expressionEnv) float64 { return boolValue(l(env) > r(env)) }
+	case ">=":
+		return func(env *expressionEnv) float64 { return boolValue(l(env) >= r(env)) }
+	case "+":
+		return func(env *expressionEnv) float64 { return l(env) + r(env) }
+	case "-":
+		return func(env *expressionEnv) float64 { return l(env) - r(env) }
+	case "*":
+		return func(env *expressionEnv) float64 { return l(env) * r(env) }
+	case "/":
+		return func(env *expressionEnv) float64 { return l(env) / r(env) }
+	case "%":
+		return func(env *expressionEnv) float64 { return math.Mod(l(env), r(env)) }
+	case "**":
+		return func(env *expressionEnv) float64 { return math.Pow(l(env), r(env)) }
+	}
+	return nil
+}
+
+// compileExpression parses an expression which can read the given names. It uses the
+// syntax of server side math expressions, names are referenced as $name and function
+// arguments are separated by commas. Supported are numbers, the arithmetic, comparison
+// and logical operators and the functions of expressionFuncs.
+func compileExpression(input string, names map[string]bool) (expression, error) {
+	tree := parse.New()
+	tree.Mode = parse.CommaArgs
+	if err := tree.Parse(input, expressionFuncs); err != nil {
+		return nil, err
+	}
+	return compileNode(tree.Root, names)
+}
+
+func compileNode(node parse.Node, names map[string]bool) (expression, error) {
+	switch n := node.(type) {
+	case *parse.ScalarNode:
+		v := n.Float64
+		return func(*expressionEnv) float64 { return v }, nil
+
+	case *parse.VarNode:
+		if !names[n.Name] {
+			return nil, fmt.Errorf("unknown name %q", n.Name)
+		}
+		name := n.Name
+		return func(env *expressionEnv) float64 { return env.vars[name] }, nil
+
+	case *parse.UnaryNode:
+		v, err := compileNode(n.Arg, names)
+		if err != nil {
+			return nil, err
+		}
+		switch n.OpStr {
+		case "-":
+			return func(env *expressionEnv) float64 { return -v(env) }, nil
+		case "!":
+			return func(env *expressionEnv) float64 { return boolValue(v(env) == 0) }, nil
+		}
+		return nil, fmt.Errorf("unsupported operator %s", n.OpStr)
+
+	case *parse.BinaryNode:
+		l, err := compileNode(n.Args[0], names)
+		if err != nil {
+			return nil, err
+		}
+		r, err := compileNode(n.Args[1], names)
+		if err != nil {
+			return nil, err
+		}
+		if op := binaryOperation(n.OpStr, l, r); op != nil {
+			return op, nil
+		}
+		return nil, fmt.Errorf("unsupported operator %s", n.OpStr)
+
+	case *parse.FuncNode:
+		args := make([]expression, len(n.Args))
+		for i, arg := range n.Args {
+			var err error
+			if args[i], err = compileNode(arg, names); err != nil {
+				return nil, err
+			}
+		}
+		if n.Name == "if" {
+			return func(env *expressionEnv) float64 {
+				if args[0](env) != 0 {
+					return args[1](env)
+				}
+				return args[2](env)
+			}, nil
+		}
+		fn, ok := n.F.F.(expressionFunc)
+		if !ok {
+			return nil, fmt.Errorf("unsupported function %s", n.Name)
+		}
+		return func(env *expressionEnv) float64 {
+			values := make([]float64, len(args))
+			for i, arg := range args {
+				values[i] = arg(env)
+			}
+			return fn(env, values)
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported expression %s", node.String())
+}
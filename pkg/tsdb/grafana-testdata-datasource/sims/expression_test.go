@@ -0,0 +1,91 @@
+package sims
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileExpression(t *testing.T) {
+	env := &expressionEnv{
+		vars: map[string]float64{"x": 3, "y": -2},
+		rand: rand.New(rand.NewSource(1)),
+	}
+	names := map[string]bool{"x": true, "y": true}
+
+	tests := []struct {
+		expr   string
+		result float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"2 ** 3 ** 2", 64},
+		{"-2 ** 2", 4},
+		{"2 ** -1", 0.5},
+		{"7 % 4", 3},
+		{"1.5e2 + .5", 150.5},
+		{"$x * ${y}", -6},
+		{"-$x", -3},
+		{"$x > $y", 1},
+		{"$x <= $y", 0},
+		{"$x == 3 && $y != 3", 1},
+		{"$x < 0 || !($y < 0)", 0},
+		{"if($x > 0, 10, 20)", 10},
+		{"if($x < 0, 10, if($y < 0, 20, 30))", 20},
+		{"if(1, 2, 1 / 0)", 2},
+		{"abs($y) + min($x, $y) + max($x, 10)", 10},
+		{"clamp($x, 0, 1)", 1},
+		{"floor(2.7) + ceil(2.2) + round(2.5)", 8},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			expr, err := compileExpression(tc.expr, names)
+			require.NoError(t, err)
+			require.InDelta(t, tc.result, expr(env), 1e-9)
+		})
+	}
+
+	t.Run("random functions", func(t *testing.T) {
+		expr, err := compileExpression("random()", names)
+		require.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			v := expr(env)
+			require.GreaterOrEqual(t, v, 0.0)
+			require.Less(t, v, 1.0)
+		}
+
+		expr, err = compileExpression("poisson(0)", names)
+		require.NoError(t, err)
+		require.Equal(t, 0.0, expr(env))
+
+		expr, err = compileExpression("poisson(4)", names)
+		require.NoError(t, err)
+		sum := 0.0
+		for i := 0; i < 1000; i++ {
+			sum += expr(env)
+		}
+		require.InDelta(t, 4, sum/1000, 0.5)
+	})
+
+	errors := []string{
+		"",
+		"1 +",
+		"(1 + 2",
+		"1 2",
+		"$z + 1",
+		"x + 1",
+		"unknown(1)",
+		"clamp(1, 2)",
+		"max()",
+		"1 ? 2",
+		"\"a\" + 1",
+	}
+	for _, input := range errors {
+		t.Run("invalid "+input, func(t *testing.T) {
+			_, err := compileExpression(input, names)
+			require.Error(t, err)
+		})
+	}
+}
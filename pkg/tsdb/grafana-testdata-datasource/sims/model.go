@@ -0,0 +1,302 @@
+package sims
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// maxModelSteps limits the steps of a single GetValues call, a model which is far
+// behind skips ahead instead.
+const maxModelSteps = 100000
+
+var modelNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedModelNames can not be used as variable or parameter names.
+var reservedModelNames = map[string]bool{
+	"dt": true, // step size in seconds
+	"t":  true, // seconds since the model started
+}
+
+// modelSim steps a user defined model. The state variables and the update equations
+// come from the config, so queues, controllers or state machines need no code.
+type modelSim struct {
+	key simulationKey
+
+	mu    sync.Mutex
+	cfg   modelConfig
+	model *compiledModel
+	state modelState
+}
+
+var (
+	_ Simulation = (*modelSim)(nil)
+)
+
+type modelVariable struct {
+	Name    string  `json:"name"`
+	Initial float64 `json:"initial"`
+	Unit    string  `json:"unit,omitempty"`
+	Hidden  bool    `json:"hidden,omitempty"` // not included in frames, e.g. an integral of a controller
+}
+
+type modelUpdate struct {
+	Variable   string `json:"variable"`
+	Expression string `json:"expression"`
+}
+
+type modelConfig struct {
+	Seed      int64              `json:"seed,omitempty"`   // random seed, the same seed returns the same values
+	Step      float64            `json:"step,omitempty"`   // seconds, defaults to the tick interval
+	Params    map[string]float64 `json:"params,omitempty"` // constants which can be changed while running
+	Variables []modelVariable    `json:"variables"`
+	Updates   []modelUpdate      `json:"updates"` // evaluated in order each step
+}
+
+type compiledUpdate struct {
+	variable string
+	expr     expression
+}
+
+type compiledModel struct {
+	step    time.Duration
+	updates []compiledUpdate
+}
+
+type modelState struct {
+	Start  time.Time
+	Time   time.Time
+	Values map[string]float64
+	rand   *rand.Rand
+}
+
+func compileModel(cfg modelConfig, tickHZ float64) (*compiledModel, error) {
+	if len(cfg.Variables) == 0 {
+		return nil, fmt.Errorf("model has no variables")
+	}
+	names := map[string]bool{"dt": true, "t": true}
+	checkName := func(name string) error {
+		if !modelNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid name %q", name)
+		}
+		if reservedModelNames[name] {
+			return fmt.Errorf("name %q is reserved", name)
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate name %q", name)
+		}
+		names[name] = true
+		return nil
+	}
+	for _, v := range cfg.Variables {
+		if err := checkName(v.Name); err != nil {
+			return nil, err
+		}
+	}
+	isVariable := make(map[string]bool, len(cfg.Variables))
+	for _, v := range cfg.Variables {
+		isVariable[v.Name] = true
+	}
+	for name := range cfg.Params {
+		if err := checkName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	m := &compiledModel{}
+	for _, u := range cfg.Updates {
+		if !isVariable[u.Variable] {
+			return nil, fmt.Errorf("update of unknown variable %q", u.Variable)
+		}
+		expr, err := compileExpression(u.Expression, names)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression for %s: %w", u.Variable, err)
+		}
+		m.updates = append(m.updates, compiledUpdate{variable: u.Variable, expr: expr})
+	}
+
+	step := cfg.Step
+	if step <= 0 {
+		step = 1 / tickHZ
+	}
+	m.step = time.Duration(step * float64(time.Second))
+	if m.step < time.Millisecond {
+		return nil, fmt.Errorf("step must be at least 1ms")
+	}
+	return m, nil
+}
+
+func (s *modelSim) GetState() simulationState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return simulationState{
+		Key:    s.key,
+		Config: s.cfg,
+	}
+}
+
+// SetConfig replaces the model. Values of variables which are still in the model are kept,
+// so parameters such as a controller set point can be changed while the model is running.
+func (s *modelSim) SetConfig(vals map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.cfg
+	next.Params = maps.Clone(s.cfg.Params)
+	if err := updateConfigObjectFromJSON(&next, vals); err != nil {
+		return err
+	}
+	model, err := compileModel(next, s.key.TickHZ)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]float64, len(next.Variables))
+	for _, v := range next.Variables {
+		current, ok := s.state.Values[v.Name]
+		if !ok {
+			current = v.Initial
+		}
+		values[v.Name] = current
+	}
+	s.state.Values = values
+	if next.Seed != s.cfg.Seed {
+		s.state.rand = rand.New(rand.NewSource(next.Seed))
+	}
+	s.cfg = next
+	s.model = model
+	return nil
+}
+
+func (s *modelSim) NewFrame(size int) *data.Frame {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frame := data.NewFrame("", data.NewField("time", nil, make([]time.Time, size)))
+	for _, v := range s.cfg.Variables {
+		if v.Hidden {
+			continue
+		}
+		field := data.NewField(v.Name, nil, make([]float64, size))
+		if v.Unit != "" {
+			field.Config = &data.FieldConfig{Unit: v.Unit}
+		}
+		frame.Fields = append(frame.Fields, field)
+	}
+	return frame
+}
+
+// GetValues steps the model up to t. The first call starts the model at t.
+func (s *modelSim) GetValues(t time.Time) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state.Time.IsZero() {
+		s.state.Start = t
+		s.state.Time = t
+	}
+	if t.Before(s.state.Time) {
+		return nil // can not look backwards!
+	}
+
+	env := &expressionEnv{
+		vars: make(map[string]float64, len(s.cfg.Params)+len(s.state.Values)+2),
+		rand: s.state.rand,
+	}
+	for k, v := range s.cfg.Params {
+		env.vars[k] = v
+	}
+	for k, v := range s.state.Values {
+		env.vars[k] = v
+	}
+	env.vars["dt"] = s.model.step.Seconds()
+
+	steps := 0
+	for !s.state.Time.Add(s.model.step).After(t) {
+		if steps == maxModelSteps {
+			s.state.Time = t
+			break
+		}
+		s.state.Time = s.state.Time.Add(s.model.step)
+		env.vars["t"] = s.state.Time.Sub(s.state.Start).Seconds()
+		for _, u := range s.model.updates {
+			env.vars[u.variable] = u.expr(env)
+		}
+		steps++
+	}
+
+	values := map[string]any{
+		"time": t,
+	}
+	for name := range s.state.Values {
+		s.state.Values[name] = env.vars[name]
+		values[name] = env.vars[name]
+	}
+	return values
+}
+
+func (s *modelSim) Close() error {
+	return nil
+}
+
+// defaultModelConfig is a queue with random arrivals and departures.
+func defaultModelConfig() modelConfig {
+	return modelConfig{
+		Seed: 1,
+		Params: map[string]float64{
+			"arrivalRate": 5,
+			"serviceRate": 5.5,
+		},
+		Variables: []modelVariable{
+			{Name: "queue", Initial: 0},
+		},
+		Updates: []modelUpdate{
+			{Variable: "queue", Expression: "max(0, $queue + poisson($arrivalRate * $dt) - poisson($serviceRate * $dt))"},
+		},
+	}
+}
+
+func newModelSimInfo() simulationInfo {
+	df := data.NewFrame("")
+	df.Fields = append(df.Fields, data.NewField("seed", nil, []int64{1}))
+	df.Fields = append(df.Fields, data.NewField("step", nil, []float64{0}).SetConfig(&data.FieldConfig{
+		Unit: "s",
+	}))
+
+	return simulationInfo{
+		Type:         "model",
+		Name:         "Model",
+		Description:  "Step variables with update equations from the config",
+		ConfigFields: df,
+		OnlyForward:  true,
+		create: func(cfg simulationState) (Simulation, error) {
+			s := &modelSim{
+				key: cfg.Key,
+				cfg: defaultModelConfig(),
+			}
+			// A config with variables replaces the default model, otherwise it changes the default model.
+			if input, err := asStringMap(cfg.Config); err == nil && input["variables"] != nil {
+				s.cfg = modelConfig{}
+			}
+			if err := updateConfigObjectFromJSON(&s.cfg, cfg.Config); err != nil {
+				return nil, err
+			}
+			model, err := compileModel(s.cfg, cfg.Key.TickHZ)
+			if err != nil {
+				return nil, err
+			}
+			s.model = model
+			s.state.rand = rand.New(rand.NewSource(s.cfg.Seed))
+			s.state.Values = make(map[string]float64, len(s.cfg.Variables))
+			for _, v := range s.cfg.Variables {
+				s.state.Values[v.Name] = v.Initial
+			}
+			return s, nil
+		},
+	}
+}
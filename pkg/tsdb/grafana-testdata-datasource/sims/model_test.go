@@ -0,0 +1,172 @@
+package sims
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelSimulation(t *testing.T) {
+	start := time.Date(2020, time.January, 10, 23, 0, 0, 0, time.UTC)
+
+	lookup := func(t *testing.T, s *SimulationEngine, uid string, config map[string]any) Simulation {
+		t.Helper()
+		sim, err := s.Lookup(simulationState{
+			Key:    simulationKey{Type: "model", TickHZ: 1, UID: uid},
+			Config: config,
+		})
+		require.NoError(t, err)
+		return sim
+	}
+
+	t.Run("default queue model", func(t *testing.T) {
+		s, err := NewSimulationEngine()
+		require.NoError(t, err)
+		sim := lookup(t, s, "", nil)
+
+		frame := sim.NewFrame(0)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, "queue", frame.Fields[1].Name)
+
+		for i := 0; i < 60; i++ {
+			v := sim.GetValues(start.Add(time.Duration(i) * time.Second))
+			require.GreaterOrEqual(t, v["queue"].(float64), 0.0)
+		}
+		// can not look backwards
+		require.Nil(t, sim.GetValues(start))
+	})
+
+	t.Run("PID controller reaches the set point", func(t *testing.T) {
+		s, err := NewSimulationEngine()
+		require.NoError(t, err)
+		sim := lookup(t, s, "pid", map[string]any{
+			"step": 0.1,
+			"params": map[string]any{
+				"setpoint": 50, "kp": 2, "ki": 0.5, "kd": 0.1,
+			},
+			"variables": []map[string]any{
+				{"name": "temperature", "initial": 20, "unit": "celsius"},
+				{"name": "output"},
+				{"name": "integral", "hidden": true},
+				{"name": "lastError", "hidden": true},
+			},
+			"updates": []map[string]any{
+				{"variable": "integral", "expression": "$integral + ($setpoint - $temperature) * $dt"},
+				{"variable": "output", "expression": "clamp($kp * ($setpoint - $temperature) + $ki * $integral + $kd * (($setpoint - $temperature) - $lastError) / $dt, 0, 100)"},
+				{"variable": "lastError", "expression": "$setpoint - $temperature"},
+				{"variable": "temperature", "expression": "$temperature + ($output - 0.5 * ($temperature - 20)) * $dt"},
+			},
+		})
+
+		frame := sim.NewFrame(0)
+		require.Len(t, frame.Fields, 3) // hidden variables are not in frames
+		require.Equal(t, "celsius", frame.Fields[1].Config.Unit)
+
+		sim.GetValues(start)
+		v := sim.GetValues(start.Add(2 * time.Minute))
+		require.InDelta(t, 50, v["temperature"].(float64), 0.5)
+
+		// parameters can be changed while running
+		require.NoError(t, sim.SetConfig(map[string]any{"params": map[string]any{"setpoint": 30}}))
+		v = sim.GetValues(start.Add(4 * time.Minute))
+		require.InDelta(t, 30, v["temperature"].(float64), 0.5)
+
+		// invalid models keep the current model
+		require.Error(t, sim.SetConfig(map[string]any{"updates": []map[string]any{{"variable": "temperature", "expression": "$unknown + 1"}}}))
+		v = sim.GetValues(start.Add(5 * time.Minute))
+		require.InDelta(t, 30, v["temperature"].(float64), 0.5)
+	})
+
+	t.Run("Markov state machine is reproducible with a seed", func(t *testing.T) {
+		config := map[string]any{
+			"seed": 42,
+			"variables": []map[string]any{
+				{"name": "state"},
+				{"name": "r", "hidden": true},
+			},
+			"updates": []map[string]any{
+				{"variable": "r", "expression": "random()"},
+				{"variable": "state", "expression": "if($state == 0, if($r < 0.9, 0, 1), if($state == 1, if($r < 0.3, 0, if($r < 0.8, 1, 2)), if($r < 0.5, 0, 2)))"},
+			},
+		}
+		values := func(uid string) []float64 {
+			s, err := NewSimulationEngine()
+			require.NoError(t, err)
+			sim := lookup(t, s, uid, config)
+			var result []float64
+			for i := 0; i < 100; i++ {
+				result = append(result, sim.GetValues(start.Add(time.Duration(i) * time.Second))["state"].(float64))
+			}
+			return result
+		}
+
+		first := values("a")
+		require.Equal(t, first, values("b"))
+		seen := map[float64]bool{}
+		for _, v := range first {
+			seen[v] = true
+		}
+		require.Equal(t, map[float64]bool{0: true, 1: true, 2: true}, seen)
+	})
+
+	t.Run("invalid models", func(t *testing.T) {
+		s, err := NewSimulationEngine()
+		require.NoError(t, err)
+		invalid := []map[string]any{
+			{"variables": []map[string]any{}},
+			{"variables": []map[string]any{{"name": "dt"}}},
+			{"variables": []map[string]any{{"name": "a"}, {"name": "a"}}},
+			{"variables": []map[string]any{{"name": "a"}}, "params": map[string]any{"a": 1}},
+			{"variables": []map[string]any{{"name": "a b"}}},
+			{"variables": []map[string]any{{"name": "a"}}, "updates": []map[string]any{{"variable": "b", "expression": "1"}}},
+			{"variables": []map[string]any{{"name": "a"}}, "updates": []map[string]any{{"variable": "a", "expression": "$a +"}}},
+		}
+		for i, config := range invalid {
+			_, err := s.Lookup(simulationState{
+				Key:    simulationKey{Type: "model", TickHZ: 1, UID: string(rune('a' + i))},
+				Config: config,
+			})
+			require.Error(t, err, config)
+		}
+	})
+
+	t.Run("query with model from query JSON", func(t *testing.T) {
+		s, err := NewSimulationEngine()
+		require.NoError(t, err)
+		sb, err := json.Marshal(map[string]any{
+			"sim": map[string]any{
+				"key": map[string]any{"type": "model", "tick": 1, "uid": "counter"},
+				"config": map[string]any{
+					"variables": []map[string]any{{"name": "count"}},
+					"updates":   []map[string]any{{"variable": "count", "expression": "$count + 1"}},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		rsp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:         "A",
+				TimeRange:     backend.TimeRange{From: start, To: start.Add(10 * time.Second)},
+				Interval:      time.Second,
+				MaxDataPoints: 10,
+				JSON:          sb,
+			}},
+		})
+		require.NoError(t, err)
+		frame := rsp.Responses["A"].Frames[0]
+		require.Equal(t, 10, frame.Rows())
+		require.Equal(t, "count", frame.Fields[1].Name)
+		for i := 0; i < 10; i++ {
+			require.Equal(t, float64(i), frame.Fields[1].At(i))
+		}
+
+		found, err := s.getSimFromPath("sim/model/1hz/counter")
+		require.NoError(t, err)
+		require.Len(t, found.GetState().Config.(modelConfig).Variables, 1)
+	})
+}
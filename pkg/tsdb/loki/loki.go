@@ -68,6 +68,9 @@ type datasourceInfo struct {
 	// open streams
 	streams   map[string]data.FrameJSONCache
 	streamsMu sync.RWMutex
+
+	// shared upstream connections of the tail streams
+	tails *tailMultiplexer
 }
 
 type QueryJSONModel struct {
@@ -104,6 +107,7 @@ func newInstanceSettings(httpClientProvider *httpclient.Provider) datasource.Ins
 			HTTPClient: client,
 			URL:        settings.URL,
 			streams:    make(map[string]data.FrameJSONCache),
+			tails:      newTailMultiplexer(dialTailWebsocket),
 		}
 		return model, nil
 	}
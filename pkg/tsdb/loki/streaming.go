@@ -4,15 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
-	"os"
-	"os/signal"
 	"strings"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/time/rate"
 )
 
 func (s *Service) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
@@ -58,7 +55,8 @@ func (s *Service) SubscribeStream(ctx context.Context, req *backend.SubscribeStr
 	}, err
 }
 
-// Single instance for each channel (results are shared with all listeners)
+// Single instance for each channel (results are shared with all listeners).
+// Channels tailing the same query share a single upstream connection.
 func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
 	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
 	if err != nil {
@@ -70,101 +68,120 @@ func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest,
 		return err
 	}
 	if query.Expr == nil || *query.Expr == "" {
-		return fmt.Errorf("missing expr in cuannel")
+		return fmt.Errorf("missing expr in channel")
 	}
 
 	logger := s.logger.FromContext(ctx)
-	count := int64(0)
 
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
-
-	params := url.Values{}
-	params.Add("query", *query.Expr)
-
-	wsurl, _ := url.Parse(dsInfo.URL)
-
-	wsurl.Path = "/loki/api/v2alpha/tail"
-
-	if wsurl.Scheme == "https" {
-		wsurl.Scheme = "wss"
-	} else {
-		wsurl.Scheme = "ws"
-	}
-	wsurl.RawQuery = params.Encode()
-
-	logger.Info("Connecting to websocket", "url", wsurl)
-	c, r, err := websocket.DefaultDialer.Dial(wsurl.String(), nil)
+	sub, unsubscribe, err := dsInfo.tails.subscribe(dsInfo.URL, *query.Expr, logger)
 	if err != nil {
-		logger.Error("Error connecting to websocket", "err", err)
-		return fmt.Errorf("error connecting to websocket")
+		logger.Error("Error subscribing to Loki tail", "error", err)
+		return fmt.Errorf("error subscribing to Loki tail")
 	}
 
 	defer func() {
+		unsubscribe()
 		dsInfo.streamsMu.Lock()
 		delete(dsInfo.streams, req.Path)
 		dsInfo.streamsMu.Unlock()
-		if r != nil {
-			_ = r.Body.Close()
-		}
-		err = c.Close()
-		logger.Error("Closing loki websocket", "err", err)
+		logger.Info("Closing Loki tail stream", "path", req.Path)
 	}()
 
-	prev := data.FrameJSONCache{}
+	return runTailStream(ctx, sub, tailLineRateLimit, func(frame *data.Frame, prev *data.FrameJSONCache) error {
+		next, err := data.FrameToJSONCache(frame)
+		if err != nil {
+			return err
+		}
+		if next.SameSchema(prev) && !frameHasNotices(frame) {
+			err = sender.SendBytes(next.Bytes(data.IncludeDataOnly))
+		} else {
+			err = sender.SendFrame(frame, data.IncludeAll)
+		}
+		*prev = next
 
-	// Read all messages
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		for {
-			_, message, err := c.ReadMessage()
-			if err != nil {
-				logger.Error("Websocket read:", "err", err)
-				return
-			}
+		// Cache the initial data
+		dsInfo.streamsMu.Lock()
+		dsInfo.streams[req.Path] = next
+		dsInfo.streamsMu.Unlock()
+		return err
+	})
+}
 
-			frame := &data.Frame{}
-			err = json.Unmarshal(message, &frame)
+// runTailStream sends the batches of the subscriber as frames, limiting the number
+// of lines per second. Dropped lines are reported with a notice on the next frame.
+func runTailStream(ctx context.Context, sub *tailSubscriber, lineRate int, send func(frame *data.Frame, prev *data.FrameJSONCache) error) error {
+	limiter := rate.NewLimiter(rate.Limit(lineRate), lineRate)
+	prev := data.FrameJSONCache{}
+	dropped := 0
 
-			if err == nil && frame != nil {
-				next, _ := data.FrameToJSONCache(frame)
-				if next.SameSchema(&prev) {
-					err = sender.SendBytes(next.Bytes(data.IncludeDataOnly))
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case batch := <-sub.batches:
+			dropped += batch.Dropped
+			entries := make([]tailEntry, 0, len(batch.Entries))
+			for _, e := range batch.Entries {
+				if limiter.Allow() {
+					entries = append(entries, e)
 				} else {
-					err = sender.SendFrame(frame, data.IncludeAll)
+					dropped++
 				}
-				prev = next
-
-				// Cache the initial data
-				dsInfo.streamsMu.Lock()
-				dsInfo.streams[req.Path] = prev
-				dsInfo.streamsMu.Unlock()
+			}
+			if len(entries) == 0 {
+				continue
 			}
 
+			frame, err := tailFrame(entries)
 			if err != nil {
-				logger.Error("Websocket write:", "err", err, "raw", message)
-				return
+				return err
+			}
+			if dropped > 0 {
+				frame.AppendNotices(data.Notice{
+					Severity: data.NoticeSeverityWarning,
+					Text:     fmt.Sprintf("%d log lines were dropped because they exceeded the live tail rate limit of %d lines per second", dropped, lineRate),
+				})
+				dropped = 0
+			}
+			if err := send(frame, &prev); err != nil {
+				return err
 			}
 		}
-	}()
+	}
+}
 
-	ticker := time.NewTicker(time.Second * 60) // .Step)
-	defer ticker.Stop()
+// tailFrame creates a logs frame with the same fields as the query response frames
+func tailFrame(entries []tailEntry) (*data.Frame, error) {
+	labels := make([]json.RawMessage, len(entries))
+	times := make([]time.Time, len(entries))
+	lines := make([]string, len(entries))
+	tsNs := make([]string, len(entries))
+	for i, e := range entries {
+		labels[i] = e.Labels
+		times[i] = e.Time
+		lines[i] = e.Line
+		tsNs[i] = e.TsNs
+	}
 
-	for {
-		select {
-		case <-done:
-			logger.Info("Socket done")
-			return nil
-		case <-ctx.Done():
-			logger.Info("Stop streaming (context canceled)")
-			return nil
-		case t := <-ticker.C:
-			count++
-			logger.Error("Loki websocket ping?", "time", t, "count", count)
-		}
+	labelsField := data.NewField("labels", nil, labels)
+	lineField := data.NewField("Line", nil, lines)
+	stringTimeField := data.NewField("tsNs", nil, tsNs)
+	idField, err := makeIdField(stringTimeField, lineField, labelsField, "")
+	if err != nil {
+		return nil, err
 	}
+
+	frame := data.NewFrame("", labelsField, data.NewField("Time", nil, times), lineField, stringTimeField, idField)
+	frame.SetMeta(&data.FrameMeta{
+		Custom: map[string]string{
+			"frameType": "LabeledTimeValues",
+		},
+	})
+	return frame, nil
+}
+
+func frameHasNotices(frame *data.Frame) bool {
+	return frame.Meta != nil && len(frame.Meta.Notices) > 0
 }
 
 func (s *Service) PublishStream(_ context.Context, _ *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
@@ -0,0 +1,330 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+)
+
+const (
+	// tailLineRateLimit is the maximum number of lines per second sent to a single stream
+	tailLineRateLimit = 100
+	// tailSubscriberBuffer is the number of batches buffered for a subscriber before new batches are dropped
+	tailSubscriberBuffer = 32
+	tailMinBackoff       = time.Second
+	tailMaxBackoff       = 30 * time.Second
+)
+
+// tailConn is the upstream tail connection, implemented by *websocket.Conn
+type tailConn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	Close() error
+}
+
+type tailDialer func(ctx context.Context, wsurl string) (tailConn, error)
+
+func dialTailWebsocket(ctx context.Context, wsurl string) (tailConn, error) {
+	c, r, err := websocket.DefaultDialer.DialContext(ctx, wsurl, nil)
+	if r != nil && r.Body != nil {
+		_ = r.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// tailEntry is a single log line received from the Loki tail API
+type tailEntry struct {
+	Labels json.RawMessage
+	Time   time.Time
+	TsNs   string
+	Line   string
+}
+
+func (e tailEntry) key() string {
+	return e.TsNs + "_" + string(e.Labels) + "_" + e.Line
+}
+
+// tailBatch is a group of entries received in a single upstream message
+type tailBatch struct {
+	Entries []tailEntry
+	// Dropped is the number of lines dropped before reaching the subscriber,
+	// either by Loki or because the subscriber could not keep up
+	Dropped int
+}
+
+// tailResponse is the message format of the /loki/api/v1/tail endpoint
+type tailResponse struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+	DroppedEntries []json.RawMessage `json:"dropped_entries"`
+}
+
+func parseTailResponse(message []byte) (*tailBatch, error) {
+	res := tailResponse{}
+	if err := json.Unmarshal(message, &res); err != nil {
+		return nil, err
+	}
+
+	batch := &tailBatch{Dropped: len(res.DroppedEntries)}
+	for _, stream := range res.Streams {
+		labels, err := json.Marshal(stream.Stream)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range stream.Values {
+			ns, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timestamp %q in tail response: %w", value[0], err)
+			}
+			batch.Entries = append(batch.Entries, tailEntry{
+				Labels: labels,
+				Time:   time.Unix(0, ns).UTC(),
+				TsNs:   value[0],
+				Line:   value[1],
+			})
+		}
+	}
+
+	// entries of different streams are interleaved in time
+	sort.SliceStable(batch.Entries, func(i, j int) bool {
+		return batch.Entries[i].Time.Before(batch.Entries[j].Time)
+	})
+	return batch, nil
+}
+
+// tailMultiplexer shares one upstream tail connection between all the streams of a
+// datasource that tail the same query
+type tailMultiplexer struct {
+	dial       tailDialer
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*tailSession
+}
+
+func newTailMultiplexer(dial tailDialer) *tailMultiplexer {
+	return &tailMultiplexer{
+		dial:       dial,
+		minBackoff: tailMinBackoff,
+		maxBackoff: tailMaxBackoff,
+		sessions:   make(map[string]*tailSession),
+	}
+}
+
+type tailSubscriber struct {
+	batches chan *tailBatch
+
+	mu      sync.Mutex
+	dropped int
+}
+
+// send delivers a batch without blocking the upstream reader, lines are
+// dropped and reported with the next delivered batch when the subscriber is too slow
+func (s *tailSubscriber) send(batch *tailBatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := &tailBatch{Entries: batch.Entries, Dropped: batch.Dropped + s.dropped}
+	select {
+	case s.batches <- next:
+		s.dropped = 0
+	default:
+		s.dropped = next.Dropped + len(batch.Entries)
+	}
+}
+
+type tailSession struct {
+	key    string
+	wsurl  string
+	cancel context.CancelFunc
+
+	subscribers map[*tailSubscriber]struct{}
+
+	// lastTs is the timestamp of the newest received entry, lastKeys counts the entries received
+	// at that timestamp. They are used to resume without duplicates after a disconnect.
+	lastTs   int64
+	lastKeys map[string]int
+	// replayed counts the entries at lastTs that Loki sends again after a resume, it is nil
+	// once an entry newer than lastTs was received
+	replayed map[string]int
+}
+
+// subscribe returns a subscriber for the tail of the query, starting the upstream
+// connection if it is the first subscriber. The returned function must be called to unsubscribe.
+func (m *tailMultiplexer) subscribe(baseURL, expr string, logger log.Logger) (*tailSubscriber, func(), error) {
+	key := baseURL + "\n" + strings.TrimSpace(expr)
+	sub := &tailSubscriber{batches: make(chan *tailBatch, tailSubscriberBuffer)}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[key]
+	if !ok {
+		wsurl, err := tailURL(baseURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		session = &tailSession{
+			key:         key,
+			wsurl:       wsurl,
+			cancel:      cancel,
+			subscribers: make(map[*tailSubscriber]struct{}),
+			lastKeys:    make(map[string]int),
+		}
+		m.sessions[key] = session
+		go m.run(ctx, session, strings.TrimSpace(expr), logger)
+	}
+	session.subscribers[sub] = struct{}{}
+
+	return sub, func() { m.unsubscribe(session, sub) }, nil
+}
+
+func (m *tailMultiplexer) unsubscribe(session *tailSession, sub *tailSubscriber) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(session.subscribers, sub)
+	if len(session.subscribers) == 0 {
+		session.cancel()
+		if m.sessions[session.key] == session {
+			delete(m.sessions, session.key)
+		}
+	}
+}
+
+func tailURL(baseURL string) (string, error) {
+	wsurl, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	wsurl.Path = strings.TrimSuffix(wsurl.Path, "/") + "/loki/api/v1/tail"
+	if wsurl.Scheme == "https" {
+		wsurl.Scheme = "wss"
+	} else {
+		wsurl.Scheme = "ws"
+	}
+	return wsurl.String(), nil
+}
+
+// run keeps the upstream connection of the session open until it is canceled,
+// reconnecting with backoff and resuming from the last received timestamp
+func (m *tailMultiplexer) run(ctx context.Context, session *tailSession, expr string, logger log.Logger) {
+	backoff := m.minBackoff
+	for {
+		params := url.Values{}
+		params.Add("query", expr)
+		if session.lastTs > 0 {
+			params.Add("start", strconv.FormatInt(session.lastTs, 10))
+			session.resume()
+		}
+
+		logger.Info("Connecting to Loki tail", "url", session.wsurl, "resume", session.lastTs > 0)
+		c, err := m.dial(ctx, session.wsurl+"?"+params.Encode())
+		if err == nil {
+			if m.read(ctx, session, c, logger) {
+				backoff = m.minBackoff
+			}
+		} else {
+			logger.Error("Error connecting to Loki tail", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Stop tailing (no subscribers left)")
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, m.maxBackoff)
+	}
+}
+
+// read forwards upstream messages to the subscribers until the connection fails or the
+// session is canceled. It returns true if at least one message was received.
+func (m *tailMultiplexer) read(ctx context.Context, session *tailSession, c tailConn, logger log.Logger) bool {
+	stop := context.AfterFunc(ctx, func() { _ = c.Close() })
+	defer func() {
+		if stop() {
+			_ = c.Close()
+		}
+	}()
+
+	received := false
+	for {
+		_, message, err := c.ReadMessage()
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn("Loki tail disconnected", "error", err)
+			}
+			return received
+		}
+		received = true
+
+		batch, err := parseTailResponse(message)
+		if err != nil {
+			logger.Error("Error parsing Loki tail message", "error", err)
+			continue
+		}
+		batch.Entries = session.dedup(batch.Entries)
+		if len(batch.Entries) == 0 && batch.Dropped == 0 {
+			continue
+		}
+
+		m.mu.Lock()
+		for sub := range session.subscribers {
+			sub.send(batch)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// resume starts the replayed window: Loki returns the entries at the resume timestamp again
+func (s *tailSession) resume() {
+	s.replayed = make(map[string]int, len(s.lastKeys))
+	for k, count := range s.lastKeys {
+		s.replayed[k] = count
+	}
+}
+
+// dedup drops the entries that Loki sends again right after a resume, matching them by
+// timestamp, labels and line. Outside of the replayed window all entries are kept.
+func (s *tailSession) dedup(entries []tailEntry) []tailEntry {
+	kept := make([]tailEntry, 0, len(entries))
+	for _, e := range entries {
+		ts := e.Time.UnixNano()
+		k := e.key()
+		if s.replayed != nil {
+			if ts > s.lastTs {
+				s.replayed = nil
+			} else if ts == s.lastTs && s.replayed[k] > 0 {
+				s.replayed[k]--
+				continue
+			}
+		}
+
+		if ts > s.lastTs {
+			s.lastTs = ts
+			s.lastKeys = make(map[string]int)
+		}
+		if ts == s.lastTs {
+			s.lastKeys[k]++
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
@@ -0,0 +1,237 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTailConn struct {
+	messages chan []byte
+	closed   chan struct{}
+	once     sync.Once
+}
+
+func newFakeTailConn() *fakeTailConn {
+	return &fakeTailConn{messages: make(chan []byte, 10), closed: make(chan struct{})}
+}
+
+func (c *fakeTailConn) ReadMessage() (int, []byte, error) {
+	select {
+	case msg, ok := <-c.messages:
+		if !ok {
+			return 0, nil, errors.New("connection reset")
+		}
+		return 1, msg, nil
+	case <-c.closed:
+		return 0, nil, errors.New("closed")
+	}
+}
+
+func (c *fakeTailConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+type fakeTailDialer struct {
+	mu    sync.Mutex
+	urls  []string
+	conns chan *fakeTailConn
+}
+
+func (d *fakeTailDialer) dial(_ context.Context, wsurl string) (tailConn, error) {
+	d.mu.Lock()
+	d.urls = append(d.urls, wsurl)
+	d.mu.Unlock()
+	return <-d.conns, nil
+}
+
+func (d *fakeTailDialer) dialed() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string{}, d.urls...)
+}
+
+func tailMessage(ts int64, line string) []byte {
+	return []byte(fmt.Sprintf(`{"streams":[{"stream":{"job":"a"},"values":[["%d","%s"]]}]}`, ts, line))
+}
+
+func receiveBatch(t *testing.T, sub *tailSubscriber) *tailBatch {
+	t.Helper()
+	select {
+	case batch := <-sub.batches:
+		return batch
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for batch")
+		return nil
+	}
+}
+
+func TestParseTailResponse(t *testing.T) {
+	batch, err := parseTailResponse([]byte(`{
+		"streams": [
+			{"stream": {"job": "a"}, "values": [["1700000000000000002", "second"]]},
+			{"stream": {"job": "b"}, "values": [["1700000000000000001", "first"]]}
+		],
+		"dropped_entries": [{"labels": {"job": "c"}, "timestamp": "1700000000000000000"}]
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, 1, batch.Dropped)
+	require.Len(t, batch.Entries, 2)
+	require.Equal(t, "first", batch.Entries[0].Line)
+	require.Equal(t, `{"job":"b"}`, string(batch.Entries[0].Labels))
+	require.Equal(t, "1700000000000000001", batch.Entries[0].TsNs)
+	require.Equal(t, int64(1700000000000000001), batch.Entries[0].Time.UnixNano())
+
+	_, err = parseTailResponse([]byte(`{"streams":[{"stream":{},"values":[["abc","line"]]}]}`))
+	require.Error(t, err)
+}
+
+func TestTailMultiplexer(t *testing.T) {
+	logger := log.New()
+
+	t.Run("shares one upstream connection between subscribers of the same query", func(t *testing.T) {
+		dialer := &fakeTailDialer{conns: make(chan *fakeTailConn, 1)}
+		conn := newFakeTailConn()
+		dialer.conns <- conn
+		m := newTailMultiplexer(dialer.dial)
+
+		sub1, unsubscribe1, err := m.subscribe("http://loki:3100", `{job="a"}`, logger)
+		require.NoError(t, err)
+		sub2, unsubscribe2, err := m.subscribe("http://loki:3100", ` {job="a"} `, logger)
+		require.NoError(t, err)
+
+		conn.messages <- tailMessage(1, "hello")
+		require.Equal(t, "hello", receiveBatch(t, sub1).Entries[0].Line)
+		require.Equal(t, "hello", receiveBatch(t, sub2).Entries[0].Line)
+
+		urls := dialer.dialed()
+		require.Len(t, urls, 1)
+		require.Equal(t, "ws://loki:3100/loki/api/v1/tail?query=%7Bjob%3D%22a%22%7D", urls[0])
+
+		unsubscribe1()
+		require.Len(t, m.sessions, 1)
+		unsubscribe2()
+		require.Empty(t, m.sessions)
+		require.Eventually(t, func() bool {
+			select {
+			case <-conn.closed:
+				return true
+			default:
+				return false
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("resumes from the last timestamp without duplicates after a disconnect", func(t *testing.T) {
+		dialer := &fakeTailDialer{conns: make(chan *fakeTailConn, 2)}
+		first, second := newFakeTailConn(), newFakeTailConn()
+		dialer.conns <- first
+		dialer.conns <- second
+		m := newTailMultiplexer(dialer.dial)
+		m.minBackoff = time.Millisecond
+
+		sub, unsubscribe, err := m.subscribe("https://loki:3100", `{job="a"}`, logger)
+		require.NoError(t, err)
+		defer unsubscribe()
+
+		first.messages <- tailMessage(10, "a")
+		first.messages <- tailMessage(20, "b")
+		require.Equal(t, "a", receiveBatch(t, sub).Entries[0].Line)
+		require.Equal(t, "b", receiveBatch(t, sub).Entries[0].Line)
+		close(first.messages)
+
+		// the entry at the resume timestamp is returned again by Loki
+		second.messages <- tailMessage(20, "b")
+		second.messages <- tailMessage(30, "c")
+		batch := receiveBatch(t, sub)
+		require.Len(t, batch.Entries, 1)
+		require.Equal(t, "c", batch.Entries[0].Line)
+
+		urls := dialer.dialed()
+		require.Len(t, urls, 2)
+		require.Equal(t, "wss://loki:3100/loki/api/v1/tail?query=%7Bjob%3D%22a%22%7D&start=20", urls[1])
+	})
+
+	t.Run("only drops the replayed entries after a resume", func(t *testing.T) {
+		entry := func(ts int64, line string) tailEntry {
+			return tailEntry{Labels: json.RawMessage(`{"job":"a"}`), Time: time.Unix(0, ts), TsNs: strconv.FormatInt(ts, 10), Line: line}
+		}
+		lines := func(entries []tailEntry) []string {
+			result := []string{}
+			for _, e := range entries {
+				result = append(result, e.Line)
+			}
+			return result
+		}
+		session := &tailSession{lastKeys: make(map[string]int)}
+
+		// identical and out of order lines are kept while tailing
+		require.Equal(t, []string{"a", "b", "b"}, lines(session.dedup([]tailEntry{entry(20, "a"), entry(20, "b"), entry(20, "b")})))
+		require.Equal(t, []string{"late"}, lines(session.dedup([]tailEntry{entry(10, "late")})))
+
+		// after a resume, only the entries already received at the resume timestamp are dropped
+		session.resume()
+		require.Empty(t, lines(session.dedup([]tailEntry{entry(20, "a"), entry(20, "b")})))
+		require.Equal(t, []string{"c", "d"}, lines(session.dedup([]tailEntry{entry(20, "b"), entry(20, "c"), entry(30, "d")})))
+
+		// the replayed window is over
+		require.Equal(t, []string{"d"}, lines(session.dedup([]tailEntry{entry(30, "d")})))
+	})
+
+	t.Run("drops batches of slow subscribers and reports them", func(t *testing.T) {
+		sub := &tailSubscriber{batches: make(chan *tailBatch, 1)}
+		sub.send(&tailBatch{Entries: []tailEntry{{Line: "a"}}})
+		sub.send(&tailBatch{Entries: []tailEntry{{Line: "b"}, {Line: "c"}}, Dropped: 1})
+
+		require.Equal(t, "a", (<-sub.batches).Entries[0].Line)
+		sub.send(&tailBatch{Entries: []tailEntry{{Line: "d"}}})
+		batch := <-sub.batches
+		require.Equal(t, "d", batch.Entries[0].Line)
+		require.Equal(t, 3, batch.Dropped)
+	})
+}
+
+func TestRunTailStream(t *testing.T) {
+	sub := &tailSubscriber{batches: make(chan *tailBatch, 1)}
+	entries := make([]tailEntry, 0, 5)
+	for i := 0; i < 5; i++ {
+		entries = append(entries, tailEntry{
+			Labels: []byte(`{"job":"a"}`),
+			Time:   time.Unix(0, int64(i)),
+			TsNs:   fmt.Sprint(i),
+			Line:   fmt.Sprintf("line %d", i),
+		})
+	}
+	sub.batches <- &tailBatch{Entries: entries}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frames := make([]*data.Frame, 0)
+	err := runTailStream(ctx, sub, 3, func(frame *data.Frame, _ *data.FrameJSONCache) error {
+		frames = append(frames, frame)
+		cancel()
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, frames, 1)
+	frame := frames[0]
+	require.Equal(t, 3, frame.Rows())
+	require.Equal(t, []string{"labels", "Time", "Line", "tsNs", "id"}, []string{
+		frame.Fields[0].Name, frame.Fields[1].Name, frame.Fields[2].Name, frame.Fields[3].Name, frame.Fields[4].Name,
+	})
+	require.Len(t, frame.Meta.Notices, 1)
+	require.Equal(t, data.NoticeSeverityWarning, frame.Meta.Notices[0].Severity)
+	require.Contains(t, frame.Meta.Notices[0].Text, "2 log lines were dropped")
+}
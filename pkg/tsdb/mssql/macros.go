@@ -7,8 +7,8 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
-	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana/pkg/tsdb/mssql/sqleng"
+	"github.com/grafana/grafana/pkg/tsdb/sqlmacros"
 )
 
 const rsIdentifier = `([_a-zA-Z0-9]+)`
@@ -24,6 +24,11 @@ func newMssqlMacroEngine() sqleng.SQLMacroEngine {
 
 func (m *msSQLMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange,
 	sql string) (string, error) {
+	sql, err := sqlmacros.ExpandIn(sql, quoteString)
+	if err != nil {
+		return "", err
+	}
+
 	// TODO: Return any error
 	rExp, _ := regexp.Compile(sExpr)
 	var macroError error
@@ -48,6 +53,7 @@ func (m *msSQLMacroEngine) Interpolate(query *backend.DataQuery, timeRange backe
 	return sql, nil
 }
 
+//nolint:gocyclo
 func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *backend.DataQuery, name string, args []string) (string, error) {
 	switch name {
 	case "__time":
@@ -66,6 +72,15 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		}
 
 		return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339), timeRange.To.UTC().Format(time.RFC3339)), nil
+	case "__timeShift":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and time shift", name)
+		}
+		shifted, err := sqlmacros.ShiftTimeRange(timeRange, args[1])
+		if err != nil {
+			return "", err
+		}
+		return m.evaluateMacro(shifted, query, "__timeFilter", args[:1])
 	case "__timeFrom":
 		return fmt.Sprintf("'%s'", timeRange.From.UTC().Format(time.RFC3339)), nil
 	case "__timeTo":
@@ -74,9 +89,12 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval", name)
 		}
-		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'"`))
+		if unit := sqlmacros.CalendarUnit(args[1]); unit != "" {
+			return m.calendarTimeGroup(args[0], unit, args[2:])
+		}
+		interval, err := sqlmacros.ParseInterval(args[1])
 		if err != nil {
-			return "", fmt.Errorf("error parsing interval %v", args[1])
+			return "", err
 		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
@@ -109,9 +127,9 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
 		}
-		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'`))
+		interval, err := sqlmacros.ParseInterval(args[1])
 		if err != nil {
-			return "", fmt.Errorf("error parsing interval %v", args[1])
+			return "", err
 		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
@@ -130,3 +148,41 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		return "", fmt.Errorf("unknown macro %q", name)
 	}
 }
+
+// calendarTimeGroup rounds the time column down to the start of a calendar
+// unit. The time zone argument takes a Windows time zone name as accepted by
+// AT TIME ZONE; without it the column is rounded as stored.
+func (m *msSQLMacroEngine) calendarTimeGroup(column string, unit string, args []string) (string, error) {
+	local := column
+	tz := ""
+	if len(args) > 0 && args[0] != "" {
+		name, err := sqlmacros.Timezone(args[0])
+		if err != nil {
+			return "", err
+		}
+		tz = quoteString(name)
+		local = fmt.Sprintf("CAST((%s AT TIME ZONE 'UTC') AT TIME ZONE %s AS datetime2)", column, tz)
+	}
+
+	var start string
+	switch unit {
+	case "day":
+		start = fmt.Sprintf("CAST(CAST(%s AS date) AS datetime2)", local)
+	case "week":
+		start = fmt.Sprintf("DATEADD(day, -((DATEPART(weekday, %s) + @@DATEFIRST + 5) %% 7), CAST(CAST(%s AS date) AS datetime2))", local, local)
+	case "month":
+		start = fmt.Sprintf("CAST(DATEFROMPARTS(YEAR(%s), MONTH(%s), 1) AS datetime2)", local, local)
+	case "year":
+		start = fmt.Sprintf("CAST(DATEFROMPARTS(YEAR(%s), 1, 1) AS datetime2)", local)
+	}
+
+	if tz != "" {
+		start = fmt.Sprintf("(%s AT TIME ZONE %s)", start, tz)
+	}
+	return fmt.Sprintf("DATEDIFF(second, '1970-01-01', %s)", start), nil
+}
+
+// quoteString returns value as a T-SQL string literal.
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -147,6 +147,59 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, "SELECT FLOOR(time_column/300)*300", sql)
 			require.Equal(t, sql+" AS [time]", sql2)
 		})
+		t.Run("interpolate __timeGroup function with $__interval_ms", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,300000)")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY FLOOR(DATEDIFF(second, '1970-01-01', time_column)/300)*300", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with calendar unit", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'month')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY DATEDIFF(second, '1970-01-01', CAST(DATEFROMPARTS(YEAR(time_column), MONTH(time_column), 1) AS datetime2))", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with calendar unit and time zone", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'day','W. Europe Standard Time')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY DATEDIFF(second, '1970-01-01', (CAST(CAST(CAST((time_column AT TIME ZONE 'UTC') AT TIME ZONE 'W. Europe Standard Time' AS datetime2) AS date) AS datetime2) AT TIME ZONE 'W. Europe Standard Time'))", sql)
+		})
+
+		t.Run("interpolate __timeShift function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeShift(time_column, 1h)")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("WHERE time_column BETWEEN '%s' AND '%s'", from.Add(-time.Hour).Format(time.RFC3339), to.Add(-time.Hour).Format(time.RFC3339)), sql)
+		})
+
+		t.Run("interpolate __in function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__in(host, 'a','it''s',b)")
+			require.Nil(t, err)
+
+			require.Equal(t, "WHERE host IN ('a','it''s','b')", sql)
+		})
+
+		t.Run("interpolate __in function with commas and parentheses in quoted values", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__in(host, 'a,b','c)','d''),e') AND up = 1")
+			require.Nil(t, err)
+
+			require.Equal(t, "WHERE host IN ('a,b','c)','d''),e') AND up = 1", sql)
+		})
+
+		t.Run("interpolate __in function with double quoted values", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, `WHERE $__in(host, "a,b", "c)")`)
+			require.Nil(t, err)
+
+			require.Equal(t, "WHERE host IN ('a,b','c)')", sql)
+		})
+
+		t.Run("interpolate __in function without closing parenthesis", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "WHERE $__in(host, 'a)'")
+			require.Error(t, err)
+		})
 	})
 
 	t.Run("Given a time range between 1960-02-01 07:00 and 1965-02-03 08:00", func(t *testing.T) {
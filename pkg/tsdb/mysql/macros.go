@@ -6,9 +6,9 @@ import (
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
-	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
 	"github.com/grafana/grafana/pkg/tsdb/mysql/sqleng"
+	"github.com/grafana/grafana/pkg/tsdb/sqlmacros"
 )
 
 const rsIdentifier = `([_a-zA-Z0-9]+)`
@@ -37,6 +37,11 @@ func (m *mySQLMacroEngine) Interpolate(query *backend.DataQuery, timeRange backe
 		return "", fmt.Errorf("invalid query - %s", m.userError)
 	}
 
+	sql, err := sqlmacros.ExpandIn(sql, quoteString)
+	if err != nil {
+		return "", err
+	}
+
 	// TODO: Handle error
 	rExp, _ := regexp.Compile(sExpr)
 	var macroError error
@@ -61,6 +66,7 @@ func (m *mySQLMacroEngine) Interpolate(query *backend.DataQuery, timeRange backe
 	return sql, nil
 }
 
+//nolint:gocyclo
 func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *backend.DataQuery, name string, args []string) (string, error) {
 	switch name {
 	case "__timeEpoch", "__time":
@@ -76,6 +82,15 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 			return fmt.Sprintf("%s BETWEEN DATE_ADD(FROM_UNIXTIME(0), INTERVAL %d SECOND) AND FROM_UNIXTIME(%d)", args[0], timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()), nil
 		}
 		return fmt.Sprintf("%s BETWEEN FROM_UNIXTIME(%d) AND FROM_UNIXTIME(%d)", args[0], timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()), nil
+	case "__timeShift":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and time shift", name)
+		}
+		shifted, err := sqlmacros.ShiftTimeRange(timeRange, args[1])
+		if err != nil {
+			return "", err
+		}
+		return m.evaluateMacro(shifted, query, "__timeFilter", args[:1])
 	case "__timeFrom":
 		return fmt.Sprintf("FROM_UNIXTIME(%d)", timeRange.From.UTC().Unix()), nil
 	case "__timeTo":
@@ -84,9 +99,12 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval", name)
 		}
-		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'"`))
+		if unit := sqlmacros.CalendarUnit(args[1]); unit != "" {
+			return m.calendarTimeGroup(args[0], unit, args[2:])
+		}
+		interval, err := sqlmacros.ParseInterval(args[1])
 		if err != nil {
-			return "", fmt.Errorf("error parsing interval %v", args[1])
+			return "", err
 		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
@@ -119,9 +137,9 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
 		}
-		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'`))
+		interval, err := sqlmacros.ParseInterval(args[1])
 		if err != nil {
-			return "", fmt.Errorf("error parsing interval %v", args[1])
+			return "", err
 		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
@@ -140,3 +158,41 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		return "", fmt.Errorf("unknown macro %v", name)
 	}
 }
+
+// calendarTimeGroup rounds the time column down to the start of a calendar
+// unit. Without a time zone argument the session time zone is used.
+func (m *mySQLMacroEngine) calendarTimeGroup(column string, unit string, args []string) (string, error) {
+	local := column
+	tz := ""
+	if len(args) > 0 && args[0] != "" {
+		name, err := sqlmacros.Timezone(args[0])
+		if err != nil {
+			return "", err
+		}
+		tz = quoteString(name)
+		local = fmt.Sprintf("CONVERT_TZ(%s, @@session.time_zone, %s)", column, tz)
+	}
+
+	var start string
+	switch unit {
+	case "day":
+		start = fmt.Sprintf("DATE(%s)", local)
+	case "week":
+		start = fmt.Sprintf("DATE(%s) - INTERVAL WEEKDAY(%s) DAY", local, local)
+	case "month":
+		start = fmt.Sprintf("CAST(DATE_FORMAT(%s, '%%Y-%%m-01') AS DATE)", local)
+	case "year":
+		start = fmt.Sprintf("CAST(DATE_FORMAT(%s, '%%Y-01-01') AS DATE)", local)
+	}
+
+	if tz != "" {
+		start = fmt.Sprintf("CONVERT_TZ(%s, %s, @@session.time_zone)", start, tz)
+	}
+	return fmt.Sprintf("UNIX_TIMESTAMP(%s)", start), nil
+}
+
+// quoteString returns value as a MySQL string literal.
+func quoteString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
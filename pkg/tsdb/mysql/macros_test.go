@@ -115,6 +115,71 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, "SELECT time_column DIV 300 * 300", sql)
 			require.Equal(t, sql+" AS \"time\"", sql2)
 		})
+		t.Run("interpolate __timeGroup function with $__interval_ms", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,300000)")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY UNIX_TIMESTAMP(time_column) DIV 300 * 300", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with calendar unit", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'month')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY UNIX_TIMESTAMP(CAST(DATE_FORMAT(time_column, '%Y-%m-01') AS DATE))", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with calendar unit and time zone", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'day','Europe/Berlin')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY UNIX_TIMESTAMP(CONVERT_TZ(DATE(CONVERT_TZ(time_column, @@session.time_zone, 'Europe/Berlin')), 'Europe/Berlin', @@session.time_zone))", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with invalid time zone", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'day','UTC'' OR 1=1 --')")
+			require.Error(t, err)
+		})
+
+		t.Run("interpolate __timeShift function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeShift(time_column, 1d)")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("WHERE time_column BETWEEN FROM_UNIXTIME(%d) AND FROM_UNIXTIME(%d)", from.AddDate(0, 0, -1).Unix(), to.AddDate(0, 0, -1).Unix()), sql)
+		})
+
+		t.Run("interpolate __in function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, `WHERE $__in(host, 'a','it''s','back\slash')`)
+			require.Nil(t, err)
+
+			require.Equal(t, `WHERE host IN ('a','it''s','back\\slash')`, sql)
+		})
+
+		t.Run("interpolate __in function with commas and parentheses in quoted values", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__in(host, 'a,b','c)','d''),e') AND up = 1")
+			require.Nil(t, err)
+
+			require.Equal(t, "WHERE host IN ('a,b','c)','d''),e') AND up = 1", sql)
+		})
+
+		t.Run("interpolate __in function with double quoted values", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, `WHERE $__in(host, "a,b", "c)")`)
+			require.Nil(t, err)
+
+			require.Equal(t, "WHERE host IN ('a,b','c)')", sql)
+		})
+
+		t.Run("interpolate __in function without closing parenthesis", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "WHERE $__in(host, 'a)'")
+			require.Error(t, err)
+		})
+
+		t.Run("interpolate __in function without values", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__in(host, )")
+			require.Nil(t, err)
+
+			require.Equal(t, "WHERE 1=0", sql)
+		})
 	})
 
 	t.Run("Given a time range between 1960-02-01 07:00 and 1965-02-03 08:00", func(t *testing.T) {
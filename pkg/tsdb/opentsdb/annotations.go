@@ -0,0 +1,96 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// queryAnnotations runs an annotation query, which reads the annotations
+// attached to the series of a metric, or the global annotations when the
+// query is marked as global.
+func (s *Service) queryAnnotations(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery, model *simplejson.Json) backend.DataResponse {
+	metric := model.Get("target").MustString()
+	if metric == "" {
+		return backend.ErrDataResponse(backend.StatusBadRequest, "annotation query requires a metric")
+	}
+
+	tsdbQuery := OpenTsdbQuery{
+		Start:             query.TimeRange.From.UnixNano() / int64(time.Millisecond),
+		End:               query.TimeRange.To.UnixNano() / int64(time.Millisecond),
+		Queries:           []map[string]any{{"aggregator": "sum", "metric": metric}},
+		GlobalAnnotations: true,
+	}
+
+	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return backend.ErrDataResponseWithSource(backend.StatusBadGateway, backend.ErrorSourceDownstream, err.Error())
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	frame, err := s.parseAnnotationResponse(logger, res, model.Get("isGlobal").MustBool())
+	if err != nil {
+		return backend.ErrDataResponseWithSource(backend.StatusBadGateway, backend.ErrorSourceDownstream, err.Error())
+	}
+	frame.RefID = query.RefID
+
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+func (s *Service) parseAnnotationResponse(logger log.Logger, res *http.Response, global bool) (*data.Frame, error) {
+	body, err := s.readResponse(logger, res)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData []OpenTsdbResponse
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
+		return nil, fmt.Errorf("failed to parse annotations: %w", err)
+	}
+
+	var annotations []OpenTsdbAnnotation
+	if len(responseData) > 0 {
+		annotations = responseData[0].Annotations
+		if global {
+			annotations = responseData[0].GlobalAnnotations
+		}
+	}
+
+	times := make([]time.Time, 0, len(annotations))
+	timeEnds := make([]*time.Time, 0, len(annotations))
+	texts := make([]string, 0, len(annotations))
+	for _, ann := range annotations {
+		times = append(times, time.Unix(int64(ann.StartTime), 0).UTC())
+		var end *time.Time
+		if ann.EndTime > 0 {
+			t := time.Unix(int64(ann.EndTime), 0).UTC()
+			end = &t
+		}
+		timeEnds = append(timeEnds, end)
+		texts = append(texts, ann.Description)
+	}
+
+	return data.NewFrame("annotations",
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+	), nil
+}
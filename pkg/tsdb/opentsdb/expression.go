@@ -0,0 +1,132 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// queryTypeExpression marks queries that are sent to the /api/query/exp
+// endpoint instead of /api/query.
+const queryTypeExpression = "expression"
+
+// queryExpression runs a query against the expression endpoint. The query
+// model carries the filters, metrics, expressions and outputs of the request
+// in its "expression" property; the time section is built from the query.
+func (s *Service) queryExpression(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery, model *simplejson.Json) backend.DataResponse {
+	body, err := s.buildExpression(query, model)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+	}
+
+	request, err := s.createPostRequest(ctx, logger, dsInfo, "api/query/exp", nil, body)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return backend.ErrDataResponseWithSource(backend.StatusBadGateway, backend.ErrorSourceDownstream, err.Error())
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	frames, err := s.parseExpressionResponse(logger, res, query.RefID)
+	if err != nil {
+		return backend.ErrDataResponseWithSource(backend.StatusBadGateway, backend.ErrorSourceDownstream, err.Error())
+	}
+
+	return backend.DataResponse{Frames: frames}
+}
+
+func (s *Service) buildExpression(query backend.DataQuery, model *simplejson.Json) (map[string]any, error) {
+	expression, err := model.Get("expression").Map()
+	if err != nil || len(expression) == 0 {
+		return nil, fmt.Errorf("expression query requires an expression")
+	}
+
+	body := make(map[string]any, len(expression)+1)
+	for key, value := range expression {
+		body[key] = value
+	}
+
+	aggregator := model.Get("aggregator").MustString("sum")
+	timeSection := map[string]any{
+		"start":      strconv.FormatInt(query.TimeRange.From.UnixMilli(), 10),
+		"end":        strconv.FormatInt(query.TimeRange.To.UnixMilli(), 10),
+		"aggregator": aggregator,
+	}
+	if !model.Get("disableDownsampling").MustBool() {
+		if interval := model.Get("downsampleInterval").MustString(); interval != "" {
+			timeSection["downsampler"] = map[string]any{
+				"interval":   interval,
+				"aggregator": model.Get("downsampleAggregator").MustString(aggregator),
+			}
+		}
+	}
+	body["time"] = timeSection
+
+	return body, nil
+}
+
+func (s *Service) parseExpressionResponse(logger log.Logger, res *http.Response, refID string) (data.Frames, error) {
+	body, err := s.readResponse(logger, res)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData OpenTsdbExpressionResponse
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		logger.Info("Failed to unmarshal opentsdb expression response", "error", err, "status", res.Status, "body", string(body))
+		return nil, err
+	}
+
+	frames := data.Frames{}
+	for _, output := range responseData.Outputs {
+		name := output.Alias
+		if name == "" {
+			name = output.ID
+		}
+
+		for _, meta := range output.Meta {
+			// index 0 describes the timestamp column
+			if meta.Index == 0 {
+				continue
+			}
+
+			labels := data.Labels{}
+			for label, value := range meta.CommonTags {
+				labels[label] = value
+			}
+
+			frame := data.NewFrameOfFieldTypes(name, 0, data.FieldTypeTime, data.FieldTypeFloat64)
+			frame.Meta = &data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti, TypeVersion: data.FrameTypeVersion{0, 1}}
+			frame.RefID = refID
+			frame.Fields[0].Name = data.TimeSeriesTimeFieldName
+			frame.Fields[1].Name = "value"
+			frame.Fields[1].Labels = labels
+
+			for _, point := range output.DataPoints {
+				if len(point) <= meta.Index {
+					continue
+				}
+				frame.AppendRow(time.UnixMilli(int64(point[0])).UTC(), point[meta.Index])
+			}
+			frames = append(frames, frame)
+		}
+	}
+
+	return frames, nil
+}
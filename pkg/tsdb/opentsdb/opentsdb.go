@@ -63,18 +63,58 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 }
 
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	var tsdbQuery OpenTsdbQuery
-
 	logger := logger.FromContext(ctx)
 
-	q := req.Queries[0]
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	result := backend.NewQueryDataResponse()
+	metricQueries := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		model, err := simplejson.NewJson(query.JSON)
+		if err != nil {
+			result.Responses[query.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse query: %v", err))
+			continue
+		}
+
+		switch {
+		case query.QueryType == queryTypeExpression:
+			result.Responses[query.RefID] = s.queryExpression(ctx, logger, dsInfo, query, model)
+		case model.Get("fromAnnotations").MustBool():
+			result.Responses[query.RefID] = s.queryAnnotations(ctx, logger, dsInfo, query, model)
+		default:
+			metricQueries = append(metricQueries, query)
+		}
+	}
+
+	if len(metricQueries) == 0 {
+		return result, nil
+	}
+
+	metricResult, err := s.queryMetrics(ctx, logger, dsInfo, metricQueries)
+	if err != nil {
+		return &backend.QueryDataResponse{}, err
+	}
+	for refID, res := range metricResult.Responses {
+		result.Responses[refID] = res
+	}
+
+	return result, nil
+}
+
+func (s *Service) queryMetrics(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, queries []backend.DataQuery) (*backend.QueryDataResponse, error) {
+	var tsdbQuery OpenTsdbQuery
+
+	q := queries[0]
 
 	myRefID := q.RefID
 
 	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
 
-	for _, query := range req.Queries {
+	for _, query := range queries {
 		metric := s.buildMetric(query)
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
 	}
@@ -84,19 +124,14 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
-	if err != nil {
-		return nil, err
-	}
-
 	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		return nil, err
 	}
 
 	res, err := dsInfo.HTTPClient.Do(request)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		return nil, err
 	}
 
 	defer func() {
@@ -106,22 +141,25 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		}
 	}()
 
-	result, err := s.parseResponse(logger, res, myRefID)
-	if err != nil {
-		return &backend.QueryDataResponse{}, err
-	}
-
-	return result, nil
+	return s.parseResponse(logger, res, myRefID)
 }
 
 func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, data OpenTsdbQuery) (*http.Request, error) {
+	return s.createPostRequest(ctx, logger, dsInfo, "api/query", url.Values{"arrays": []string{"true"}}, data)
+}
+
+func (s *Service) createPostRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, apiPath string, params url.Values, data any) (*http.Request, error) {
 	u, err := url.Parse(dsInfo.URL)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, "api/query")
+	u.Path = path.Join(u.Path, apiPath)
 	queryParams := u.Query()
-	queryParams.Set("arrays", "true")
+	for key, values := range params {
+		for _, value := range values {
+			queryParams.Add(key, value)
+		}
+	}
 	u.RawQuery = queryParams.Encode()
 
 	postData, err := json.Marshal(data)
@@ -140,9 +178,9 @@ func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *
 	return req, nil
 }
 
-func (s *Service) parseResponse(logger log.Logger, res *http.Response, myRefID string) (*backend.QueryDataResponse, error) {
-	resp := backend.NewQueryDataResponse()
-
+// readResponse reads the body of an OpenTSDB response, failing on non-2xx
+// status codes.
+func (s *Service) readResponse(logger log.Logger, res *http.Response) ([]byte, error) {
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
@@ -158,6 +196,17 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response, myRefID s
 		return nil, fmt.Errorf("request failed, status: %s", res.Status)
 	}
 
+	return body, nil
+}
+
+func (s *Service) parseResponse(logger log.Logger, res *http.Response, myRefID string) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+
+	body, err := s.readResponse(logger, res)
+	if err != nil {
+		return nil, err
+	}
+
 	var responseData []OpenTsdbResponse
 	err = json.Unmarshal(body, &responseData)
 	if err != nil {
@@ -201,8 +250,13 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 		return nil
 	}
 
-	// Setting metric and aggregator
-	metric["metric"] = model.Get("metric").MustString()
+	// Setting metric or TSUIDs and aggregator
+	tsuids := queryTSUIDs(model)
+	if len(tsuids) > 0 {
+		metric["tsuids"] = tsuids
+	} else {
+		metric["metric"] = model.Get("metric").MustString()
+	}
 	metric["aggregator"] = model.Get("aggregator").MustString()
 
 	// Setting downsampling options
@@ -243,6 +297,11 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 		metric["rateOptions"] = rateOptions
 	}
 
+	// TSUIDs already identify the series, so tags and filters don't apply
+	if len(tsuids) > 0 {
+		return metric
+	}
+
 	// Setting tags
 	tags, tagsCheck := model.CheckGet("tags")
 	if tagsCheck && len(tags.MustMap()) > 0 {
@@ -258,6 +317,25 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 	return metric
 }
 
+// queryTSUIDs returns the TSUIDs of a query, given either as a list or as a
+// comma separated string.
+func queryTSUIDs(model *simplejson.Json) []string {
+	var tsuids []string
+	if list, err := model.Get("tsuids").StringArray(); err == nil {
+		tsuids = list
+	} else {
+		tsuids = strings.Split(model.Get("tsuids").MustString(), ",")
+	}
+
+	result := make([]string, 0, len(tsuids))
+	for _, tsuid := range tsuids {
+		if tsuid = strings.TrimSpace(tsuid); tsuid != "" {
+			result = append(result, tsuid)
+		}
+	}
+	return result
+}
+
 func (s *Service) getDSInfo(ctx context.Context, pluginCtx backend.PluginContext) (*datasourceInfo, error) {
 	i, err := s.im.Get(ctx, pluginCtx)
 	if err != nil {
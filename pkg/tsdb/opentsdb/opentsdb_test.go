@@ -13,6 +13,8 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
 func TestOpenTsdbExecutor(t *testing.T) {
@@ -286,4 +288,117 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, float64(45), metricRateOptions["counterMax"])
 		require.Equal(t, float64(60), metricRateOptions["resetValue"])
 	})
+
+	t.Run("Build metric with TSUIDs", func(t *testing.T) {
+		query := backend.DataQuery{
+			JSON: []byte(`
+					{
+						"tsuids": "000001000002000042, 000001000002000043",
+						"aggregator": "sum",
+						"disableDownsampling": true,
+						"tags": {
+							"env": "prod"
+						}
+					}`,
+			),
+		}
+
+		metric := service.buildMetric(query)
+
+		require.Len(t, metric, 2)
+		require.Equal(t, []string{"000001000002000042", "000001000002000043"}, metric["tsuids"])
+		require.Equal(t, "sum", metric["aggregator"])
+	})
+
+	t.Run("Parse annotation response", func(t *testing.T) {
+		response := `
+		[
+			{
+				"metric": "test",
+				"dps": [],
+				"annotations": [
+					{"tsuid": "000001", "description": "deploy", "startTime": 1405544146}
+				],
+				"globalAnnotations": [
+					{"description": "outage", "startTime": 1405544146, "endTime": 1405544746}
+				]
+			}
+		]`
+
+		resp := http.Response{Body: io.NopCloser(strings.NewReader(response)), StatusCode: 200}
+		frame, err := service.parseAnnotationResponse(logger, &resp, false)
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, "deploy", frame.Fields[2].At(0))
+		require.Nil(t, frame.Fields[1].At(0))
+
+		resp = http.Response{Body: io.NopCloser(strings.NewReader(response)), StatusCode: 200}
+		frame, err = service.parseAnnotationResponse(logger, &resp, true)
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, "outage", frame.Fields[2].At(0))
+		end := time.Date(2014, 7, 16, 21, 5, 46, 0, time.UTC)
+		require.Equal(t, &end, frame.Fields[1].At(0))
+	})
+
+	t.Run("Build expression query", func(t *testing.T) {
+		from := time.Date(2014, 7, 16, 20, 0, 0, 0, time.UTC)
+		query := backend.DataQuery{
+			QueryType: queryTypeExpression,
+			TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+			JSON: []byte(`
+					{
+						"aggregator": "max",
+						"downsampleInterval": "5m",
+						"expression": {
+							"metrics": [{"id": "a", "metric": "sys.cpu.user"}],
+							"expressions": [{"id": "e", "expr": "a * 2"}]
+						}
+					}`,
+			),
+		}
+		model, err := simplejson.NewJson(query.JSON)
+		require.NoError(t, err)
+
+		body, err := service.buildExpression(query, model)
+		require.NoError(t, err)
+		require.Len(t, body, 3)
+		require.Equal(t, map[string]any{
+			"start":       "1405540800000",
+			"end":         "1405544400000",
+			"aggregator":  "max",
+			"downsampler": map[string]any{"interval": "5m", "aggregator": "max"},
+		}, body["time"])
+
+		_, err = service.buildExpression(query, simplejson.New())
+		require.Error(t, err)
+	})
+
+	t.Run("Parse expression response", func(t *testing.T) {
+		response := `
+		{
+			"outputs": [
+				{
+					"id": "e",
+					"dps": [[1405544146000, 1, 2], [1405544206000, 3, 4]],
+					"meta": [
+						{"index": 0, "metrics": ["timestamp"]},
+						{"index": 1, "metrics": ["sys.cpu.user"], "commonTags": {"host": "web01"}},
+						{"index": 2, "metrics": ["sys.cpu.user"], "commonTags": {"host": "web02"}}
+					]
+				}
+			]
+		}`
+
+		resp := http.Response{Body: io.NopCloser(strings.NewReader(response)), StatusCode: 200}
+		frames, err := service.parseExpressionResponse(logger, &resp, "B")
+		require.NoError(t, err)
+		require.Len(t, frames, 2)
+		require.Equal(t, "e", frames[0].Name)
+		require.Equal(t, "B", frames[1].RefID)
+		require.Equal(t, data.Labels{"host": "web02"}, frames[1].Fields[1].Labels)
+		require.Equal(t, 2, frames[1].Rows())
+		require.Equal(t, float64(4), frames[1].Fields[1].At(1))
+		require.Equal(t, time.Date(2014, 7, 16, 20, 55, 46, 0, time.UTC), frames[0].Fields[0].At(0))
+	})
 }
@@ -0,0 +1,83 @@
+package opentsdb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+)
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	handler := httpadapter.New(s.registerResourceRoutes())
+	return handler.CallResource(ctx, req, sender)
+}
+
+func (s *Service) registerResourceRoutes() *http.ServeMux {
+	router := http.NewServeMux()
+	// metric, tag key and tag value suggestions
+	router.HandleFunc("GET /api/suggest", s.proxyHandler("api/suggest", "type", "q", "max"))
+	// series lookups used to resolve tag values for a metric
+	router.HandleFunc("GET /api/search/lookup", s.proxyHandler("api/search/lookup", "m", "limit", "useMeta"))
+	router.HandleFunc("GET /api/aggregators", s.proxyHandler("api/aggregators"))
+	return router
+}
+
+// proxyHandler forwards a GET request to the given OpenTSDB API path, passing
+// on only the allowed query parameters.
+func (s *Service) proxyHandler(apiPath string, allowedParams ...string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := logger.FromContext(ctx)
+
+		dsInfo, err := s.getDSInfo(ctx, backend.PluginConfigFromContext(ctx))
+		if err != nil {
+			logger.Error("Failed to get data source info", "error", err)
+			http.Error(rw, "failed to get data source info", http.StatusInternalServerError)
+			return
+		}
+
+		u, err := url.Parse(dsInfo.URL)
+		if err != nil {
+			logger.Error("Failed to parse data source URL", "error", err)
+			http.Error(rw, "invalid data source URL", http.StatusInternalServerError)
+			return
+		}
+		u.Path = path.Join(u.Path, apiPath)
+		params := url.Values{}
+		for _, name := range allowedParams {
+			if value := r.URL.Query().Get(name); value != "" {
+				params.Set(name, value)
+			}
+		}
+		u.RawQuery = params.Encode()
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			logger.Error("Failed to create request", "error", err)
+			http.Error(rw, "failed to create request", http.StatusInternalServerError)
+			return
+		}
+
+		res, err := dsInfo.HTTPClient.Do(request)
+		if err != nil {
+			logger.Warn("Resource request to OpenTSDB failed", "error", err, "path", apiPath)
+			http.Error(rw, "request to OpenTSDB failed", http.StatusBadGateway)
+			return
+		}
+		defer func() {
+			if err := res.Body.Close(); err != nil {
+				logger.Warn("Failed to close response body", "error", err)
+			}
+		}()
+
+		rw.Header().Set("Content-Type", res.Header.Get("Content-Type"))
+		rw.WriteHeader(res.StatusCode)
+		if _, err := io.Copy(rw, res.Body); err != nil {
+			logger.Warn("Failed to write resource response", "error", err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallResource(t *testing.T) {
+	var requestedURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedURL = r.URL.String()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`["cpu.user","cpu.system"]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		im: datasource.NewInstanceManager(func(ctx context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+			return &datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL}, nil
+		}),
+	}
+	pluginCtx := backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{}}
+
+	callResource := func(t *testing.T, path string, query string) *backend.CallResourceResponse {
+		t.Helper()
+		var res *backend.CallResourceResponse
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: pluginCtx,
+			Method:        http.MethodGet,
+			Path:          path,
+			URL:           path + "?" + query,
+		}, backend.CallResourceResponseSenderFunc(func(r *backend.CallResourceResponse) error {
+			res = r
+			return nil
+		}))
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("proxies suggest requests with allowed parameters", func(t *testing.T) {
+		res := callResource(t, "api/suggest", "type=metrics&q=cpu&max=10&other=1")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, `["cpu.user","cpu.system"]`, string(res.Body))
+		require.Equal(t, "/api/suggest?max=10&q=cpu&type=metrics", requestedURL)
+	})
+
+	t.Run("proxies lookup requests", func(t *testing.T) {
+		res := callResource(t, "api/search/lookup", "m=cpu%7Bhost%3D*%7D&limit=100")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, "/api/search/lookup?limit=100&m=cpu%7Bhost%3D%2A%7D", requestedURL)
+	})
+
+	t.Run("proxies aggregators requests", func(t *testing.T) {
+		res := callResource(t, "api/aggregators", "")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, "/api/aggregators", requestedURL)
+	})
+
+	t.Run("rejects unknown paths", func(t *testing.T) {
+		res := callResource(t, "api/put", "")
+		require.Equal(t, http.StatusNotFound, res.Status)
+	})
+}
@@ -1,13 +1,41 @@
 package opentsdb
 
 type OpenTsdbQuery struct {
-	Start   int64            `json:"start"`
-	End     int64            `json:"end"`
-	Queries []map[string]any `json:"queries"`
+	Start             int64            `json:"start"`
+	End               int64            `json:"end"`
+	Queries           []map[string]any `json:"queries"`
+	GlobalAnnotations bool             `json:"globalAnnotations,omitempty"`
 }
 
 type OpenTsdbResponse struct {
-	Metric     string            `json:"metric"`
-	Tags       map[string]string `json:"tags"`
-	DataPoints [][]float64       `json:"dps"`
+	Metric            string               `json:"metric"`
+	Tags              map[string]string    `json:"tags"`
+	DataPoints        [][]float64          `json:"dps"`
+	Annotations       []OpenTsdbAnnotation `json:"annotations,omitempty"`
+	GlobalAnnotations []OpenTsdbAnnotation `json:"globalAnnotations,omitempty"`
+}
+
+type OpenTsdbAnnotation struct {
+	TSUID       string  `json:"tsuid"`
+	Description string  `json:"description"`
+	Notes       string  `json:"notes"`
+	StartTime   float64 `json:"startTime"`
+	EndTime     float64 `json:"endTime"`
+}
+
+type OpenTsdbExpressionResponse struct {
+	Outputs []OpenTsdbExpressionOutput `json:"outputs"`
+}
+
+type OpenTsdbExpressionOutput struct {
+	ID         string                   `json:"id"`
+	Alias      string                   `json:"alias"`
+	DataPoints [][]float64              `json:"dps"`
+	Meta       []OpenTsdbExpressionMeta `json:"meta"`
+}
+
+type OpenTsdbExpressionMeta struct {
+	Index      int               `json:"index"`
+	Metrics    []string          `json:"metrics"`
+	CommonTags map[string]string `json:"commonTags"`
 }
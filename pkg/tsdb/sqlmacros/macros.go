@@ -0,0 +1,157 @@
+// Package sqlmacros contains the macro helpers shared by the SQL data sources.
+package sqlmacros
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+)
+
+// ParseInterval parses the interval argument of the time grouping macros.
+// A bare number is read as milliseconds, so $__interval_ms can be passed as the
+// interval as well as $__interval.
+func ParseInterval(value string) (time.Duration, error) {
+	value = strings.Trim(value, `'"`)
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if ms <= 0 {
+			return 0, fmt.Errorf("error parsing interval %v", value)
+		}
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	interval, err := gtime.ParseInterval(value)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing interval %v", value)
+	}
+	return interval, nil
+}
+
+// CalendarUnit returns the calendar unit named by a time grouping macro
+// argument, or an empty string if the argument is a regular interval.
+func CalendarUnit(value string) string {
+	unit := strings.ToLower(strings.Trim(value, `'"`))
+	switch unit {
+	case "day", "week", "month", "year":
+		return unit
+	}
+	return ""
+}
+
+var timezoneRegExp = regexp.MustCompile(`^[A-Za-z0-9_/+\-:. ]+$`)
+
+// Timezone returns the time zone argument of a macro with quotes removed,
+// rejecting anything that is not a plain time zone name or offset.
+func Timezone(value string) (string, error) {
+	tz := strings.Trim(value, `'"`)
+	if !timezoneRegExp.MatchString(tz) {
+		return "", fmt.Errorf("invalid time zone %v", value)
+	}
+	return tz, nil
+}
+
+// ShiftTimeRange moves the time range back by the given offset. An offset
+// prefixed with "-" moves it forward instead.
+func ShiftTimeRange(timeRange backend.TimeRange, offset string) (backend.TimeRange, error) {
+	offset = strings.Trim(offset, `'"`)
+	sign := time.Duration(-1)
+	if strings.HasPrefix(offset, "-") {
+		sign = 1
+		offset = offset[1:]
+	}
+	d, err := gtime.ParseInterval(strings.TrimPrefix(offset, "+"))
+	if err != nil {
+		return timeRange, fmt.Errorf("error parsing time shift %v", offset)
+	}
+	return backend.TimeRange{
+		From: timeRange.From.Add(sign * d),
+		To:   timeRange.To.Add(sign * d),
+	}, nil
+}
+
+const inMacro = "$__in("
+
+// ExpandIn replaces the $__in(column, values...) macros of the query with an IN
+// condition on the column. The arguments are split on the commas outside of
+// quotes, so quoted values may contain commas and parentheses. Each value is
+// quoted with the quote function of the data source dialect.
+func ExpandIn(sql string, quote func(value string) string) (string, error) {
+	var result strings.Builder
+	for {
+		start := strings.Index(sql, inMacro)
+		if start < 0 {
+			result.WriteString(sql)
+			return result.String(), nil
+		}
+		args, length, err := splitInArgs(sql[start+len(inMacro):])
+		if err != nil {
+			return "", err
+		}
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("missing column argument for macro __in")
+		}
+
+		result.WriteString(sql[:start])
+		values := inValues(args[1:])
+		if len(values) == 0 {
+			result.WriteString("1=0")
+		} else {
+			for i, v := range values {
+				values[i] = quote(v)
+			}
+			fmt.Fprintf(&result, "%s IN (%s)", args[0], strings.Join(values, ","))
+		}
+		sql = sql[start+len(inMacro)+length:]
+	}
+}
+
+// splitInArgs splits the arguments of a $__in macro up to its closing
+// parenthesis, ignoring commas and parentheses inside quotes. It returns the
+// trimmed arguments and the length of the arguments including the parenthesis.
+func splitInArgs(s string) ([]string, int, error) {
+	var args []string
+	var quote byte
+	argStart := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			// a doubled quote inside a quoted value is read as leaving and
+			// entering the value again
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ',':
+			args = append(args, strings.TrimSpace(s[argStart:i]))
+			argStart = i + 1
+		case c == ')':
+			args = append(args, strings.TrimSpace(s[argStart:i]))
+			return args, i + 1, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("missing closing parenthesis for macro __in")
+}
+
+// inValues returns the raw values passed to the $__in macro. Values that were
+// already quoted by the frontend variable interpolation are unquoted so the
+// macro engine can apply the quoting of its own dialect.
+func inValues(args []string) []string {
+	values := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "" {
+			continue
+		}
+		if len(arg) >= 2 && arg[0] == '\'' && arg[len(arg)-1] == '\'' {
+			arg = strings.ReplaceAll(arg[1:len(arg)-1], "''", "'")
+		} else if len(arg) >= 2 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+			arg = strings.ReplaceAll(arg[1:len(arg)-1], `""`, `"`)
+		}
+		values = append(values, arg)
+	}
+	return values
+}
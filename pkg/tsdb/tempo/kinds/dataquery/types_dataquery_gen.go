@@ -9,6 +9,12 @@
 
 package dataquery
 
+// Defines values for MetricsQueryType.
+const (
+	MetricsQueryTypeInstant MetricsQueryType = "instant"
+	MetricsQueryTypeRange   MetricsQueryType = "range"
+)
+
 // Defines values for SearchStreamingState.
 const (
 	SearchStreamingStateDone      SearchStreamingState = "done"
@@ -69,6 +75,9 @@ type DataQuery struct {
 	RefId string `json:"refId"`
 }
 
+// The type of the metrics query
+type MetricsQueryType string
+
 // The state of the TraceQL streaming search query
 type SearchStreamingState string
 
@@ -99,6 +108,9 @@ type TempoQuery struct {
 	// @deprecated Define the maximum duration to select traces. Use duration format, for example: 1.2s, 100ms
 	MaxDuration *string `json:"maxDuration,omitempty"`
 
+	// For metric queries, whether to run a range or an instant query
+	MetricsQueryType *MetricsQueryType `json:"metricsQueryType,omitempty"`
+
 	// @deprecated Define the minimum duration to select traces. Use duration format, for example: 1.2s, 100ms
 	MinDuration *string `json:"minDuration,omitempty"`
 
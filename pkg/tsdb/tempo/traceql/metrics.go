@@ -15,23 +15,7 @@ func TransformMetricsResponse(resp tempopb.QueryRangeResponse) []*data.Frame {
 	// prealloc frames
 	frames := make([]*data.Frame, len(resp.Series))
 	for i, series := range resp.Series {
-		labels := make(data.Labels)
-		for _, label := range series.Labels {
-			labels[label.GetKey()] = metricsValueToString(label.GetValue())
-		}
-
-		name := ""
-		if len(series.Labels) > 0 {
-			if len(series.Labels) == 1 {
-				name = metricsValueToString(series.Labels[0].GetValue())
-			} else {
-				var labelStrings []string
-				for key, val := range labels {
-					labelStrings = append(labelStrings, fmt.Sprintf("%s=%s", key, val))
-				}
-				name = fmt.Sprintf("{%s}", strings.Join(labelStrings, ", "))
-			}
-		}
+		labels, name := seriesLabels(series.Labels)
 
 		valueField := data.NewField(name, labels, []float64{})
 		valueField.Config = &data.FieldConfig{
@@ -61,6 +45,54 @@ func TransformMetricsResponse(resp tempopb.QueryRangeResponse) []*data.Frame {
 	return frames
 }
 
+// TransformInstantMetricsResponse converts an instant metrics response to one frame
+// per series with a single sample at the given time
+func TransformInstantMetricsResponse(resp tempopb.QueryInstantResponse, at time.Time) []*data.Frame {
+	frames := make([]*data.Frame, len(resp.Series))
+	for i, series := range resp.Series {
+		labels, name := seriesLabels(series.Labels)
+
+		valueField := data.NewField(name, labels, []float64{series.GetValue()})
+		valueField.Config = &data.FieldConfig{
+			DisplayName: name,
+		}
+
+		frames[i] = &data.Frame{
+			RefID: name,
+			Name:  "Trace",
+			Fields: []*data.Field{
+				data.NewField("time", nil, []time.Time{at}),
+				valueField,
+			},
+			Meta: &data.FrameMeta{
+				PreferredVisualization: data.VisTypeTable,
+			},
+		}
+	}
+	return frames
+}
+
+func seriesLabels(seriesLabels []v1.KeyValue) (data.Labels, string) {
+	labels := make(data.Labels)
+	for _, label := range seriesLabels {
+		labels[label.GetKey()] = metricsValueToString(label.GetValue())
+	}
+
+	name := ""
+	if len(seriesLabels) > 0 {
+		if len(seriesLabels) == 1 {
+			name = metricsValueToString(seriesLabels[0].GetValue())
+		} else {
+			var labelStrings []string
+			for key, val := range labels {
+				labelStrings = append(labelStrings, fmt.Sprintf("%s=%s", key, val))
+			}
+			name = fmt.Sprintf("{%s}", strings.Join(labelStrings, ", "))
+		}
+	}
+	return labels, name
+}
+
 func metricsValueToString(value *v1.AnyValue) string {
 	switch value.GetValue().(type) {
 	case *v1.AnyValue_DoubleValue:
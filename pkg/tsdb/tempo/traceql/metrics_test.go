@@ -113,3 +113,31 @@ func TestTransformMetricsResponse_MultipleSeries(t *testing.T) {
 	assert.Equal(t, time.UnixMilli(1638316800000), frames[1].Fields[0].At(0))
 	assert.Equal(t, 4.56, frames[1].Fields[1].At(0))
 }
+
+func TestTransformInstantMetricsResponse(t *testing.T) {
+	at := time.UnixMilli(1638316800000)
+	resp := tempopb.QueryInstantResponse{
+		Series: []*tempopb.InstantSeries{
+			{
+				Labels: []v1.KeyValue{
+					{Key: "label1", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "value1"}}},
+				},
+				Value: 4.5,
+			},
+			{
+				Labels: []v1.KeyValue{
+					{Key: "label1", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "value2"}}},
+				},
+				Value: 2,
+			},
+		},
+	}
+	frames := TransformInstantMetricsResponse(resp, at)
+	assert.Len(t, frames, 2)
+	assert.Equal(t, "\"value1\"", frames[0].RefID)
+	assert.Equal(t, 1, frames[0].Rows())
+	assert.Equal(t, at, frames[0].Fields[0].At(0))
+	assert.Equal(t, 4.5, frames[0].Fields[1].At(0))
+	assert.Equal(t, data.Labels{"label1": "\"value1\""}, frames[0].Fields[1].Labels)
+	assert.Equal(t, 2.0, frames[1].Fields[1].At(0))
+}
@@ -0,0 +1,219 @@
+package traceql
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+)
+
+// DatasourceRef identifies the data source that internal links of the search frames point to
+type DatasourceRef struct {
+	UID  string
+	Name string
+}
+
+// TransformSearchResponse converts a TraceQL search response to the same table frames
+// the frontend builds for the given table type.
+func TransformSearchResponse(resp *tempopb.SearchResponse, tableType dataquery.SearchTableType, ds DatasourceRef) ([]*data.Frame, error) {
+	traces := make([]*tempopb.TraceSearchMetadata, 0, len(resp.GetTraces()))
+	traces = append(traces, resp.GetTraces()...)
+	// Show the most recent traces
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traces[i].GetStartTimeUnixNano() > traces[j].GetStartTimeUnixNano()
+	})
+
+	switch tableType {
+	case dataquery.SearchTableTypeSpans:
+		return []*data.Frame{spansFrame(traces, ds)}, nil
+	case dataquery.SearchTableTypeRaw:
+		raw, err := json.MarshalIndent(traces, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		frame := data.NewFrame("Raw response", data.NewField("response", nil, []string{string(raw)}))
+		return []*data.Frame{frame}, nil
+	default:
+		return []*data.Frame{tracesFrame(traces, ds)}, nil
+	}
+}
+
+// tracesFrame creates a table with one row per trace. The frontend nests the spans of each
+// trace as sub tables, which can't be represented in backend frames, use the spans table type instead.
+func tracesFrame(traces []*tempopb.TraceSearchMetadata, ds DatasourceRef) *data.Frame {
+	traceIDField := data.NewField("traceID", nil, make([]string, 0, len(traces)))
+	traceIDField.Config = &data.FieldConfig{
+		Unit:              "string",
+		DisplayNameFromDS: "Trace ID",
+		Custom:            map[string]any{"width": 200},
+		Links: []data.DataLink{{
+			Title: "Trace: ${__value.raw}",
+			Internal: &data.InternalDataLink{
+				DatasourceUID:  ds.UID,
+				DatasourceName: ds.Name,
+				Query: map[string]any{
+					"query":     "${__value.raw}",
+					"queryType": string(dataquery.TempoQueryTypeTraceql),
+				},
+			},
+		}},
+	}
+	startTimeField := data.NewField("startTime", nil, make([]time.Time, 0, len(traces)))
+	startTimeField.Config = &data.FieldConfig{DisplayNameFromDS: "Start time", Custom: map[string]any{"width": 200}}
+	serviceField := data.NewField("traceService", nil, make([]string, 0, len(traces)))
+	serviceField.Config = &data.FieldConfig{DisplayNameFromDS: "Service"}
+	nameField := data.NewField("traceName", nil, make([]string, 0, len(traces)))
+	nameField.Config = &data.FieldConfig{DisplayNameFromDS: "Name"}
+	durationField := data.NewField("traceDuration", nil, make([]float64, 0, len(traces)))
+	durationField.Config = &data.FieldConfig{DisplayNameFromDS: "Duration", Unit: "ms", Custom: map[string]any{"width": 120}}
+
+	for _, trace := range traces {
+		traceIDField.Append(trace.GetTraceID())
+		startTimeField.Append(time.Unix(0, int64(trace.GetStartTimeUnixNano())))
+		serviceField.Append(trace.GetRootServiceName())
+		nameField.Append(trace.GetRootTraceName())
+		durationField.Append(float64(trace.GetDurationMs()))
+	}
+
+	frame := data.NewFrame("Traces", traceIDField, startTimeField, serviceField, nameField, durationField)
+	frame.Meta = &data.FrameMeta{
+		PreferredVisualization: data.VisTypeTable,
+	}
+	return frame
+}
+
+// spansFrame creates a table with one row per matched span
+func spansFrame(traces []*tempopb.TraceSearchMetadata, ds DatasourceRef) *data.Frame {
+	// collect the attribute columns and whether any span has a name
+	attrNames := map[string]struct{}{}
+	hasName := false
+	rows := 0
+	for _, trace := range traces {
+		for _, spanSet := range spanSets(trace) {
+			for _, attr := range spanSet.GetAttributes() {
+				attrNames[attr.GetKey()] = struct{}{}
+			}
+			for _, span := range spanSet.GetSpans() {
+				rows++
+				if span.GetName() != "" {
+					hasName = true
+				}
+				for _, attr := range span.GetAttributes() {
+					attrNames[attr.GetKey()] = struct{}{}
+				}
+			}
+		}
+	}
+	sortedAttrs := make([]string, 0, len(attrNames))
+	for name := range attrNames {
+		sortedAttrs = append(sortedAttrs, name)
+	}
+	sort.Strings(sortedAttrs)
+
+	traceIDField := data.NewField("traceIdHidden", nil, make([]string, 0, rows))
+	traceIDField.Config = &data.FieldConfig{Custom: map[string]any{"hidden": true}}
+	serviceField := data.NewField("traceService", nil, make([]string, 0, rows))
+	serviceField.Config = &data.FieldConfig{DisplayNameFromDS: "Trace Service", Custom: map[string]any{"width": 200}}
+	traceNameField := data.NewField("traceName", nil, make([]string, 0, rows))
+	traceNameField.Config = &data.FieldConfig{DisplayNameFromDS: "Trace Name", Custom: map[string]any{"width": 200}}
+	var panelsState data.ExplorePanelsState = map[string]any{
+		"trace": map[string]any{"spanId": "${__value.raw}"},
+	}
+	spanIDField := data.NewField("spanID", nil, make([]string, 0, rows))
+	spanIDField.Config = &data.FieldConfig{
+		Unit:              "string",
+		DisplayNameFromDS: "Span ID",
+		Custom:            map[string]any{"width": 200},
+		Links: []data.DataLink{{
+			Title: "Span: ${__value.raw}",
+			Internal: &data.InternalDataLink{
+				DatasourceUID:  ds.UID,
+				DatasourceName: ds.Name,
+				Query: map[string]any{
+					"query":     "${__data.fields.traceIdHidden}",
+					"queryType": string(dataquery.TempoQueryTypeTraceql),
+				},
+				ExplorePanelsState: &panelsState,
+			},
+		}},
+	}
+	timeField := data.NewField("time", nil, make([]time.Time, 0, rows))
+	timeField.Config = &data.FieldConfig{DisplayNameFromDS: "Start time"}
+	nameField := data.NewField("name", nil, make([]string, 0, rows))
+	nameField.Config = &data.FieldConfig{DisplayNameFromDS: "Name", Custom: map[string]any{"hidden": !hasName}}
+	attrFields := make(map[string]*data.Field, len(sortedAttrs))
+	for _, name := range sortedAttrs {
+		field := data.NewField(name, nil, make([]*string, 0, rows))
+		field.Config = &data.FieldConfig{DisplayNameFromDS: name}
+		attrFields[name] = field
+	}
+	durationField := data.NewField("duration", nil, make([]float64, 0, rows))
+	durationField.Config = &data.FieldConfig{DisplayNameFromDS: "Duration", Unit: "ns", Custom: map[string]any{"width": 120}}
+
+	for _, trace := range traces {
+		for _, spanSet := range spanSets(trace) {
+			for _, span := range spanSet.GetSpans() {
+				traceIDField.Append(trace.GetTraceID())
+				serviceField.Append(trace.GetRootServiceName())
+				traceNameField.Append(trace.GetRootTraceName())
+				spanIDField.Append(span.GetSpanID())
+				timeField.Append(time.Unix(0, int64(span.GetStartTimeUnixNano())))
+				nameField.Append(span.GetName())
+				durationField.Append(float64(span.GetDurationNanos()))
+
+				values := make(map[string]*string, len(attrFields))
+				for _, attr := range append(append([]*v1.KeyValue{}, spanSet.GetAttributes()...), span.GetAttributes()...) {
+					if value, ok := attributeValueToString(attr.GetValue()); ok {
+						values[attr.GetKey()] = &value
+					}
+				}
+				for name, field := range attrFields {
+					field.Append(values[name])
+				}
+			}
+		}
+	}
+
+	fields := []*data.Field{traceIDField, serviceField, traceNameField, spanIDField, timeField, nameField}
+	for _, name := range sortedAttrs {
+		fields = append(fields, attrFields[name])
+	}
+	fields = append(fields, durationField)
+
+	frame := data.NewFrame("Spans", fields...)
+	frame.Meta = &data.FrameMeta{
+		PreferredVisualization: data.VisTypeTable,
+	}
+	return frame
+}
+
+// spanSets returns the spansets of a trace, spanSets is preferred to the deprecated spanSet
+func spanSets(trace *tempopb.TraceSearchMetadata) []*tempopb.SpanSet {
+	if len(trace.GetSpanSets()) > 0 {
+		return trace.GetSpanSets()
+	}
+	if trace.GetSpanSet() != nil {
+		return []*tempopb.SpanSet{trace.GetSpanSet()}
+	}
+	return nil
+}
+
+func attributeValueToString(value *v1.AnyValue) (string, bool) {
+	switch value.GetValue().(type) {
+	case *v1.AnyValue_StringValue:
+		return value.GetStringValue(), true
+	case *v1.AnyValue_IntValue:
+		return strconv.FormatInt(value.GetIntValue(), 10), true
+	case *v1.AnyValue_DoubleValue:
+		return strconv.FormatFloat(value.GetDoubleValue(), 'f', -1, 64), true
+	case *v1.AnyValue_BoolValue:
+		return strconv.FormatBool(value.GetBoolValue()), true
+	}
+	return "", false
+}
@@ -0,0 +1,121 @@
+package traceql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+)
+
+func searchResponse() *tempopb.SearchResponse {
+	return &tempopb.SearchResponse{
+		Traces: []*tempopb.TraceSearchMetadata{
+			{
+				TraceID:           "older",
+				RootServiceName:   "api",
+				RootTraceName:     "GET /",
+				StartTimeUnixNano: 1000000000,
+				DurationMs:        12,
+				SpanSet: &tempopb.SpanSet{
+					Spans: []*tempopb.Span{
+						{SpanID: "s1", StartTimeUnixNano: 1000000000, DurationNanos: 500},
+					},
+				},
+			},
+			{
+				TraceID:           "newer",
+				RootServiceName:   "db",
+				RootTraceName:     "query",
+				StartTimeUnixNano: 2000000000,
+				DurationMs:        3,
+				SpanSets: []*tempopb.SpanSet{
+					{
+						Attributes: []*v1.KeyValue{
+							{Key: "by", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "x"}}},
+						},
+						Spans: []*tempopb.Span{
+							{
+								SpanID:            "s2",
+								Name:              "select",
+								StartTimeUnixNano: 2000000000,
+								DurationNanos:     1500,
+								Attributes: []*v1.KeyValue{
+									{Key: "http.status", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 200}}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTransformSearchResponse_Traces(t *testing.T) {
+	frames, err := TransformSearchResponse(searchResponse(), dataquery.SearchTableTypeTraces, DatasourceRef{UID: "tempo-uid", Name: "Tempo"})
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+
+	frame := frames[0]
+	assert.Equal(t, "Traces", frame.Name)
+	assert.Equal(t, data.VisType(data.VisTypeTable), frame.Meta.PreferredVisualization)
+	assert.Equal(t, 2, frame.Rows())
+	assert.Equal(t, "traceID", frame.Fields[0].Name)
+	// most recent trace first
+	assert.Equal(t, "newer", frame.Fields[0].At(0))
+	assert.Equal(t, time.Unix(2, 0), frame.Fields[1].At(0))
+	assert.Equal(t, "db", frame.Fields[2].At(0))
+	assert.Equal(t, "query", frame.Fields[3].At(0))
+	assert.Equal(t, 3.0, frame.Fields[4].At(0))
+
+	link := frame.Fields[0].Config.Links[0]
+	assert.Equal(t, "tempo-uid", link.Internal.DatasourceUID)
+	assert.Equal(t, "Tempo", link.Internal.DatasourceName)
+}
+
+func TestTransformSearchResponse_Spans(t *testing.T) {
+	frames, err := TransformSearchResponse(searchResponse(), dataquery.SearchTableTypeSpans, DatasourceRef{UID: "tempo-uid"})
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+
+	frame := frames[0]
+	assert.Equal(t, "Spans", frame.Name)
+	assert.Equal(t, 2, frame.Rows())
+
+	names := make([]string, 0, len(frame.Fields))
+	for _, f := range frame.Fields {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"traceIdHidden", "traceService", "traceName", "spanID", "time", "name", "by", "http.status", "duration"}, names)
+
+	assert.Equal(t, "newer", frame.Fields[0].At(0))
+	assert.Equal(t, "s2", frame.Fields[3].At(0))
+	assert.Equal(t, "select", frame.Fields[5].At(0))
+	assert.Equal(t, "x", *frame.Fields[6].At(0).(*string))
+	assert.Equal(t, "200", *frame.Fields[7].At(0).(*string))
+	assert.Equal(t, 1500.0, frame.Fields[8].At(0))
+	// spans of the deprecated spanSet are included too
+	assert.Equal(t, "s1", frame.Fields[3].At(1))
+	assert.Nil(t, frame.Fields[6].At(1))
+}
+
+func TestTransformSearchResponse_Raw(t *testing.T) {
+	frames, err := TransformSearchResponse(searchResponse(), dataquery.SearchTableTypeRaw, DatasourceRef{})
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+	assert.Equal(t, "Raw response", frames[0].Name)
+	assert.Contains(t, frames[0].Fields[0].At(0), `"traceID": "newer"`)
+}
+
+func TestTransformSearchResponse_Empty(t *testing.T) {
+	frames, err := TransformSearchResponse(&tempopb.SearchResponse{}, dataquery.SearchTableTypeTraces, DatasourceRef{})
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+	assert.Equal(t, 0, frames[0].Rows())
+}
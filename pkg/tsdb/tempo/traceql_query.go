@@ -15,6 +15,7 @@ import (
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/tracing"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
 	"github.com/grafana/grafana/pkg/tsdb/tempo/traceql"
 	"github.com/grafana/tempo/pkg/tempopb"
@@ -34,15 +35,88 @@ func (s *Service) runTraceQlQuery(ctx context.Context, pCtx backend.PluginContex
 		return nil, err
 	}
 
-	if isMetricsQuery(*tempoQuery.Query) {
+	if tempoQuery.Query != nil && isMetricsQuery(*tempoQuery.Query) {
 		return s.runTraceQlQueryMetrics(ctx, pCtx, backendQuery, tempoQuery)
 	}
 
-	return s.runTraceQlQuerySearch()
+	return s.runTraceQlQuerySearch(ctx, pCtx, backendQuery, tempoQuery)
 }
 
-func (s *Service) runTraceQlQuerySearch() (*backend.DataResponse, error) {
-	return nil, fmt.Errorf("backend TraceQL search queries are not supported")
+func (s *Service) runTraceQlQuerySearch(ctx context.Context, pCtx backend.PluginContext, backendQuery backend.DataQuery, tempoQuery *dataquery.TempoQuery) (*backend.DataResponse, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+	ctxLogger.Debug("Running TraceQL Search query", "function", logEntrypoint())
+
+	ctx, span := tracing.DefaultTracer().Start(ctx, "datasource.tempo.runTraceQLSearch", trace.WithAttributes(
+		attribute.String("queryType", backendQuery.QueryType),
+	))
+	defer span.End()
+
+	result := &backend.DataResponse{}
+
+	dsInfo, err := s.getDSInfo(ctx, pCtx)
+	if err != nil {
+		ctxLogger.Error("Failed to get datasource information", "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+
+	if tempoQuery.Query == nil || *tempoQuery.Query == "" {
+		err := fmt.Errorf("query is required")
+		ctxLogger.Error("Failed to validate model query", "error", err, "function", logEntrypoint())
+		return result, err
+	}
+
+	request, err := s.createSearchQuery(ctx, dsInfo, tempoQuery, backendQuery.TimeRange.From.Unix(), backendQuery.TimeRange.To.Unix())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return result, err
+	}
+
+	resp, responseBody, err := s.performRequest(ctx, dsInfo, request, span)
+	if err != nil {
+		return result, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		ctxLogger.Error("Failed to execute TraceQL search query", "status", resp.Status, "function", logEntrypoint())
+		result.Error = fmt.Errorf("failed to execute TraceQL query: %s Status: %s Body: %s", *tempoQuery.Query, resp.Status, string(responseBody))
+		result.ErrorSource = backend.ErrorSourceFromHTTPStatus(resp.StatusCode)
+		span.RecordError(result.Error)
+		span.SetStatus(codes.Error, result.Error.Error())
+		return result, nil
+	}
+
+	var searchResponse tempopb.SearchResponse
+	err = jsonpb.Unmarshal(bytes.NewReader(responseBody), &searchResponse)
+	if err != nil {
+		ctxLogger.Error("Failed to convert response to type", "error", err, "function", logEntrypoint())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return &backend.DataResponse{}, fmt.Errorf("failed to convert response to type: %w", err)
+	}
+
+	tableType := dataquery.SearchTableTypeTraces
+	if tempoQuery.TableType != nil {
+		tableType = *tempoQuery.TableType
+	}
+	ds := traceql.DatasourceRef{}
+	if pCtx.DataSourceInstanceSettings != nil {
+		ds.UID = pCtx.DataSourceInstanceSettings.UID
+		ds.Name = pCtx.DataSourceInstanceSettings.Name
+	}
+
+	frames, err := traceql.TransformSearchResponse(&searchResponse, tableType, ds)
+	if err != nil {
+		return result, err
+	}
+	for _, frame := range frames {
+		frame.RefID = backendQuery.RefID
+		frame.Meta.ExecutedQueryString = *tempoQuery.Query
+	}
+
+	result.Frames = frames
+	ctxLogger.Debug("Successfully performed TraceQL search query", "function", logEntrypoint())
+	return result, nil
 }
 
 func (s *Service) runTraceQlQueryMetrics(ctx context.Context, pCtx backend.PluginContext, backendQuery backend.DataQuery, tempoQuery *dataquery.TempoQuery) (*backend.DataResponse, error) {
@@ -69,11 +143,6 @@ func (s *Service) runTraceQlQueryMetrics(ctx context.Context, pCtx backend.Plugi
 	}
 
 	resp, responseBody, err := s.performMetricsQuery(ctx, dsInfo, tempoQuery, backendQuery, span)
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			ctxLogger.Error("Failed to close response body", "error", err, "function", logEntrypoint())
-		}
-	}()
 	if err != nil {
 		return result, err
 	}
@@ -86,8 +155,20 @@ func (s *Service) runTraceQlQueryMetrics(ctx context.Context, pCtx backend.Plugi
 		return result, nil
 	}
 
-	var queryResponse tempopb.QueryRangeResponse
-	err = jsonpb.Unmarshal(bytes.NewReader(responseBody), &queryResponse)
+	var frames []*data.Frame
+	if isInstantMetricsQuery(tempoQuery) {
+		var queryResponse tempopb.QueryInstantResponse
+		err = jsonpb.Unmarshal(bytes.NewReader(responseBody), &queryResponse)
+		if err == nil {
+			frames = traceql.TransformInstantMetricsResponse(queryResponse, backendQuery.TimeRange.To)
+		}
+	} else {
+		var queryResponse tempopb.QueryRangeResponse
+		err = jsonpb.Unmarshal(bytes.NewReader(responseBody), &queryResponse)
+		if err == nil {
+			frames = traceql.TransformMetricsResponse(queryResponse)
+		}
+	}
 
 	if err != nil {
 		ctxLogger.Error("Failed to convert response to type", "error", err, "function", logEntrypoint())
@@ -96,8 +177,6 @@ func (s *Service) runTraceQlQueryMetrics(ctx context.Context, pCtx backend.Plugi
 		return &backend.DataResponse{}, fmt.Errorf("failed to convert response to type: %w", err)
 	}
 
-	frames := traceql.TransformMetricsResponse(queryResponse)
-
 	result.Frames = frames
 	ctxLogger.Debug("Successfully performed TraceQL query", "function", logEntrypoint())
 	return result, nil
@@ -114,6 +193,13 @@ func (s *Service) performMetricsQuery(ctx context.Context, dsInfo *Datasource, m
 		return nil, nil, err
 	}
 
+	return s.performRequest(ctx, dsInfo, request, span)
+}
+
+// performRequest sends the request to Tempo and returns the response with its body, which is already closed
+func (s *Service) performRequest(ctx context.Context, dsInfo *Datasource, request *http.Request, span trace.Span) (*http.Response, []byte, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+
 	resp, err := dsInfo.HTTPClient.Do(request)
 	if err != nil {
 		ctxLogger.Error("Failed to send request to Tempo", "error", err, "function", logEntrypoint())
@@ -121,6 +207,11 @@ func (s *Service) performMetricsQuery(ctx context.Context, dsInfo *Datasource, m
 		span.SetStatus(codes.Error, err.Error())
 		return nil, nil, fmt.Errorf("failed get to tempo: %w", err)
 	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			ctxLogger.Error("Failed to close response body", "error", err, "function", logEntrypoint())
+		}
+	}()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -133,7 +224,11 @@ func (s *Service) performMetricsQuery(ctx context.Context, dsInfo *Datasource, m
 func (s *Service) createMetricsQuery(ctx context.Context, dsInfo *Datasource, query *dataquery.TempoQuery, start int64, end int64) (*http.Request, error) {
 	ctxLogger := s.logger.FromContext(ctx)
 
-	rawUrl := fmt.Sprintf("%s/api/metrics/query_range", dsInfo.URL)
+	path := "query_range"
+	if isInstantMetricsQuery(query) {
+		path = "query"
+	}
+	rawUrl := fmt.Sprintf("%s/api/metrics/%s", dsInfo.URL, path)
 	searchUrl, err := url.Parse(rawUrl)
 	if err != nil {
 		ctxLogger.Error("Failed to parse URL", "url", rawUrl, "error", err, "function", logEntrypoint())
@@ -148,7 +243,7 @@ func (s *Service) createMetricsQuery(ctx context.Context, dsInfo *Datasource, qu
 	if end > 0 {
 		q.Set("end", strconv.FormatInt(end, 10))
 	}
-	if query.Step != nil {
+	if query.Step != nil && !isInstantMetricsQuery(query) {
 		q.Set("step", *query.Step)
 	}
 
@@ -164,6 +259,47 @@ func (s *Service) createMetricsQuery(ctx context.Context, dsInfo *Datasource, qu
 	return req, nil
 }
 
+func (s *Service) createSearchQuery(ctx context.Context, dsInfo *Datasource, query *dataquery.TempoQuery, start int64, end int64) (*http.Request, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+
+	rawUrl := fmt.Sprintf("%s/api/search", dsInfo.URL)
+	searchUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		ctxLogger.Error("Failed to parse URL", "url", rawUrl, "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+
+	q := searchUrl.Query()
+	q.Set("q", *query.Query)
+	if query.Limit != nil && *query.Limit > 0 {
+		q.Set("limit", strconv.FormatInt(*query.Limit, 10))
+	}
+	if query.Spss != nil && *query.Spss > 0 {
+		q.Set("spss", strconv.FormatInt(*query.Spss, 10))
+	}
+	if start > 0 {
+		q.Set("start", strconv.FormatInt(start, 10))
+	}
+	if end > 0 {
+		q.Set("end", strconv.FormatInt(end, 10))
+	}
+
+	searchUrl.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchUrl.String(), nil)
+	if err != nil {
+		ctxLogger.Error("Failed to create request", "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+func isInstantMetricsQuery(query *dataquery.TempoQuery) bool {
+	return query.MetricsQueryType != nil && *query.MetricsQueryType == dataquery.MetricsQueryTypeInstant
+}
+
 func isMetricsQuery(query string) bool {
 	match, _ := regexp.MatchString("\\|\\s*(rate|count_over_time|avg_over_time|max_over_time|min_over_time|quantile_over_time|histogram_over_time|compare)\\s*\\(", query)
 	return match
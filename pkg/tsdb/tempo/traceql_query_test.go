@@ -52,6 +52,55 @@ func TestCreateMetricsQuery_OnlyQuery(t *testing.T) {
 	assert.Equal(t, "application/json", req.Header.Get("Accept"))
 }
 
+func TestCreateMetricsQuery_Instant(t *testing.T) {
+	logger := backend.NewLoggerWith("logger", "tsdb.tempo.test")
+	service := &Service{
+		logger: logger,
+	}
+	dsInfo := &Datasource{
+		URL: "http://tempo:3100",
+	}
+	queryVal := "{attribute=\"value\"} | rate()"
+	stepVal := "14"
+	instant := dataquery.MetricsQueryTypeInstant
+	query := &dataquery.TempoQuery{
+		Query:            &queryVal,
+		Step:             &stepVal,
+		MetricsQueryType: &instant,
+	}
+
+	req, err := service.createMetricsQuery(context.Background(), dsInfo, query, 1625097600, 1625184000)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://tempo:3100/api/metrics/query?end=1625184000&q=%7Battribute%3D%22value%22%7D+%7C+rate%28%29&start=1625097600", req.URL.String())
+}
+
+func TestCreateSearchQuery(t *testing.T) {
+	logger := backend.NewLoggerWith("logger", "tsdb.tempo.test")
+	service := &Service{
+		logger: logger,
+	}
+	dsInfo := &Datasource{
+		URL: "http://tempo:3100",
+	}
+	queryVal := "{attribute=\"value\"}"
+	limit := int64(20)
+	spss := int64(3)
+	query := &dataquery.TempoQuery{
+		Query: &queryVal,
+		Limit: &limit,
+		Spss:  &spss,
+	}
+
+	req, err := service.createSearchQuery(context.Background(), dsInfo, query, 1625097600, 1625184000)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://tempo:3100/api/search?end=1625184000&limit=20&q=%7Battribute%3D%22value%22%7D&spss=3&start=1625097600", req.URL.String())
+	assert.Equal(t, "application/json", req.Header.Get("Accept"))
+
+	req, err = service.createSearchQuery(context.Background(), dsInfo, &dataquery.TempoQuery{Query: &queryVal}, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://tempo:3100/api/search?q=%7Battribute%3D%22value%22%7D", req.URL.String())
+}
+
 func TestCreateMetricsQuery_URLParseError(t *testing.T) {
 	logger := backend.NewLoggerWith("logger", "tsdb.tempo.test")
 	service := &Service{
@@ -20,6 +20,11 @@ import { BackendSrv as BackendService, BackendSrvRequest, config, FetchError, Fe
 import appEvents from 'app/core/app_events';
 import { getConfig } from 'app/core/config';
 import { getSessionExpiry, hasSessionExpiry } from 'app/core/utils/auth';
+import {
+  isPublicDashboardUrl,
+  loadPublicDashboardPassword,
+  PUBLIC_DASHBOARD_PASSWORD_HEADER,
+} from 'app/core/utils/publicDashboardPassword';
 import { loadUrlToken } from 'app/core/utils/urlToken';
 import { getDashboardAPI } from 'app/features/dashboard/api/dashboard_api';
 import { DashboardModel } from 'app/features/dashboard/state/DashboardModel';
@@ -162,6 +167,12 @@ export class BackendSrv implements BackendService {
       options.headers['X-Grafana-Device-Id'] = `${this.deviceID}`;
     }
 
+    const publicDashboardPassword = loadPublicDashboardPassword();
+    if (publicDashboardPassword && isPublicDashboardUrl(options.url)) {
+      options.headers = options.headers ?? {};
+      options.headers[PUBLIC_DASHBOARD_PASSWORD_HEADER] = publicDashboardPassword;
+    }
+
     return this.getFromFetchStream<T>(options).pipe(
       this.handleStreamResponse<T>(options),
       this.handleStreamError(options),
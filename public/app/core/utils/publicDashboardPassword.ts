@@ -0,0 +1,23 @@
+import { config } from '@grafana/runtime';
+
+export const PUBLIC_DASHBOARD_PASSWORD_HEADER = 'X-Grafana-Public-Dashboard-Password';
+
+const storageKey = (accessToken: string) => `grafana.publicDashboard.password.${accessToken}`;
+
+// The password of a public dashboard token is kept for the browser session, so viewers don't enter it again on reload
+export const loadPublicDashboardPassword = (): string | null => {
+  if (!config.publicDashboardAccessToken) {
+    return null;
+  }
+  return window.sessionStorage.getItem(storageKey(config.publicDashboardAccessToken));
+};
+
+export const savePublicDashboardPassword = (password: string) => {
+  if (config.publicDashboardAccessToken) {
+    window.sessionStorage.setItem(storageKey(config.publicDashboardAccessToken), password);
+  }
+};
+
+export const isPublicDashboardUrl = (url: string): boolean => {
+  return url.startsWith('api/public/dashboards/') || url.startsWith('/api/public/dashboards/');
+};
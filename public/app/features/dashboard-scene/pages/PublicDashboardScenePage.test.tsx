@@ -114,6 +114,7 @@ const runRequestMock = jest.fn().mockReturnValue(
 setRunRequest(runRequestMock);
 
 const componentsSelector = e2eSelectors.components;
+const dashboardSelector = e2eSelectors.pages.Dashboard;
 const publicDashboardSelector = e2eSelectors.pages.PublicDashboard;
 const publicDashboardSceneSelector = e2eSelectors.pages.PublicDashboardScene;
 
@@ -186,6 +187,45 @@ describe('PublicDashboardScenePage', () => {
     expect(screen.queryByTestId(componentsSelector.RefreshPicker.runButtonV2)).not.toBeInTheDocument();
     expect(screen.queryByTestId(componentsSelector.RefreshPicker.intervalButtonV2)).not.toBeInTheDocument();
   });
+
+  it('shows the template variables viewers can change', async () => {
+    const accessToken = 'variables-pubdash-access-token';
+    config.publicDashboardAccessToken = accessToken;
+    setupLoadDashboardMock({
+      dashboard: {
+        ...simpleDashboard,
+        templating: {
+          list: [
+            {
+              name: 'job',
+              type: 'custom',
+              query: 'api,web',
+              current: { text: 'api', value: 'api' },
+              options: [
+                { text: 'api', value: 'api', selected: true },
+                { text: 'web', value: 'web', selected: false },
+              ],
+            },
+            {
+              name: 'env',
+              type: 'custom',
+              hide: 2,
+              query: 'prod',
+              current: { text: 'prod', value: 'prod' },
+              options: [{ text: 'prod', value: 'prod', selected: true }],
+            },
+          ],
+        },
+      },
+      meta: {},
+    });
+    setup(accessToken);
+
+    await waitForDashboardGridToRender();
+
+    expect(await screen.findByTestId(dashboardSelector.SubMenu.submenuItemLabels('job'))).toBeInTheDocument();
+    expect(screen.queryByTestId(dashboardSelector.SubMenu.submenuItemLabels('env'))).not.toBeInTheDocument();
+  });
 });
 
 describe('given unavailable public dashboard', () => {
@@ -220,6 +260,22 @@ describe('given unavailable public dashboard', () => {
     expect(screen.queryByTestId(publicDashboardSelector.NotAvailable.pausedDescription)).not.toBeInTheDocument();
     expect(screen.getByTestId(publicDashboardSelector.NotAvailable.title)).toBeInTheDocument();
   });
+
+  it('renders the password prompt when the token requires a password', async () => {
+    const accessToken = 'password-pubdash-access-token';
+    config.publicDashboardAccessToken = accessToken;
+    setupLoadDashboardMock({
+      dashboard: simpleDashboard,
+      meta: { publicDashboardEnabled: true, publicDashboardPasswordRequired: true, dashboardNotFound: false },
+    });
+    setup(accessToken);
+
+    await waitForElementToBeRemoved(screen.getByTestId(publicDashboardSceneSelector.loadingPage));
+
+    expect(screen.queryByTestId(publicDashboardSceneSelector.page)).not.toBeInTheDocument();
+    expect(screen.getByTestId(publicDashboardSelector.PasswordPrompt.container)).toBeInTheDocument();
+    expect(screen.getByTestId(publicDashboardSelector.PasswordPrompt.passwordInput)).toBeInTheDocument();
+  });
 });
 
 interface VizOptions {
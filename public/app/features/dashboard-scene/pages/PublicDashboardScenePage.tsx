@@ -4,13 +4,14 @@ import { useParams } from 'react-router-dom-v5-compat';
 
 import { GrafanaTheme2, PageLayoutType } from '@grafana/data';
 import { selectors as e2eSelectors } from '@grafana/e2e-selectors';
-import { SceneComponentProps, UrlSyncContextProvider } from '@grafana/scenes';
+import { SceneComponentProps, UrlSyncContextProvider, VariableValueSelectors } from '@grafana/scenes';
 import { Icon, Stack, useStyles2 } from '@grafana/ui';
 import { Page } from 'app/core/components/Page/Page';
 import PageLoader from 'app/core/components/PageLoader/PageLoader';
 import { GrafanaRouteComponentProps } from 'app/core/navigation/types';
 import { PublicDashboardFooter } from 'app/features/dashboard/components/PublicDashboard/PublicDashboardsFooter';
 import { PublicDashboardNotAvailable } from 'app/features/dashboard/components/PublicDashboardNotAvailable/PublicDashboardNotAvailable';
+import { PublicDashboardPasswordPrompt } from 'app/features/dashboard/components/PublicDashboardPasswordPrompt/PublicDashboardPasswordPrompt';
 import {
   PublicDashboardPageRouteParams,
   PublicDashboardPageRouteSearchParams,
@@ -51,6 +52,18 @@ export function PublicDashboardScenePage({ route }: Props) {
     );
   }
 
+  const { publicDashboardPasswordRequired, publicDashboardTooManyRequests } = dashboard.state.meta;
+  if (publicDashboardPasswordRequired || publicDashboardTooManyRequests) {
+    const onPasswordSubmit = () => {
+      // the scene of the failed load is cached with the same version as the next failure
+      stateManager.clearSceneCache();
+      stateManager.loadDashboard({ uid: accessToken, route: DashboardRoutes.Public });
+    };
+    return (
+      <PublicDashboardPasswordPrompt tooManyRequests={publicDashboardTooManyRequests} onSubmit={onPasswordSubmit} />
+    );
+  }
+
   if (dashboard.state.meta.publicDashboardEnabled === false) {
     return <PublicDashboardNotAvailable paused />;
   }
@@ -74,7 +87,7 @@ export function PublicDashboardScenePage({ route }: Props) {
 function PublicDashboardSceneRenderer({ model }: SceneComponentProps<DashboardScene>) {
   const [isActive, setIsActive] = useState(false);
   const { controls, title } = model.useState();
-  const { timePicker, refreshPicker, hideTimeControls } = controls!.useState();
+  const { timePicker, refreshPicker, hideTimeControls, variableControls, hideVariableControls } = controls!.useState();
   const bodyToRender = model.getBodyToRender();
   const styles = useStyles2(getStyles);
 
@@ -100,6 +113,16 @@ function PublicDashboardSceneRenderer({ model }: SceneComponentProps<DashboardSc
           </div>
           <span className={styles.title}>{title}</span>
         </Stack>
+        {!hideVariableControls && (
+          <Stack grow={1} wrap={'wrap'}>
+            {/* only the values allowed by the public dashboard can be selected */}
+            {variableControls
+              .filter((c) => c instanceof VariableValueSelectors)
+              .map((c) => (
+                <c.Component model={c} key={c.state.key} />
+              ))}
+          </Stack>
+        )}
         {!hideTimeControls && (
           <Stack>
             <timePicker.Component model={timePicker} />
@@ -127,6 +150,7 @@ function getStyles(theme: GrafanaTheme2) {
       display: 'flex',
       justifyContent: 'space-between',
       alignItems: 'center',
+      gap: theme.spacing(2),
       position: 'sticky',
       top: 0,
       zIndex: theme.zIndex.navbarFixed,
@@ -1,4 +1,7 @@
+import { contextSrv } from 'app/core/core';
 import { publicDashboardApi } from 'app/features/dashboard/api/publicDashboardApi';
+import { AccessTokensConfiguration } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ConfigPublicDashboard/AccessTokensConfiguration';
+import { AccessControlAction } from 'app/types';
 
 import { useShareDrawerContext } from '../../../ShareDrawer/ShareDrawerContext';
 import ShareConfiguration from '../ShareConfiguration';
@@ -11,5 +14,18 @@ export function PublicSharing() {
     dashboard.state.uid!
   );
 
-  return <>{!publicDashboard ? <CreatePublicSharing hasError={isError} /> : <ShareConfiguration />}</>;
+  if (!publicDashboard) {
+    return <CreatePublicSharing hasError={isError} />;
+  }
+
+  return (
+    <>
+      <ShareConfiguration />
+      <AccessTokensConfiguration
+        disabled={!contextSrv.hasPermission(AccessControlAction.DashboardsPublicWrite)}
+        dashboardUid={dashboard.state.uid!}
+        publicDashboard={publicDashboard}
+      />
+    </>
+  );
 }
@@ -26,7 +26,7 @@ beforeEach(() => {
 
 describe('ShareAlerts', () => {
   describe('UnsupportedTemplateVariablesAlert', () => {
-    it('should render alert when hasPermission and the dashboard has template vars', async () => {
+    it('should not render alert when the dashboard has template vars', async () => {
       await setup(undefined, {
         $variables: new SceneVariableSet({
           variables: [
@@ -40,11 +40,6 @@ describe('ShareAlerts', () => {
         }),
       });
 
-      expect(await screen.findByTestId(selectors.TemplateVariablesWarningAlert)).toBeInTheDocument();
-    });
-    it('should not render alert when hasPermission but the dashboard has no template vars', async () => {
-      await setup();
-
       expect(screen.queryByTestId(selectors.TemplateVariablesWarningAlert)).not.toBeInTheDocument();
     });
   });
@@ -1,7 +1,6 @@
 import { contextSrv } from 'app/core/core';
 import { EmailSharingPricingAlert } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ModalAlerts/EmailSharingPricingAlert';
 import { UnsupportedDataSourcesAlert } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ModalAlerts/UnsupportedDataSourcesAlert';
-import { UnsupportedTemplateVariablesAlert } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ModalAlerts/UnsupportedTemplateVariablesAlert';
 import {
   isEmailSharingEnabled,
   PublicDashboard,
@@ -18,11 +17,9 @@ export default function ShareAlerts({ publicDashboard }: { publicDashboard?: Pub
   const { dashboard } = useShareDrawerContext();
   const hasWritePermissions = contextSrv.hasPermission(AccessControlAction.DashboardsPublicWrite);
   const unsupportedDataSources = useUnsupportedDatasources(dashboard);
-  const hasTemplateVariables = (dashboard.state.$variables?.state.variables.length ?? 0) > 0;
 
   return (
     <>
-      {hasWritePermissions && hasTemplateVariables && <UnsupportedTemplateVariablesAlert showDescription={false} />}
       {!hasWritePermissions && <NoUpsertPermissionsAlert mode={publicDashboard ? 'edit' : 'create'} />}
       {hasWritePermissions && !!unsupportedDataSources?.length && (
         <UnsupportedDataSourcesAlert unsupportedDataSources={unsupportedDataSources.join(', ')} />
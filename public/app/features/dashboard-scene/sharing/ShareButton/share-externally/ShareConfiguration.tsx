@@ -10,6 +10,12 @@ import { contextSrv } from 'app/core/core';
 import { t, Trans } from 'app/core/internationalization';
 import { publicDashboardApi, useUpdatePublicDashboardMutation } from 'app/features/dashboard/api/publicDashboardApi';
 import { ConfigPublicDashboardForm } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ConfigPublicDashboard/ConfigPublicDashboard';
+import { VariablesConfiguration } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ConfigPublicDashboard/VariablesConfiguration';
+import {
+  getPublicDashboardVariables,
+  PublicDashboardVariableSettings,
+} from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
+import { getVariablesCompatibility } from 'app/features/dashboard-scene/utils/getVariablesCompatibility';
 import { DashboardInteractions } from 'app/features/dashboard-scene/utils/interactions';
 import { AccessControlAction } from 'app/types';
 
@@ -32,6 +38,7 @@ export default function ShareConfiguration() {
   const disableForm = isLoading || !hasWritePermissions;
   const timeRangeState = sceneGraph.getTimeRange(dashboard);
   const timeRange = timeRangeState.useState();
+  const variables = getPublicDashboardVariables(getVariablesCompatibility(dashboard));
 
   const { handleSubmit, setValue, control } = useForm<FormInput>({
     defaultValues: {
@@ -58,6 +65,16 @@ export default function ShareConfiguration() {
     });
   };
 
+  const onVariableSettingsChange = (variableSettings: PublicDashboardVariableSettings) => {
+    update({
+      dashboard: dashboard,
+      payload: {
+        ...publicDashboard!,
+        variableSettings,
+      },
+    });
+  };
+
   return (
     <Stack direction="column" gap={2}>
       <Text element="p">
@@ -139,6 +156,12 @@ export default function ShareConfiguration() {
                   <Icon name="info-circle" size="md" />
                 </Tooltip>
               </Stack>
+              <VariablesConfiguration
+                disabled={disableForm}
+                onChange={onVariableSettingsChange}
+                variables={variables}
+                variableSettings={publicDashboard?.variableSettings}
+              />
             </Stack>
           </FieldSet>
         </form>
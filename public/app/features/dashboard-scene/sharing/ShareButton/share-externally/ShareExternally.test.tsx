@@ -17,7 +17,6 @@ import { shareDashboardType } from 'app/features/dashboard/components/ShareModal
 import { DefaultGridLayoutManager } from 'app/features/dashboard-scene/scene/layout-default/DefaultGridLayoutManager';
 
 import { contextSrv } from '../../../../../core/services/context_srv';
-import * as sharePublicDashboardUtils from '../../../../dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
 import { DashboardScene, DashboardSceneState } from '../../../scene/DashboardScene';
 import { activateFullSceneTree } from '../../../utils/test-utils';
 import { ShareDrawer } from '../../ShareDrawer/ShareDrawer';
@@ -52,9 +51,7 @@ describe('Alerts', () => {
     await buildAndRenderScenario({});
     expect(screen.queryByTestId(selectors.NoUpsertPermissionsWarningAlert)).toBeInTheDocument();
   });
-  it('when dashboard has template variables, warning is shown', async () => {
-    jest.spyOn(sharePublicDashboardUtils, 'dashboardHasTemplateVariables').mockReturnValue(true);
-
+  it('when dashboard has template variables, warning is not shown', async () => {
     await buildAndRenderScenario({
       overrides: {
         $variables: new SceneVariableSet({
@@ -64,7 +61,7 @@ describe('Alerts', () => {
         }),
       },
     });
-    expect(screen.queryByTestId(selectors.TemplateVariablesWarningAlert)).toBeInTheDocument();
+    expect(screen.queryByTestId(selectors.TemplateVariablesWarningAlert)).not.toBeInTheDocument();
   });
 
   it('when dashboard has unsupported datasources, warning is shown', async () => {
@@ -6,10 +6,14 @@ import { useStyles2 } from '@grafana/ui';
 import { contextSrv } from 'app/core/core';
 import { useDeletePublicDashboardMutation } from 'app/features/dashboard/api/publicDashboardApi';
 import { ConfigPublicDashboardBase } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ConfigPublicDashboard/ConfigPublicDashboard';
-import { PublicDashboard } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
+import {
+  getPublicDashboardVariables,
+  PublicDashboard,
+} from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
 import { AccessControlAction } from 'app/types';
 
 import { shareDashboardType } from '../../../dashboard/components/ShareModal/utils';
+import { getVariablesCompatibility } from '../../utils/getVariablesCompatibility';
 import { getDashboardSceneFor } from '../../utils/utils';
 import { ShareModal } from '../ShareModal';
 
@@ -30,7 +34,7 @@ export function ConfigPublicDashboard({ model, publicDashboard, isGetLoading }:
   const dashboard = getDashboardSceneFor(model);
   const { isDirty } = dashboard.useState();
   const [deletePublicDashboard] = useDeletePublicDashboardMutation();
-  const hasTemplateVariables = (dashboard.state.$variables?.state.variables.length ?? 0) > 0;
+  const variables = getPublicDashboardVariables(getVariablesCompatibility(dashboard));
   const unsupportedDataSources = useUnsupportedDatasources(dashboard);
   const timeRangeState = sceneGraph.getTimeRange(model);
   const timeRange = timeRangeState.useState();
@@ -64,7 +68,7 @@ export function ConfigPublicDashboard({ model, publicDashboard, isGetLoading }:
       }}
       timeRange={timeRange.value}
       showSaveChangesAlert={hasWritePermissions && isDirty}
-      hasTemplateVariables={hasTemplateVariables}
+      variables={variables}
     />
   );
 }
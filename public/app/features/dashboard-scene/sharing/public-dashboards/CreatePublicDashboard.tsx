@@ -9,13 +9,6 @@ import { useUnsupportedDatasources } from './hooks';
 export function CreatePublicDashboard({ model }: SceneComponentProps<SharePublicDashboardTab>) {
   const dashboard = getDashboardSceneFor(model);
   const unsupportedDataSources = useUnsupportedDatasources(dashboard);
-  const hasTemplateVariables = (dashboard.state.$variables?.state.variables.length ?? 0) > 0;
 
-  return (
-    <CreatePublicDashboardBase
-      dashboard={dashboard}
-      unsupportedDatasources={unsupportedDataSources}
-      unsupportedTemplateVariables={hasTemplateVariables}
-    />
-  );
+  return <CreatePublicDashboardBase dashboard={dashboard} unsupportedDatasources={unsupportedDataSources} />;
 }
@@ -9,6 +9,9 @@ import {
   PublicDashboard,
   PublicDashboardSettings,
   PublicDashboardShareType,
+  PublicDashboardToken,
+  PublicDashboardTokenSettings,
+  PublicDashboardTokenWithSecret,
   SessionDashboard,
   SessionUser,
 } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
@@ -52,7 +55,13 @@ const getConfigError = (err: unknown) => ({
 export const publicDashboardApi = createApi({
   reducerPath: 'publicDashboardApi',
   baseQuery: backendSrvBaseQuery({ baseUrl: '/api' }),
-  tagTypes: ['PublicDashboard', 'AuditTablePublicDashboard', 'UsersWithActiveSessions', 'ActiveUserDashboards'],
+  tagTypes: [
+    'PublicDashboard',
+    'PublicDashboardTokens',
+    'AuditTablePublicDashboard',
+    'UsersWithActiveSessions',
+    'ActiveUserDashboards',
+  ],
   refetchOnMountOrArgChange: true,
   endpoints: (builder) => ({
     getPublicDashboard: builder.query<PublicDashboard | undefined, string>({
@@ -297,6 +306,42 @@ export const publicDashboardApi = createApi({
         'ActiveUserDashboards',
       ],
     }),
+    listPublicDashboardTokens: builder.query<PublicDashboardToken[], { dashboardUid: string; uid: string }>({
+      query: ({ dashboardUid, uid }) => ({
+        url: `/dashboards/uid/${dashboardUid}/public-dashboards/${uid}/tokens`,
+      }),
+      providesTags: (result, error, { uid }) => [{ type: 'PublicDashboardTokens', id: uid }],
+    }),
+    createPublicDashboardToken: builder.mutation<
+      PublicDashboardTokenWithSecret,
+      { dashboardUid: string; uid: string; payload: PublicDashboardTokenSettings }
+    >({
+      query: ({ dashboardUid, uid, payload }) => ({
+        url: `/dashboards/uid/${dashboardUid}/public-dashboards/${uid}/tokens`,
+        method: 'POST',
+        data: payload,
+      }),
+      async onQueryStarted(_, { dispatch, queryFulfilled }) {
+        await queryFulfilled;
+        dispatch(
+          notifyApp(createSuccessNotification(t('public-dashboard.tokens.success-creation', 'Access token created')))
+        );
+      },
+      invalidatesTags: (result, error, { uid }) => [{ type: 'PublicDashboardTokens', id: uid }],
+    }),
+    revokePublicDashboardToken: builder.mutation<void, { dashboardUid: string; uid: string; tokenUid: string }>({
+      query: ({ dashboardUid, uid, tokenUid }) => ({
+        url: `/dashboards/uid/${dashboardUid}/public-dashboards/${uid}/tokens/${tokenUid}`,
+        method: 'DELETE',
+      }),
+      async onQueryStarted(_, { dispatch, queryFulfilled }) {
+        await queryFulfilled;
+        dispatch(
+          notifyApp(createSuccessNotification(t('public-dashboard.tokens.success-revoke', 'Access token revoked')))
+        );
+      },
+      invalidatesTags: (result, error, { uid }) => [{ type: 'PublicDashboardTokens', id: uid }],
+    }),
     revokeAllAccess: builder.mutation<void, { email: string }>({
       query: () => ({
         url: '',
@@ -319,4 +364,7 @@ export const {
   useRevokeAllAccessMutation,
   usePauseOrResumePublicDashboardMutation,
   useUpdatePublicDashboardAccessMutation,
+  useListPublicDashboardTokensQuery,
+  useCreatePublicDashboardTokenMutation,
+  useRevokePublicDashboardTokenMutation,
 } = publicDashboardApi;
@@ -0,0 +1,103 @@
+import { css, cx } from '@emotion/css';
+import { FormEvent, useState } from 'react';
+
+import { GrafanaTheme2 } from '@grafana/data';
+import { selectors as e2eSelectors } from '@grafana/e2e-selectors';
+import { Alert, Button, Field, Input, useStyles2 } from '@grafana/ui';
+import { Branding } from 'app/core/components/Branding/Branding';
+import { getLoginStyles } from 'app/core/components/Login/LoginLayout';
+import { t, Trans } from 'app/core/internationalization';
+import { loadPublicDashboardPassword, savePublicDashboardPassword } from 'app/core/utils/publicDashboardPassword';
+
+const selectors = e2eSelectors.pages.PublicDashboard.PasswordPrompt;
+
+export const PublicDashboardPasswordPrompt = ({
+  tooManyRequests,
+  onSubmit,
+}: {
+  tooManyRequests?: boolean;
+  onSubmit: () => void;
+}) => {
+  const styles = useStyles2(getStyles);
+  const loginStyles = useStyles2(getLoginStyles);
+  const loginBoxBackground = Branding.LoginBoxBackground();
+  const [password, setPassword] = useState('');
+  // a saved password was sent with the request, so it was rejected
+  const invalidPassword = !tooManyRequests && Boolean(loadPublicDashboardPassword());
+
+  const onFormSubmit = (e: FormEvent) => {
+    e.preventDefault();
+    savePublicDashboardPassword(password);
+    onSubmit();
+  };
+
+  return (
+    <Branding.LoginBackground className={styles.container} data-testid={selectors.container}>
+      <form className={cx(styles.box, loginBoxBackground)} onSubmit={onFormSubmit}>
+        <Branding.LoginLogo className={loginStyles.loginLogo} />
+        <p className={styles.title}>
+          <Trans i18nKey="public-dashboard.password-prompt.title">This dashboard is protected by a password</Trans>
+        </p>
+        {tooManyRequests && (
+          <Alert
+            severity="warning"
+            title={t(
+              'public-dashboard.password-prompt.too-many-requests',
+              'Too many attempts. Wait a minute before you try again'
+            )}
+          />
+        )}
+        <Field
+          className={styles.field}
+          label={t('public-dashboard.password-prompt.password-label', 'Password')}
+          invalid={invalidPassword}
+          error={t('public-dashboard.password-prompt.invalid-password', 'Invalid password')}
+        >
+          <Input
+            type="password"
+            autoFocus
+            value={password}
+            onChange={(e) => setPassword(e.currentTarget.value)}
+            data-testid={selectors.passwordInput}
+          />
+        </Field>
+        <Button type="submit" disabled={!password} data-testid={selectors.submitButton}>
+          <Trans i18nKey="public-dashboard.password-prompt.submit-button">Open dashboard</Trans>
+        </Button>
+      </form>
+    </Branding.LoginBackground>
+  );
+};
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  container: css({
+    display: 'flex',
+    justifyContent: 'center',
+    alignItems: 'center',
+    height: '100%',
+
+    ':before': {
+      opacity: 1,
+    },
+  }),
+  box: css({
+    width: '608px',
+    display: 'flex',
+    alignItems: 'center',
+    flexDirection: 'column',
+    gap: theme.spacing(2),
+    zIndex: 1,
+    borderRadius: theme.shape.borderRadius(4),
+    padding: theme.spacing(6, 8),
+    opacity: 1,
+  }),
+  title: css({
+    fontSize: theme.typography.h3.fontSize,
+    textAlign: 'center',
+    margin: 0,
+  }),
+  field: css({
+    width: '100%',
+    margin: 0,
+  }),
+});
@@ -0,0 +1,309 @@
+import { css } from '@emotion/css';
+import { useState } from 'react';
+import { Controller, useForm } from 'react-hook-form';
+
+import { dateTime, dateTimeFormat, DateTime, GrafanaTheme2 } from '@grafana/data';
+import { selectors as e2eSelectors } from '@grafana/e2e-selectors';
+import {
+  Alert,
+  Badge,
+  Button,
+  ClipboardButton,
+  ConfirmButton,
+  DateTimePicker,
+  Field,
+  Input,
+  Label,
+  Stack,
+  Text,
+  useStyles2,
+} from '@grafana/ui';
+import { t, Trans } from 'app/core/internationalization';
+import {
+  useCreatePublicDashboardTokenMutation,
+  useListPublicDashboardTokensQuery,
+  useRevokePublicDashboardTokenMutation,
+} from 'app/features/dashboard/api/publicDashboardApi';
+
+import { generatePublicDashboardUrl, PublicDashboard, PublicDashboardToken } from '../SharePublicDashboardUtils';
+
+const selectors = e2eSelectors.pages.ShareDashboardModal.PublicDashboard.AccessTokens;
+
+interface TokenForm {
+  name: string;
+  expiresAt?: DateTime;
+  maxUses: number;
+  ipAllowlist: string;
+  password: string;
+}
+
+const tokenStatus = (token: PublicDashboardToken) => {
+  if (token.revokedAt) {
+    return <Badge color="red" text={t('public-dashboard.tokens.status-revoked', 'Revoked')} />;
+  }
+  if (token.expiresAt && dateTime(token.expiresAt).isBefore(dateTime())) {
+    return <Badge color="orange" text={t('public-dashboard.tokens.status-expired', 'Expired')} />;
+  }
+  if (token.maxUses > 0 && token.useCount >= token.maxUses) {
+    return <Badge color="orange" text={t('public-dashboard.tokens.status-used', 'Used')} />;
+  }
+  return <Badge color="green" text={t('public-dashboard.tokens.status-active', 'Active')} />;
+};
+
+const TokenList = ({
+  tokens,
+  disabled,
+  onRevoke,
+}: {
+  tokens: PublicDashboardToken[];
+  disabled: boolean;
+  onRevoke: (token: PublicDashboardToken) => void;
+}) => {
+  const styles = useStyles2(getStyles);
+
+  return (
+    <table data-testid={selectors.TokenList} className={styles.table}>
+      <tbody>
+        {tokens.map((token) => (
+          <tr key={token.uid} className={styles.listItem}>
+            <td className={styles.name}>
+              <Stack direction="column" gap={0}>
+                <Text>{token.name}</Text>
+                <Text variant="bodySmall" color="secondary">
+                  {token.maxUses > 0
+                    ? t('public-dashboard.tokens.uses-limited', '{{useCount}} of {{maxUses}} uses', {
+                        useCount: token.useCount,
+                        maxUses: token.maxUses,
+                      })
+                    : t('public-dashboard.tokens.uses', '{{useCount}} uses', { useCount: token.useCount })}
+                  {token.expiresAt &&
+                    ` · ${t('public-dashboard.tokens.expires-at', 'Expires {{date}}', {
+                      date: dateTimeFormat(token.expiresAt),
+                    })}`}
+                </Text>
+              </Stack>
+            </td>
+            <td>
+              <Stack gap={1} alignItems="center">
+                {token.hasPassword && (
+                  <Badge color="blue" icon="lock" text={t('public-dashboard.tokens.password-badge', 'Password')} />
+                )}
+                {!!token.ipAllowlist?.length && (
+                  <Badge
+                    color="blue"
+                    icon="shield"
+                    text={t('public-dashboard.tokens.ip-allowlist-badge', 'IP allowlist')}
+                  />
+                )}
+                {tokenStatus(token)}
+              </Stack>
+            </td>
+            <td>
+              {!token.revokedAt && (
+                <ConfirmButton
+                  size="sm"
+                  disabled={disabled}
+                  confirmText={t('public-dashboard.tokens.revoke-confirm', 'Revoke')}
+                  confirmVariant="destructive"
+                  onConfirm={() => onRevoke(token)}
+                >
+                  {t('public-dashboard.tokens.revoke-button', 'Revoke')}
+                </ConfirmButton>
+              )}
+            </td>
+          </tr>
+        ))}
+      </tbody>
+    </table>
+  );
+};
+
+export const AccessTokensConfiguration = ({
+  disabled,
+  dashboardUid,
+  publicDashboard,
+}: {
+  disabled: boolean;
+  dashboardUid: string;
+  publicDashboard: PublicDashboard;
+}) => {
+  const styles = useStyles2(getStyles);
+  const [showForm, setShowForm] = useState(false);
+  // the access token of a new token is only returned once, so its URL is shown until the next token is created
+  const [createdUrl, setCreatedUrl] = useState<string>();
+
+  const { data: tokens = [] } = useListPublicDashboardTokensQuery({ dashboardUid, uid: publicDashboard.uid });
+  const [createToken, { isLoading: isCreateLoading }] = useCreatePublicDashboardTokenMutation();
+  const [revokeToken, { isLoading: isRevokeLoading }] = useRevokePublicDashboardTokenMutation();
+
+  const {
+    control,
+    register,
+    handleSubmit,
+    reset,
+    formState: { errors },
+  } = useForm<TokenForm>({ defaultValues: { name: '', maxUses: 0, ipAllowlist: '', password: '' } });
+
+  const onCreate = async (data: TokenForm) => {
+    const token = await createToken({
+      dashboardUid,
+      uid: publicDashboard.uid,
+      payload: {
+        name: data.name,
+        expiresAt: data.expiresAt?.toISOString(),
+        maxUses: Number(data.maxUses) || 0,
+        ipAllowlist: data.ipAllowlist
+          .split(',')
+          .map((entry) => entry.trim())
+          .filter(Boolean),
+        password: data.password,
+      },
+    }).unwrap();
+    setCreatedUrl(generatePublicDashboardUrl(token.accessToken));
+    setShowForm(false);
+    reset();
+  };
+
+  const onRevoke = (token: PublicDashboardToken) => {
+    revokeToken({ dashboardUid, uid: publicDashboard.uid, tokenUid: token.uid });
+  };
+
+  return (
+    <Stack direction="column" gap={1}>
+      <Label
+        description={t(
+          'public-dashboard.tokens.label-desc',
+          'Share additional links which can expire, be limited to a number of views, IP addresses or require a password'
+        )}
+      >
+        <Trans i18nKey="public-dashboard.tokens.label">Access tokens</Trans>
+      </Label>
+
+      {createdUrl && (
+        <Alert
+          severity="success"
+          title={t('public-dashboard.tokens.created-title', 'Copy the URL of the access token now')}
+          onRemove={() => setCreatedUrl(undefined)}
+        >
+          <Stack direction="column" gap={1}>
+            <Text>
+              {t(
+                'public-dashboard.tokens.created-description',
+                "It won't be shown again. Share the password of the token separately."
+              )}
+            </Text>
+            <Input
+              value={createdUrl}
+              readOnly
+              data-testid={selectors.CreatedUrlInput}
+              addonAfter={
+                <ClipboardButton variant="primary" getText={() => createdUrl}>
+                  <Trans i18nKey="public-dashboard.tokens.copy-button">Copy</Trans>
+                </ClipboardButton>
+              }
+            />
+          </Stack>
+        </Alert>
+      )}
+
+      {tokens.length > 0 && (
+        <div className={styles.listContainer}>
+          <TokenList tokens={tokens} disabled={disabled || isRevokeLoading} onRevoke={onRevoke} />
+        </div>
+      )}
+
+      {showForm ? (
+        <form onSubmit={handleSubmit(onCreate)}>
+          <Field
+            label={t('public-dashboard.tokens.name-label', 'Name')}
+            invalid={!!errors.name}
+            error={t('public-dashboard.tokens.name-required', 'Name is required')}
+            disabled={disabled}
+          >
+            <Input {...register('name', { required: true })} data-testid={selectors.NameInput} />
+          </Field>
+          <Field
+            label={t('public-dashboard.tokens.expires-at-label', 'Expiration')}
+            description={t('public-dashboard.tokens.expires-at-desc', 'Leave empty for a token which never expires')}
+            disabled={disabled}
+          >
+            <Controller
+              control={control}
+              name="expiresAt"
+              render={({ field: { value, onChange } }) => (
+                <DateTimePicker date={value} onChange={onChange} minDate={new Date()} clearable />
+              )}
+            />
+          </Field>
+          <Field
+            label={t('public-dashboard.tokens.max-uses-label', 'Maximum number of views')}
+            description={t('public-dashboard.tokens.max-uses-desc', 'Leave 0 for unlimited views')}
+            disabled={disabled}
+          >
+            <Input type="number" min={0} {...register('maxUses', { valueAsNumber: true, min: 0 })} />
+          </Field>
+          <Field
+            label={t('public-dashboard.tokens.ip-allowlist-label', 'IP allowlist')}
+            description={t(
+              'public-dashboard.tokens.ip-allowlist-desc',
+              'Comma separated IP addresses and CIDR ranges. Leave empty to allow every address'
+            )}
+            disabled={disabled}
+          >
+            <Input {...register('ipAllowlist')} placeholder="192.168.0.1, 10.0.0.0/8" />
+          </Field>
+          <Field
+            label={t('public-dashboard.tokens.password-label', 'Password')}
+            description={t(
+              'public-dashboard.tokens.password-desc',
+              'Viewers are asked for the password. Leave empty to allow viewing without a password'
+            )}
+            disabled={disabled}
+          >
+            <Input type="password" autoComplete="new-password" {...register('password')} />
+          </Field>
+          <Stack gap={1}>
+            <Button type="submit" disabled={disabled || isCreateLoading} data-testid={selectors.CreateButton}>
+              <Trans i18nKey="public-dashboard.tokens.create-button">Create access token</Trans>
+            </Button>
+            <Button variant="secondary" fill="outline" onClick={() => setShowForm(false)}>
+              <Trans i18nKey="public-dashboard.tokens.cancel-button">Cancel</Trans>
+            </Button>
+          </Stack>
+        </form>
+      ) : (
+        <div>
+          <Button
+            icon="plus"
+            variant="secondary"
+            size="sm"
+            disabled={disabled}
+            onClick={() => setShowForm(true)}
+            data-testid={selectors.AddButton}
+          >
+            <Trans i18nKey="public-dashboard.tokens.add-button">Add access token</Trans>
+          </Button>
+        </div>
+      )}
+    </Stack>
+  );
+};
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  listContainer: css({
+    maxHeight: '240px',
+    overflowY: 'auto',
+  }),
+  table: css({
+    width: '100%',
+  }),
+  listItem: css({
+    display: 'flex',
+    alignItems: 'center',
+    gap: theme.spacing(1),
+    padding: theme.spacing(0.75, 1),
+  }),
+  name: css({
+    flex: 1,
+  }),
+});
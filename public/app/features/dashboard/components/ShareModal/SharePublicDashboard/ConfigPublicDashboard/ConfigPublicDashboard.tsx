@@ -35,18 +35,21 @@ import { shareDashboardType } from '../../utils';
 import { NoUpsertPermissionsAlert } from '../ModalAlerts/NoUpsertPermissionsAlert';
 import { SaveDashboardChangesAlert } from '../ModalAlerts/SaveDashboardChangesAlert';
 import { UnsupportedDataSourcesAlert } from '../ModalAlerts/UnsupportedDataSourcesAlert';
-import { UnsupportedTemplateVariablesAlert } from '../ModalAlerts/UnsupportedTemplateVariablesAlert';
 import {
-  dashboardHasTemplateVariables,
   generatePublicDashboardUrl,
+  getPublicDashboardVariables,
   isEmailSharingEnabled,
   PublicDashboard,
+  PublicDashboardVariable,
+  PublicDashboardVariableSettings,
 } from '../SharePublicDashboardUtils';
 
+import { AccessTokensConfiguration } from './AccessTokensConfiguration';
 import { Configuration } from './Configuration';
 import { EmailSharingConfiguration } from './EmailSharingConfiguration';
 import { SettingsBar } from './SettingsBar';
 import { SettingsSummary } from './SettingsSummary';
+import { VariablesConfiguration } from './VariablesConfiguration';
 
 const selectors = e2eSelectors.pages.ShareDashboardModal.PublicDashboard;
 
@@ -60,7 +63,7 @@ interface Props {
   unsupportedDatasources?: string[];
   showSaveChangesAlert?: boolean;
   publicDashboard?: PublicDashboard;
-  hasTemplateVariables?: boolean;
+  variables?: PublicDashboardVariable[];
   timeRange: TimeRange;
   onRevoke: () => void;
   dashboard: DashboardModel | DashboardScene;
@@ -69,7 +72,7 @@ interface Props {
 export function ConfigPublicDashboardBase({
   onRevoke,
   timeRange,
-  hasTemplateVariables = false,
+  variables = [],
   showSaveChangesAlert = false,
   unsupportedDatasources = [],
   publicDashboard,
@@ -118,6 +121,16 @@ export function ConfigPublicDashboardBase({
     });
   };
 
+  const onVariableSettingsChange = (variableSettings: PublicDashboardVariableSettings) => {
+    update({
+      dashboard: dashboard,
+      payload: {
+        ...publicDashboard!,
+        variableSettings,
+      },
+    });
+  };
+
   const onChange = async (name: keyof ConfigPublicDashboardForm, value: boolean) => {
     setValue(name, value);
     await handleSubmit((data) => onPublicDashboardUpdate(data))();
@@ -136,7 +149,6 @@ export function ConfigPublicDashboardBase({
     <div className={styles.configContainer}>
       {showSaveChangesAlert && <SaveDashboardChangesAlert />}
       {!hasWritePermissions && <NoUpsertPermissionsAlert mode="edit" />}
-      {hasTemplateVariables && <UnsupportedTemplateVariablesAlert />}
       {unsupportedDatasources.length > 0 && (
         <UnsupportedDataSourcesAlert unsupportedDataSources={unsupportedDatasources.join(', ')} />
       )}
@@ -204,6 +216,22 @@ export function ConfigPublicDashboardBase({
           data-testid={selectors.SettingsDropdown}
         >
           <Configuration disabled={disableInputs} onChange={onChange} register={register} timeRange={timeRange} />
+          <VariablesConfiguration
+            disabled={disableInputs}
+            onChange={onVariableSettingsChange}
+            variables={variables}
+            variableSettings={publicDashboard?.variableSettings}
+          />
+        </SettingsBar>
+      </Field>
+
+      <Field className={styles.fieldSpace}>
+        <SettingsBar title={t('public-dashboard.config.access-tokens-title', 'Access tokens')}>
+          <AccessTokensConfiguration
+            disabled={disableInputs}
+            dashboardUid={publicDashboard!.dashboardUid}
+            publicDashboard={publicDashboard!}
+          />
         </SettingsBar>
       </Field>
 
@@ -240,7 +268,7 @@ export function ConfigPublicDashboard({ publicDashboard, unsupportedDatasources
   const dashboard = dashboardState.getModel()!;
   const timeRange = getTimeRange(dashboard.getDefaultTime(), dashboard);
   const hasWritePermissions = contextSrv.hasPermission(AccessControlAction.DashboardsPublicWrite);
-  const hasTemplateVariables = dashboardHasTemplateVariables(dashboard.getVariables());
+  const variables = getPublicDashboardVariables(dashboard.getVariables());
   const [deletePublicDashboard] = useDeletePublicDashboardMutation();
   const onDeletePublicDashboardClick = (onDelete: () => void) => {
     deletePublicDashboard({
@@ -260,7 +288,7 @@ export function ConfigPublicDashboard({ publicDashboard, unsupportedDatasources
           unsupportedDatasources={unsupportedDatasources}
           timeRange={timeRange}
           showSaveChangesAlert={hasWritePermissions && dashboard.hasUnsavedChanges()}
-          hasTemplateVariables={hasTemplateVariables}
+          variables={variables}
           onRevoke={() => {
             DashboardInteractions.revokePublicDashboardClicked();
             showModal(DeletePublicDashboardModal, {
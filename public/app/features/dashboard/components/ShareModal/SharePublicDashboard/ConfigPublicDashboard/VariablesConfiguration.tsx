@@ -0,0 +1,65 @@
+import { selectors as e2eSelectors } from '@grafana/e2e-selectors/src';
+import { Field, Label, MultiSelect, Stack } from '@grafana/ui/src';
+import { Trans, t } from 'app/core/internationalization';
+
+import { PublicDashboardVariable, PublicDashboardVariableSettings } from '../SharePublicDashboardUtils';
+
+const selectors = e2eSelectors.pages.ShareDashboardModal.PublicDashboard;
+
+// The value of a variable when "All" is selected
+const ALL_VALUE = '$__all';
+
+export const VariablesConfiguration = ({
+  disabled,
+  onChange,
+  variables,
+  variableSettings,
+}: {
+  disabled: boolean;
+  onChange: (variableSettings: PublicDashboardVariableSettings) => void;
+  variables: PublicDashboardVariable[];
+  variableSettings?: PublicDashboardVariableSettings;
+}) => {
+  if (!variables.length) {
+    return null;
+  }
+
+  const allowed = variableSettings?.allowed ?? {};
+
+  const onVariableChange = (name: string, values: string[]) => {
+    const next = { ...allowed, [name]: values };
+    // variables without allowed values keep the value saved with the dashboard
+    if (!values.length) {
+      delete next[name];
+    }
+    onChange({ allowed: next });
+  };
+
+  return (
+    <Stack direction="column" gap={1}>
+      <Label
+        description={t(
+          'public-dashboard.settings-configuration.variables-label-desc',
+          'Choose the values viewers can select. Variables without values always use the value saved with the dashboard'
+        )}
+      >
+        <Trans i18nKey="public-dashboard.settings-configuration.variables-label">Template variables</Trans>
+      </Label>
+      {variables.map((variable) => (
+        <Field key={variable.name} label={variable.label || variable.name} disabled={disabled}>
+          <MultiSelect
+            data-testid={selectors.VariableValuesSelect(variable.name)}
+            disabled={disabled}
+            options={variable.options.map((value) => ({
+              label: value === ALL_VALUE ? t('public-dashboard.settings-configuration.variables-all', 'All') : value,
+              value,
+            }))}
+            value={allowed[variable.name] ?? []}
+            onChange={(items) => onVariableChange(variable.name, items.flatMap((item) => item.value ?? []))}
+            placeholder={t('public-dashboard.settings-configuration.variables-placeholder', 'Saved value only')}
+          />
+        </Field>
+      ))}
+    </Stack>
+  );
+};
@@ -14,8 +14,6 @@ import { AccessControlAction, useSelector } from 'app/types';
 
 import { NoUpsertPermissionsAlert } from '../ModalAlerts/NoUpsertPermissionsAlert';
 import { UnsupportedDataSourcesAlert } from '../ModalAlerts/UnsupportedDataSourcesAlert';
-import { UnsupportedTemplateVariablesAlert } from '../ModalAlerts/UnsupportedTemplateVariablesAlert';
-import { dashboardHasTemplateVariables } from '../SharePublicDashboardUtils';
 import { useGetUnsupportedDataSources } from '../useGetUnsupportedDataSources';
 
 import { AcknowledgeCheckboxes } from './AcknowledgeCheckboxes';
@@ -30,14 +28,12 @@ export type SharePublicDashboardAcknowledgmentInputs = {
 
 interface CreatePublicDashboarBaseProps {
   unsupportedDatasources?: string[];
-  unsupportedTemplateVariables?: boolean;
   dashboard: DashboardModel | DashboardScene;
   hasError?: boolean;
 }
 
 export const CreatePublicDashboardBase = ({
   unsupportedDatasources = [],
-  unsupportedTemplateVariables = false,
   dashboard,
   hasError = false,
 }: CreatePublicDashboarBaseProps) => {
@@ -64,15 +60,13 @@ export const CreatePublicDashboardBase = ({
         </p>
         <p className={styles.description}>
           <Trans i18nKey="public-dashboard.create-page.unsupported-features-desc">
-            Currently, we don’t support template variables or frontend data sources
+            Currently, we don’t support ad hoc filters or frontend data sources
           </Trans>
         </p>
       </div>
 
       {!hasWritePermissions && <NoUpsertPermissionsAlert mode="create" />}
 
-      {unsupportedTemplateVariables && <UnsupportedTemplateVariablesAlert />}
-
       {unsupportedDatasources.length > 0 && (
         <UnsupportedDataSourcesAlert unsupportedDataSources={unsupportedDatasources.join(', ')} />
       )}
@@ -96,13 +90,11 @@ export function CreatePublicDashboard({ hasError }: { hasError?: boolean }) {
   const dashboardState = useSelector((store) => store.dashboard);
   const dashboard = dashboardState.getModel()!;
   const { unsupportedDataSources } = useGetUnsupportedDataSources(dashboard);
-  const hasTemplateVariables = dashboardHasTemplateVariables(dashboard.getVariables());
 
   return (
     <CreatePublicDashboardBase
       dashboard={dashboard}
       unsupportedDatasources={unsupportedDataSources}
-      unsupportedTemplateVariables={hasTemplateVariables}
       hasError={hasError}
     />
   );
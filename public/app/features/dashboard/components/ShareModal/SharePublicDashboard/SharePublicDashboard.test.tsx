@@ -3,7 +3,7 @@ import userEvent from '@testing-library/user-event';
 import { http, HttpResponse } from 'msw';
 import { setupServer } from 'msw/node';
 
-import { BootData, DataQuery } from '@grafana/data/src';
+import { BootData, DataQuery, TypedVariableModel } from '@grafana/data/src';
 import { selectors as e2eSelectors } from '@grafana/e2e-selectors/src';
 import { reportInteraction, setEchoSrv } from '@grafana/runtime';
 import { Panel } from '@grafana/schema';
@@ -16,7 +16,6 @@ import { DashboardInteractions } from 'app/features/dashboard-scene/utils/intera
 
 import { shareDashboardType } from '../utils';
 
-import * as sharePublicDashboardUtils from './SharePublicDashboardUtils';
 import {
   getExistentPublicDashboardResponse,
   mockDashboard,
@@ -108,6 +107,15 @@ const getErrorPublicDashboardResponse = () =>
     );
   });
 
+const jobVariable = {
+  name: 'job',
+  type: 'custom',
+  options: [
+    { text: 'api', value: 'api', selected: true },
+    { text: 'web', value: 'web', selected: false },
+  ],
+} as unknown as TypedVariableModel;
+
 const alertTests = () => {
   it('when user has no write permissions, warning is shown', async () => {
     jest.spyOn(contextSrv, 'hasPermission').mockReturnValue(false);
@@ -115,11 +123,12 @@ const alertTests = () => {
     await renderSharePublicDashboard();
     expect(screen.queryByTestId(selectors.NoUpsertPermissionsWarningAlert)).toBeInTheDocument();
   });
-  it('when dashboard has template variables, warning is shown', async () => {
-    jest.spyOn(sharePublicDashboardUtils, 'dashboardHasTemplateVariables').mockReturnValue(true);
+  it('when dashboard has template variables, warning is not shown', async () => {
+    const dashboard = createDashboardModelFixture({ id: 1 });
+    jest.spyOn(dashboard, 'getVariables').mockReturnValue([jobVariable]);
 
-    await renderSharePublicDashboard();
-    expect(screen.queryByTestId(selectors.TemplateVariablesWarningAlert)).toBeInTheDocument();
+    await renderSharePublicDashboard({ dashboard });
+    expect(screen.queryByTestId(selectors.TemplateVariablesWarningAlert)).not.toBeInTheDocument();
   });
   it('when dashboard has unsupported datasources, warning is shown', async () => {
     const panelModel = {
@@ -318,6 +327,17 @@ describe('SharePublic - Already persisted', () => {
 
     expect(screen.getByTestId(selectors.PauseSwitch)).toBeChecked();
   });
+  it('when dashboard has template variables, the allowed values can be selected', async () => {
+    server.use(getExistentPublicDashboardResponse({ variableSettings: { allowed: { job: ['api'] } } }));
+    const dashboard = createDashboardModelFixture({ id: 1 });
+    jest.spyOn(dashboard, 'getVariables').mockReturnValue([jobVariable]);
+
+    await renderSharePublicDashboard({ dashboard });
+    await userEvent.click(screen.getByText('Settings'));
+
+    expect(await screen.findByTestId(selectors.VariableValuesSelect('job'))).toBeEnabled();
+    expect(screen.getByText('api')).toBeInTheDocument();
+  });
   it('does not render email sharing section', async () => {
     await renderSharePublicDashboard();
 
@@ -7,7 +7,7 @@ import { PanelModel } from 'app/features/dashboard/state/PanelModel';
 
 import {
   PublicDashboard,
-  dashboardHasTemplateVariables,
+  getPublicDashboardVariables,
   publicDashboardPersisted,
   generatePublicDashboardUrl,
   getUnsupportedDashboardDatasources,
@@ -36,16 +36,26 @@ jest.mock('@grafana/runtime/src/services/dataSourceSrv', () => {
   };
 });
 
-describe('dashboardHasTemplateVariables', () => {
-  it('false', () => {
-    let variables: TypedVariableModel[] = [];
-    expect(dashboardHasTemplateVariables(variables)).toBe(false);
-  });
+describe('getPublicDashboardVariables', () => {
+  it('returns the values of the variables viewers can change', () => {
+    const variables = [
+      {
+        name: 'job',
+        label: 'Job',
+        type: 'custom',
+        options: [
+          { text: 'All', value: '$__all', selected: false },
+          { text: 'api', value: 'api', selected: true },
+          { text: 'web', value: ['web'], selected: false },
+        ],
+      },
+      { name: 'filters', type: 'adhoc' },
+      { name: 'empty', type: 'query', options: [] },
+    ] as unknown as TypedVariableModel[];
 
-  it('true', () => {
-    //@ts-ignore
-    let variables: TypedVariableModel[] = ['a'];
-    expect(dashboardHasTemplateVariables(variables)).toBe(true);
+    expect(getPublicDashboardVariables(variables)).toEqual([
+      { name: 'job', label: 'Job', options: ['$__all', 'api', 'web'] },
+    ]);
   });
 });
 
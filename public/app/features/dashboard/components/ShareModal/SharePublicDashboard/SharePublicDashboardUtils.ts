@@ -20,14 +20,55 @@ export interface PublicDashboardSettings {
   share: PublicDashboardShareType;
 }
 
+export interface PublicDashboardVariableSettings {
+  // The values viewers can select, keyed by the variable name
+  allowed?: Record<string, string[]>;
+}
+
 export interface PublicDashboard extends PublicDashboardSettings {
   accessToken?: string;
   uid: string;
   dashboardUid: string;
   timeSettings?: object;
+  variableSettings?: PublicDashboardVariableSettings;
   recipients?: Array<{ uid: string; recipient: string }>;
 }
 
+// An additional access token of a public dashboard, which can expire, be revoked and restrict its viewers
+export interface PublicDashboardToken {
+  uid: string;
+  publicDashboardUid: string;
+  name: string;
+  expiresAt?: string;
+  maxUses: number;
+  useCount: number;
+  lastUsedAt?: string;
+  ipAllowlist: string[] | null;
+  hasPassword: boolean;
+  createdBy: number;
+  createdAt: string;
+  revokedAt?: string;
+}
+
+export interface PublicDashboardTokenSettings {
+  name: string;
+  expiresAt?: string;
+  maxUses: number;
+  ipAllowlist: string[];
+  password: string;
+}
+
+// The access token is only returned when the token is created
+export interface PublicDashboardTokenWithSecret extends PublicDashboardToken {
+  accessToken: string;
+}
+
+export interface PublicDashboardVariable {
+  name: string;
+  label?: string;
+  options: string[];
+}
+
 export interface SessionDashboard {
   dashboardTitle: string;
   dashboardUid: string;
@@ -43,8 +84,23 @@ export interface SessionUser {
 }
 
 // Instance methods
-export const dashboardHasTemplateVariables = (variables: TypedVariableModel[]): boolean => {
-  return variables.length > 0;
+/**
+ * Get the template variables which viewers can be allowed to change, with the values they can select.
+ * Ad hoc filters are not supported by public dashboards.
+ */
+export const getPublicDashboardVariables = (variables: TypedVariableModel[]): PublicDashboardVariable[] => {
+  return variables.flatMap((variable) => {
+    if (variable.type === 'adhoc' || variable.type === 'system' || !('options' in variable)) {
+      return [];
+    }
+    const options = new Set<string>();
+    for (const option of variable.options ?? []) {
+      for (const value of Array.isArray(option.value) ? option.value : [option.value]) {
+        options.add(String(value));
+      }
+    }
+    return options.size > 0 ? [{ name: variable.name, label: variable.label, options: Array.from(options) }] : [];
+  });
 };
 
 export const publicDashboardPersisted = (publicDashboard?: PublicDashboard): boolean => {
@@ -1,9 +1,9 @@
 import { css } from '@emotion/css';
-import { useEffect } from 'react';
+import { useEffect, useState } from 'react';
 import { useLocation, useParams } from 'react-router-dom-v5-compat';
 import { usePrevious } from 'react-use';
 
-import { GrafanaTheme2, PageLayoutType, TimeZone } from '@grafana/data';
+import { GrafanaTheme2, PageLayoutType, TimeZone, VariableHide } from '@grafana/data';
 import { selectors as e2eSelectors } from '@grafana/e2e-selectors/src';
 import { PageToolbar, useStyles2 } from '@grafana/ui';
 import { Page } from 'app/core/components/Page/Page';
@@ -22,6 +22,8 @@ import { DashboardLoading } from '../components/DashboardLoading/DashboardLoadin
 import { PublicDashboardFooter } from '../components/PublicDashboard/PublicDashboardsFooter';
 import { useGetPublicDashboardConfig } from '../components/PublicDashboard/usePublicDashboardConfig';
 import { PublicDashboardNotAvailable } from '../components/PublicDashboardNotAvailable/PublicDashboardNotAvailable';
+import { PublicDashboardPasswordPrompt } from '../components/PublicDashboardPasswordPrompt/PublicDashboardPasswordPrompt';
+import { SubMenu } from '../components/SubMenu/SubMenu';
 import { DashboardGrid } from '../dashgrid/DashboardGrid';
 import { getTimeSrv } from '../services/TimeSrv';
 import { DashboardModel } from '../state/DashboardModel';
@@ -65,6 +67,8 @@ const PublicDashboardPage = (props: Props) => {
   const styles = useStyles2(getStyles);
   const dashboardState = useSelector((store) => store.dashboard);
   const dashboard = dashboardState.getModel();
+  // incremented to load the dashboard again after the password is entered
+  const [loadCount, setLoadCount] = useState(0);
 
   useEffect(() => {
     dispatch(
@@ -75,7 +79,7 @@ const PublicDashboardPage = (props: Props) => {
         keybindingSrv: context.keybindings,
       })
     );
-  }, [route.routeName, accessToken, context.keybindings, dispatch]);
+  }, [route.routeName, accessToken, context.keybindings, dispatch, loadCount]);
 
   useEffect(() => {
     if (prevProps?.location.search !== location.search) {
@@ -100,6 +104,15 @@ const PublicDashboardPage = (props: Props) => {
     return <DashboardLoading initPhase={dashboardState.initPhase} />;
   }
 
+  if (dashboard.meta.publicDashboardPasswordRequired || dashboard.meta.publicDashboardTooManyRequests) {
+    return (
+      <PublicDashboardPasswordPrompt
+        tooManyRequests={dashboard.meta.publicDashboardTooManyRequests}
+        onSubmit={() => setLoadCount((count) => count + 1)}
+      />
+    );
+  }
+
   if (dashboard.meta.publicDashboardEnabled === false) {
     return <PublicDashboardNotAvailable paused />;
   }
@@ -113,6 +126,11 @@ const PublicDashboardPage = (props: Props) => {
       <Toolbar dashboard={dashboard} />
       {dashboardState.initError && <DashboardFailed initError={dashboardState.initError} />}
       <div className={styles.gridContainer}>
+        {dashboard.getVariables().some((variable) => variable.hide !== VariableHide.hideVariable) && (
+          <section aria-label={e2eSelectors.pages.Dashboard.SubMenu.submenu}>
+            <SubMenu dashboard={dashboard} annotations={[]} links={[]} />
+          </section>
+        )}
         <DashboardGrid dashboard={dashboard} isEditable={false} viewPanel={null} editPanel={null} hidePanelMenus />
       </div>
       <div className={styles.footer}>
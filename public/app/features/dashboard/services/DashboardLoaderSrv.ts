@@ -65,6 +65,15 @@ export class DashboardLoaderSrv {
             e.data.statusCode === 404 && e.data.messageId === 'publicdashboards.notFound';
           const isDashboardNotFound =
             e.data.statusCode === 404 && e.data.messageId === 'publicdashboards.dashboardNotFound';
+          const isPasswordRequired =
+            e.data.statusCode === 401 && e.data.messageId === 'publicdashboards.tokenPasswordRequired';
+          const isTooManyRequests =
+            e.data.statusCode === 429 && e.data.messageId === 'publicdashboards.tooManyRequests';
+
+          // the public dashboard page prompts for the password instead
+          if (isPasswordRequired || isTooManyRequests) {
+            e.isHandled = true;
+          }
 
           const dashboardModel = this._dashboardLoadFailed(
             isPublicDashboardPaused ? 'Public Dashboard paused' : 'Public Dashboard Not found',
@@ -75,6 +84,8 @@ export class DashboardLoaderSrv {
             meta: {
               ...dashboardModel.meta,
               publicDashboardEnabled: isPublicDashboardNotFound ? undefined : !isPublicDashboardPaused,
+              publicDashboardPasswordRequired: isPasswordRequired,
+              publicDashboardTooManyRequests: isTooManyRequests,
               dashboardNotFound: isPublicDashboardNotFound || isDashboardNotFound,
             },
           };
@@ -15,6 +15,7 @@ import { GrafanaLiveEditor } from './components/GrafanaLiveEditor';
 import { NodeGraphEditor } from './components/NodeGraphEditor';
 import { PredictablePulseEditor } from './components/PredictablePulseEditor';
 import { RawFrameEditor } from './components/RawFrameEditor';
+import { SeededScenarioEditor } from './components/SeededScenarioEditor';
 import { SimulationQueryEditor } from './components/SimulationQueryEditor';
 import { USAQueryEditor, usaQueryModes } from './components/USAQueryEditor';
 import { defaultCSVWaveQuery, defaultPulseQuery, defaultQuery } from './constants';
@@ -383,6 +384,13 @@ export const QueryEditor = ({ query, datasource, onChange, onRunQuery }: Props)
           />
         </InlineField>
       )}
+      {(scenarioId === TestDataQueryType.HighCardinality ||
+        scenarioId === TestDataQueryType.Flapping ||
+        scenarioId === TestDataQueryType.CounterReset ||
+        scenarioId === TestDataQueryType.SeededLogs ||
+        scenarioId === TestDataQueryType.SyntheticTraces) && (
+        <SeededScenarioEditor onChange={onUpdate} query={query} />
+      )}
       {scenarioId === TestDataQueryType.ErrorWithSource && (
         <ErrorWithSourceQueryEditor onChange={onUpdate} query={query} ds={datasource} />
       )}
@@ -0,0 +1,98 @@
+import { FormEvent } from 'react';
+
+import { InlineField, InlineFieldRow, InlineSwitch, Input } from '@grafana/ui';
+
+import { TestDataDataQuery, TestDataQueryType } from '../dataquery';
+
+interface SeededField {
+  label: string;
+  id: string;
+  placeholder: string;
+  // options object of the scenario, top level query field if not set
+  group?: 'highCardinality' | 'flapping' | 'counterReset';
+  min?: number;
+  max?: number;
+  step?: number;
+}
+
+const seedField: SeededField = { label: 'Seed', id: 'seed', placeholder: 'random', step: 1 };
+const seriesCountField: SeededField = { label: 'Series count', id: 'seriesCount', placeholder: '1', min: 1, step: 1 };
+
+const seededFields: Partial<Record<TestDataQueryType, SeededField[]>> = {
+  [TestDataQueryType.HighCardinality]: [
+    seedField,
+    seriesCountField,
+    { label: 'Labels', id: 'labelCount', group: 'highCardinality', placeholder: '3', min: 1, max: 20, step: 1 },
+    { label: 'Label values', id: 'valuesPerLabel', group: 'highCardinality', placeholder: '10', min: 1, step: 1 },
+  ],
+  [TestDataQueryType.Flapping]: [
+    seedField,
+    seriesCountField,
+    { label: 'Low', id: 'low', group: 'flapping', placeholder: '0' },
+    { label: 'High', id: 'high', group: 'flapping', placeholder: '1' },
+    { label: 'Flap chance', id: 'flapProbability', group: 'flapping', placeholder: '0.1', min: 0, step: 0.05 },
+  ],
+  [TestDataQueryType.CounterReset]: [
+    seedField,
+    seriesCountField,
+    { label: 'Rate', id: 'rate', group: 'counterReset', placeholder: '1', min: 0, step: 0.1 },
+    { label: 'Reset chance', id: 'resetProbability', group: 'counterReset', placeholder: '0.05', min: 0, step: 0.01 },
+  ],
+  [TestDataQueryType.SeededLogs]: [seedField, { label: 'Lines', id: 'lines', placeholder: '10', min: 0, step: 1 }],
+  [TestDataQueryType.SyntheticTraces]: [
+    seedField,
+    { label: 'Span count', id: 'spanCount', placeholder: '10', min: 1, step: 1 },
+  ],
+};
+
+export interface SeededScenarioEditorProps {
+  onChange: (value: TestDataDataQuery) => void;
+  query: TestDataDataQuery;
+}
+
+export const SeededScenarioEditor = ({ onChange, query }: SeededScenarioEditorProps) => {
+  const fields = (query.scenarioId && seededFields[query.scenarioId]) || [];
+
+  const onInputChange = (field: SeededField) => (e: FormEvent<HTMLInputElement>) => {
+    const value = e.currentTarget.value === '' ? undefined : Number(e.currentTarget.value);
+    if (!field.group) {
+      onChange({ ...query, [field.id]: value });
+      return;
+    }
+    onChange({ ...query, [field.group]: { ...query[field.group], [field.id]: value } });
+  };
+
+  const getValue = (field: SeededField) => {
+    const values = (field.group ? query[field.group] : query) as Record<string, unknown> | undefined;
+    const value = values?.[field.id];
+    return typeof value === 'number' ? value : '';
+  };
+
+  return (
+    <InlineFieldRow>
+      {fields.map((field) => (
+        <InlineField label={field.label} labelWidth={14} key={field.id}>
+          <Input
+            width={16}
+            type="number"
+            id={`seeded-${field.id}-${query.refId}`}
+            min={field.min}
+            max={field.max}
+            step={field.step}
+            value={getValue(field)}
+            placeholder={field.placeholder}
+            onChange={onInputChange(field)}
+          />
+        </InlineField>
+      ))}
+      {query.scenarioId === TestDataQueryType.SeededLogs && (
+        <InlineField label="Level" labelWidth={14}>
+          <InlineSwitch
+            value={Boolean(query.levelColumn)}
+            onChange={(e) => onChange({ ...query, levelColumn: e.currentTarget.checked })}
+          />
+        </InlineField>
+      )}
+    </InlineFieldRow>
+  );
+};
@@ -9,10 +9,13 @@ export enum TestDataQueryType {
   CSVContent = 'csv_content',
   CSVFile = 'csv_file',
   CSVMetricValues = 'csv_metric_values',
+  CounterReset = 'counter_reset',
   DataPointsOutsideRange = 'datapoints_outside_range',
   ExponentialHeatmapBucketData = 'exponential_heatmap_bucket_data',
   FlameGraph = 'flame_graph',
+  Flapping = 'flapping',
   GrafanaAPI = 'grafana_api',
+  HighCardinality = 'high_cardinality',
   LinearHeatmapBucketData = 'linear_heatmap_bucket_data',
   Live = 'live',
   Logs = 'logs',
@@ -25,10 +28,12 @@ export enum TestDataQueryType {
   RandomWalkTable = 'random_walk_table',
   RandomWalkWithError = 'random_walk_with_error',
   RawFrame = 'raw_frame',
+  SeededLogs = 'seeded_logs',
   ServerError500 = 'server_error_500',
   Simulation = 'simulation',
   SlowQuery = 'slow_query',
   StreamingClient = 'streaming_client',
+  SyntheticTraces = 'synthetic_traces',
   TableStatic = 'table_static',
   Trace = 'trace',
   USA = 'usa',
@@ -53,6 +58,22 @@ export interface PulseWaveQuery {
   timeStep?: number;
 }
 
+export interface HighCardinalityQuery {
+  labelCount?: number;
+  valuesPerLabel?: number;
+}
+
+export interface FlappingQuery {
+  flapProbability?: number;
+  high?: number;
+  low?: number;
+}
+
+export interface CounterResetQuery {
+  rate?: number;
+  resetProbability?: number;
+}
+
 export interface SimulationQuery {
   config?: Record<string, unknown>;
   key: {
@@ -103,6 +124,7 @@ export interface Scenario {
 export interface TestDataDataQuery extends common.DataQuery {
   alias?: string;
   channel?: string;
+  counterReset?: CounterResetQuery;
   csvContent?: string;
   csvFileName?: string;
   csvWave?: CSVWave[]; // TODO can we prevent partial from being generated
@@ -112,6 +134,8 @@ export interface TestDataDataQuery extends common.DataQuery {
   dropPercent?: number;
   errorType?: 'server_panic' | 'frontend_exception' | 'frontend_observable';
   flamegraphDiff?: boolean;
+  flapping?: FlappingQuery;
+  highCardinality?: HighCardinalityQuery;
   labels?: string;
   levelColumn?: boolean;
   lines?: number;
@@ -120,6 +144,10 @@ export interface TestDataDataQuery extends common.DataQuery {
   pulseWave?: PulseWaveQuery;
   rawFrameContent?: string;
   scenarioId?: TestDataQueryType;
+  /**
+   * Seed for generated data, the same seed and time range return the same data
+   */
+  seed?: number;
   seriesCount?: number;
   sim?: SimulationQuery;
   spanCount?: number;
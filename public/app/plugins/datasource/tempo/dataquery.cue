@@ -53,6 +53,8 @@ composableKinds: DataQuery: {
 					tableType?: #SearchTableType
 					// For metric queries, the step size to use
 					step?: string
+					// For metric queries, whether to run a range or an instant query
+					metricsQueryType?: #MetricsQueryType
 				} @cuetsy(kind="interface") @grafana(TSVeneer="type")
 
 				#TempoQueryType: "traceql" | "traceqlSearch" | "serviceMap" | "upload" | "nativeSearch" | "traceId" | "clear" @cuetsy(kind="type")
@@ -63,6 +65,9 @@ composableKinds: DataQuery: {
 				// The type of the table that is used to display the search results
 				#SearchTableType: "traces" | "spans" | "raw" @cuetsy(kind="enum")
 
+				// The type of the metrics query
+				#MetricsQueryType: "range" | "instant" @cuetsy(kind="enum")
+
 				// static fields are pre-set in the UI, dynamic fields are added by the user
 				#TraceqlSearchScope: "intrinsic" | "unscoped" | "event" | "instrumentation" | "link" | "resource" | "span" @cuetsy(kind="enum")
 				#TraceqlFilter: {
@@ -24,6 +24,10 @@ export interface TempoQuery extends common.DataQuery {
    * @deprecated Define the maximum duration to select traces. Use duration format, for example: 1.2s, 100ms
    */
   maxDuration?: string;
+  /**
+   * For metric queries, whether to run a range or an instant query
+   */
+  metricsQueryType?: MetricsQueryType;
   /**
    * @deprecated Define the minimum duration to select traces. Use duration format, for example: 1.2s, 100ms
    */
@@ -92,6 +96,14 @@ export enum SearchTableType {
   Traces = 'traces',
 }
 
+/**
+ * The type of the metrics query
+ */
+export enum MetricsQueryType {
+  Instant = 'instant',
+  Range = 'range',
+}
+
 /**
  * static fields are pre-set in the UI, dynamic fields are added by the user
  */
@@ -65,6 +65,8 @@ export interface DashboardMeta {
   hasUnsavedFolderChange?: boolean;
   annotationsPermissions?: AnnotationsPermissions;
   publicDashboardEnabled?: boolean;
+  publicDashboardPasswordRequired?: boolean;
+  publicDashboardTooManyRequests?: boolean;
   dashboardNotFound?: boolean;
   isEmbedded?: boolean;
   isNew?: boolean;